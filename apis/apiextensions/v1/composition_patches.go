@@ -22,10 +22,12 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
 const (
@@ -33,6 +35,9 @@ const (
 	errCombineRequiresVariables = "combine patch types require at least one variable"
 
 	errFmtRequiredField               = "%s is required by type %s"
+	errFmtForbiddenField              = "%s cannot be set by type %s"
+	errFmtExternalNameNotString       = "external name must be a string, not %T"
+	errFmtNotAnObject                 = "cannot set the external name of %T"
 	errFmtUndefinedPatchSet           = "cannot find PatchSet by name %s"
 	errFmtInvalidPatchType            = "patch type %s is unsupported"
 	errFmtCombineStrategyNotSupported = "combine strategy %s is not supported"
@@ -46,11 +51,13 @@ type PatchType string
 
 // Patch types.
 const (
-	PatchTypeFromCompositeFieldPath PatchType = "FromCompositeFieldPath" // Default
-	PatchTypePatchSet               PatchType = "PatchSet"
-	PatchTypeToCompositeFieldPath   PatchType = "ToCompositeFieldPath"
-	PatchTypeCombineFromComposite   PatchType = "CombineFromComposite"
-	PatchTypeCombineToComposite     PatchType = "CombineToComposite"
+	PatchTypeFromCompositeFieldPath    PatchType = "FromCompositeFieldPath" // Default
+	PatchTypePatchSet                  PatchType = "PatchSet"
+	PatchTypeToCompositeFieldPath      PatchType = "ToCompositeFieldPath"
+	PatchTypeCombineFromComposite      PatchType = "CombineFromComposite"
+	PatchTypeCombineToComposite        PatchType = "CombineToComposite"
+	PatchTypeTemplateFromComposite     PatchType = "TemplateFromComposite"
+	PatchTypeExternalNameFromComposite PatchType = "ExternalNameFromComposite"
 )
 
 // A FromFieldPathPolicy determines how to patch from a field path.
@@ -82,13 +89,13 @@ type Patch struct {
 	// Type sets the patching behaviour to be used. Each patch type may require
 	// its' own fields to be set on the Patch object.
 	// +optional
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite;TemplateFromComposite;ExternalNameFromComposite
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type PatchType `json:"type,omitempty"`
 
 	// FromFieldPath is the path of the field on the resource whose value is
-	// to be used as input. Required when type is FromCompositeFieldPath or
-	// ToCompositeFieldPath.
+	// to be used as input. Required when type is FromCompositeFieldPath,
+	// ToCompositeFieldPath, or ExternalNameFromComposite.
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
@@ -97,9 +104,19 @@ type Patch struct {
 	// +optional
 	Combine *Combine `json:"combine,omitempty"`
 
+	// Template is a Go template that is rendered using the composite
+	// resource, for example "{{ .spec.parameters.name }}-{{ .spec.parameters.size }}".
+	// Required when type is TemplateFromComposite. Besides Go's built in
+	// template functions the lower, upper, trimPrefix, trimSuffix, replace,
+	// join, default, add, sub, mul, div, and mod functions are available. The
+	// template renders a string, which may be converted using a transform.
+	// +optional
+	Template *string `json:"template,omitempty"`
+
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. Must be empty when type is
+	// ExternalNameFromComposite.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
@@ -133,6 +150,10 @@ func (c *Patch) Apply(cp, cd runtime.Object, only ...PatchType) error {
 		return c.applyCombineFromVariablesPatch(cp, cd)
 	case PatchTypeCombineToComposite:
 		return c.applyCombineFromVariablesPatch(cd, cp)
+	case PatchTypeTemplateFromComposite:
+		return c.applyTemplatePatch(cp, cd)
+	case PatchTypeExternalNameFromComposite:
+		return c.applyExternalNamePatch(cp, cd)
 	case PatchTypePatchSet:
 		// Already resolved - nothing to do.
 	}
@@ -318,6 +339,93 @@ func (c *Patch) applyCombineFromVariablesPatch(from, to runtime.Object) error {
 	return patchFieldValueToObject(*c.ToFieldPath, out, to, nil)
 }
 
+// applyTemplatePatch patches the "to" resource with the result of rendering a
+// template using the "from" resource. The result may then be further
+// transformed if any transforms are defined on the patch.
+func (c *Patch) applyTemplatePatch(from, to runtime.Object) error {
+	if c.Template == nil {
+		return errors.Errorf(errFmtRequiredField, "Template", c.Type)
+	}
+	// Destination field path is required since there's no field path to
+	// default to.
+	if c.ToFieldPath == nil {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", c.Type)
+	}
+
+	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	if err != nil {
+		return err
+	}
+
+	in, err := renderTemplate(*c.Template, fromMap)
+	// Like a patch from a field path, a template that references a field that
+	// doesn't exist is skipped unless the patch policy requires it.
+	if IsTemplateMissingKey(err) && (c.Policy == nil || c.Policy.FromFieldPath == nil || *c.Policy.FromFieldPath == FromFieldPathPolicyOptional) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := c.applyTransforms(in)
+	if err != nil {
+		return err
+	}
+
+	var mo *xpv1.MergeOptions
+	if c.Policy != nil {
+		mo = c.Policy.MergeOptions
+	}
+	return patchFieldValueToObject(*c.ToFieldPath, out, to, mo)
+}
+
+// applyExternalNamePatch sets the external name annotation of the "to" resource
+// to the value of a field of the "from" resource, for example an identifier of
+// an existing external resource that the "to" resource should adopt. The value
+// may be transformed if any transforms are defined on the patch, but must be a
+// string. An empty value is ignored.
+func (c *Patch) applyExternalNamePatch(from, to runtime.Object) error {
+	if c.FromFieldPath == nil {
+		return errors.Errorf(errFmtRequiredField, "FromFieldPath", c.Type)
+	}
+	// The external name annotation is always patched.
+	if c.ToFieldPath != nil {
+		return errors.Errorf(errFmtForbiddenField, "ToFieldPath", c.Type)
+	}
+
+	o, ok := to.(metav1.Object)
+	if !ok {
+		return errors.Errorf(errFmtNotAnObject, to)
+	}
+
+	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	if err != nil {
+		return err
+	}
+
+	in, err := fieldpath.Pave(fromMap).GetValue(*c.FromFieldPath)
+	if IsOptionalFieldPathNotFound(err, c.Policy) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := c.applyTransforms(in)
+	if err != nil {
+		return err
+	}
+
+	name, ok := out.(string)
+	if !ok {
+		return errors.Errorf(errFmtExternalNameNotString, out)
+	}
+	if name != "" {
+		meta.SetExternalName(o, name)
+	}
+	return nil
+}
+
 // IsOptionalFieldPathNotFound returns true if the supplied error indicates a
 // field path was not found, and the supplied policy indicates a patch from that
 // field path was optional.
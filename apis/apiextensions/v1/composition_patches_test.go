@@ -821,6 +821,195 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
+		"MissingTemplateFromCompositeConfig": {
+			reason: "Should return an error if a TemplateFromComposite patch has no template",
+			args: args{
+				patch: Patch{
+					Type:        PatchTypeTemplateFromComposite,
+					ToFieldPath: pointer.StringPtr("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtRequiredField, "Template", PatchTypeTemplateFromComposite),
+			},
+		},
+		"NoOpOptionalTemplateFromComposite": {
+			reason: "Should not patch when a template references a missing field and the patch is optional",
+			args: args{
+				patch: Patch{
+					Type:        PatchTypeTemplateFromComposite,
+					Template:    pointer.StringPtr("{{ .objectMeta.labels.source1 }}"),
+					ToFieldPath: pointer.StringPtr("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta:                          metav1.ObjectMeta{Name: "cp"},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+		},
+		"RequiredTemplateFromComposite": {
+			reason: "Should return an error when a template references a missing field and the patch is required",
+			args: args{
+				patch: Patch{
+					Type:        PatchTypeTemplateFromComposite,
+					Template:    pointer.StringPtr("{{ .missing }}"),
+					ToFieldPath: pointer.StringPtr("objectMeta.labels.destination"),
+					Policy: &PatchPolicy{
+						FromFieldPath: func() *FromFieldPathPolicy {
+							s := FromFieldPathPolicyRequired
+							return &s
+						}(),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta:                          metav1.ObjectMeta{Name: "cp"},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				err: func() error {
+					_, err := renderTemplate("{{ .missing }}", map[string]any{})
+					return err
+				}(),
+			},
+		},
+		"ValidTemplateFromComposite": {
+			reason: "Should correctly apply a TemplateFromComposite patch with valid settings",
+			args: args{
+				patch: Patch{
+					Type:        PatchTypeTemplateFromComposite,
+					Template:    pointer.StringPtr(`{{ .objectMeta.labels.source1 }}-{{ if eq .objectMeta.labels.source2 "bar" }}{{ upper .objectMeta.labels.source2 }}{{ else }}none{{ end }}`),
+					ToFieldPath: pointer.StringPtr("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"destination": "foo-BAR",
+						}},
+				},
+			},
+		},
+		"MissingExternalNameFromCompositeConfig": {
+			reason: "Should return an error if an ExternalNameFromComposite patch has no fromFieldPath",
+			args: args{
+				patch: Patch{
+					Type: PatchTypeExternalNameFromComposite,
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtRequiredField, "FromFieldPath", PatchTypeExternalNameFromComposite),
+			},
+		},
+		"ExternalNameFromCompositeWithToFieldPath": {
+			reason: "Should return an error if an ExternalNameFromComposite patch has a toFieldPath",
+			args: args{
+				patch: Patch{
+					Type:          PatchTypeExternalNameFromComposite,
+					FromFieldPath: pointer.StringPtr("objectMeta.labels.id"),
+					ToFieldPath:   pointer.StringPtr("objectMeta.labels.id"),
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtForbiddenField, "ToFieldPath", PatchTypeExternalNameFromComposite),
+			},
+		},
+		"NoOpOptionalExternalNameFromComposite": {
+			reason: "Should not patch the external name when the fromFieldPath is missing and the patch is optional",
+			args: args{
+				patch: Patch{
+					Type:          PatchTypeExternalNameFromComposite,
+					FromFieldPath: pointer.StringPtr("objectMeta.labels.id"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta:                          metav1.ObjectMeta{Name: "cp"},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+		},
+		"ExternalNameFromCompositeNotString": {
+			reason: "Should return an error if an ExternalNameFromComposite patch's value is not a string",
+			args: args{
+				patch: Patch{
+					Type:          PatchTypeExternalNameFromComposite,
+					FromFieldPath: pointer.StringPtr("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"id": "cool-id"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd:  &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				err: errors.Errorf(errFmtExternalNameNotString, map[string]any{"id": "cool-id"}),
+			},
+		},
+		"ValidExternalNameFromComposite": {
+			reason: "Should correctly set the external name of the composed resource",
+			args: args{
+				patch: Patch{
+					Type:          PatchTypeExternalNameFromComposite,
+					FromFieldPath: pointer.StringPtr("objectMeta.labels.id"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"id": "cool-id"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "cd",
+						Annotations: map[string]string{"crossplane.io/external-name": "cool-id"},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
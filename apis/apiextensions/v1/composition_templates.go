@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseTemplate   = "cannot parse template"
+	errExecuteTemplate = "cannot execute template"
+	errDivideByZero    = "cannot divide by zero"
+
+	errFmtNotNumber       = "%v (%T) is not a number"
+	errFmtUnknownOperator = "unknown operator %q"
+)
+
+// templateFuncs are the functions available to a template patch. They are
+// deliberately limited to pure functions of their arguments, so that a
+// template can't read anything but the resource it's rendered from.
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"join":       join,
+	"default":    defaultValue,
+	"add":        func(a, b any) (any, error) { return arithmetic("+", a, b) },
+	"sub":        func(a, b any) (any, error) { return arithmetic("-", a, b) },
+	"mul":        func(a, b any) (any, error) { return arithmetic("*", a, b) },
+	"div":        func(a, b any) (any, error) { return arithmetic("/", a, b) },
+	"mod":        func(a, b any) (any, error) { return arithmetic("%", a, b) },
+}
+
+// ParseTemplate parses the template of a template patch. A template that
+// references a key that does not exist fails to execute, rather than rendering
+// "<no value>".
+func ParseTemplate(t string) (*template.Template, error) {
+	tmpl, err := template.New("patch").Option("missingkey=error").Funcs(templateFuncs).Parse(t)
+	return tmpl, errors.Wrap(err, errParseTemplate)
+}
+
+// renderTemplate renders the supplied template using the supplied data.
+func renderTemplate(t string, data map[string]any) (string, error) {
+	tmpl, err := ParseTemplate(t)
+	if err != nil {
+		return "", err
+	}
+	b := &bytes.Buffer{}
+	if err := tmpl.Execute(b, data); err != nil {
+		return "", errors.Wrap(err, errExecuteTemplate)
+	}
+	return b.String(), nil
+}
+
+// IsTemplateMissingKey returns true if the supplied error indicates a template
+// referenced a key that does not exist.
+func IsTemplateMissingKey(err error) bool {
+	// text/template doesn't expose a typed error for missing keys.
+	var e template.ExecError
+	return errors.As(err, &e) && strings.Contains(e.Error(), "map has no entry for key")
+}
+
+// join joins the supplied values into a string using the supplied separator.
+func join(sep string, values []any) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, sep)
+}
+
+// defaultValue returns the supplied value, or the supplied default if the
+// value is nil or an empty string.
+func defaultValue(def, v any) any {
+	if v == nil || v == "" {
+		return def
+	}
+	return v
+}
+
+// A number is either an integer or a float. Fields of unstructured resources
+// are int64 or float64, while template literals are int or float64.
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func asNumber(v any) (number, error) {
+	switch n := v.(type) {
+	case int:
+		return number{i: int64(n)}, nil
+	case int32:
+		return number{i: int64(n)}, nil
+	case int64:
+		return number{i: n}, nil
+	case float32:
+		return number{f: float64(n), isFloat: true}, nil
+	case float64:
+		return number{f: n, isFloat: true}, nil
+	}
+	return number{}, errors.Errorf(errFmtNotNumber, v, v)
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+// arithmetic applies the supplied operator to the supplied numbers. The result
+// is an integer if both numbers are integers, and a float otherwise.
+func arithmetic(op string, a, b any) (any, error) { //nolint:gocyclo // Just a switch per operator.
+	x, err := asNumber(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := asNumber(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !x.isFloat && !y.isFloat {
+		switch op {
+		case "+":
+			return x.i + y.i, nil
+		case "-":
+			return x.i - y.i, nil
+		case "*":
+			return x.i * y.i, nil
+		case "/", "%":
+			if y.i == 0 {
+				return nil, errors.New(errDivideByZero)
+			}
+			if op == "/" {
+				return x.i / y.i, nil
+			}
+			return x.i % y.i, nil
+		}
+	}
+
+	xf, yf := x.float(), y.float()
+	switch op {
+	case "+":
+		return xf + yf, nil
+	case "-":
+		return xf - yf, nil
+	case "*":
+		return xf * yf, nil
+	case "/", "%":
+		if yf == 0 {
+			return nil, errors.New(errDivideByZero)
+		}
+		if op == "/" {
+			return xf / yf, nil
+		}
+		return math.Mod(xf, yf), nil
+	}
+	return nil, errors.Errorf(errFmtUnknownOperator, op)
+}
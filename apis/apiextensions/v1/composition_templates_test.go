@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := map[string]any{
+		"spec": map[string]any{
+			"name":  "cool",
+			"size":  int64(3),
+			"ratio": float64(1.5),
+			"empty": "",
+			"tags":  []any{"a", "b", int64(3)},
+		},
+	}
+
+	type want struct {
+		out        string
+		err        bool
+		missingKey bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		template string
+		want     want
+	}{
+		"Concatenation": {
+			reason:   "Fields should be concatenated with literal text.",
+			template: "{{ .spec.name }}-db",
+			want:     want{out: "cool-db"},
+		},
+		"Conditional": {
+			reason:   "Built in template conditionals should be supported.",
+			template: `{{ if gt .spec.size 2 }}large{{ else }}small{{ end }}`,
+			want:     want{out: "large"},
+		},
+		"IntegerArithmetic": {
+			reason:   "Arithmetic on integers should produce an integer.",
+			template: "{{ add (mul .spec.size 10) 2 }} {{ div .spec.size 2 }} {{ mod .spec.size 2 }} {{ sub .spec.size 5 }}",
+			want:     want{out: "32 1 1 -2"},
+		},
+		"FloatArithmetic": {
+			reason:   "Arithmetic involving a float should produce a float.",
+			template: "{{ mul .spec.ratio .spec.size }}",
+			want:     want{out: "4.5"},
+		},
+		"DivideByZero": {
+			reason:   "Dividing by zero should return an error.",
+			template: "{{ div .spec.size 0 }}",
+			want:     want{err: true},
+		},
+		"NotANumber": {
+			reason:   "Arithmetic on a string should return an error.",
+			template: "{{ add .spec.name 1 }}",
+			want:     want{err: true},
+		},
+		"Strings": {
+			reason:   "String functions should be supported.",
+			template: `{{ upper .spec.name }} {{ trimPrefix "co" .spec.name }} {{ replace "o" "0" .spec.name }} {{ join "," .spec.tags }}`,
+			want:     want{out: "COOL ol c00l a,b,3"},
+		},
+		"Default": {
+			reason:   "The default function should replace empty values.",
+			template: `{{ default "none" .spec.empty }} {{ default "none" .spec.name }}`,
+			want:     want{out: "none cool"},
+		},
+		"MissingKey": {
+			reason:   "Referencing a missing field should return a missing key error.",
+			template: "{{ .spec.missing }}",
+			want:     want{err: true, missingKey: true},
+		},
+		"ParseError": {
+			reason:   "A template that can't be parsed should return an error.",
+			template: "{{ .spec.name ",
+			want:     want{err: true},
+		},
+		"UnsafeFunction": {
+			reason:   "Functions beyond the safe set should not be available.",
+			template: `{{ env "HOME" }}`,
+			want:     want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := renderTemplate(tc.template, data)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nrenderTemplate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nrenderTemplate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.missingKey, IsTemplateMissingKey(err)); diff != "" {
+				t.Errorf("\n%s\nIsTemplateMissingKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsTemplateMissingKey(t *testing.T) {
+	if IsTemplateMissingKey(errors.New("map has no entry for key")) {
+		t.Errorf("IsTemplateMissingKey(...): want false for an error that isn't a template execution error")
+	}
+}
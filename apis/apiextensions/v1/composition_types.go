@@ -56,8 +56,44 @@ type CompositionSpec struct {
 	// +optional
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
+
+	// DriftPolicy specifies whether composite resources using this
+	// composition correct or only report drift between the desired and
+	// observed state of their existing composed resources. Composite resources
+	// may override it using their spec.driftPolicy field.
+	// +optional
+	// +kubebuilder:validation:Enum=Correct;Report
+	DriftPolicy *DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// ConnectionDetailsTTL specifies how long the values of rotating
+	// connection details are considered fresh. Composite resources using this
+	// composition re-read rotating connection details from their composed
+	// resources at least this often. Defaults to the poll interval.
+	// +optional
+	ConnectionDetailsTTL *metav1.Duration `json:"connectionDetailsTTL,omitempty"`
+
+	// ServiceAccountName is the name of a ServiceAccount in Crossplane's
+	// namespace that composite resources using this composition impersonate
+	// when they apply composed resources, so that they can only compose what
+	// the ServiceAccount is permitted to. Composed resources are applied as
+	// Crossplane if unset, or if Crossplane was not started with
+	// --enable-composition-service-accounts.
+	// +optional
+	ServiceAccountName *string `json:"serviceAccountName,omitempty"`
 }
 
+// A DriftPolicy determines what a composite resource does when its existing
+// composed resources drift from their desired state.
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect corrects drift by applying the desired state.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+
+	// DriftPolicyReport reports drift without correcting it.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
 // A StoreConfigReference references a secret store config that may be used to
 // write connection details.
 type StoreConfigReference struct {
@@ -108,6 +144,30 @@ type ComposedTemplate struct {
 	// +kubebuilder:validation:EmbeddedResource
 	Base runtime.RawExtension `json:"base"`
 
+	// BaseFrom specifies a ConfigMap key from which to read the target
+	// resource, for bases too large to embed in a Composition. The resource
+	// read from the ConfigMap replaces Base, which must specify only its
+	// apiVersion and kind.
+	// +optional
+	BaseFrom *BaseSource `json:"baseFrom,omitempty"`
+
+	// ManagementPolicy specifies whether Crossplane manages or only observes
+	// the composed resource. An ObserveOnly composed resource must already
+	// exist; it is never created, updated, or deleted. Its name must be
+	// specified by its base or patches. Use it to patch the status of existing,
+	// shared resources into the composite resource, and from there into other
+	// composed resources.
+	// +optional
+	// +kubebuilder:validation:Enum=Default;ObserveOnly
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// Wave in which the composed resource is created. A composed resource is
+	// only created once all composed resources in earlier waves are ready,
+	// so that it isn't created before the resources it depends on. Composed
+	// resources that already exist are always updated. Defaults to 0.
+	// +optional
+	Wave *int `json:"wave,omitempty"`
+
 	// Patches will be applied as overlay to the base resource.
 	// +optional
 	Patches []Patch `json:"patches,omitempty"`
@@ -124,6 +184,38 @@ type ComposedTemplate struct {
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
 }
 
+// A ManagementPolicy determines how Crossplane manages a composed resource.
+type ManagementPolicy string
+
+const (
+	// ManagementPolicyDefault composed resources are created, updated, and
+	// deleted by their composite resource.
+	ManagementPolicyDefault ManagementPolicy = "Default"
+
+	// ManagementPolicyObserveOnly composed resources are only observed by
+	// their composite resource.
+	ManagementPolicyObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
+// A BaseSource specifies where to read the base of a composed template from.
+type BaseSource struct {
+	// ConfigMapKeyRef selects a ConfigMap key whose value is a resource,
+	// encoded as YAML or JSON.
+	ConfigMapKeyRef ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// A ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap. Must be the namespace Crossplane runs in.
+	Namespace string `json:"namespace"`
+
+	// Key of the ConfigMap.
+	Key string `json:"key"`
+}
+
 // ReadinessCheckType is used for readiness check types.
 type ReadinessCheckType string
 
@@ -201,6 +293,15 @@ type ConnectionDetail struct {
 	// FromConnectionSecretKey when set.
 	// +optional
 	Value *string `json:"value,omitempty"`
+
+	// Rotating indicates that the value of this connection detail is rotated
+	// periodically, for example by the composed resource's provider. Composite
+	// resources re-read rotating connection details at least once per the
+	// composition's connectionDetailsTTL, and bump the
+	// crossplane.io/rotation-generation annotation of their connection secret
+	// when any rotating value changes.
+	// +optional
+	Rotating *bool `json:"rotating,omitempty"`
 }
 
 // +kubebuilder:object:root=true
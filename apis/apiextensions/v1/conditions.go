@@ -41,6 +41,8 @@ const (
 
 	ReasonTerminatingComposite xpv1.ConditionReason = "TerminatingCompositeResource"
 	ReasonTerminatingClaim     xpv1.ConditionReason = "TerminatingCompositeResourceClaim"
+
+	ReasonWithdrawnClaim xpv1.ConditionReason = "WithdrawnCompositeResourceClaim"
 )
 
 // WatchingComposite indicates that Crossplane has defined and is watching for a
@@ -86,3 +88,15 @@ func TerminatingClaim() xpv1.Condition {
 		Reason:             ReasonTerminatingClaim,
 	}
 }
+
+// WithdrawnClaim indicates that Crossplane has stopped the controller for and
+// removed the definition of a composite resource claim that is no longer
+// offered.
+func WithdrawnClaim() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeOffered,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithdrawnClaim,
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -46,11 +48,30 @@ type CompositeResourceDefinitionSpec struct {
 	// the composite resource; creating, updating, or deleting the claim will
 	// create, update, or delete a corresponding composite resource. You may add
 	// claim names to an existing CompositeResourceDefinition, but they cannot
-	// be changed or removed once they have been set.
+	// be changed once they have been set. They may only be removed once the
+	// claim is no longer offered - see OfferClaim.
 	// +immutable
 	// +optional
 	ClaimNames *extv1.CustomResourceDefinitionNames `json:"claimNames,omitempty"`
 
+	// OfferClaim specifies whether the composite resource claim named by
+	// ClaimNames is offered. Setting it to false causes Crossplane to stop
+	// the claim controller and delete the claim CRD once no claims exist.
+	// Defaults to true when ClaimNames are specified.
+	// +optional
+	OfferClaim *bool `json:"offerClaim,omitempty"`
+
+	// PreviousClaimNames specifies the names of composite resource claims
+	// that were previously offered, for example before the claim was
+	// renamed. ClaimNames may be changed to new names if the names they
+	// replace are listed here. Crossplane continues to serve each previous
+	// kind of claim, but rejects new claims of a previous kind and migrates
+	// existing claims to the kind named by ClaimNames. A previous kind of
+	// claim stops being served once it is no longer listed and all claims
+	// of that kind have been migrated.
+	// +optional
+	PreviousClaimNames []extv1.CustomResourceDefinitionNames `json:"previousClaimNames,omitempty"`
+
 	// ConnectionSecretKeys is the list of keys that will be exposed to the end
 	// user of the defined kind.
 	// If the list is empty, all keys will be published.
@@ -68,6 +89,29 @@ type CompositeResourceDefinitionSpec struct {
 	// +immutable
 	EnforcedCompositionRef *CompositionReference `json:"enforcedCompositionRef,omitempty"`
 
+	// PollInterval specifies how often composite resources of the defined
+	// kind are checked for drift from their desired state. Defaults to the
+	// poll interval Crossplane was started with. Changes take effect the next
+	// time the composite resource controller starts.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// ReadyTimeout specifies how long composite resources of the defined
+	// kind, and their claims, may take to become ready. Those that take
+	// longer report a ReadyTimeoutExceeded condition so that they can be
+	// told apart from those that are still being provisioned. They continue
+	// to be reconciled, and become ready as usual. Changes take effect the
+	// next time the composite resource controller starts.
+	// +optional
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+
+	// MetadataPropagation specifies which labels and annotations propagate
+	// from a claim to its composite resource, and from a composite resource
+	// to its composed resources. Changes take effect the next time the
+	// composite resource and claim controllers start.
+	// +optional
+	MetadataPropagation *MetadataPropagation `json:"metadataPropagation,omitempty"`
+
 	// Versions is the list of all API versions of the defined composite
 	// resource. Version names are used to compute the order in which served
 	// versions are listed in API discovery. If the version string is
@@ -90,6 +134,89 @@ type CompositionReference struct {
 	Name string `json:"name"`
 }
 
+// MetadataPropagation specifies which labels and annotations propagate from a
+// claim to its composite resource, and from a composite resource to its
+// composed resources.
+type MetadataPropagation struct {
+	// Claim specifies which labels and annotations propagate from a claim
+	// to its composite resource. All labels and annotations propagate if
+	// omitted.
+	// +optional
+	Claim *MetadataFilter `json:"claim,omitempty"`
+
+	// Composed specifies which labels and annotations propagate from a
+	// composite resource to its composed resources. No labels or annotations
+	// propagate if omitted. Propagated labels and annotations may be
+	// overridden by patches.
+	// +optional
+	Composed *MetadataFilter `json:"composed,omitempty"`
+}
+
+// A MetadataFilter selects labels and annotations by the prefixes of their
+// keys.
+type MetadataFilter struct {
+	// Labels selects the labels that propagate. No labels propagate if
+	// omitted.
+	// +optional
+	Labels *KeyPrefixFilter `json:"labels,omitempty"`
+
+	// Annotations selects the annotations that propagate. No annotations
+	// propagate if omitted.
+	// +optional
+	Annotations *KeyPrefixFilter `json:"annotations,omitempty"`
+}
+
+// A KeyPrefixFilter selects keys by their prefixes.
+type KeyPrefixFilter struct {
+	// Include only keys that begin with one of these prefixes. All keys are
+	// included if omitted.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude keys that begin with one of these prefixes, even if they are
+	// included.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Selects returns true if the supplied key is selected by this filter. A nil
+// filter selects no keys.
+func (f *KeyPrefixFilter) Selects(key string) bool {
+	if f == nil {
+		return false
+	}
+	for _, p := range f.Exclude {
+		if strings.HasPrefix(key, p) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, p := range f.Include {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the subset of the supplied map whose keys are selected by
+// this filter, or nil if no keys are selected.
+func (f *KeyPrefixFilter) Select(m map[string]string) map[string]string {
+	var out map[string]string
+	for k, v := range m {
+		if !f.Selects(k) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[k] = v
+	}
+	return out
+}
+
 // CompositeResourceDefinitionVersion describes a version of an XR.
 type CompositeResourceDefinitionVersion struct {
 	// Name of this version, e.g. “v1”, “v2beta1”, etc. Composite resources are
@@ -168,6 +295,11 @@ type CompositeResourceDefinitionControllerStatus struct {
 	// version. Note that clients may interact with any served type; this is
 	// simply the type that Crossplane interacts with.
 	CompositeResourceClaimTypeRef TypeReference `json:"compositeResourceClaimType,omitempty"`
+
+	// The PreviousCompositeResourceClaimTypeRefs are the types of composite
+	// resource claim that this definition previously offered, and that
+	// Crossplane is currently migrating to the type of claim it offers.
+	PreviousCompositeResourceClaimTypeRefs []TypeReference `json:"previousCompositeResourceClaimTypes,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -216,15 +348,15 @@ func (in CompositeResourceDefinition) GetCompositeGroupVersionKind() schema.Grou
 // OffersClaim is true when a CompositeResourceDefinition offers a claim for the
 // composite resource it defines.
 func (in CompositeResourceDefinition) OffersClaim() bool {
-	return in.Spec.ClaimNames != nil
+	return in.Spec.ClaimNames != nil && (in.Spec.OfferClaim == nil || *in.Spec.OfferClaim)
 }
 
 // GetClaimGroupVersionKind returns the schema.GroupVersionKind of the CRD for
 // the composite resource claim this CompositeResourceDefinition defines. An
 // empty GroupVersionKind is returned if the CompositeResourceDefinition does
-// not offer a claim.
+// not name a claim. Note that a claim may be named but not offered.
 func (in CompositeResourceDefinition) GetClaimGroupVersionKind() schema.GroupVersionKind {
-	if !in.OffersClaim() {
+	if in.Spec.ClaimNames == nil {
 		return schema.GroupVersionKind{}
 	}
 
@@ -238,6 +370,18 @@ func (in CompositeResourceDefinition) GetClaimGroupVersionKind() schema.GroupVer
 	return schema.GroupVersionKind{Group: in.Spec.Group, Version: v, Kind: in.Spec.ClaimNames.Kind}
 }
 
+// GetPreviousClaimGroupVersionKinds returns the schema.GroupVersionKinds of the
+// CRDs for the composite resource claims this CompositeResourceDefinition
+// previously offered.
+func (in CompositeResourceDefinition) GetPreviousClaimGroupVersionKinds() []schema.GroupVersionKind {
+	v := in.GetClaimGroupVersionKind().Version
+	gvks := make([]schema.GroupVersionKind, len(in.Spec.PreviousClaimNames))
+	for i, n := range in.Spec.PreviousClaimNames {
+		gvks[i] = schema.GroupVersionKind{Group: in.Spec.Group, Version: v, Kind: n.Kind}
+	}
+	return gvks
+}
+
 // GetConnectionSecretKeys returns the set of allowed keys to filter the connection
 // secret.
 func (in *CompositeResourceDefinition) GetConnectionSecretKeys() []string {
@@ -17,8 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
@@ -31,13 +31,29 @@ const (
 	errKindImmutable        = "spec.names.kind is immutable"
 	errClaimPluralImmutable = "spec.claimNames.plural is immutable"
 	errClaimKindImmutable   = "spec.claimNames.kind is immutable"
-)
 
-// +kubebuilder:webhook:verbs=update,path=/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition,mutating=false,failurePolicy=fail,groups=apiextensions.crossplane.io,resources=compositeresourcedefinitions,versions=v1,name=compositeresourcedefinitions.apiextensions.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+	errClaimNamesOffered      = "spec.claimNames cannot be removed while the claim is offered - set spec.offerClaim to false and wait for the claim to be withdrawn first"
+	errOfferClaimWithoutNames = "spec.offerClaim cannot be true when spec.claimNames is not set"
+	errClaimKindConflict      = "spec.claimNames.kind must differ from spec.names.kind"
+	errClaimPluralConflict    = "spec.claimNames.plural must differ from spec.names.plural"
+
+	errPreviousClaimNamesWithoutNames = "spec.previousClaimNames cannot be set when spec.claimNames is not set"
+	errFmtPreviousClaimKindConflict   = "spec.previousClaimNames[%d].kind must differ from spec.names.kind and spec.claimNames.kind"
+	errFmtPreviousClaimPluralConflict = "spec.previousClaimNames[%d].plural must differ from spec.names.plural and spec.claimNames.plural"
+
+	errPollIntervalNotPositive = "spec.pollInterval must be greater than zero"
+	errReadyTimeoutNotPositive = "spec.readyTimeout must be greater than zero"
+)
 
 // ValidateCreate is run for creation actions.
 func (in *CompositeResourceDefinition) ValidateCreate() error {
-	return nil
+	if err := in.validatePollInterval(); err != nil {
+		return err
+	}
+	if err := in.validateReadyTimeout(); err != nil {
+		return err
+	}
+	return in.validateClaim()
 }
 
 // ValidateUpdate is run for update actions.
@@ -54,7 +70,9 @@ func (in *CompositeResourceDefinition) ValidateUpdate(old runtime.Object) error
 	case in.Spec.Names.Kind != oldObj.Spec.Names.Kind:
 		return errors.New(errKindImmutable)
 	}
-	if in.Spec.ClaimNames != nil && oldObj.Spec.ClaimNames != nil {
+	// Claim names may be changed if the names they replace are listed as
+	// previous claim names, so that existing claims can be migrated.
+	if in.Spec.ClaimNames != nil && oldObj.Spec.ClaimNames != nil && !in.previouslyClaimed(oldObj.Spec.ClaimNames) {
 		switch {
 		case in.Spec.ClaimNames.Plural != oldObj.Spec.ClaimNames.Plural:
 			return errors.New(errClaimPluralImmutable)
@@ -62,17 +80,77 @@ func (in *CompositeResourceDefinition) ValidateUpdate(old runtime.Object) error
 			return errors.New(errClaimKindImmutable)
 		}
 	}
+	if in.Spec.ClaimNames == nil && oldObj.Spec.ClaimNames != nil && oldObj.Status.Controllers.CompositeResourceClaimTypeRef.Kind != "" {
+		return errors.New(errClaimNamesOffered)
+	}
+	if err := in.validatePollInterval(); err != nil {
+		return err
+	}
+	if err := in.validateReadyTimeout(); err != nil {
+		return err
+	}
+	return in.validateClaim()
+}
+
+// validateClaim validates the composite resource claim this
+// CompositeResourceDefinition offers, if any.
+func (in *CompositeResourceDefinition) validateClaim() error {
+	if in.Spec.ClaimNames == nil {
+		if in.Spec.OfferClaim != nil && *in.Spec.OfferClaim {
+			return errors.New(errOfferClaimWithoutNames)
+		}
+		if len(in.Spec.PreviousClaimNames) > 0 {
+			return errors.New(errPreviousClaimNamesWithoutNames)
+		}
+		return nil
+	}
+	switch {
+	case in.Spec.ClaimNames.Kind == in.Spec.Names.Kind:
+		return errors.New(errClaimKindConflict)
+	case in.Spec.ClaimNames.Plural == in.Spec.Names.Plural:
+		return errors.New(errClaimPluralConflict)
+	}
+	for i, n := range in.Spec.PreviousClaimNames {
+		switch {
+		case n.Kind == in.Spec.Names.Kind || n.Kind == in.Spec.ClaimNames.Kind:
+			return errors.Errorf(errFmtPreviousClaimKindConflict, i)
+		case n.Plural == in.Spec.Names.Plural || n.Plural == in.Spec.ClaimNames.Plural:
+			return errors.Errorf(errFmtPreviousClaimPluralConflict, i)
+		}
+	}
 	return nil
 }
 
-// ValidateDelete is run for delete actions.
-func (in *CompositeResourceDefinition) ValidateDelete() error {
+// previouslyClaimed returns true if the kind and plural of the supplied claim
+// names are listed as previous claim names.
+func (in *CompositeResourceDefinition) previouslyClaimed(n *extv1.CustomResourceDefinitionNames) bool {
+	for _, p := range in.Spec.PreviousClaimNames {
+		if p.Kind == n.Kind && p.Plural == n.Plural {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePollInterval validates the poll interval of the composite resources
+// this CompositeResourceDefinition defines, if any.
+func (in *CompositeResourceDefinition) validatePollInterval() error {
+	if in.Spec.PollInterval != nil && in.Spec.PollInterval.Duration <= 0 {
+		return errors.New(errPollIntervalNotPositive)
+	}
+	return nil
+}
+
+// validateReadyTimeout validates how long the composite resources this
+// CompositeResourceDefinition defines may take to become ready, if set.
+func (in *CompositeResourceDefinition) validateReadyTimeout() error {
+	if in.Spec.ReadyTimeout != nil && in.Spec.ReadyTimeout.Duration <= 0 {
+		return errors.New(errReadyTimeoutNotPositive)
+	}
 	return nil
 }
 
-// SetupWebhookWithManager sets up webhook with manager.
-func (in *CompositeResourceDefinition) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(in).
-		Complete()
+// ValidateDelete is run for delete actions.
+func (in *CompositeResourceDefinition) ValidateDelete() error {
+	return nil
 }
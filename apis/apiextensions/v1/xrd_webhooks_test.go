@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -28,6 +29,8 @@ import (
 )
 
 func TestValidateUpdate(t *testing.T) {
+	offer := true
+
 	type args struct {
 		old runtime.Object
 		new *CompositeResourceDefinition
@@ -134,6 +137,169 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			err: errors.New(errClaimKindImmutable),
 		},
+		"ClaimRenamed": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind:   "b",
+							Plural: "bs",
+						},
+					},
+				},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind:   "c",
+							Plural: "cs",
+						},
+						PreviousClaimNames: []extv1.CustomResourceDefinitionNames{{
+							Kind:   "b",
+							Plural: "bs",
+						}},
+					},
+				},
+			},
+		},
+		"PreviousClaimNamesWithoutClaimNames": {
+			args: args{
+				old: &CompositeResourceDefinition{},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						PreviousClaimNames: []extv1.CustomResourceDefinitionNames{{
+							Kind:   "b",
+							Plural: "bs",
+						}},
+					},
+				},
+			},
+			err: errors.New(errPreviousClaimNamesWithoutNames),
+		},
+		"PreviousClaimKindConflict": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+					},
+				},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind:   "c",
+							Plural: "cs",
+						},
+						PreviousClaimNames: []extv1.CustomResourceDefinitionNames{{
+							Kind:   "c",
+							Plural: "bs",
+						}},
+					},
+				},
+			},
+			err: errors.Errorf(errFmtPreviousClaimKindConflict, 0),
+		},
+		"ClaimNamesRemovedWhileOffered": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind: "b",
+						},
+					},
+					Status: CompositeResourceDefinitionStatus{
+						Controllers: CompositeResourceDefinitionControllerStatus{
+							CompositeResourceClaimTypeRef: TypeReference{Kind: "b"},
+						},
+					},
+				},
+				new: &CompositeResourceDefinition{},
+			},
+			err: errors.New(errClaimNamesOffered),
+		},
+		"ClaimNamesRemovedAfterWithdrawal": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind: "b",
+						},
+					},
+				},
+				new: &CompositeResourceDefinition{},
+			},
+		},
+		"OfferClaimWithoutClaimNames": {
+			args: args{
+				old: &CompositeResourceDefinition{},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						OfferClaim: &offer,
+					},
+				},
+			},
+			err: errors.New(errOfferClaimWithoutNames),
+		},
+		"ClaimKindConflict": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+					},
+				},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "as",
+						},
+						ClaimNames: &extv1.CustomResourceDefinitionNames{
+							Kind:   "a",
+							Plural: "bs",
+						},
+					},
+				},
+			},
+			err: errors.New(errClaimKindConflict),
+		},
+		"PollIntervalNotPositive": {
+			args: args{
+				old: &CompositeResourceDefinition{},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						PollInterval: &metav1.Duration{},
+					},
+				},
+			},
+			err: errors.New(errPollIntervalNotPositive),
+		},
+		"ReadyTimeoutNotPositive": {
+			args: args{
+				old: &CompositeResourceDefinition{},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						ReadyTimeout: &metav1.Duration{},
+					},
+				},
+			},
+			err: errors.New(errReadyTimeoutNotPositive),
+		},
 		"Success": {
 			args: args{
 				old: &CompositeResourceDefinition{
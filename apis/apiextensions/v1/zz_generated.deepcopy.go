@@ -24,9 +24,26 @@ package v1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseSource) DeepCopyInto(out *BaseSource) {
+	*out = *in
+	out.ConfigMapKeyRef = in.ConfigMapKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseSource.
+func (in *BaseSource) DeepCopy() *BaseSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Combine) DeepCopyInto(out *Combine) {
 	*out = *in
@@ -76,6 +93,21 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 		**out = **in
 	}
 	in.Base.DeepCopyInto(&out.Base)
+	if in.BaseFrom != nil {
+		in, out := &in.BaseFrom, &out.BaseFrom
+		*out = new(BaseSource)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.Wave != nil {
+		in, out := &in.Wave, &out.Wave
+		*out = new(int)
+		**out = **in
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]Patch, len(*in))
@@ -139,6 +171,11 @@ func (in *CompositeResourceDefinitionControllerStatus) DeepCopyInto(out *Composi
 	*out = *in
 	out.CompositeResourceTypeRef = in.CompositeResourceTypeRef
 	out.CompositeResourceClaimTypeRef = in.CompositeResourceClaimTypeRef
+	if in.PreviousCompositeResourceClaimTypeRefs != nil {
+		in, out := &in.PreviousCompositeResourceClaimTypeRefs, &out.PreviousCompositeResourceClaimTypeRefs
+		*out = make([]TypeReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionControllerStatus.
@@ -192,6 +229,18 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(apiextensionsv1.CustomResourceDefinitionNames)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OfferClaim != nil {
+		in, out := &in.OfferClaim, &out.OfferClaim
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreviousClaimNames != nil {
+		in, out := &in.PreviousClaimNames, &out.PreviousClaimNames
+		*out = make([]apiextensionsv1.CustomResourceDefinitionNames, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ConnectionSecretKeys != nil {
 		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
 		*out = make([]string, len(*in))
@@ -207,6 +256,21 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(CompositionReference)
 		**out = **in
 	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(MetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Versions != nil {
 		in, out := &in.Versions, &out.Versions
 		*out = make([]CompositeResourceDefinitionVersion, len(*in))
@@ -230,7 +294,7 @@ func (in *CompositeResourceDefinitionSpec) DeepCopy() *CompositeResourceDefiniti
 func (in *CompositeResourceDefinitionStatus) DeepCopyInto(out *CompositeResourceDefinitionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.Controllers = in.Controllers
+	in.Controllers.DeepCopyInto(&out.Controllers)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionStatus.
@@ -395,6 +459,21 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(DriftPolicy)
+		**out = **in
+	}
+	if in.ConnectionDetailsTTL != nil {
+		in, out := &in.ConnectionDetailsTTL, &out.ConnectionDetailsTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ServiceAccountName != nil {
+		in, out := &in.ServiceAccountName, &out.ServiceAccountName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionSpec.
@@ -407,6 +486,21 @@ func (in *CompositionSpec) DeepCopy() *CompositionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 	*out = *in
@@ -435,6 +529,11 @@ func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Rotating != nil {
+		in, out := &in.Rotating, &out.Rotating
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
@@ -462,6 +561,31 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyPrefixFilter) DeepCopyInto(out *KeyPrefixFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPrefixFilter.
+func (in *KeyPrefixFilter) DeepCopy() *KeyPrefixFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyPrefixFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapTransform) DeepCopyInto(out *MapTransform) {
 	*out = *in
@@ -504,6 +628,56 @@ func (in *MathTransform) DeepCopy() *MathTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataFilter) DeepCopyInto(out *MetadataFilter) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(KeyPrefixFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(KeyPrefixFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataFilter.
+func (in *MetadataFilter) DeepCopy() *MetadataFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPropagation) DeepCopyInto(out *MetadataPropagation) {
+	*out = *in
+	if in.Claim != nil {
+		in, out := &in.Claim, &out.Claim
+		*out = new(MetadataFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Composed != nil {
+		in, out := &in.Composed, &out.Composed
+		*out = new(MetadataFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPropagation.
+func (in *MetadataPropagation) DeepCopy() *MetadataPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Patch) DeepCopyInto(out *Patch) {
 	*out = *in
@@ -517,6 +691,11 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(Combine)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
 	if in.ToFieldPath != nil {
 		in, out := &in.ToFieldPath, &out.ToFieldPath
 		*out = new(string)
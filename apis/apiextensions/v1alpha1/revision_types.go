@@ -80,11 +80,47 @@ type CompositionRevisionSpec struct {
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 
+	// DriftPolicy specifies whether composite resources using this
+	// composition correct or only report drift between the desired and
+	// observed state of their existing composed resources. Composite resources
+	// may override it using their spec.driftPolicy field.
+	// +optional
+	// +kubebuilder:validation:Enum=Correct;Report
+	DriftPolicy *DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// ConnectionDetailsTTL specifies how long the values of rotating
+	// connection details are considered fresh. Composite resources using this
+	// composition re-read rotating connection details from their composed
+	// resources at least this often. Defaults to the poll interval.
+	// +optional
+	ConnectionDetailsTTL *metav1.Duration `json:"connectionDetailsTTL,omitempty"`
+
+	// ServiceAccountName is the name of a ServiceAccount in Crossplane's
+	// namespace that composite resources using this composition impersonate
+	// when they apply composed resources, so that they can only compose what
+	// the ServiceAccount is permitted to. Composed resources are applied as
+	// Crossplane if unset, or if Crossplane was not started with
+	// --enable-composition-service-accounts.
+	// +optional
+	ServiceAccountName *string `json:"serviceAccountName,omitempty"`
+
 	// Revision number. Newer revisions have larger numbers.
 	// +immutable
 	Revision int64 `json:"revision"`
 }
 
+// A DriftPolicy determines what a composite resource does when its existing
+// composed resources drift from their desired state.
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect corrects drift by applying the desired state.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+
+	// DriftPolicyReport reports drift without correcting it.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
 // A StoreConfigReference references a secret store config that may be used to
 // write connection details.
 type StoreConfigReference struct {
@@ -137,6 +173,33 @@ type ComposedTemplate struct {
 	// +immutable
 	Base runtime.RawExtension `json:"base"`
 
+	// BaseFrom specifies a ConfigMap key from which to read the target
+	// resource, for bases too large to embed in a Composition. The resource
+	// read from the ConfigMap replaces Base, which must specify only its
+	// apiVersion and kind.
+	// +optional
+	// +immutable
+	BaseFrom *BaseSource `json:"baseFrom,omitempty"`
+
+	// ManagementPolicy specifies whether Crossplane manages or only observes
+	// the composed resource. An ObserveOnly composed resource must already
+	// exist; it is never created, updated, or deleted. Its name must be
+	// specified by its base or patches. Use it to patch the status of existing,
+	// shared resources into the composite resource, and from there into other
+	// composed resources.
+	// +optional
+	// +immutable
+	// +kubebuilder:validation:Enum=Default;ObserveOnly
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// Wave in which the composed resource is created. A composed resource is
+	// only created once all composed resources in earlier waves are ready,
+	// so that it isn't created before the resources it depends on. Composed
+	// resources that already exist are always updated. Defaults to 0.
+	// +optional
+	// +immutable
+	Wave *int `json:"wave,omitempty"`
+
 	// Patches will be applied as overlay to the base resource.
 	// +optional
 	// +immutable
@@ -156,6 +219,38 @@ type ComposedTemplate struct {
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
 }
 
+// A ManagementPolicy determines how Crossplane manages a composed resource.
+type ManagementPolicy string
+
+const (
+	// ManagementPolicyDefault composed resources are created, updated, and
+	// deleted by their composite resource.
+	ManagementPolicyDefault ManagementPolicy = "Default"
+
+	// ManagementPolicyObserveOnly composed resources are only observed by
+	// their composite resource.
+	ManagementPolicyObserveOnly ManagementPolicy = "ObserveOnly"
+)
+
+// A BaseSource specifies where to read the base of a composed template from.
+type BaseSource struct {
+	// ConfigMapKeyRef selects a ConfigMap key whose value is a resource,
+	// encoded as YAML or JSON.
+	ConfigMapKeyRef ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// A ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap. Must be the namespace Crossplane runs in.
+	Namespace string `json:"namespace"`
+
+	// Key of the ConfigMap.
+	Key string `json:"key"`
+}
+
 // ReadinessCheckType is used for readiness check types.
 type ReadinessCheckType string
 
@@ -196,11 +291,13 @@ type PatchType string
 
 // Patch types.
 const (
-	PatchTypeFromCompositeFieldPath PatchType = "FromCompositeFieldPath" // Default
-	PatchTypePatchSet               PatchType = "PatchSet"
-	PatchTypeToCompositeFieldPath   PatchType = "ToCompositeFieldPath"
-	PatchTypeCombineFromComposite   PatchType = "CombineFromComposite"
-	PatchTypeCombineToComposite     PatchType = "CombineToComposite"
+	PatchTypeFromCompositeFieldPath    PatchType = "FromCompositeFieldPath" // Default
+	PatchTypePatchSet                  PatchType = "PatchSet"
+	PatchTypeToCompositeFieldPath      PatchType = "ToCompositeFieldPath"
+	PatchTypeCombineFromComposite      PatchType = "CombineFromComposite"
+	PatchTypeCombineToComposite        PatchType = "CombineToComposite"
+	PatchTypeTemplateFromComposite     PatchType = "TemplateFromComposite"
+	PatchTypeExternalNameFromComposite PatchType = "ExternalNameFromComposite"
 )
 
 // Patch objects are applied between composite and composed resources. Their
@@ -212,13 +309,13 @@ type Patch struct {
 	// its' own fields to be set on the Patch object.
 	// +optional
 	// +immutable
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite;TemplateFromComposite;ExternalNameFromComposite
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type PatchType `json:"type,omitempty"`
 
 	// FromFieldPath is the path of the field on the resource whose value is
-	// to be used as input. Required when type is FromCompositeFieldPath or
-	// ToCompositeFieldPath.
+	// to be used as input. Required when type is FromCompositeFieldPath,
+	// ToCompositeFieldPath, or ExternalNameFromComposite.
 	// +optional
 	// +immutable
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
@@ -229,9 +326,20 @@ type Patch struct {
 	// +immutable
 	Combine *Combine `json:"combine,omitempty"`
 
+	// Template is a Go template that is rendered using the composite
+	// resource, for example "{{ .spec.parameters.name }}-{{ .spec.parameters.size }}".
+	// Required when type is TemplateFromComposite. Besides Go's built in
+	// template functions the lower, upper, trimPrefix, trimSuffix, replace,
+	// join, default, add, sub, mul, div, and mod functions are available. The
+	// template renders a string, which may be converted using a transform.
+	// +optional
+	// +immutable
+	Template *string `json:"template,omitempty"`
+
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. Must be empty when type is
+	// ExternalNameFromComposite.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
@@ -547,6 +655,16 @@ type ConnectionDetail struct {
 	// +optional
 	// +immutable
 	Value *string `json:"value,omitempty"`
+
+	// Rotating indicates that the value of this connection detail is rotated
+	// periodically, for example by the composed resource's provider. Composite
+	// resources re-read rotating connection details at least once per the
+	// composition's connectionDetailsTTL, and bump the
+	// crossplane.io/rotation-generation annotation of their connection secret
+	// when any rotating value changes.
+	// +optional
+	// +immutable
+	Rotating *bool `json:"rotating,omitempty"`
 }
 
 // CompositionRevisionStatus shows the observed state of the composition
@@ -22,9 +22,26 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseSource) DeepCopyInto(out *BaseSource) {
+	*out = *in
+	out.ConfigMapKeyRef = in.ConfigMapKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseSource.
+func (in *BaseSource) DeepCopy() *BaseSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Combine) DeepCopyInto(out *Combine) {
 	*out = *in
@@ -74,6 +91,21 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 		**out = **in
 	}
 	in.Base.DeepCopyInto(&out.Base)
+	if in.BaseFrom != nil {
+		in, out := &in.BaseFrom, &out.BaseFrom
+		*out = new(BaseSource)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.Wave != nil {
+		in, out := &in.Wave, &out.Wave
+		*out = new(int)
+		**out = **in
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]Patch, len(*in))
@@ -192,6 +224,21 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(DriftPolicy)
+		**out = **in
+	}
+	if in.ConnectionDetailsTTL != nil {
+		in, out := &in.ConnectionDetailsTTL, &out.ConnectionDetailsTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ServiceAccountName != nil {
+		in, out := &in.ServiceAccountName, &out.ServiceAccountName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
@@ -220,6 +267,21 @@ func (in *CompositionRevisionStatus) DeepCopy() *CompositionRevisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 	*out = *in
@@ -248,6 +310,11 @@ func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Rotating != nil {
+		in, out := &in.Rotating, &out.Rotating
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
@@ -330,6 +397,11 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(Combine)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(string)
+		**out = **in
+	}
 	if in.ToFieldPath != nil {
 		in, out := &in.ToFieldPath, &out.ToFieldPath
 		*out = new(string)
@@ -23,6 +23,7 @@ import (
 	"github.com/crossplane/crossplane/apis/apiextensions"
 	"github.com/crossplane/crossplane/apis/pkg"
 	"github.com/crossplane/crossplane/apis/secrets"
+	"github.com/crossplane/crossplane/apis/status"
 )
 
 func init() {
@@ -31,6 +32,7 @@ func init() {
 		apiextensions.AddToScheme,
 		pkg.AddToScheme,
 		secrets.AddToScheme,
+		status.AddToScheme,
 	)
 }
 
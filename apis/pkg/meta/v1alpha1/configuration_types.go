@@ -64,9 +64,10 @@ func (c *Configuration) ConvertTo(hub conversion.Hub) error {
 	out.Spec.DependsOn = make([]v1.Dependency, len(c.Spec.DependsOn))
 	for i := range c.Spec.DependsOn {
 		out.Spec.DependsOn[i] = v1.Dependency{
-			Provider:      c.Spec.DependsOn[i].Provider,
-			Configuration: c.Spec.DependsOn[i].Configuration,
-			Version:       c.Spec.DependsOn[i].Version,
+			Provider:       c.Spec.DependsOn[i].Provider,
+			Configuration:  c.Spec.DependsOn[i].Configuration,
+			Version:        c.Spec.DependsOn[i].Version,
+			WaitForHealthy: c.Spec.DependsOn[i].WaitForHealthy,
 		}
 	}
 
@@ -93,9 +94,10 @@ func (c *Configuration) ConvertFrom(hub conversion.Hub) error {
 	c.Spec.DependsOn = make([]Dependency, len(in.Spec.DependsOn))
 	for i := range in.Spec.DependsOn {
 		c.Spec.DependsOn[i] = Dependency{
-			Provider:      in.Spec.DependsOn[i].Provider,
-			Configuration: in.Spec.DependsOn[i].Configuration,
-			Version:       in.Spec.DependsOn[i].Version,
+			Provider:       in.Spec.DependsOn[i].Provider,
+			Configuration:  in.Spec.DependsOn[i].Configuration,
+			Version:        in.Spec.DependsOn[i].Version,
+			WaitForHealthy: in.Spec.DependsOn[i].WaitForHealthy,
 		}
 	}
 
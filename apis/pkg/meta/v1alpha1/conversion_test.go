@@ -152,8 +152,9 @@ func TestConvertTo(t *testing.T) {
 								Version:  version,
 							},
 							{
-								Provider: &config,
-								Version:  version,
+								Provider:       &config,
+								Version:        version,
+								WaitForHealthy: true,
 							},
 						},
 					},
@@ -181,8 +182,9 @@ func TestConvertTo(t *testing.T) {
 									Version:  version,
 								},
 								{
-									Provider: &config,
-									Version:  version,
+									Provider:       &config,
+									Version:        version,
+									WaitForHealthy: true,
 								},
 							},
 						},
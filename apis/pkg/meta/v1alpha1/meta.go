@@ -41,4 +41,9 @@ type Dependency struct {
 
 	// Version is the semantic version constraints of the dependency image.
 	Version string `json:"version"`
+
+	// WaitForHealthy specifies that this package should not become active
+	// until the dependency is healthy, for example because this package
+	// extends custom resources the dependency defines.
+	WaitForHealthy bool `json:"waitForHealthy,omitempty"`
 }
@@ -88,9 +88,10 @@ func (p *Provider) ConvertTo(hub conversion.Hub) error {
 	out.Spec.DependsOn = make([]v1.Dependency, len(p.Spec.DependsOn))
 	for i := range p.Spec.DependsOn {
 		out.Spec.DependsOn[i] = v1.Dependency{
-			Provider:      p.Spec.DependsOn[i].Provider,
-			Configuration: p.Spec.DependsOn[i].Configuration,
-			Version:       p.Spec.DependsOn[i].Version,
+			Provider:       p.Spec.DependsOn[i].Provider,
+			Configuration:  p.Spec.DependsOn[i].Configuration,
+			Version:        p.Spec.DependsOn[i].Version,
+			WaitForHealthy: p.Spec.DependsOn[i].WaitForHealthy,
 		}
 	}
 
@@ -124,9 +125,10 @@ func (p *Provider) ConvertFrom(hub conversion.Hub) error {
 	p.Spec.DependsOn = make([]Dependency, len(in.Spec.DependsOn))
 	for i := range in.Spec.DependsOn {
 		p.Spec.DependsOn[i] = Dependency{
-			Provider:      in.Spec.DependsOn[i].Provider,
-			Configuration: in.Spec.DependsOn[i].Configuration,
-			Version:       in.Spec.DependsOn[i].Version,
+			Provider:       in.Spec.DependsOn[i].Provider,
+			Configuration:  in.Spec.DependsOn[i].Configuration,
+			Version:        in.Spec.DependsOn[i].Version,
+			WaitForHealthy: in.Spec.DependsOn[i].WaitForHealthy,
 		}
 	}
 
@@ -40,6 +40,7 @@ const (
 	ReasonUnhealthy     xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy       xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+	ReasonWaiting       xpv1.ConditionReason = "WaitingForDependencies"
 )
 
 // Unpacking indicates that the package manager is waiting for a package
@@ -85,6 +86,17 @@ func Unhealthy() xpv1.Condition {
 	}
 }
 
+// WaitingForDependencies indicates that the current revision is not yet active
+// because it is waiting for the packages it depends on to become healthy.
+func WaitingForDependencies() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaiting,
+	}
+}
+
 // Healthy indicates that the current revision is healthy.
 func Healthy() xpv1.Condition {
 	return xpv1.Condition{
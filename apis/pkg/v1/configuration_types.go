@@ -32,6 +32,9 @@ import (
 // +kubebuilder:printcolumn:name="INSTALLED",type="string",JSONPath=".status.conditions[?(@.type=='Installed')].status"
 // +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status"
 // +kubebuilder:printcolumn:name="PACKAGE",type="string",JSONPath=".spec.package"
+// +kubebuilder:printcolumn:name="REVISION",type="string",JSONPath=".status.currentRevision",priority=1
+// +kubebuilder:printcolumn:name="DIGEST",type="string",JSONPath=".status.currentDigest",priority=1
+// +kubebuilder:printcolumn:name="HEALTH-CHANGED",type="date",JSONPath=".status.conditions[?(@.type=='Healthy')].lastTransitionTime",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,pkg}
 type Configuration struct {
@@ -74,8 +77,9 @@ type ConfigurationList struct {
 // +kubebuilder:printcolumn:name="REVISION",type="string",JSONPath=".spec.revision"
 // +kubebuilder:printcolumn:name="IMAGE",type="string",JSONPath=".spec.image"
 // +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".spec.desiredState"
-// +kubebuilder:printcolumn:name="DEP-FOUND",type="string",JSONPath=".status.foundDependencies"
-// +kubebuilder:printcolumn:name="DEP-INSTALLED",type="string",JSONPath=".status.installedDependencies"
+// +kubebuilder:printcolumn:name="DEP-FOUND",type="string",JSONPath=".status.foundDependencies",priority=1
+// +kubebuilder:printcolumn:name="DEP-INSTALLED",type="string",JSONPath=".status.installedDependencies",priority=1
+// +kubebuilder:printcolumn:name="HEALTH-CHANGED",type="date",JSONPath=".status.conditions[?(@.type=='Healthy')].lastTransitionTime",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,pkgrev}
 type ConfigurationRevision struct {
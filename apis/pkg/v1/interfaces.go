@@ -28,6 +28,15 @@ const (
 	// LabelParentPackage is used as key for the owner package label we add to the
 	// revisions. Its corresponding value should be the name of the owner package.
 	LabelParentPackage = "pkg.crossplane.io/package"
+
+	// AnnotationSnapshotSource, AnnotationSnapshotRevision, and
+	// AnnotationSnapshotDigest record the source, current revision, and
+	// current digest of a package when a snapshot of the package manager's
+	// state was taken. A package restored from a snapshot in restore mode
+	// uses the recorded revision until its source changes.
+	AnnotationSnapshotSource   = "pkg.crossplane.io/snapshot-source"
+	AnnotationSnapshotRevision = "pkg.crossplane.io/snapshot-revision"
+	AnnotationSnapshotDigest   = "pkg.crossplane.io/snapshot-digest"
 )
 
 // RevisionActivationPolicy indicates how a package should activate its
@@ -88,6 +97,9 @@ type Package interface {
 	GetCurrentIdentifier() string
 	SetCurrentIdentifier(r string)
 
+	GetCurrentDigest() string
+	SetCurrentDigest(d string)
+
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(*bool)
 }
@@ -202,6 +214,16 @@ func (p *Provider) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetCurrentDigest of this Provider.
+func (p *Provider) GetCurrentDigest() string {
+	return p.Status.CurrentDigest
+}
+
+// SetCurrentDigest of this Provider.
+func (p *Provider) SetCurrentDigest(d string) {
+	p.Status.CurrentDigest = d
+}
+
 // GetCondition of this Configuration.
 func (p *Configuration) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -310,6 +332,16 @@ func (p *Configuration) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetCurrentDigest of this Configuration.
+func (p *Configuration) GetCurrentDigest() string {
+	return p.Status.CurrentDigest
+}
+
+// SetCurrentDigest of this Configuration.
+func (p *Configuration) SetCurrentDigest(d string) {
+	p.Status.CurrentDigest = d
+}
+
 var _ PackageRevision = &ProviderRevision{}
 var _ PackageRevision = &ConfigurationRevision{}
 
@@ -352,6 +384,12 @@ type PackageRevision interface {
 	GetDependencyStatus() (found, installed, invalid int64)
 	SetDependencyStatus(found, installed, invalid int64)
 
+	GetSBOMDigest() string
+	SetSBOMDigest(d string)
+
+	GetControllerImage() (declared, effective string)
+	SetControllerImage(declared, effective string)
+
 	GetWebhookTLSSecretName() *string
 	SetWebhookTLSSecretName(n *string)
 }
@@ -448,6 +486,29 @@ func (p *ProviderRevision) SetDependencyStatus(found, installed, invalid int64)
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetSBOMDigest of this ProviderRevision.
+func (p *ProviderRevision) GetSBOMDigest() string {
+	return p.Status.SBOMDigest
+}
+
+// SetSBOMDigest of this ProviderRevision.
+func (p *ProviderRevision) SetSBOMDigest(d string) {
+	p.Status.SBOMDigest = d
+}
+
+// GetControllerImage returns the declared and effective controller image of
+// this ProviderRevision.
+func (p *ProviderRevision) GetControllerImage() (declared, effective string) {
+	return p.Status.DeclaredImage, p.Status.EffectiveImage
+}
+
+// SetControllerImage sets the declared and effective controller image of this
+// ProviderRevision.
+func (p *ProviderRevision) SetControllerImage(declared, effective string) {
+	p.Status.DeclaredImage = declared
+	p.Status.EffectiveImage = effective
+}
+
 // GetIgnoreCrossplaneConstraints of this ProviderRevision.
 func (p *ProviderRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -580,6 +641,29 @@ func (p *ConfigurationRevision) SetDependencyStatus(found, installed, invalid in
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetSBOMDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetSBOMDigest() string {
+	return p.Status.SBOMDigest
+}
+
+// SetSBOMDigest of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetSBOMDigest(d string) {
+	p.Status.SBOMDigest = d
+}
+
+// GetControllerImage returns the declared and effective controller image of
+// this ConfigurationRevision.
+func (p *ConfigurationRevision) GetControllerImage() (declared, effective string) {
+	return p.Status.DeclaredImage, p.Status.EffectiveImage
+}
+
+// SetControllerImage sets the declared and effective controller image of this
+// ConfigurationRevision.
+func (p *ConfigurationRevision) SetControllerImage(declared, effective string) {
+	p.Status.DeclaredImage = declared
+	p.Status.EffectiveImage = effective
+}
+
 // GetIgnoreCrossplaneConstraints of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -78,4 +78,10 @@ type PackageStatus struct {
 	// will cause the package manager to check that the current revision is
 	// correct for the given package source.
 	CurrentIdentifier string `json:"currentIdentifier,omitempty"`
+
+	// CurrentDigest is the digest of the package image the current revision
+	// was produced from. It is empty if the package manager didn't resolve a
+	// digest, for example because the package's pull policy is Never.
+	// +optional
+	CurrentDigest string `json:"currentDigest,omitempty"`
 }
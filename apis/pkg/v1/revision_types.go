@@ -100,6 +100,15 @@ type PackageRevisionStatus struct {
 	// installed.
 	ControllerRef ControllerReference `json:"controllerRef,omitempty"`
 
+	// DeclaredImage is the controller image declared by the package, if any.
+	// +optional
+	DeclaredImage string `json:"declaredImage,omitempty"`
+
+	// EffectiveImage is the controller image that is run, after any image
+	// override and digest pinning is applied.
+	// +optional
+	EffectiveImage string `json:"effectiveImage,omitempty"`
+
 	// References to objects owned by PackageRevision.
 	ObjectRefs []xpv1.TypedReference `json:"objectRefs,omitempty"`
 
@@ -112,6 +121,11 @@ type PackageRevisionStatus struct {
 	// controller needs these permissions to run. The RBAC manager is
 	// responsible for granting them.
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// SBOMDigest is the digest of the package's SBOM (software bill of
+	// materials) layer, if it has one.
+	// +optional
+	SBOMDigest string `json:"sbomDigest,omitempty"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
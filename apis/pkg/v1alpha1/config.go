@@ -21,6 +21,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// A WorkloadType is a kind of workload that runs a packaged controller.
+type WorkloadType string
+
+// Workload types.
+const (
+	WorkloadTypeDeployment WorkloadType = "Deployment"
+	WorkloadTypeDaemonSet  WorkloadType = "DaemonSet"
+)
+
 // ControllerConfigSpec specifies the configuration for a packaged controller.
 // Values provided will override package manager defaults. Labels and
 // annotations are passed to both the controller Deployment and ServiceAccount.
@@ -30,18 +39,31 @@ type ControllerConfigSpec struct {
 	Metadata *PodObjectMeta `json:"metadata,omitempty"`
 
 	// Number of desired pods. This is a pointer to distinguish between explicit
-	// zero and not specified. Defaults to 1.
-	// Note: If more than 1 replica is set and leader election is not enabled then
-	// controllers could conflict. Environment variable "LEADER_ELECTION" can be
-	// used to enable leader election process.
+	// zero and not specified. Defaults to 1. If more than 1 replica is set the
+	// --leader-election argument is passed to the controller, so that only one
+	// replica reconciles at a time. Ignored when WorkloadType is DaemonSet.
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
+	// WorkloadType is the kind of workload that runs the controller. A
+	// Deployment runs the number of pods specified by Replicas. A DaemonSet
+	// runs one pod on each node the controller may be scheduled to, and is
+	// intended for providers that manage node-local resources. Defaults to
+	// Deployment.
+	// +optional
+	// +kubebuilder:validation:Enum=Deployment;DaemonSet
+	WorkloadType *WorkloadType `json:"workloadType,omitempty"`
 	// Docker image name.
 	// More info: https://kubernetes.io/docs/concepts/containers/images
 	// This field is optional to allow higher level config management to default or override
 	// container images in workload controllers like Deployments and StatefulSets.
 	// +optional
 	Image *string `json:"image,omitempty"`
+	// ImageDigest pins the controller image to the supplied digest, for
+	// example sha256:0123... The image declared by the package, or overridden
+	// by Image, is pulled by this digest rather than by its tag.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	ImageDigest *string `json:"imageDigest,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node.
 	// Selector which must match a node's labels for the pod to be scheduled on that node.
 	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
@@ -96,6 +96,10 @@ type Dependency struct {
 	// Constraints is a valid semver range, which will be used to select a valid
 	// dependency version.
 	Constraints string `json:"constraints"`
+
+	// WaitForHealthy specifies that the dependent package should not become
+	// active until this dependency is healthy.
+	WaitForHealthy bool `json:"waitForHealthy,omitempty"`
 }
 
 // Identifier returns a dependency's source.
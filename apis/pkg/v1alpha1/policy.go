@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PackagePolicySpec specifies which packages may be installed.
+type PackagePolicySpec struct {
+	// AllowedSources are the sources from which packages may be installed. A
+	// source may be a registry (e.g. xpkg.upbound.io), an organization within
+	// a registry (e.g. xpkg.upbound.io/crossplane-contrib), or a repository
+	// (e.g. xpkg.upbound.io/crossplane-contrib/provider-aws). Packages that
+	// don't specify a registry are assumed to be from the default registry.
+	// +kubebuilder:validation:MinItems=1
+	AllowedSources []string `json:"allowedSources"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PackagePolicy restricts the sources from which Providers and
+// Configurations may be installed. When one or more PackagePolicies exist a
+// package may only be installed if its source is allowed by at least one of
+// them. All packages may be installed when no PackagePolicy exists.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster
+type PackagePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PackagePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PackagePolicyList contains a list of PackagePolicy.
+type PackagePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackagePolicy `json:"items"`
+}
@@ -56,7 +56,25 @@ var (
 	LockGroupVersionKind = SchemeGroupVersion.WithKind(LockKind)
 )
 
+// PackagePolicy type metadata.
+var (
+	PackagePolicyKind             = reflect.TypeOf(PackagePolicy{}).Name()
+	PackagePolicyGroupKind        = schema.GroupKind{Group: Group, Kind: PackagePolicyKind}.String()
+	PackagePolicyKindAPIVersion   = PackagePolicyKind + "." + SchemeGroupVersion.String()
+	PackagePolicyGroupVersionKind = SchemeGroupVersion.WithKind(PackagePolicyKind)
+)
+
+// RegistryConfig type metadata.
+var (
+	RegistryConfigKind             = reflect.TypeOf(RegistryConfig{}).Name()
+	RegistryConfigGroupKind        = schema.GroupKind{Group: Group, Kind: RegistryConfigKind}.String()
+	RegistryConfigKindAPIVersion   = RegistryConfigKind + "." + SchemeGroupVersion.String()
+	RegistryConfigGroupVersionKind = SchemeGroupVersion.WithKind(RegistryConfigKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ControllerConfig{}, &ControllerConfigList{})
 	SchemeBuilder.Register(&Lock{}, &LockList{})
+	SchemeBuilder.Register(&PackagePolicy{}, &PackagePolicyList{})
+	SchemeBuilder.Register(&RegistryConfig{}, &RegistryConfigList{})
 }
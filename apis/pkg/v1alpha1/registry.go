@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryConfigSpec specifies how packages are fetched from a registry.
+type RegistryConfigSpec struct {
+	// Registry to which this configuration applies, for example
+	// registry.example.org or registry.example.org:5000. Packages that don't
+	// specify a registry are fetched from the default registry.
+	// +kubebuilder:validation:MinLength=1
+	Registry string `json:"registry"`
+
+	// ProxyURL is the URL of an HTTP or HTTPS proxy through which the
+	// registry is accessed, for example http://proxy.example.org:3128. The
+	// proxy specified by Crossplane's HTTPS_PROXY environment variable, if
+	// any, is used when no proxy URL is specified.
+	// +optional
+	ProxyURL *string `json:"proxyURL,omitempty"`
+
+	// TLS configures how the registry is authenticated, and how Crossplane
+	// authenticates to the registry.
+	// +optional
+	TLS *RegistryTLS `json:"tls,omitempty"`
+}
+
+// RegistryTLS configures TLS connections to a registry.
+type RegistryTLS struct {
+	// CABundleSecretRef references a Secret in Crossplane's namespace. The PEM
+	// encoded certificates under its ca.crt key are trusted in addition to the
+	// system's certificate authorities, for example to trust a proxy that
+	// intercepts TLS connections.
+	// +optional
+	CABundleSecretRef *corev1.LocalObjectReference `json:"caBundleSecretRef,omitempty"`
+
+	// ClientCertificateSecretRef references a kubernetes.io/tls Secret in
+	// Crossplane's namespace. The certificate and key under its tls.crt and
+	// tls.key keys are presented to registries that require mutual TLS.
+	// +optional
+	ClientCertificateSecretRef *corev1.LocalObjectReference `json:"clientCertificateSecretRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A RegistryConfig configures how Providers and Configurations are fetched
+// from a registry, for example a registry that may only be accessed through a
+// proxy, or that requires a client certificate.
+// +kubebuilder:printcolumn:name="REGISTRY",type="string",JSONPath=".spec.registry"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster
+type RegistryConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RegistryConfigSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// RegistryConfigList contains a list of RegistryConfig.
+type RegistryConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RegistryConfig `json:"items"`
+}
@@ -97,11 +97,21 @@ func (in *ControllerConfigSpec) DeepCopyInto(out *ControllerConfigSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.WorkloadType != nil {
+		in, out := &in.WorkloadType, &out.WorkloadType
+		*out = new(WorkloadType)
+		**out = **in
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(string)
 		**out = **in
 	}
+	if in.ImageDigest != nil {
+		in, out := &in.ImageDigest, &out.ImageDigest
+		*out = new(string)
+		**out = **in
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -301,6 +311,84 @@ func (in *LockPackage) DeepCopy() *LockPackage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackagePolicy) DeepCopyInto(out *PackagePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackagePolicy.
+func (in *PackagePolicy) DeepCopy() *PackagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PackagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackagePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackagePolicyList) DeepCopyInto(out *PackagePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PackagePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackagePolicyList.
+func (in *PackagePolicyList) DeepCopy() *PackagePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PackagePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackagePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackagePolicySpec) DeepCopyInto(out *PackagePolicySpec) {
+	*out = *in
+	if in.AllowedSources != nil {
+		in, out := &in.AllowedSources, &out.AllowedSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackagePolicySpec.
+func (in *PackagePolicySpec) DeepCopy() *PackagePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackagePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodObjectMeta) DeepCopyInto(out *PodObjectMeta) {
 	*out = *in
@@ -329,3 +417,111 @@ func (in *PodObjectMeta) DeepCopy() *PodObjectMeta {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfig.
+func (in *RegistryConfig) DeepCopy() *RegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfigList) DeepCopyInto(out *RegistryConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RegistryConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfigList.
+func (in *RegistryConfigList) DeepCopy() *RegistryConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RegistryConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfigSpec) DeepCopyInto(out *RegistryConfigSpec) {
+	*out = *in
+	if in.ProxyURL != nil {
+		in, out := &in.ProxyURL, &out.ProxyURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RegistryTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfigSpec.
+func (in *RegistryConfigSpec) DeepCopy() *RegistryConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryTLS) DeepCopyInto(out *RegistryTLS) {
+	*out = *in
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ClientCertificateSecretRef != nil {
+		in, out := &in.ClientCertificateSecretRef, &out.ClientCertificateSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryTLS.
+func (in *RegistryTLS) DeepCopy() *RegistryTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryTLS)
+	in.DeepCopyInto(out)
+	return out
+}
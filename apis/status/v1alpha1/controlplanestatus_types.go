@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ControlPlaneStatusName is the name of the singleton ControlPlaneStatus.
+const ControlPlaneStatusName = "crossplane"
+
+// A PackageSummary summarizes the health of a kind of package.
+type PackageSummary struct {
+	// Total number of packages of this kind.
+	Total int64 `json:"total"`
+
+	// Healthy packages of this kind.
+	Healthy int64 `json:"healthy"`
+
+	// Unhealthy packages of this kind.
+	Unhealthy int64 `json:"unhealthy"`
+}
+
+// A DefinitionSummary summarizes the state of CompositeResourceDefinitions.
+type DefinitionSummary struct {
+	// Total number of CompositeResourceDefinitions.
+	Total int64 `json:"total"`
+
+	// Established CompositeResourceDefinitions, i.e. those whose composite
+	// resource CRD is being served.
+	Established int64 `json:"established"`
+}
+
+// A ControlPlaneSummary summarizes the health of a Crossplane control plane.
+type ControlPlaneSummary struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// Providers summarizes the health of installed providers.
+	Providers PackageSummary `json:"providers,omitempty"`
+
+	// Configurations summarizes the health of installed configurations.
+	Configurations PackageSummary `json:"configurations,omitempty"`
+
+	// CompositeResourceDefinitions summarizes the state of
+	// CompositeResourceDefinitions.
+	CompositeResourceDefinitions DefinitionSummary `json:"compositeResourceDefinitions,omitempty"`
+
+	// PendingRevisions is the number of active package revisions that are not
+	// yet healthy.
+	PendingRevisions int64 `json:"pendingRevisions,omitempty"`
+
+	// WebhookCertificateExpiry is when the TLS certificate that Crossplane's
+	// webhooks are served with expires.
+	// +optional
+	WebhookCertificateExpiry *metav1.Time `json:"webhookCertificateExpiry,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ControlPlaneStatus summarizes the health of a Crossplane control plane.
+// Crossplane maintains a single ControlPlaneStatus named 'crossplane', giving
+// tooling one object to watch in order to determine the health of Crossplane.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="PROVIDERS",type="integer",JSONPath=".status.providers.healthy"
+// +kubebuilder:printcolumn:name="CONFIGURATIONS",type="integer",JSONPath=".status.configurations.healthy"
+// +kubebuilder:printcolumn:name="PENDING",type="integer",JSONPath=".status.pendingRevisions"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane}
+type ControlPlaneStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ControlPlaneSummary `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ControlPlaneStatusList contains a list of ControlPlaneStatus.
+type ControlPlaneStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ControlPlaneStatus `json:"items"`
+}
+
+// GetCondition of this ControlPlaneStatus.
+func (s *ControlPlaneStatus) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return s.Status.GetCondition(ct)
+}
+
+// SetConditions of this ControlPlaneStatus.
+func (s *ControlPlaneStatus) SetConditions(c ...xpv1.Condition) {
+	s.Status.SetConditions(c...)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/alecthomas/kong"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/apiextensions"
+	"github.com/crossplane/crossplane/internal/describe"
+)
+
+const (
+	errRESTMapper         = "cannot create REST mapper"
+	errFmtResolveType     = "cannot resolve type %q"
+	errMarshalComposition = "cannot marshal composition"
+)
+
+// describeCompositionCmd prints the effective Composition of a composite
+// resource or claim.
+type describeCompositionCmd struct {
+	Type      string `arg:"" help:"Type of the composite resource or claim, e.g. xpostgresqlinstances.example.org."`
+	Name      string `arg:"" help:"Name of the composite resource or claim."`
+	Namespace string `short:"n" help:"Namespace of the claim. Omit to describe a composite resource."`
+}
+
+// Run runs the describe-composition cmd.
+func (c *describeCompositionCmd) Run(k *kong.Context, logger logging.Logger) error {
+	ctx := context.Background()
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	s := runtime.NewScheme()
+	if err := apiextensions.AddToScheme(s); err != nil {
+		return errors.Wrap(err, errAddToScheme)
+	}
+	rm, err := apiutil.NewDynamicRESTMapper(cfg)
+	if err != nil {
+		return errors.Wrap(err, errRESTMapper)
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s, Mapper: rm})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+
+	// Resolve the type the same way kubectl does, i.e. prefer
+	// resource.version.group, then fall back to resource.group.
+	gvr, gr := schema.ParseResourceArg(c.Type)
+	var gvk schema.GroupVersionKind
+	if gvr != nil {
+		gvk, err = rm.KindFor(*gvr)
+	}
+	if gvr == nil || err != nil {
+		gvk, err = rm.KindFor(gr.WithVersion(""))
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtResolveType, c.Type)
+	}
+	logger.Debug("Resolved type", "type", c.Type, "gvk", gvk)
+
+	cr, err := describe.Composite(ctx, kube, gvk, c.Name, c.Namespace)
+	if err != nil {
+		return err
+	}
+	logger.Debug("Found composite resource", "name", cr.GetName())
+
+	comp, err := describe.EffectiveComposition(ctx, kube, cr)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(comp)
+	if err != nil {
+		return errors.Wrap(err, errMarshalComposition)
+	}
+	_, err = k.Stdout.Write(b)
+	return err
+}
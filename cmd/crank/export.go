@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/alecthomas/kong"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/export"
+)
+
+// exportCmd exports the packages and APIs installed in a control plane.
+type exportCmd struct {
+	Snapshot bool `help:"Export a snapshot of the package manager's state - its Lock, package revisions, and packages - instead, to be restored in restore mode."`
+}
+
+// Run runs the export cmd.
+func (c *exportCmd) Run(k *kong.Context, logger logging.Logger) error {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	kube, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+
+	list := export.Objects
+	if c.Snapshot {
+		list = export.Snapshot
+	}
+	objs, err := list(context.Background(), kube)
+	if err != nil {
+		return err
+	}
+	logger.Debug("Exporting resources", "count", len(objs))
+
+	return export.Write(k.Stdout, objs)
+}
@@ -60,6 +60,14 @@ var cli struct {
 	Install installCmd `cmd:"" help:"Install Crossplane packages."`
 	Update  updateCmd  `cmd:"" help:"Update Crossplane packages."`
 	Push    pushCmd    `cmd:"" help:"Push Crossplane packages."`
+
+	Validate validateCmd `cmd:"" help:"Validate XRDs, Compositions, and claims offline."`
+	Top      topCmd      `cmd:"" help:"Summarize the load on a Crossplane control plane."`
+	Export   exportCmd   `cmd:"" help:"Export installed packages and APIs as manifests."`
+
+	DescribeComposition describeCompositionCmd `cmd:"" name:"describe-composition" help:"Print the effective Composition of a composite resource or claim."`
+
+	Xpkg xpkgCmd `cmd:"" help:"Inspect and modify Crossplane packages."`
 }
 
 func main() {
@@ -69,13 +77,19 @@ func main() {
 	pushChild := &pushChild{
 		fs: afero.NewOsFs(),
 	}
+	validateChild := &validateChild{
+		fs: afero.NewOsFs(),
+	}
+	xpkgChild := &xpkgChild{
+		fs: afero.NewOsFs(),
+	}
 	logger := logging.NewNopLogger()
 	ctx := kong.Parse(&cli,
 		kong.Name("kubectl crossplane"),
 		kong.Description("A command line tool for interacting with Crossplane."),
 		// Binding a variable to kong context makes it available to all commands
 		// at runtime.
-		kong.Bind(buildChild, pushChild),
+		kong.Bind(buildChild, pushChild, validateChild, xpkgChild),
 		kong.BindTo(logger, (*logging.Logger)(nil)),
 		kong.UsageOnError())
 	err := ctx.Run()
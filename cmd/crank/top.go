@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/alecthomas/kong"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/top"
+)
+
+const (
+	errListXRDs       = "cannot list CompositeResourceDefinitions"
+	errFmtCountKind   = "cannot count %s"
+	errListPods       = "cannot list Crossplane pods"
+	errScrapeMetrics  = "cannot scrape Crossplane metrics"
+	errPodMetrics     = "cannot get pod metrics"
+	errAddToScheme    = "cannot add CompositeResourceDefinitions to scheme"
+	errCrossplanePods = "no Crossplane pods found"
+)
+
+// topCmd summarizes the load on a Crossplane control plane.
+type topCmd struct {
+	Namespace string        `short:"n" default:"crossplane-system" help:"Namespace Crossplane and its providers run in."`
+	Interval  time.Duration `short:"i" default:"10s" help:"Interval over which to measure reconcile rates."`
+
+	Selector    string `default:"app=crossplane" help:"Label selector of Crossplane pods."`
+	MetricsPort string `default:"8080" help:"Port on which Crossplane pods serve metrics."`
+}
+
+// Run runs the top cmd.
+func (c *topCmd) Run(k *kong.Context, logger logging.Logger) error { //nolint:gocyclo
+	ctx := context.Background()
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		return errors.Wrap(err, errAddToScheme)
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+
+	snap := top.Snapshot{}
+
+	if snap.Definitions, err = definitionLoad(ctx, kube); err != nil {
+		return err
+	}
+
+	pods, err := cs.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: c.Selector})
+	if err != nil {
+		return errors.Wrap(err, errListPods)
+	}
+	if len(pods.Items) == 0 {
+		return errors.New(errCrossplanePods)
+	}
+	scrape := func() (map[string]top.Reconciles, error) {
+		total := map[string]top.Reconciles{}
+		for _, p := range pods.Items {
+			b, err := cs.CoreV1().Pods(c.Namespace).ProxyGet("http", p.GetName(), c.MetricsPort, "metrics", nil).DoRaw(ctx)
+			if err != nil {
+				return nil, errors.Wrap(err, errScrapeMetrics)
+			}
+			r, err := top.ParseReconciles(bytes.NewReader(b))
+			if err != nil {
+				return nil, err
+			}
+			for controller, rc := range r {
+				t := total[controller]
+				t.Total += rc.Total
+				t.Errors += rc.Errors
+				total[controller] = t
+			}
+		}
+		return total, nil
+	}
+	if before, err := scrape(); err != nil {
+		// Metrics are optional; they must be enabled in the Helm chart.
+		logger.Debug("Cannot scrape metrics; skipping reconcile rates", "error", err)
+	} else {
+		logger.Debug("Measuring reconcile rates", "interval", c.Interval)
+		time.Sleep(c.Interval)
+		after, err := scrape()
+		if err != nil {
+			return err
+		}
+		snap.Controllers = top.Rates(before, after, c.Interval)
+	}
+
+	if b, err := cs.Discovery().RESTClient().Get().AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", c.Namespace, "pods").DoRaw(ctx); err != nil {
+		// The metrics API is optional; it's served by metrics-server.
+		logger.Debug(errPodMetrics, "error", err)
+	} else if snap.Pods, err = top.ParsePodMetrics(b); err != nil {
+		return err
+	}
+
+	return snap.Write(k.Stdout)
+}
+
+// definitionLoad counts the composite resources and claims of each
+// CompositeResourceDefinition.
+func definitionLoad(ctx context.Context, kube client.Client) ([]top.DefinitionLoad, error) {
+	xrds := &v1.CompositeResourceDefinitionList{}
+	if err := kube.List(ctx, xrds); err != nil {
+		return nil, errors.Wrap(err, errListXRDs)
+	}
+
+	out := make([]top.DefinitionLoad, 0, len(xrds.Items))
+	for _, xrd := range xrds.Items {
+		dl := top.DefinitionLoad{Name: xrd.GetName()}
+
+		l := &metav1.PartialObjectMetadataList{}
+		l.SetGroupVersionKind(xrd.GetCompositeGroupVersionKind().GroupVersion().WithKind(xrd.Spec.Names.Kind + "List"))
+		err := kube.List(ctx, l)
+		if kmeta.IsNoMatchError(err) {
+			// The XRD hasn't been established yet.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCountKind, xrd.Spec.Names.Kind)
+		}
+		dl.Composites = len(l.Items)
+
+		if xrd.OffersClaim() {
+			l := &metav1.PartialObjectMetadataList{}
+			l.SetGroupVersionKind(xrd.GetClaimGroupVersionKind().GroupVersion().WithKind(xrd.Spec.ClaimNames.Kind + "List"))
+			if err := kube.List(ctx, l); err != nil {
+				return nil, errors.Wrapf(err, errFmtCountKind, xrd.Spec.ClaimNames.Kind)
+			}
+			dl.Claims = len(l.Items)
+		}
+
+		out = append(out, dl)
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/validate"
+)
+
+const (
+	errReadPath      = "failed to read path"
+	errFmtParseFile  = "failed to parse file %s"
+	errNewValidator  = "failed to create validator"
+	errFmtValidation = "found %d validation errors"
+)
+
+// validateCmd validates XRDs, Compositions, and example claims and composite
+// resources offline.
+type validateCmd struct {
+	Paths []string `arg:"" type:"path" help:"Files or directories containing XRDs, Compositions, example claims and composite resources, and optionally CRDs of composed resources."`
+}
+
+// Run runs the validate cmd.
+func (c *validateCmd) Run(k *kong.Context, child *validateChild, logger logging.Logger) error {
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, p := range c.Paths {
+		o, err := readObjects(child.fs, p)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, o...)
+	}
+	logger.Debug("Read objects", "count", len(objs))
+
+	v, err := validate.New(objs)
+	if err != nil {
+		return errors.Wrap(err, errNewValidator)
+	}
+
+	errs := v.Validate()
+	for _, err := range errs {
+		fmt.Fprintln(k.Stderr, err)
+	}
+	// Likely authoring mistakes are reported, but aren't validation errors.
+	for _, w := range v.Lint() {
+		fmt.Fprintln(k.Stderr, "warning: "+w)
+	}
+	if len(errs) > 0 {
+		return errors.Errorf(errFmtValidation, len(errs))
+	}
+	return nil
+}
+
+// readObjects reads all objects from the supplied path. Directories are walked
+// recursively, and only YAML and JSON files are read.
+func readObjects(fs afero.Fs, path string) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, 0)
+	err := afero.Walk(fs, path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		f, err := fs.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // Only open for reading.
+		o, err := validate.Parse(f)
+		if err != nil {
+			return errors.Wrapf(err, errFmtParseFile, p)
+		}
+		objs = append(objs, o...)
+		return nil
+	})
+	return objs, errors.Wrap(err, errReadPath)
+}
+
+type validateChild struct {
+	fs afero.Fs
+}
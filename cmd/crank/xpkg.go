@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errParseReference  = "failed to parse package reference"
+	errFetchImage      = "failed to fetch package image"
+	errReadPackageFile = "failed to read package file"
+	errExtractPackage  = "failed to extract package"
+	errSplitStream     = "failed to split package YAML stream"
+	errWriteContents   = "failed to write package contents"
+	errReadExamples    = "failed to read examples"
+	errBuildExamples   = "failed to build examples layer"
+	errAppendExamples  = "failed to append examples layer"
+	errWritePackage    = "failed to write package file"
+)
+
+// xpkgCmd inspects and modifies packages.
+type xpkgCmd struct {
+	Extract        xpkgExtractCmd        `cmd:"" help:"Extract the contents of a package to a directory."`
+	AppendExamples xpkgAppendExamplesCmd `cmd:"" name:"append-examples" help:"Append examples to a package file, replacing any existing examples."`
+}
+
+// xpkgExtractCmd extracts the contents of a package.
+type xpkgExtractCmd struct {
+	Package  string `arg:"" help:"Package to extract. Either an OCI image reference, or the path to a package file if --from-file is set."`
+	FromFile bool   `name:"from-file" help:"Read the package from a local package file rather than a registry."`
+	Output   string `short:"o" help:"Directory to which the package contents are written." default:"."`
+}
+
+// Run runs the extract cmd. The package metadata is written to crossplane.yaml,
+// each other object to <kind>/<name>.yaml, and examples to the examples
+// directory, all within the output directory.
+func (c *xpkgExtractCmd) Run(child *xpkgChild, logger logging.Logger) error {
+	logger = logger.WithValues("package", c.Package)
+
+	img, err := c.image()
+	if err != nil {
+		logger.Debug("Failed to get package image", "error", err)
+		return err
+	}
+
+	contents, err := xpkg.Extract(img)
+	if err != nil {
+		logger.Debug(errExtractPackage, "error", err)
+		return errors.Wrap(err, errExtractPackage)
+	}
+
+	files, err := packageFiles(contents)
+	if err != nil {
+		logger.Debug(errSplitStream, "error", err)
+		return errors.Wrap(err, errSplitStream)
+	}
+	if err := writeFiles(child.fs, c.Output, files); err != nil {
+		logger.Debug(errWriteContents, "error", err)
+		return errors.Wrap(err, errWriteContents)
+	}
+	logger.Debug("Successfully extracted package", "output", c.Output)
+	return nil
+}
+
+// image returns the package image, either from a registry or a package file.
+func (c *xpkgExtractCmd) image() (ociv1.Image, error) {
+	if c.FromFile {
+		img, err := tarball.ImageFromPath(c.Package, nil)
+		return img, errors.Wrap(err, errReadPackageFile)
+	}
+	ref, err := name.ParseReference(c.Package)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseReference)
+	}
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	return img, errors.Wrap(err, errFetchImage)
+}
+
+// xpkgAppendExamplesCmd appends examples to a package file.
+type xpkgAppendExamplesCmd struct {
+	Package      string `short:"f" help:"Path to package. If not specified and only one package exists in current directory it will be used."`
+	ExamplesRoot string `short:"e" name:"examples-root" help:"Path to a directory of examples. All YAML files within it are added to the package." default:"./examples"`
+	Output       string `short:"o" help:"Path to which the package is written. Overwrites the package if not specified."`
+}
+
+// Run runs the append-examples cmd.
+func (c *xpkgAppendExamplesCmd) Run(child *xpkgChild, logger logging.Logger) error {
+	if c.Package == "" {
+		logger.Debug("Trying to find package in current directory")
+		wd, err := os.Getwd()
+		if err != nil {
+			return errors.Wrap(err, errGetwd)
+		}
+		path, err := xpkg.FindXpkgInDir(child.fs, wd)
+		if err != nil {
+			return errors.Wrap(err, errFindPackageinWd)
+		}
+		c.Package = path
+		logger.Debug("Found package in directory", "path", path)
+	}
+	if c.Output == "" {
+		c.Output = c.Package
+	}
+	logger = logger.WithValues("package", c.Package)
+
+	examples, err := readExamples(child.fs, c.ExamplesRoot)
+	if err != nil {
+		logger.Debug(errReadExamples, "error", err)
+		return errors.Wrap(err, errReadExamples)
+	}
+	layer, err := xpkg.ExamplesLayer(examples)
+	if err != nil {
+		logger.Debug(errBuildExamples, "error", err)
+		return errors.Wrap(err, errBuildExamples)
+	}
+
+	img, err := tarball.ImageFromPath(c.Package, nil)
+	if err != nil {
+		logger.Debug(errReadPackageFile, "error", err)
+		return errors.Wrap(err, errReadPackageFile)
+	}
+	img, err = xpkg.WithExamples(img, layer)
+	if err != nil {
+		logger.Debug(errAppendExamples, "error", err)
+		return errors.Wrap(err, errAppendExamples)
+	}
+
+	// The package is read lazily, so we write to a temporary file in the same
+	// directory and rename it in case we're overwriting the package.
+	f, err := afero.TempFile(child.fs, filepath.Dir(c.Output), filepath.Base(c.Output))
+	if err != nil {
+		return errors.Wrap(err, errWritePackage)
+	}
+	defer func() { _ = child.fs.Remove(f.Name()) }()
+	if err := tarball.Write(nil, img, f); err != nil {
+		_ = f.Close()
+		logger.Debug(errWritePackage, "error", err)
+		return errors.Wrap(err, errWritePackage)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, errWritePackage)
+	}
+	if err := child.fs.Rename(f.Name(), c.Output); err != nil {
+		return errors.Wrap(err, errWritePackage)
+	}
+	logger.Debug("Successfully appended examples", "output", c.Output)
+	return nil
+}
+
+type xpkgChild struct {
+	fs afero.Fs
+}
+
+// packageFiles returns the files that represent the supplied package contents,
+// keyed by their slash separated path.
+func packageFiles(c *xpkg.Contents) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for p, b := range c.Examples {
+		files["examples/"+p] = b
+	}
+
+	r := kyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(c.Stream)))
+	for i := 0; ; i++ {
+		doc, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// The YAML reader includes the separator that terminates an empty
+		// document in the document that follows it.
+		for bytes.HasPrefix(doc, []byte("---")) {
+			_, doc, _ = bytes.Cut(doc, []byte("\n"))
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		o := &metav1.PartialObjectMetadata{}
+		if err := yaml.Unmarshal(doc, o); err != nil {
+			return nil, err
+		}
+		files[objectPath(o, i)] = doc
+	}
+}
+
+// objectPath returns the path to which the supplied object is written.
+func objectPath(o *metav1.PartialObjectMetadata, i int) string {
+	gv, _ := schema.ParseGroupVersion(o.APIVersion)
+	if gv.Group == pkgmetav1.Group {
+		return xpkg.MetaFile
+	}
+	n := o.GetName()
+	if n == "" || strings.ContainsAny(n, `/\`) || n == "." || n == ".." {
+		n = fmt.Sprintf("object-%d", i)
+	}
+	k := strings.ToLower(o.Kind)
+	if k == "" {
+		k = "unknown"
+	}
+	return k + "/" + n + ".yaml"
+}
+
+// writeFiles writes the supplied files, keyed by their slash separated path,
+// to the supplied directory.
+func writeFiles(fs afero.Fs, dir string, files map[string][]byte) error {
+	for p, b := range files {
+		path := filepath.Join(dir, filepath.FromSlash(p))
+		if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, path, b, xpkg.StreamFileMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readExamples returns the YAML files within the supplied directory, keyed by
+// their slash separated path relative to the directory.
+func readExamples(fs afero.Fs, dir string) (map[string][]byte, error) {
+	examples := map[string][]byte{}
+	err := afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		examples[filepath.ToSlash(rel)] = b
+		return nil
+	})
+	return examples, err
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestPackageFiles(t *testing.T) {
+	meta := "apiVersion: meta.pkg.crossplane.io/v1\nkind: Provider\nmetadata:\n  name: provider-aws\n"
+	crd := "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: buckets.aws.crossplane.io\n"
+	unnamed := "apiVersion: example.org/v1\nkind: Thing\n"
+
+	c := &xpkg.Contents{
+		Stream:   []byte(meta + "---\n" + crd + "---\n---\n" + unnamed),
+		Examples: map[string][]byte{"s3/bucket.yaml": []byte("bucket")},
+	}
+	want := map[string][]byte{
+		"crossplane.yaml": []byte(meta),
+		"customresourcedefinition/buckets.aws.crossplane.io.yaml": []byte(crd),
+		"thing/object-2.yaml":     []byte(unnamed),
+		"examples/s3/bucket.yaml": []byte("bucket"),
+	}
+
+	got, err := packageFiles(c)
+	if err != nil {
+		t.Fatalf("packageFiles(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("packageFiles(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReadExamples(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "examples/s3/bucket.yaml", []byte("bucket"), 0o644)
+	_ = afero.WriteFile(fs, "examples/rds.yml", []byte("rds"), 0o644)
+	_ = afero.WriteFile(fs, "examples/README.md", []byte("readme"), 0o644)
+
+	want := map[string][]byte{
+		"s3/bucket.yaml": []byte("bucket"),
+		"rds.yml":        []byte("rds"),
+	}
+
+	got, err := readExamples(fs, "examples")
+	if err != nil {
+		t.Fatalf("readExamples(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("readExamples(...): -want, +got:\n%s", diff)
+	}
+}
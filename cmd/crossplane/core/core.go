@@ -17,14 +17,19 @@ limitations under the License.
 package core
 
 import (
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -32,11 +37,20 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
-	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/catalog"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/controller/status"
+	"github.com/crossplane/crossplane/internal/denylist"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/initializer"
+	"github.com/crossplane/crossplane/internal/profile"
+	"github.com/crossplane/crossplane/internal/webhook/claim"
+	"github.com/crossplane/crossplane/internal/webhook/composition"
+	"github.com/crossplane/crossplane/internal/webhook/definition"
+	pkgwebhook "github.com/crossplane/crossplane/internal/webhook/pkg"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -46,10 +60,44 @@ type Command struct {
 	Init  initCommand  `cmd:"" help:"Make cluster ready for Crossplane controllers."`
 }
 
+// Available orphaned composed resource policies.
+const (
+	OrphanPolicyReport = string(apiextensionscontroller.OrphanPolicyReport)
+	OrphanPolicyDelete = string(apiextensionscontroller.OrphanPolicyDelete)
+)
+
+// Available Composition update policies.
+const (
+	CompositionUpdatePolicyEnforce = string(composition.UpdatePolicyEnforce)
+	CompositionUpdatePolicyWarn    = string(composition.UpdatePolicyWarn)
+)
+
 // KongVars represent the kong variables associated with the CLI parser
-// required for the Registry default variable interpolation.
+// required for the Registry default variable, and orphan policy, Composition
+// update policy, and webhook TLS issuer kind enum interpolation.
 var KongVars = kong.Vars{
-	"default_registry": name.DefaultRegistry,
+	"default_registry":          name.DefaultRegistry,
+	"orphan_policy_default_var": OrphanPolicyReport,
+	"orphan_policy_enum_var": strings.Join(
+		[]string{
+			OrphanPolicyReport,
+			OrphanPolicyDelete,
+		},
+		", "),
+	"composition_update_policy_default_var": CompositionUpdatePolicyEnforce,
+	"composition_update_policy_enum_var": strings.Join(
+		[]string{
+			CompositionUpdatePolicyEnforce,
+			CompositionUpdatePolicyWarn,
+		},
+		", "),
+	"issuer_kind_default_var": initializer.IssuerKindIssuer,
+	"issuer_kind_enum_var": strings.Join(
+		[]string{
+			initializer.IssuerKindIssuer,
+			initializer.IssuerKindClusterIssuer,
+		},
+		", "),
 }
 
 // Run is the no-op method required for kong call tree
@@ -61,6 +109,7 @@ func (c *Command) Run() error {
 
 type startCommand struct {
 	Namespace            string `short:"n" help:"Namespace used to unpack and run packages." default:"crossplane-system" env:"POD_NAMESPACE"`
+	ServiceAccount       string `help:"Name of the Crossplane Service Account." default:"crossplane" env:"POD_SERVICE_ACCOUNT"`
 	CacheDir             string `short:"c" help:"Directory used for caching package images." default:"/cache" env:"CACHE_DIR"`
 	LeaderElection       bool   `short:"l" help:"Use leader election for the controller manager." default:"false" env:"LEADER_ELECTION"`
 	Registry             string `short:"r" help:"Default registry used to fetch packages when not specified in tag." default:"${default_registry}" env:"REGISTRY"`
@@ -72,6 +121,41 @@ type startCommand struct {
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
 
+	ProviderNodeSelector     map[string]string `help:"Node selector of every provider Pod, unless overridden by the provider's ControllerConfig." placeholder:"KEY=VALUE;..."`
+	ProviderTolerations      []string          `help:"Tolerations of every provider Pod, in the form key[=value][:effect], unless overridden by the provider's ControllerConfig." placeholder:"TOLERATION,..."`
+	ProviderRuntimeClassName string            `help:"RuntimeClass of every provider Pod, unless overridden by the provider's ControllerConfig."`
+
+	UncachedSecretTypes []string `help:"Types of Secret that Crossplane never reads, and that are therefore excluded from its cache to reduce memory usage." default:"helm.sh/release.v1,kubernetes.io/service-account-token"`
+
+	OrphanPolicy        string        `help:"What to do with composed resources whose composite resource no longer exists." default:"${orphan_policy_default_var}" enum:"${orphan_policy_enum_var}"`
+	OrphanCheckInterval time.Duration `help:"How often composed resources will be checked to determine whether their composite resource no longer exists. Orphaned composed resources are only deleted once they have been orphaned for at least this long." default:"1h"`
+
+	MaxConcurrentComposedApplies int `help:"The maximum number of composed resources that will be applied concurrently while reconciling a composite resource." default:"5"`
+
+	MaxComposedResources    int `help:"The maximum number of composed resources a composite resource may compose. Crossplane refuses to compose resources for a composite resource whose Composition exceeds this limit. There is no limit if this is zero." default:"0"`
+	MaxComposedResourceSize int `help:"The maximum size in bytes of a rendered composed resource. Crossplane refuses to compose resources for a composite resource if any of its composed resources would exceed this limit. There is no limit if this is zero." default:"0"`
+
+	CompositeDeletionTimeout time.Duration `help:"How long a composite resource deleted in the foreground waits for its composed resources to be deleted before orphaning them, leaving them behind. Composed resources are never orphaned if this is zero." default:"0s"`
+
+	RestoreMode bool `help:"Re-bind claims and composite resources restored from a backup to their existing composite and composed resources, rather than creating duplicates. Enable while restoring, e.g. with Velero."`
+
+	RecordComposedDiffs bool `help:"Record an event describing how each composed resource will change whenever it is updated. Values that may be sensitive are redacted. Useful to debug composed resources that are updated on every reconcile."`
+
+	RecordReconcileSummaries bool `help:"Log a unique ID with each message logged while reconciling a composite resource, and record the ID and why the composite resource was requeued in its status.lastReconcile field. Useful to debug composite resources that never become ready."`
+
+	EnableCompositionServiceAccounts bool `help:"Apply composed resources as the ServiceAccount a Composition names in its spec.serviceAccountName, rather than as Crossplane, so that each Composition may only compose what its ServiceAccount is permitted to."`
+
+	DenyComposedKinds []string `help:"Kinds of resource that Compositions may not compose, in the form [NAMESPACE/]KIND[.GROUP]. A KIND of * denies every kind in the group. For example ClusterRole.rbac.authorization.k8s.io or kube-system/Secret. Compositions that compose a denied kind are rejected by the Composition webhook, and composite resources will not compose denied kinds." placeholder:"KIND,..."`
+
+	CompositionUpdatePolicy string `help:"Whether to reject (Enforce) or warn about (Warn) Composition updates that could break existing composite resources. Requires webhooks to be enabled." default:"${composition_update_policy_default_var}" enum:"${composition_update_policy_enum_var}"`
+	LintCompositions        bool   `help:"Warn clients that create or update Compositions about likely authoring mistakes, such as patches that overwrite each other or that patch from fields the composite resource's schema doesn't define. Requires webhooks to be enabled."`
+
+	CatalogAddress string `help:"Address at which to serve a catalog of the claims offered by CompositeResourceDefinitions as JSON, for example to developer portals. The catalog is not served if unset." placeholder:":8090"`
+
+	ProfileAddress  string        `help:"Address at which to serve Go runtime profiles (pprof) at /debug/pprof/. Profiles are not served if unset." placeholder:":6060"`
+	ProfileDir      string        `help:"Directory, for example a mounted volume, to which a bundle of CPU, heap, allocation, and goroutine profiles is written when Crossplane receives SIGUSR1, or a POST request to /debug/capture at the profile address. Bundles are not captured if unset." placeholder:"/profiles"`
+	ProfileDuration time.Duration `help:"How long to capture a CPU profile for when capturing a bundle of profiles." default:"30s"`
+
 	EnableCompositionRevisions bool `group:"Alpha Features:" help:"Enable support for CompositionRevisions."`
 	EnableExternalSecretStores bool `group:"Alpha Features:" help:"Enable support for ExternalSecretStores."`
 }
@@ -87,6 +171,14 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Scheme:     s,
 		SyncPeriod: &c.SyncInterval,
 
+		// Crossplane reads Secrets, for example to propagate connection
+		// details, which causes every Secret in the cluster to be cached.
+		// Secrets of types we know we'll never read, like Helm releases,
+		// can be large and numerous, so we exclude them from the cache.
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: secretSelectors(c.UncachedSecretTypes),
+		}),
+
 		// controller-runtime uses both ConfigMaps and Leases for leader
 		// election by default. Leases expire after 15 seconds, with a
 		// 10 second renewal deadline. We've observed leader loss due to
@@ -113,6 +205,22 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		feats.Enable(features.EnableAlphaExternalSecretStores)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaExternalSecretStores)
 	}
+	if c.RestoreMode {
+		feats.Enable(features.RestoreMode)
+		log.Info("Restore mode enabled; composite resources and claims will adopt restored resources, and packages will use their snapshot revisions")
+	}
+	if c.RecordComposedDiffs {
+		feats.Enable(features.RecordComposedResourceDiffs)
+		log.Info("Composed resource diffs will be recorded as events")
+	}
+	if c.RecordReconcileSummaries {
+		feats.Enable(features.RecordReconcileSummaries)
+		log.Info("Composite resource reconcile summaries will be recorded")
+	}
+	if c.EnableCompositionServiceAccounts {
+		feats.Enable(features.CompositionServiceAccounts)
+		log.Info("Composed resources will be applied as their Composition's ServiceAccount")
+	}
 
 	o := controller.Options{
 		Logger:                  log,
@@ -122,10 +230,51 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Features:                feats,
 	}
 
-	if err := apiextensions.Setup(mgr, o); err != nil {
+	denied, err := denylist.Parse(c.DenyComposedKinds)
+	if err != nil {
+		return errors.Wrap(err, "Cannot parse denied composed kinds")
+	}
+
+	ao := apiextensionscontroller.Options{
+		Options:                 o,
+		OrphanPolicy:            apiextensionscontroller.OrphanPolicy(c.OrphanPolicy),
+		OrphanCheckInterval:     c.OrphanCheckInterval,
+		MaxConcurrentApplies:    c.MaxConcurrentComposedApplies,
+		MaxComposedResources:    c.MaxComposedResources,
+		MaxComposedResourceSize: c.MaxComposedResourceSize,
+		DeletionTimeout:         c.CompositeDeletionTimeout,
+		DeniedComposedKinds:     denied,
+		Namespace:               c.Namespace,
+	}
+
+	if err := apiextensions.Setup(mgr, ao); err != nil {
 		return errors.Wrap(err, "Cannot setup API extension controllers")
 	}
 
+	if c.CatalogAddress != "" {
+		if err := mgr.Add(catalog.NewServer(catalog.New(mgr.GetClient()), c.CatalogAddress, log.WithValues("component", "catalog"))); err != nil {
+			return errors.Wrap(err, "Cannot add claim catalog server to manager")
+		}
+	}
+
+	var capturer *profile.Capturer
+	if c.ProfileDir != "" {
+		capturer = profile.NewCapturer(c.ProfileDir, c.ProfileDuration, log.WithValues("component", "profile"))
+		if err := mgr.Add(capturer); err != nil {
+			return errors.Wrap(err, "Cannot add profile capturer to manager")
+		}
+	}
+	if c.ProfileAddress != "" {
+		if err := mgr.Add(profile.NewServer(c.ProfileAddress, capturer, log.WithValues("component", "profile"))); err != nil {
+			return errors.Wrap(err, "Cannot add profile server to manager")
+		}
+	}
+
+	tols, err := parseTolerations(c.ProviderTolerations)
+	if err != nil {
+		return errors.Wrap(err, "Cannot parse provider tolerations")
+	}
+
 	po := pkgcontroller.Options{
 		Options:              o,
 		Cache:                xpkg.NewFsPackageCache(c.CacheDir, afero.NewOsFs()),
@@ -133,6 +282,13 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		DefaultRegistry:      c.Registry,
 		Features:             feats,
 		WebhookTLSSecretName: c.WebhookTLSSecretName,
+		DeploymentDefaults: pkgcontroller.DeploymentDefaults{
+			NodeSelector: c.ProviderNodeSelector,
+			Tolerations:  tols,
+		},
+	}
+	if c.ProviderRuntimeClassName != "" {
+		po.DeploymentDefaults.RuntimeClassName = &c.ProviderRuntimeClassName
 	}
 
 	if c.CABundlePath != "" {
@@ -140,13 +296,25 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		if err != nil {
 			return errors.Wrap(err, "Cannot parse CA bundle")
 		}
-		po.FetcherOptions = []xpkg.FetcherOpt{xpkg.WithCustomCA(rootCAs)}
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
 	}
 
+	// RegistryConfigs may configure a proxy, CA bundle, or client certificate
+	// used to fetch packages from a particular registry.
+	po.FetcherOptions = append(po.FetcherOptions, xpkg.WithRegistryConfigs(mgr.GetClient()))
+
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "Cannot add packages controllers to manager")
 	}
 
+	so := status.Options{Options: o}
+	if c.WebhookTLSSecretName != "" {
+		so.WebhookTLSSecretRef = &types.NamespacedName{Namespace: c.Namespace, Name: c.WebhookTLSSecretName}
+	}
+	if err := status.Setup(mgr, so); err != nil {
+		return errors.Wrap(err, "Cannot add status controller to manager")
+	}
+
 	if c.WebhookTLSCertDir != "" {
 		ws := mgr.GetWebhookServer()
 		ws.Port = 9443
@@ -155,10 +323,65 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		// TODO(muvaf): Once the implementation of other webhook handlers are
 		// fleshed out, implement a registration pattern similar to scheme
 		// registrations.
-		if err := (&apiextensionsv1.CompositeResourceDefinition{}).SetupWebhookWithManager(mgr); err != nil {
-			return errors.Wrap(err, "cannot setup webhook for compositeresourcedefinitions")
+		definition.SetupWebhookWithManager(mgr)
+		copts := []composition.ValidatorOption{
+			composition.WithUpdatePolicy(composition.UpdatePolicy(c.CompositionUpdatePolicy)),
+			composition.WithDeniedKinds(denied),
+		}
+		if c.LintCompositions {
+			copts = append(copts, composition.WithLinting())
+		}
+		composition.SetupWebhookWithManager(mgr, copts...)
+		// Crossplane creates claims on behalf of their original requester
+		// when it migrates them to a renamed kind.
+		sa := "system:serviceaccount:" + c.Namespace + ":" + c.ServiceAccount
+		if err := claim.SetupWebhookWithManager(mgr, claim.WithTrustedUsers(sa)); err != nil {
+			return errors.Wrap(err, "Cannot setup claim webhook")
+		}
+		if err := pkgwebhook.SetupWebhookWithManager(mgr, c.Registry); err != nil {
+			return errors.Wrap(err, "Cannot setup package webhook")
 		}
 	}
 
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
+
+// secretSelectors returns cache selectors that exclude Secrets of the supplied
+// types from the cache.
+func secretSelectors(excludeTypes []string) cache.SelectorsByObject {
+	if len(excludeTypes) == 0 {
+		return nil
+	}
+	sel := make([]fields.Selector, len(excludeTypes))
+	for i, t := range excludeTypes {
+		sel[i] = fields.OneTermNotEqualSelector("type", t)
+	}
+	return cache.SelectorsByObject{&corev1.Secret{}: {Field: fields.AndSelectors(sel...)}}
+}
+
+// parseTolerations parses tolerations of the form key[=value][:effect]. A
+// toleration with a value uses the Equal operator, and one without uses the
+// Exists operator. A toleration without an effect tolerates all effects.
+func parseTolerations(specs []string) ([]corev1.Toleration, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	tols := make([]corev1.Toleration, len(specs))
+	for i, spec := range specs {
+		kv, effect, _ := strings.Cut(spec, ":")
+		t := corev1.Toleration{Key: kv, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffect(effect)}
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			t = corev1.Toleration{Key: k, Operator: corev1.TolerationOpEqual, Value: v, Effect: t.Effect}
+		}
+		if t.Operator == corev1.TolerationOpEqual && t.Key == "" {
+			return nil, errors.Errorf("toleration %q has a value but no key", spec)
+		}
+		switch t.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return nil, errors.Errorf("toleration %q has unknown effect %q", spec, effect)
+		}
+		tols[i] = t
+	}
+	return tols, nil
+}
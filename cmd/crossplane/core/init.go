@@ -41,6 +41,8 @@ type initCommand struct {
 	WebhookServiceName      string `help:"The name of the Service object that the webhook service will be run." env:"WEBHOOK_SERVICE_NAME"`
 	WebhookServiceNamespace string `help:"The namespace of the Service object that the webhook service will be run." env:"WEBHOOK_SERVICE_NAMESPACE"`
 	WebhookServicePort      int32  `help:"The port of the Service that the webhook service will be run." env:"WEBHOOK_SERVICE_PORT"`
+	WebhookTLSIssuerName    string `help:"The name of the cert-manager Issuer or ClusterIssuer to request the webhook TLS certificate from. A self-signed certificate is generated if unset." env:"WEBHOOK_TLS_ISSUER_NAME"`
+	WebhookTLSIssuerKind    string `help:"The kind of the cert-manager issuer to request the webhook TLS certificate from." default:"${issuer_kind_default_var}" enum:"${issuer_kind_enum_var}" env:"WEBHOOK_TLS_ISSUER_KIND"`
 }
 
 // Run starts the initialization process.
@@ -65,9 +67,15 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 			Namespace: c.WebhookServiceNamespace,
 			Port:      &c.WebhookServicePort,
 		}
+		var certs initializer.Step = initializer.NewWebhookCertificateGenerator(nn, c.Namespace,
+			log.WithValues("Step", "WebhookCertificateGenerator"))
+		if c.WebhookTLSIssuerName != "" {
+			certs = initializer.NewCertManagerCertificate(nn, c.Namespace,
+				initializer.IssuerRef{Name: c.WebhookTLSIssuerName, Kind: c.WebhookTLSIssuerKind},
+				log.WithValues("Step", "CertManagerCertificate"))
+		}
 		steps = append(steps,
-			initializer.NewWebhookCertificateGenerator(nn, c.Namespace,
-				log.WithValues("Step", "WebhookCertificateGenerator")),
+			certs,
 			initializer.NewCoreCRDs("/crds", s, initializer.WithWebhookTLSSecretRef(nn)),
 			initializer.NewWebhookConfigurations("/webhookconfigurations", s, nn, svc))
 	} else {
@@ -21,9 +21,11 @@ import (
 	"time"
 
 	"github.com/alecthomas/kong"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -32,6 +34,7 @@ import (
 
 	"github.com/crossplane/crossplane/internal/controller/rbac"
 	rbaccontroller "github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/controller/rbac/subject"
 )
 
 // Available RBAC management policies.
@@ -70,6 +73,16 @@ type startCommand struct {
 	LeaderElection      bool   `name:"leader-election" short:"l" help:"Use leader election for the conroller manager." env:"LEADER_ELECTION"`
 	ManagementPolicy    string `name:"manage" short:"m" help:"RBAC management policy." default:"${rbac_manage_default_var}" enum:"${rbac_manage_enum_var}"`
 
+	LeaderElectionLeaseDuration time.Duration `help:"How long non-leader controller managers wait before trying to acquire a lease that has not been renewed." default:"15s"`
+	LeaderElectionRenewDeadline time.Duration `help:"How long the leader controller manager tries to renew its lease before giving up leadership." default:"10s"`
+	LeaderElectionRetryPeriod   time.Duration `help:"How long controller managers wait between attempts to acquire or renew a lease." default:"2s"`
+
+	MetricsBindAddress     string `help:"Address at which to serve Prometheus metrics. Metrics are not served if set to 0." default:":8080"`
+	HealthProbeBindAddress string `help:"Address at which to serve the /healthz liveness and /readyz readiness probes. Probes are not served if unset." placeholder:":8081"`
+
+	BindSubjects []string `name:"bind-subject" help:"An additional subject to bind to a Crossplane ClusterRole, in the form ROLE=KIND:NAME. ROLE is one of admin, edit, view, or browse. KIND is one of Group, User, or ServiceAccount. The NAME of a ServiceAccount is in the form NAMESPACE/NAME." placeholder:"ROLE=KIND:NAME"`
+	CRDPageSize  int64    `name:"crd-page-size" help:"How many CustomResourceDefinitions to request per page when listing them directly from the API server. CustomResourceDefinitions are listed from a cache if zero." default:"0"`
+
 	SyncInterval     time.Duration `short:"s" help:"How often all resources will be double-checked for drift from the desired state." default:"1h"`
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
@@ -89,12 +102,31 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 		LeaderElection:             c.LeaderElection,
 		LeaderElectionID:           "crossplane-leader-election-rbac",
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+		LeaseDuration:              &c.LeaderElectionLeaseDuration,
+		RenewDeadline:              &c.LeaderElectionRenewDeadline,
+		RetryPeriod:                &c.LeaderElectionRetryPeriod,
 		SyncPeriod:                 &c.SyncInterval,
+		MetricsBindAddress:         c.MetricsBindAddress,
+		HealthProbeBindAddress:     c.HealthProbeBindAddress,
 	})
 	if err != nil {
 		return errors.Wrap(err, "cannot create manager")
 	}
 
+	if c.HealthProbeBindAddress != "" {
+		if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+			return errors.Wrap(err, "cannot add liveness probe")
+		}
+		if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+			return errors.Wrap(err, "cannot add readiness probe")
+		}
+	}
+
+	subjects, err := parseSubjects(c.BindSubjects)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse subjects to bind")
+	}
+
 	o := rbaccontroller.Options{
 		Options: controller.Options{
 			Logger:                  log,
@@ -104,6 +136,8 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 		},
 		AllowClusterRole: c.ProviderClusterRole,
 		ManagementPolicy: rbaccontroller.ManagementPolicy(c.ManagementPolicy),
+		BindSubjects:     subjects,
+		CRDPageSize:      c.CRDPageSize,
 	}
 
 	if err := rbac.Setup(mgr, o); err != nil {
@@ -112,3 +146,44 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "cannot start controller manager")
 }
+
+// parseSubjects parses subjects in the form ROLE=KIND:NAME, returning them
+// keyed by the name of the ClusterRole they should be bound to.
+func parseSubjects(specs []string) (map[string][]rbacv1.Subject, error) {
+	roles := map[string]string{
+		"admin":  subject.ClusterRoleAdmin,
+		"edit":   subject.ClusterRoleEdit,
+		"view":   subject.ClusterRoleView,
+		"browse": subject.ClusterRoleBrowse,
+	}
+	subjects := map[string][]rbacv1.Subject{}
+	for _, spec := range specs {
+		role, ks, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, errors.Errorf("subject %q is not in the form ROLE=KIND:NAME", spec)
+		}
+		cr, ok := roles[role]
+		if !ok {
+			return nil, errors.Errorf("subject %q has unknown role %q", spec, role)
+		}
+		kind, name, ok := strings.Cut(ks, ":")
+		if !ok || name == "" {
+			return nil, errors.Errorf("subject %q is not in the form ROLE=KIND:NAME", spec)
+		}
+		var sub rbacv1.Subject
+		switch kind {
+		case rbacv1.GroupKind, rbacv1.UserKind:
+			sub = rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: kind, Name: name}
+		case rbacv1.ServiceAccountKind:
+			ns, n, ok := strings.Cut(name, "/")
+			if !ok || ns == "" || n == "" {
+				return nil, errors.Errorf("subject %q must name a ServiceAccount in the form NAMESPACE/NAME", spec)
+			}
+			sub = rbacv1.Subject{Kind: kind, Namespace: ns, Name: n}
+		default:
+			return nil, errors.Errorf("subject %q has unknown kind %q", spec, kind)
+		}
+		subjects[cr] = append(subjects[cr], sub)
+	}
+	return subjects, nil
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package catalog serves a catalog of the composite resource claims offered
+// by CompositeResourceDefinitions, for example to developer portals, and the
+// JSON schemas of composite resources, claims, and Compositions, for example
+// to editors and validators.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	errListXRDs    = "cannot list CompositeResourceDefinitions"
+	errRenderCRD   = "cannot render claim CustomResourceDefinition"
+	errServe       = "cannot serve claim catalog"
+	errShutdown    = "cannot shut down claim catalog server"
+	errFmtNotFound = "no claim of kind %q is offered in API group %q"
+)
+
+const (
+	// PathClaims is the path at which the catalog of claims is served.
+	PathClaims = "/v1/claims"
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// A Claim offered by a CompositeResourceDefinition.
+type Claim struct {
+	// Group, Version, Kind, and Plural name of the claim.
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Plural  string `json:"plural"`
+
+	// CompositeKind is the kind of composite resource the claim is bound to.
+	CompositeKind string `json:"compositeKind"`
+
+	// DefaultComposition is used when a claim doesn't select one.
+	DefaultComposition string `json:"defaultComposition,omitempty"`
+
+	// EnforcedComposition is always used, regardless of which a claim
+	// selects.
+	EnforcedComposition string `json:"enforcedComposition,omitempty"`
+
+	// ConnectionSecretKeys are the keys of the claim's connection secret.
+	ConnectionSecretKeys []string `json:"connectionSecretKeys,omitempty"`
+
+	// Schema of the claim, including the fields Crossplane adds to all
+	// claims.
+	Schema *extv1.JSONSchemaProps `json:"schema,omitempty"`
+
+	// Example of a minimal claim, including only required fields and
+	// fields with defaults.
+	Example map[string]any `json:"example"`
+}
+
+// A Catalog of the claims offered by CompositeResourceDefinitions.
+type Catalog struct {
+	client client.Reader
+}
+
+// New returns a Catalog that reads CompositeResourceDefinitions using the
+// supplied client.
+func New(c client.Reader) *Catalog {
+	return &Catalog{client: c}
+}
+
+// Claims returns all offered claims, sorted by API group and kind.
+func (c *Catalog) Claims(ctx context.Context) ([]Claim, error) {
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := c.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListXRDs)
+	}
+
+	claims := make([]Claim, 0, len(l.Items))
+	for i := range l.Items {
+		xrd := &l.Items[i]
+		if !xrd.OffersClaim() {
+			continue
+		}
+		cl, err := ClaimOf(xrd)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, cl)
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].Group != claims[j].Group {
+			return claims[i].Group < claims[j].Group
+		}
+		return claims[i].Kind < claims[j].Kind
+	})
+	return claims, nil
+}
+
+// ClaimOf returns the claim offered by the supplied CompositeResourceDefinition.
+func ClaimOf(xrd *v1.CompositeResourceDefinition) (Claim, error) {
+	gvk := xrd.GetClaimGroupVersionKind()
+	cl := Claim{
+		Group:                gvk.Group,
+		Version:              gvk.Version,
+		Kind:                 gvk.Kind,
+		Plural:               xrd.Spec.ClaimNames.Plural,
+		CompositeKind:        xrd.Spec.Names.Kind,
+		ConnectionSecretKeys: xrd.Spec.ConnectionSecretKeys,
+	}
+	if ref := xrd.Spec.DefaultCompositionRef; ref != nil {
+		cl.DefaultComposition = ref.Name
+	}
+	if ref := xrd.Spec.EnforcedCompositionRef; ref != nil {
+		cl.EnforcedComposition = ref.Name
+	}
+
+	crd, err := xcrd.ForCompositeResourceClaim(xrd)
+	if err != nil {
+		return Claim{}, errors.Wrap(err, errRenderCRD)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == gvk.Version && v.Schema != nil {
+			cl.Schema = v.Schema.OpenAPIV3Schema
+		}
+	}
+
+	cl.Example = map[string]any{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   map[string]any{"name": "example", "namespace": "default"},
+	}
+	if cl.Schema != nil {
+		if spec, ok := cl.Schema.Properties["spec"]; ok {
+			// Omit the fields Crossplane adds to all claims; folks rarely
+			// need to set them.
+			machinery := xcrd.CompositeResourceClaimSpecProps()
+			props := make(map[string]extv1.JSONSchemaProps, len(spec.Properties))
+			for k, v := range spec.Properties {
+				if _, ok := machinery[k]; !ok {
+					props[k] = v
+				}
+			}
+			spec.Properties = props
+			cl.Example["spec"] = Example(spec)
+		}
+	}
+	return cl, nil
+}
+
+// Example returns an example value for the supplied schema. The value of a
+// field with a default is its default, and the value of an enum is its first
+// allowed value. Objects include only their required properties and properties
+// with defaults.
+func Example(s extv1.JSONSchemaProps) any {
+	var v any
+	if s.Default != nil && json.Unmarshal(s.Default.Raw, &v) == nil {
+		return v
+	}
+	if len(s.Enum) > 0 && json.Unmarshal(s.Enum[0].Raw, &v) == nil {
+		return v
+	}
+
+	switch s.Type {
+	case "object":
+		o := map[string]any{}
+		required := map[string]bool{}
+		for _, r := range s.Required {
+			required[r] = true
+		}
+		for name, p := range s.Properties {
+			if required[name] || p.Default != nil {
+				o[name] = Example(p)
+			}
+		}
+		return o
+	case "array":
+		return []any{}
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
+
+// A Server serves the catalog of claims over HTTP.
+type Server struct {
+	catalog *Catalog
+	address string
+	log     logging.Logger
+}
+
+// NewServer returns a Server that serves the supplied catalog at the supplied
+// address.
+func NewServer(c *Catalog, address string, log logging.Logger) *Server {
+	return &Server{catalog: c, address: address, log: log}
+}
+
+// ServeHTTP serves the list of claims at PathClaims, and each claim at
+// PathClaims/<group>/<kind>. It serves a bundle of schemas at PathSchemas, and
+// each schema at PathSchemas/<group>/<kind>_<version>.json.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, PathSchemas) {
+		s.serveSchemas(w, r)
+		return
+	}
+	s.serveClaims(w, r)
+}
+
+func (s *Server) serveClaims(w http.ResponseWriter, r *http.Request) {
+	claims, err := s.catalog.Claims(r.Context())
+	if err != nil {
+		s.log.Debug("Cannot list claims", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, PathClaims), "/")
+	if p == "" {
+		s.write(w, map[string]any{"claims": claims})
+		return
+	}
+
+	group, kind, ok := strings.Cut(p, "/")
+	if !ok || strings.Contains(kind, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	for _, cl := range claims {
+		if cl.Group == group && strings.EqualFold(cl.Kind, kind) {
+			s.write(w, cl)
+			return
+		}
+	}
+	http.Error(w, errors.Errorf(errFmtNotFound, kind, group).Error(), http.StatusNotFound)
+}
+
+func (s *Server) serveSchemas(w http.ResponseWriter, r *http.Request) {
+	schemas, err := s.catalog.Schemas(r.Context())
+	if err != nil {
+		s.log.Debug("Cannot list schemas", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, PathSchemas), "/")
+	if p == "" {
+		s.write(w, map[string]any{"schemas": schemas})
+		return
+	}
+
+	// Individual schemas are served using the same layout as popular schema
+	// catalogs, so that validators like kubeconform can use them.
+	group, file, ok := strings.Cut(p, "/")
+	if !ok || strings.Contains(file, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	kind, version, ok := strings.Cut(strings.TrimSuffix(file, ".json"), "_")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for _, sc := range schemas {
+		if sc.Group == group && sc.Version == version && strings.EqualFold(sc.Kind, kind) {
+			s.write(w, sc.Schema)
+			return
+		}
+	}
+	http.Error(w, errors.Errorf(errFmtNoSchema, kind, version, group).Error(), http.StatusNotFound)
+}
+
+func (s *Server) write(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log.Debug("Cannot write response", "error", err)
+	}
+}
+
+// Start serving the catalog. Start blocks until the supplied context is
+// cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathClaims, s)
+	mux.Handle(PathClaims+"/", s)
+	mux.Handle(PathSchemas, s)
+	mux.Handle(PathSchemas+"/", s)
+	srv := &http.Server{Addr: s.address, Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.log.Info("Serving claim catalog", "address", s.address)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(err, errServe)
+	case <-ctx.Done():
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return errors.Wrap(srv.Shutdown(sctx), errShutdown)
+	}
+}
+
+// NeedLeaderElection returns false; every replica serves the catalog.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
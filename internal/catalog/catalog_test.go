@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func xrd(kind, claimKind string) v1.CompositeResourceDefinition {
+	d := v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group: "example.org",
+			Names: extv1.CustomResourceDefinitionNames{Kind: kind, Plural: kind + "s"},
+			Versions: []v1.CompositeResourceDefinitionVersion{{
+				Name:          "v1",
+				Referenceable: true,
+				Served:        true,
+				Schema: &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{
+					"type": "object",
+					"properties": {
+						"spec": {
+							"type": "object",
+							"required": ["parameters"],
+							"properties": {
+								"parameters": {
+									"type": "object",
+									"required": ["storageGB"],
+									"properties": {
+										"storageGB": {"type": "integer"},
+										"engine": {"type": "string", "enum": ["postgres", "mysql"]},
+										"region": {"type": "string", "default": "us-east-1"}
+									}
+								}
+							}
+						}
+					}
+				}`)}},
+			}},
+		},
+	}
+	if claimKind != "" {
+		d.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Kind: claimKind, Plural: claimKind + "s"}
+	}
+	return d
+}
+
+func withXRDs(xrds ...v1.CompositeResourceDefinition) test.MockListFn {
+	return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		obj.(*v1.CompositeResourceDefinitionList).Items = xrds
+		return nil
+	})
+}
+
+func TestClaims(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		kinds []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered while listing XRDs.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRDs),
+			},
+		},
+		"OnlyOffered": {
+			reason: "We should return only offered claims, sorted by kind.",
+			client: &test.MockClient{MockList: withXRDs(xrd("XDatabase", "Database"), xrd("XNetwork", ""), xrd("XBucket", "Bucket"))},
+			want: want{
+				kinds: []string{"Bucket", "Database"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			claims, err := New(tc.client).Claims(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nClaims(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			var kinds []string
+			for _, cl := range claims {
+				kinds = append(kinds, cl.Kind)
+			}
+			if diff := cmp.Diff(tc.want.kinds, kinds); diff != "" {
+				t.Errorf("\n%s\nClaims(...): -want kinds, +got kinds:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClaimOf(t *testing.T) {
+	d := xrd("XDatabase", "Database")
+	d.Spec.DefaultCompositionRef = &v1.CompositionReference{Name: "default"}
+
+	cl, err := ClaimOf(&d)
+	if err != nil {
+		t.Fatalf("ClaimOf(...): %s", err)
+	}
+
+	if cl.Schema == nil {
+		t.Errorf("ClaimOf(...): want schema, got none")
+	}
+	want := Claim{
+		Group:              "example.org",
+		Version:            "v1",
+		Kind:               "Database",
+		Plural:             "Databases",
+		CompositeKind:      "XDatabase",
+		DefaultComposition: "default",
+		Example: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Database",
+			"metadata":   map[string]any{"name": "example", "namespace": "default"},
+			"spec": map[string]any{
+				"parameters": map[string]any{
+					"storageGB": 0,
+					"region":    "us-east-1",
+				},
+			},
+		},
+	}
+	cl.Schema = nil
+	if diff := cmp.Diff(want, cl); diff != "" {
+		t.Errorf("ClaimOf(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	c := &test.MockClient{
+		MockList: withXRDs(xrd("XDatabase", "Database")),
+		MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+	}
+	s := NewServer(New(c), "", logging.NewNopLogger())
+
+	cases := map[string]struct {
+		reason string
+		method string
+		path   string
+		want   int
+	}{
+		"List": {
+			reason: "We should serve the list of claims.",
+			method: http.MethodGet,
+			path:   PathClaims,
+			want:   http.StatusOK,
+		},
+		"Get": {
+			reason: "We should serve a claim by API group and case insensitive kind.",
+			method: http.MethodGet,
+			path:   PathClaims + "/example.org/database",
+			want:   http.StatusOK,
+		},
+		"NotFound": {
+			reason: "We should return 404 if no such claim is offered.",
+			method: http.MethodGet,
+			path:   PathClaims + "/example.org/Bucket",
+			want:   http.StatusNotFound,
+		},
+		"ListSchemas": {
+			reason: "We should serve the bundle of schemas.",
+			method: http.MethodGet,
+			path:   PathSchemas,
+			want:   http.StatusOK,
+		},
+		"GetSchema": {
+			reason: "We should serve a schema by API group, case insensitive kind, and version.",
+			method: http.MethodGet,
+			path:   PathSchemas + "/example.org/xdatabase_v1.json",
+			want:   http.StatusOK,
+		},
+		"SchemaNotFound": {
+			reason: "We should return 404 if there is no such schema.",
+			method: http.MethodGet,
+			path:   PathSchemas + "/example.org/database_v2.json",
+			want:   http.StatusNotFound,
+		},
+		"MethodNotAllowed": {
+			reason: "We should only serve GET requests.",
+			method: http.MethodPost,
+			path:   PathClaims,
+			want:   http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if diff := cmp.Diff(tc.want, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if w.Code == http.StatusOK && !json.Valid(w.Body.Bytes()) {
+				t.Errorf("\n%s\nServeHTTP(...): response is not valid JSON:\n%s", tc.reason, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	errGetCRD       = "cannot get CustomResourceDefinition"
+	errRenderXRCRD  = "cannot render composite resource CustomResourceDefinition"
+	errMarshalEnum  = "cannot marshal schema enum"
+	errFmtNoSchema  = "no schema for kind %q at version %q of API group %q"
+	errFmtBadSchema = "cannot derive schema for CustomResourceDefinition %q"
+)
+
+// PathSchemas is the path at which JSON schemas are served.
+const PathSchemas = "/v1/schemas"
+
+// coreCRDs are the CustomResourceDefinitions of the Crossplane types folks
+// author alongside their claims.
+var coreCRDs = []string{
+	"compositeresourcedefinitions." + v1.Group,
+	"compositions." + v1.Group,
+}
+
+// A Schema is a standalone JSON schema for one version of a kind of resource.
+// Unlike the OpenAPI schema of a CustomResourceDefinition it constrains the
+// apiVersion and kind of the resource, so that editors and validators can
+// use it without a cluster.
+type Schema struct {
+	Group   string                 `json:"group"`
+	Version string                 `json:"version"`
+	Kind    string                 `json:"kind"`
+	Schema  *extv1.JSONSchemaProps `json:"schema"`
+}
+
+// Schemas returns a schema for each served version of each composite resource
+// and claim defined by a CompositeResourceDefinition, and for Compositions and
+// CompositeResourceDefinitions. Schemas are sorted by API group, kind, and
+// version.
+func (c *Catalog) Schemas(ctx context.Context) ([]Schema, error) {
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := c.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListXRDs)
+	}
+
+	schemas := make([]Schema, 0)
+	for _, name := range coreCRDs {
+		crd := &extv1.CustomResourceDefinition{}
+		err := c.client.Get(ctx, types.NamespacedName{Name: name}, crd)
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, errGetCRD)
+		}
+		if err != nil {
+			continue
+		}
+		s, err := SchemasOf(crd)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, s...)
+	}
+
+	for i := range l.Items {
+		xrd := &l.Items[i]
+		crd, err := xcrd.ForCompositeResource(xrd)
+		if err != nil {
+			return nil, errors.Wrap(err, errRenderXRCRD)
+		}
+		s, err := SchemasOf(crd)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, s...)
+
+		if !xrd.OffersClaim() {
+			continue
+		}
+		crd, err = xcrd.ForCompositeResourceClaim(xrd)
+		if err != nil {
+			return nil, errors.Wrap(err, errRenderCRD)
+		}
+		s, err = SchemasOf(crd)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, s...)
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Group != schemas[j].Group {
+			return schemas[i].Group < schemas[j].Group
+		}
+		if schemas[i].Kind != schemas[j].Kind {
+			return schemas[i].Kind < schemas[j].Kind
+		}
+		return schemas[i].Version < schemas[j].Version
+	})
+	return schemas, nil
+}
+
+// SchemasOf returns a schema for each served version of the supplied
+// CustomResourceDefinition.
+func SchemasOf(crd *extv1.CustomResourceDefinition) ([]Schema, error) {
+	schemas := make([]Schema, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		if !v.Served || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		s := v.Schema.OpenAPIV3Schema.DeepCopy()
+		if s.Properties == nil {
+			s.Properties = map[string]extv1.JSONSchemaProps{}
+		}
+
+		apiVersion, err := enum(crd.Spec.Group + "/" + v.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtBadSchema, crd.GetName())
+		}
+		kind, err := enum(crd.Spec.Names.Kind)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtBadSchema, crd.GetName())
+		}
+		s.Properties["apiVersion"] = extv1.JSONSchemaProps{Type: "string", Enum: apiVersion}
+		s.Properties["kind"] = extv1.JSONSchemaProps{Type: "string", Enum: kind}
+		if _, ok := s.Properties["metadata"]; !ok {
+			s.Properties["metadata"] = extv1.JSONSchemaProps{Type: "object"}
+		}
+		s.Required = appendMissing(s.Required, "apiVersion", "kind")
+
+		schemas = append(schemas, Schema{
+			Group:   crd.Spec.Group,
+			Version: v.Name,
+			Kind:    crd.Spec.Names.Kind,
+			Schema:  s,
+		})
+	}
+	return schemas, nil
+}
+
+func enum(values ...string) ([]extv1.JSON, error) {
+	out := make([]extv1.JSON, len(values))
+	for i, v := range values {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalEnum)
+		}
+		out[i] = extv1.JSON{Raw: raw}
+	}
+	return out, nil
+}
+
+func appendMissing(s []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, e := range s {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s = append(s, v)
+		}
+	}
+	return s
+}
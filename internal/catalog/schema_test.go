@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSchemas(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// withCRD returns a MockGetFn that gets the supplied
+	// CustomResourceDefinition, or returns not found for any other.
+	withCRD := func(want *extv1.CustomResourceDefinition) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name != want.GetName() {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			want.DeepCopyInto(obj.(*extv1.CustomResourceDefinition))
+			return nil
+		}
+	}
+
+	comp := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "compositions.apiextensions.crossplane.io"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "apiextensions.crossplane.io",
+			Names: extv1.CustomResourceDefinitionNames{Kind: "Composition"},
+			Versions: []extv1.CustomResourceDefinitionVersion{{
+				Name:   "v1",
+				Served: true,
+				Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{Type: "object"}},
+			}},
+		},
+	}
+
+	type want struct {
+		kinds []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered while listing XRDs.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRDs),
+			},
+		},
+		"GetCRDError": {
+			reason: "We should return any error encountered while getting a core CRD.",
+			client: &test.MockClient{
+				MockList: withXRDs(),
+				MockGet:  test.NewMockGetFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCRD),
+			},
+		},
+		"Success": {
+			reason: "We should return schemas for core types, composite resources, and offered claims, sorted by group and kind.",
+			client: &test.MockClient{
+				MockList: withXRDs(xrd("XDatabase", "Database"), xrd("XNetwork", "")),
+				MockGet:  withCRD(comp),
+			},
+			want: want{
+				kinds: []string{"Composition", "Database", "XDatabase", "XNetwork"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			schemas, err := New(tc.client).Schemas(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSchemas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			var kinds []string
+			for _, s := range schemas {
+				kinds = append(kinds, s.Kind)
+			}
+			if diff := cmp.Diff(tc.want.kinds, kinds); diff != "" {
+				t.Errorf("\n%s\nSchemas(...): -want kinds, +got kinds:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSchemasOf(t *testing.T) {
+	crd := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "databases.example.org"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: extv1.CustomResourceDefinitionNames{Kind: "Database"},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:   "v1",
+					Served: true,
+					Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{
+						Type:       "object",
+						Required:   []string{"spec"},
+						Properties: map[string]extv1.JSONSchemaProps{"spec": {Type: "object"}},
+					}},
+				},
+				{
+					Name:   "v1beta1",
+					Served: false,
+					Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{Type: "object"}},
+				},
+			},
+		},
+	}
+
+	want := []Schema{{
+		Group:   "example.org",
+		Version: "v1",
+		Kind:    "Database",
+		Schema: &extv1.JSONSchemaProps{
+			Type:     "object",
+			Required: []string{"spec", "apiVersion", "kind"},
+			Properties: map[string]extv1.JSONSchemaProps{
+				"apiVersion": {Type: "string", Enum: []extv1.JSON{{Raw: []byte(`"example.org/v1"`)}}},
+				"kind":       {Type: "string", Enum: []extv1.JSON{{Raw: []byte(`"Database"`)}}},
+				"metadata":   {Type: "object"},
+				"spec":       {Type: "object"},
+			},
+		},
+	}}
+
+	got, err := SchemasOf(crd)
+	if err != nil {
+		t.Fatalf("SchemasOf(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SchemasOf(...): -want, +got:\n%s", diff)
+	}
+	if _, ok := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["kind"]; ok {
+		t.Errorf("SchemasOf(...): must not modify the supplied CustomResourceDefinition")
+	}
+}
@@ -19,10 +19,10 @@ package apiextensions
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/crossplane/crossplane-runtime/pkg/controller"
-
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composition"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/definition"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/gc"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/offered"
 	"github.com/crossplane/crossplane/internal/features"
 )
@@ -34,7 +34,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	// CompositionRevisions, so we don't need it at all unless the
 	// CompositionRevision feature flag is enabled.
 	if o.Features.Enabled(features.EnableAlphaCompositionRevisions) {
-		if err := composition.Setup(mgr, o); err != nil {
+		if err := composition.Setup(mgr, o.Options); err != nil {
 			return err
 		}
 	}
@@ -43,5 +43,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
-	return offered.Setup(mgr, o)
+	if err := gc.Setup(mgr, o); err != nil {
+		return err
+	}
+
+	return offered.Setup(mgr, o.Options)
 }
@@ -30,6 +30,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 // Error strings.
@@ -61,7 +63,7 @@ func (a *APIBinder) Bind(ctx context.Context, cm resource.CompositeClaim, cp res
 	// We refuse to 're-bind' a claim that is already bound to a different
 	// composite resource.
 	if existing != nil && !equal {
-		return errors.New(errBindClaimConflict)
+		return reason.Wrap(errors.New(errBindClaimConflict), reason.BindConflict)
 	}
 
 	// There's no need to call update if the claim already references this
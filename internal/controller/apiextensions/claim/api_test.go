@@ -32,6 +32,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 var (
@@ -87,7 +89,7 @@ func TestBind(t *testing.T) {
 						},
 					},
 				},
-				err: errors.New(errBindClaimConflict),
+				err: reason.Wrap(errors.New(errBindClaimConflict), reason.BindConflict),
 			},
 		},
 		"UpdateClaimError": {
@@ -19,11 +19,14 @@ package claim
 import (
 	"context"
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/imdario/mergo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -32,6 +35,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/reason"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -51,14 +56,31 @@ const (
 // perform a dry-run create against an API server in order to name and validate
 // the configured resource.
 type APIDryRunCompositeConfigurator struct {
-	client client.Client
+	client   client.Client
+	metadata *v1.MetadataFilter
+}
+
+// A CompositeConfiguratorOption configures an APIDryRunCompositeConfigurator.
+type CompositeConfiguratorOption func(*APIDryRunCompositeConfigurator)
+
+// WithPropagatedMetadata specifies which labels and annotations propagate
+// from a claim to its composite resource. All labels and annotations propagate
+// by default.
+func WithPropagatedMetadata(f *v1.MetadataFilter) CompositeConfiguratorOption {
+	return func(c *APIDryRunCompositeConfigurator) {
+		c.metadata = f
+	}
 }
 
 // NewAPIDryRunCompositeConfigurator returns a Configurator of composite
 // resources that may perform a dry-run create against an API server in order to
 // name and validate the configured resource.
-func NewAPIDryRunCompositeConfigurator(c client.Client) *APIDryRunCompositeConfigurator {
-	return &APIDryRunCompositeConfigurator{client: c}
+func NewAPIDryRunCompositeConfigurator(c client.Client, o ...CompositeConfiguratorOption) *APIDryRunCompositeConfigurator {
+	cc := &APIDryRunCompositeConfigurator{client: c}
+	for _, fn := range o {
+		fn(cc)
+	}
+	return cc
 }
 
 // Configure the supplied composite resource by propagating configuration from
@@ -85,7 +107,7 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	existing := ucp.GetClaimReference()
 	proposed := meta.ReferenceTo(ucm, ucm.GetObjectKind().GroupVersionKind())
 	if existing != nil && !cmp.Equal(existing, proposed, cmpopts.IgnoreFields(corev1.ObjectReference{}, "UID")) {
-		return errors.New(errBindCompositeConflict)
+		return reason.Wrap(errors.New(errBindCompositeConflict), reason.BindConflict)
 	}
 
 	// It's possible we're being asked to configure a statically provisioned
@@ -93,13 +115,27 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	// external name.
 	en := meta.GetExternalName(ucp)
 
-	meta.AddAnnotations(ucp, ucm.GetAnnotations())
-	meta.AddLabels(ucp, cm.GetLabels())
+	annotations, labels := ucm.GetAnnotations(), cm.GetLabels()
+	if c.metadata != nil {
+		annotations = c.metadata.Annotations.Select(annotations)
+		labels = c.metadata.Labels.Select(labels)
+	}
+	meta.AddAnnotations(ucp, annotations)
+	meta.AddLabels(ucp, labels)
 	meta.AddLabels(ucp, map[string]string{
 		xcrd.LabelKeyClaimName:      ucm.GetName(),
 		xcrd.LabelKeyClaimNamespace: ucm.GetNamespace(),
 	})
 
+	// The requester of a claim is always propagated, regardless of which
+	// labels and annotations are configured to propagate.
+	if u := ucm.GetAnnotations()[xcrd.AnnotationKeyClaimRequester]; u != "" {
+		meta.AddAnnotations(ucp, map[string]string{xcrd.AnnotationKeyClaimRequester: u})
+		if l := requesterLabelValue(u); l != "" {
+			meta.AddLabels(ucp, map[string]string{xcrd.LabelKeyClaimRequester: l})
+		}
+	}
+
 	// If our composite resource already exists we want to restore its
 	// original external name (if set) in order to ensure we don't try to
 	// rename anything after the fact.
@@ -138,6 +174,26 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	return nil
 }
 
+// requesterLabelValue returns the supplied username as a valid label value.
+// Characters that aren't allowed in label values are replaced with '_', and the
+// result is truncated to the maximum length of a label value. Usernames often
+// contain such characters, for example 'system:serviceaccount:ns:name' or
+// 'alice@example.org'.
+func requesterLabelValue(username string) string {
+	v := []rune(username)
+	for i, r := range v {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.') {
+			v[i] = '_'
+		}
+	}
+	if len(v) > validation.LabelValueMaxLength {
+		v = v[:validation.LabelValueMaxLength]
+	}
+	return strings.TrimFunc(string(v), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
 func filter(in map[string]any, keys ...string) map[string]any {
 	filter := map[string]bool{}
 	for _, k := range keys {
@@ -33,6 +33,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/reason"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -58,6 +61,7 @@ func TestCompositeConfigure(t *testing.T) {
 	cases := map[string]struct {
 		reason string
 		c      client.Client
+		o      []CompositeConfiguratorOption
 		args   args
 		want   want
 	}{
@@ -160,7 +164,7 @@ func TestCompositeConfigure(t *testing.T) {
 						},
 					},
 				},
-				err: errors.New(errBindCompositeConflict),
+				err: reason.Wrap(errors.New(errBindCompositeConflict), reason.BindConflict),
 			},
 		},
 		"DryRunError": {
@@ -371,11 +375,124 @@ func TestCompositeConfigure(t *testing.T) {
 				},
 			},
 		},
+		"PropagatedMetadata": {
+			reason: "Only the claim labels and annotations selected by the propagation filter should be propagated to the composite resource",
+			c: &test.MockClient{
+				MockCreate: test.NewMockCreateFn(nil),
+			},
+			o: []CompositeConfiguratorOption{WithPropagatedMetadata(&v1.MetadataFilter{
+				Labels: &v1.KeyPrefixFilter{
+					Include: []string{"example.org/"},
+					Exclude: []string{"example.org/internal-"},
+				},
+			})},
+			args: args{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"apiVersion": apiVersion,
+							"kind":       kind,
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+								"labels": map[string]any{
+									"example.org/team":          "platform",
+									"example.org/internal-cost": "42",
+									"app":                       "cool",
+								},
+								"annotations": map[string]any{
+									"example.org/owner": "me",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{},
+			},
+			want: want{
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"generateName": name + "-",
+								"labels": map[string]any{
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+									"example.org/team":          "platform",
+								},
+							},
+							"spec": map[string]any{
+								"claimRef": map[string]any{
+									"apiVersion": apiVersion,
+									"kind":       kind,
+									"namespace":  ns,
+									"name":       name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"PropagatedRequester": {
+			reason: "The claim's requester should be propagated to the composite resource regardless of the propagation filter",
+			c: &test.MockClient{
+				MockCreate: test.NewMockCreateFn(nil),
+			},
+			o: []CompositeConfiguratorOption{WithPropagatedMetadata(&v1.MetadataFilter{})},
+			args: args{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"apiVersion": apiVersion,
+							"kind":       kind,
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+								"annotations": map[string]any{
+									xcrd.AnnotationKeyClaimRequester: "system:serviceaccount:ci:deployer",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{},
+			},
+			want: want{
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"generateName": name + "-",
+								"labels": map[string]any{
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+									xcrd.LabelKeyClaimRequester: "system_serviceaccount_ci_deployer",
+								},
+								"annotations": map[string]any{
+									xcrd.AnnotationKeyClaimRequester: "system:serviceaccount:ci:deployer",
+								},
+							},
+							"spec": map[string]any{
+								"claimRef": map[string]any{
+									"apiVersion": apiVersion,
+									"kind":       kind,
+									"namespace":  ns,
+									"name":       name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c := NewAPIDryRunCompositeConfigurator(tc.c)
+			c := NewAPIDryRunCompositeConfigurator(tc.c, tc.o...)
 			got := c.Configure(tc.args.ctx, tc.args.cm, tc.args.cp)
 			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
 				t.Errorf("Configure(...): %s\n-want error, +got error:\n%s\n", tc.reason, diff)
@@ -388,6 +505,54 @@ func TestCompositeConfigure(t *testing.T) {
 
 }
 
+func TestRequesterLabelValue(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		username string
+		want     string
+	}{
+		"Valid": {
+			reason:   "A username that is a valid label value should be unchanged.",
+			username: "alice",
+			want:     "alice",
+		},
+		"ServiceAccount": {
+			reason:   "Colons should be replaced.",
+			username: "system:serviceaccount:ci:deployer",
+			want:     "system_serviceaccount_ci_deployer",
+		},
+		"Email": {
+			reason:   "At signs should be replaced.",
+			username: "alice@example.org",
+			want:     "alice_example.org",
+		},
+		"Trimmed": {
+			reason:   "A label value must begin and end with an alphanumeric character.",
+			username: "@alice:",
+			want:     "alice",
+		},
+		"Truncated": {
+			reason:   "A label value must be no longer than 63 characters.",
+			username: strings.Repeat("a", 70),
+			want:     strings.Repeat("a", 63),
+		},
+		"NothingValid": {
+			reason:   "A username with no alphanumeric characters should produce no label value.",
+			username: ":::",
+			want:     "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := requesterLabelValue(tc.username)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrequesterLabelValue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestClaimConfigure(t *testing.T) {
 	errBoom := errors.New("boom")
 	ns := "spacename"
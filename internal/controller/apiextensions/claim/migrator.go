@@ -0,0 +1,296 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errGetPrevious        = "cannot get composite resource claim of previous kind"
+	errGetMigrated        = "cannot get migrated composite resource claim"
+	errCreateMigrated     = "cannot create migrated composite resource claim"
+	errBindMigrated       = "cannot bind composite resource to migrated composite resource claim"
+	errTransferSecret     = "cannot transfer connection secret to migrated composite resource claim"
+	errDeletePrevious     = "cannot delete composite resource claim of previous kind"
+	errFmtMigrateConflict = "cannot migrate composite resource claim: a %s with the same name that was not migrated from this claim already exists"
+)
+
+// Event reasons.
+const (
+	reasonMigrate event.Reason = "MigrateClaim"
+)
+
+// MigratorControllerName returns the recommended name for controllers that use
+// this package to migrate a particular previous kind of composite resource
+// claim.
+func MigratorControllerName(name, kind string) string {
+	return "claim-migrator/" + name + "/" + strings.ToLower(kind)
+}
+
+// A MigratorOption configures a Migrator.
+type MigratorOption func(*Migrator)
+
+// WithMigratorClient specifies how the Migrator should interact with the
+// Kubernetes API.
+func WithMigratorClient(c client.Client) MigratorOption {
+	return func(m *Migrator) {
+		m.client = c
+	}
+}
+
+// WithMigratorFinalizer specifies how the Migrator should remove the finalizer
+// from claims of the previous kind.
+func WithMigratorFinalizer(f resource.Finalizer) MigratorOption {
+	return func(m *Migrator) {
+		m.finalizer = f
+	}
+}
+
+// WithMigratorLogger specifies how the Migrator should log messages.
+func WithMigratorLogger(l logging.Logger) MigratorOption {
+	return func(m *Migrator) {
+		m.log = l
+	}
+}
+
+// WithMigratorRecorder specifies how the Migrator should record events.
+func WithMigratorRecorder(er event.Recorder) MigratorOption {
+	return func(m *Migrator) {
+		m.record = er
+	}
+}
+
+// NewMigrator returns a Migrator that migrates composite resource claims of
+// the supplied previous kind to the supplied kind.
+func NewMigrator(mgr manager.Manager, from, to resource.CompositeClaimKind, o ...MigratorOption) *Migrator {
+	c := unstructured.NewClient(mgr.GetClient())
+	m := &Migrator{
+		client:    c,
+		from:      schema.GroupVersionKind(from),
+		to:        schema.GroupVersionKind(to),
+		finalizer: resource.NewAPIFinalizer(c, finalizer),
+		log:       logging.NewNopLogger(),
+		record:    event.NewNopRecorder(),
+	}
+	for _, fn := range o {
+		fn(m)
+	}
+	return m
+}
+
+// A Migrator migrates composite resource claims of a kind that was previously
+// offered to the kind that is now offered. It creates a claim of the new kind
+// with the same name, binds the claim's composite resource and connection
+// secret to it, then deletes the claim of the previous kind. Claims of the
+// previous kind are not reconciled by a claim controller, so deleting one
+// doesn't delete its composite resource.
+type Migrator struct {
+	client    client.Client
+	from      schema.GroupVersionKind
+	to        schema.GroupVersionKind
+	finalizer resource.Finalizer
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a composite resource claim of a previous kind by migrating it to
+// the kind that is now offered.
+func (m *Migrator) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := m.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	prev := claim.New(claim.WithGroupVersionKind(m.from))
+	if err := m.client.Get(ctx, req.NamespacedName, prev); err != nil {
+		// There's no need to requeue if the claim was already migrated.
+		log.Debug(errGetPrevious, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPrevious)
+	}
+
+	log = log.WithValues(
+		"uid", prev.GetUID(),
+		"version", prev.GetResourceVersion(),
+	)
+
+	cm := claim.New(claim.WithGroupVersionKind(m.to))
+	err := m.client.Get(ctx, req.NamespacedName, cm)
+	switch {
+	case kerrors.IsNotFound(err):
+		cm = Migrated(prev, m.to)
+	case err != nil:
+		log.Debug(errGetMigrated, "error", err)
+		err = errors.Wrap(err, errGetMigrated)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{}, err
+	case cm.GetAnnotations()[xcrd.AnnotationKeyMigratedFrom] != string(prev.GetUID()):
+		// We don't requeue (or return an error, which would requeue) in
+		// this situation because one of the claims will need to be
+		// renamed or deleted before we can proceed, and we'll be queued
+		// implicitly when this claim is edited.
+		err := errors.Errorf(errFmtMigrateConflict, m.to.Kind)
+		log.Debug("Cannot migrate composite resource claim", "error", err)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	if !meta.WasCreated(cm) {
+		if err := m.client.Create(ctx, cm); err != nil {
+			log.Debug(errCreateMigrated, "error", err)
+			err = errors.Wrap(err, errCreateMigrated)
+			m.record.Event(prev, event.Warning(reasonMigrate, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// We bind the composite resource to the migrated claim only once it has
+	// been created, so that the composite's claim reference includes the
+	// migrated claim's UID. The migrated claim's controller won't be able to
+	// bind the composite until we do.
+	if err := m.bind(ctx, prev, cm); err != nil {
+		log.Debug(errBindMigrated, "error", err)
+		err = errors.Wrap(err, errBindMigrated)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{}, err
+	}
+
+	if err := m.transferSecret(ctx, prev, cm); err != nil {
+		log.Debug(errTransferSecret, "error", err)
+		err = errors.Wrap(err, errTransferSecret)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{}, err
+	}
+
+	// The claim's composite resource is no longer bound to it, but we remove
+	// the finalizer rather than waiting for a claim controller that isn't
+	// running to do so.
+	if err := m.finalizer.RemoveFinalizer(ctx, prev); err != nil {
+		log.Debug(errRemoveFinalizer, "error", err)
+		err = errors.Wrap(err, errRemoveFinalizer)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{}, err
+	}
+
+	if err := m.client.Delete(ctx, prev); resource.IgnoreNotFound(err) != nil {
+		log.Debug(errDeletePrevious, "error", err)
+		err = errors.Wrap(err, errDeletePrevious)
+		m.record.Event(prev, event.Warning(reasonMigrate, err))
+		return reconcile.Result{}, err
+	}
+
+	log.Debug("Successfully migrated composite resource claim", "kind", m.to.Kind)
+	m.record.Event(prev, event.Normal(reasonMigrate, "Successfully migrated composite resource claim", "kind", m.to.Kind))
+	return reconcile.Result{Requeue: false}, nil
+}
+
+// bind the composite resource that is bound to the supplied claim of a
+// previous kind to the supplied migrated claim. Composite resources that are
+// bound to another claim are left as they are.
+func (m *Migrator) bind(ctx context.Context, prev, cm *claim.Unstructured) error {
+	ref := prev.GetResourceReference()
+	if ref == nil {
+		return nil
+	}
+
+	cp := composite.New(composite.WithGroupVersionKind(ref.GroupVersionKind()))
+	if err := m.client.Get(ctx, meta.NamespacedNameOf(ref), cp); err != nil {
+		return resource.IgnoreNotFound(err)
+	}
+
+	cr := cp.GetClaimReference()
+	if cr == nil || cr.Kind != m.from.Kind || cr.Name != prev.GetName() || cr.Namespace != prev.GetNamespace() {
+		return nil
+	}
+
+	cp.SetClaimReference(meta.ReferenceTo(cm, m.to))
+	return m.client.Update(ctx, cp)
+}
+
+// transferSecret makes the supplied migrated claim the controller of the
+// connection secret the supplied claim of a previous kind controls, if any, so
+// that the secret isn't garbage collected when the previous claim is deleted.
+func (m *Migrator) transferSecret(ctx context.Context, prev, cm *claim.Unstructured) error {
+	ref := prev.GetWriteConnectionSecretToReference()
+	if ref == nil || ref.Name == "" {
+		return nil
+	}
+
+	s := &corev1.Secret{}
+	if err := m.client.Get(ctx, types.NamespacedName{Namespace: prev.GetNamespace(), Name: ref.Name}, s); err != nil {
+		return resource.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(s, prev) {
+		return nil
+	}
+
+	refs := make([]metav1.OwnerReference, 0, len(s.GetOwnerReferences()))
+	for _, r := range s.GetOwnerReferences() {
+		if r.UID != prev.GetUID() {
+			refs = append(refs, r)
+		}
+	}
+	refs = append(refs, meta.AsController(meta.TypedReferenceTo(cm, m.to)))
+	s.SetOwnerReferences(refs)
+	return m.client.Update(ctx, s)
+}
+
+// Migrated returns a composite resource claim of the supplied kind that is
+// migrated from the supplied claim of a previous kind. The migrated claim has
+// the name, namespace, labels, annotations, and spec of the previous claim.
+func Migrated(prev *claim.Unstructured, to schema.GroupVersionKind) *claim.Unstructured {
+	cm := claim.New(claim.WithGroupVersionKind(to))
+	cm.SetNamespace(prev.GetNamespace())
+	cm.SetName(prev.GetName())
+	cm.SetLabels(prev.GetLabels())
+	cm.SetAnnotations(prev.GetAnnotations())
+
+	// The last applied configuration is of the previous kind, so it would
+	// confuse kubectl apply.
+	meta.RemoveAnnotations(cm, corev1.LastAppliedConfigAnnotation)
+	meta.AddAnnotations(cm, map[string]string{xcrd.AnnotationKeyMigratedFrom: string(prev.GetUID())})
+
+	if spec, ok := prev.Object["spec"]; ok {
+		cm.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+	return cm
+}
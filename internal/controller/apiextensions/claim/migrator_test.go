@@ -0,0 +1,317 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestMigratorReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	from := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "DB"}
+	to := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"}
+	xrGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}
+
+	// prev returns a claim of the previous kind that is bound to a composite
+	// resource and writes a connection secret.
+	prev := func(o client.Object) {
+		cm := o.(*claim.Unstructured)
+		cm.SetName("cool-claim")
+		cm.SetNamespace("default")
+		cm.SetUID("prev-uid")
+		cm.SetResourceReference(&corev1.ObjectReference{APIVersion: xrGVK.GroupVersion().String(), Kind: xrGVK.Kind, Name: "cool-xr"})
+		cm.SetWriteConnectionSecretToReference(&xpv1.LocalSecretReference{Name: "conn"})
+	}
+	// xr returns a composite resource bound to the supplied kind of claim.
+	xr := func(o client.Object, kind, uid string) {
+		cp := o.(*composite.Unstructured)
+		cp.SetName("cool-xr")
+		cp.SetClaimReference(&corev1.ObjectReference{APIVersion: to.GroupVersion().String(), Kind: kind, Name: "cool-claim", Namespace: "default", UID: types.UID(uid)})
+	}
+	// secret returns a connection secret controlled by the supplied claim.
+	secret := func(o client.Object, kind, uid string) {
+		s := o.(*corev1.Secret)
+		s.SetName("conn")
+		s.SetNamespace("default")
+		s.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(&xpv1.TypedReference{APIVersion: to.GroupVersion().String(), Kind: kind, Name: "cool-claim", UID: types.UID(uid)})})
+	}
+
+	// withObjects returns a MockGetFn that gets a claim of the previous kind,
+	// a composite resource and a connection secret bound to it, and either a
+	// claim of the new kind migrated from the supplied UID or a not found
+	// error if the UID is empty.
+	withObjects := func(migratedFrom string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *claim.Unstructured:
+				if o.GetKind() == from.Kind {
+					prev(o)
+					return nil
+				}
+				if migratedFrom == "" {
+					return kerrors.NewNotFound(schema.GroupResource{}, "")
+				}
+				o.SetName("cool-claim")
+				o.SetUID("new-uid")
+				o.SetCreationTimestamp(metav1.Now())
+				meta.AddAnnotations(o, map[string]string{xcrd.AnnotationKeyMigratedFrom: migratedFrom})
+			case *composite.Unstructured:
+				xr(o, from.Kind, "prev-uid")
+			case *corev1.Secret:
+				secret(o, from.Kind, "prev-uid")
+			}
+			return nil
+		}
+	}
+	// created simulates the API server creating the migrated claim.
+	created := test.NewMockCreateFn(nil, func(obj client.Object) error {
+		obj.SetUID("new-uid")
+		obj.SetCreationTimestamp(metav1.Now())
+		return nil
+	})
+	noFinalizer := resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}
+
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		opts   []MigratorOption
+		want   want
+	}{
+		"PreviousNotFound": {
+			reason: "We should not return an error if the claim of the previous kind was not found, i.e. it was already migrated.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+		},
+		"GetPreviousError": {
+			reason: "We should return any error encountered while getting the claim of the previous kind.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPrevious),
+			},
+		},
+		"GetMigratedError": {
+			reason: "We should return any error encountered while getting the migrated claim.",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				if obj.GetObjectKind().GroupVersionKind() == to {
+					return errBoom
+				}
+				prev(obj)
+				return nil
+			}},
+			want: want{
+				err: errors.Wrap(errBoom, errGetMigrated),
+			},
+		},
+		"Conflict": {
+			reason: "We should not migrate a claim if a claim of the new kind with the same name was not migrated from it.",
+			client: &test.MockClient{
+				MockGet:    withObjects("some-other-uid"),
+				MockCreate: test.NewMockCreateFn(errBoom),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+				MockDelete: test.NewMockDeleteFn(errBoom),
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"CreateMigratedError": {
+			reason: "We should return any error encountered while creating the migrated claim.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: test.NewMockCreateFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateMigrated),
+			},
+		},
+		"BindError": {
+			reason: "We should return any error encountered while binding the composite resource to the migrated claim.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: created,
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errBindMigrated),
+			},
+		},
+		"TransferSecretError": {
+			reason: "We should return any error encountered while transferring the connection secret to the migrated claim.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: created,
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					if _, ok := obj.(*corev1.Secret); ok {
+						return errBoom
+					}
+					return nil
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errTransferSecret),
+			},
+		},
+		"RemoveFinalizerError": {
+			reason: "We should return any error encountered while removing the finalizer of the claim of the previous kind.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: created,
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			opts: []MigratorOption{WithMigratorFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return errBoom }})},
+			want: want{
+				err: errors.Wrap(errBoom, errRemoveFinalizer),
+			},
+		},
+		"DeletePreviousError": {
+			reason: "We should return any error encountered while deleting the claim of the previous kind.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: created,
+				MockUpdate: test.NewMockUpdateFn(nil),
+				MockDelete: test.NewMockDeleteFn(errBoom),
+			},
+			opts: []MigratorOption{WithMigratorFinalizer(noFinalizer)},
+			want: want{
+				err: errors.Wrap(errBoom, errDeletePrevious),
+			},
+		},
+		"Migrated": {
+			reason: "We should bind the composite resource and connection secret to the created claim, then delete the claim of the previous kind.",
+			client: &test.MockClient{
+				MockGet:    withObjects(""),
+				MockCreate: created,
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					switch o := obj.(type) {
+					case *composite.Unstructured:
+						want := composite.New()
+						xr(want, to.Kind, "new-uid")
+						if diff := cmp.Diff(want.GetClaimReference(), o.GetClaimReference()); diff != "" {
+							t.Errorf("Update(...): -want claim reference, +got claim reference:\n%s", diff)
+						}
+					case *corev1.Secret:
+						want := &corev1.Secret{}
+						secret(want, to.Kind, "new-uid")
+						if diff := cmp.Diff(want.GetOwnerReferences(), o.GetOwnerReferences()); diff != "" {
+							t.Errorf("Update(...): -want owner references, +got owner references:\n%s", diff)
+						}
+					}
+					return nil
+				},
+				MockDelete: test.NewMockDeleteFn(nil, func(obj client.Object) error {
+					if diff := cmp.Diff(from, obj.GetObjectKind().GroupVersionKind()); diff != "" {
+						t.Errorf("Delete(...): -want kind, +got kind:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+			opts: []MigratorOption{WithMigratorFinalizer(noFinalizer)},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"AlreadyMigrated": {
+			reason: "We should resume migrating a claim whose migrated claim was already created.",
+			client: &test.MockClient{
+				MockGet:    withObjects("prev-uid"),
+				MockCreate: test.NewMockCreateFn(errBoom),
+				MockUpdate: test.NewMockUpdateFn(nil),
+				MockDelete: test.NewMockDeleteFn(nil),
+			},
+			opts: []MigratorOption{WithMigratorFinalizer(noFinalizer)},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMigrator(&fake.Manager{}, resource.CompositeClaimKind(from), resource.CompositeClaimKind(to),
+				append([]MigratorOption{WithMigratorClient(tc.client), WithMigratorLogger(testLog)}, tc.opts...)...)
+			got, err := m.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nm.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nm.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMigrated(t *testing.T) {
+	to := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"}
+
+	prev := claim.New(claim.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "DB"}))
+	prev.SetName("cool-claim")
+	prev.SetNamespace("default")
+	prev.SetUID("prev-uid")
+	prev.SetResourceVersion("42")
+	prev.SetLabels(map[string]string{"cool": "label"})
+	prev.SetAnnotations(map[string]string{
+		"cool":                             "annotation",
+		corev1.LastAppliedConfigAnnotation: "{}",
+	})
+	prev.SetCompositionReference(&corev1.ObjectReference{Name: "cool-composition"})
+
+	want := claim.New(claim.WithGroupVersionKind(to))
+	want.SetName("cool-claim")
+	want.SetNamespace("default")
+	want.SetLabels(map[string]string{"cool": "label"})
+	want.SetAnnotations(map[string]string{
+		"cool":                         "annotation",
+		xcrd.AnnotationKeyMigratedFrom: "prev-uid",
+	})
+	want.SetCompositionReference(&corev1.ObjectReference{Name: "cool-composition"})
+
+	got := Migrated(prev, to)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Migrated(...): -want, +got:\n%s", diff)
+	}
+}
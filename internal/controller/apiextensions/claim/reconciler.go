@@ -39,6 +39,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	xr "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 const (
@@ -64,6 +67,7 @@ const (
 	errApplyComposite     = "cannot apply composite resource"
 	errConfigureClaim     = "cannot configure composite resource claim"
 	errPropagateCDs       = "cannot propagate connection details from composite"
+	errAdoptRestored      = "cannot adopt restored composite resource"
 
 	errUpdateClaimStatus = "cannot update composite resource claim status"
 )
@@ -182,6 +186,8 @@ type Reconciler struct {
 
 	log    logging.Logger
 	record event.Recorder
+
+	restore bool
 }
 
 type crComposite struct {
@@ -285,6 +291,16 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithRestoreMode configures the Reconciler to re-bind claims that were
+// restored from a backup to their composite resources, rather than creating
+// new composite resources. Claims annotated as restored are treated this way
+// regardless.
+func WithRestoreMode() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.restore = true
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles composite resource claims of
 // the supplied CompositeClaimKind with resources of the supplied CompositeKind.
 // The returned Reconciler will apply only the ObjectMetaConfigurator by
@@ -317,7 +333,7 @@ func NewReconciler(m manager.Manager, of resource.CompositeClaimKind, with resou
 }
 
 // Reconcile a composite resource claim with a concrete composite resource.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { // nolint:gocyclo
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) { // nolint:gocyclo
 	// NOTE(negz): This method is well over our cyclomatic complexity goal.
 	// Be wary of adding additional complexity.
 
@@ -342,6 +358,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"external-name", meta.GetExternalName(cm),
 	)
 
+	// Tell folks when we can't reconcile the claim, so that they (and tools
+	// like kubectl wait) can tell whether it's synced. This update is best
+	// effort; we'll try again when we're requeued.
+	defer func() {
+		if err == nil {
+			return
+		}
+		cm.SetConditions(xpv1.ReconcileError(err))
+		_ = r.client.Status().Update(ctx, cm)
+	}()
+
 	cp := r.newComposite()
 	if ref := cm.GetResourceReference(); ref != nil {
 		record = record.WithAnnotations("composite-name", cm.GetResourceReference().Name)
@@ -350,7 +377,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.client.Get(ctx, meta.NamespacedNameOf(ref), cp); resource.IgnoreNotFound(err) != nil {
 			log.Debug(errGetComposite, "error", err)
 			err = errors.Wrap(err, errGetComposite)
-			record.Event(cm, event.Warning(reasonBind, err))
+			record.Event(cm, reason.Warning(reasonBind, err))
 			return reconcile.Result{}, err
 		}
 	}
@@ -410,14 +437,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if err := r.claim.AddFinalizer(ctx, cm); err != nil {
 		log.Debug(errAddFinalizer, "error", err)
 		err = errors.Wrap(err, errAddFinalizer)
-		record.Event(cm, event.Warning(reasonBind, err))
+		record.Event(cm, reason.Warning(reasonBind, err))
 		return reconcile.Result{}, err
 	}
 
+	if r.restore || IsRestored(cm) {
+		if err := AdoptRestored(ctx, r.client, cm, cp); err != nil {
+			log.Debug(errAdoptRestored, "error", err)
+			err = errors.Wrap(err, errAdoptRestored)
+			record.Event(cm, reason.Warning(reasonBind, err))
+			return reconcile.Result{}, err
+		}
+	}
+
 	if err := r.composite.Configure(ctx, cm, cp); err != nil {
 		log.Debug(errConfigureComposite, "error", err)
 		err = errors.Wrap(err, errConfigureComposite)
-		record.Event(cm, event.Warning(reasonCompositeConfigure, err))
+		record.Event(cm, reason.Warning(reasonCompositeConfigure, err))
 		return reconcile.Result{}, err
 	}
 
@@ -436,14 +472,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if err := r.claim.Bind(ctx, cm, cp); err != nil {
 		log.Debug(errBindComposite, "error", err)
 		err = errors.Wrap(err, errBindComposite)
-		record.Event(cm, event.Warning(reasonBind, err))
+		record.Event(cm, reason.Warning(reasonBind, err))
 		return reconcile.Result{}, err
 	}
 
 	if err := r.client.Apply(ctx, cp); err != nil {
 		log.Debug(errApplyComposite, "error", err)
-		err = errors.Wrap(err, errApplyComposite)
-		record.Event(cm, event.Warning(reasonCompositeConfigure, err))
+		err = errors.Wrap(reason.WrapAPIError(err, reason.ApplyFailed), errApplyComposite)
+		record.Event(cm, reason.Warning(reasonCompositeConfigure, err))
 		return reconcile.Result{}, err
 	}
 
@@ -457,13 +493,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Tell folks when the composite resource has taken longer to become
+	// ready than its definition expects, and when it recovers.
+	if c := cp.GetCondition(xr.TypeReadyTimeoutExceeded); c.Status != corev1.ConditionUnknown {
+		if c.Status == corev1.ConditionTrue && !resource.IsConditionTrue(cm.GetCondition(c.Type)) {
+			record.Event(cm, event.Warning(reasonBind, errors.New(c.Message)))
+		}
+		cm.SetConditions(c)
+	}
+
 	if !resource.IsConditionTrue(cp.GetCondition(xpv1.TypeReady)) {
 		log.Debug("Composite resource is not yet ready")
 		record.Event(cm, event.Normal(reasonBind, "Composite resource is not yet ready"))
 
 		// We should be watching the composite resource and will have a
 		// request queued if it changes, so no need to requeue.
-		cm.SetConditions(Waiting())
+		cm.SetConditions(Waiting(), xpv1.ReconcileSuccess())
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -485,7 +530,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	// We have a watch on both the claim and its composite, so there's no
 	// need to requeue here.
-	cm.SetConditions(xpv1.Available())
+	cm.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 }
 
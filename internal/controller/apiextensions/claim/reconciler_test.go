@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,6 +40,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	xr "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
 
 func TestReconcile(t *testing.T) {
@@ -95,6 +98,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 				},
@@ -123,6 +127,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithClaimFinalizer(resource.FinalizerFns{
@@ -154,7 +159,8 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(errBoom),
+							MockDelete:       test.NewMockDeleteFn(errBoom),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 				},
@@ -183,7 +189,8 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(errBoom),
+							MockDelete:       test.NewMockDeleteFn(errBoom),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 				},
@@ -207,7 +214,8 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(nil),
+							MockDelete:       test.NewMockDeleteFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithClaimFinalizer(resource.FinalizerFns{
@@ -234,7 +242,8 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockDelete: test.NewMockDeleteFn(nil),
+							MockDelete:       test.NewMockDeleteFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithClaimFinalizer(resource.FinalizerFns{
@@ -259,6 +268,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithClaimFinalizer(resource.FinalizerFns{
@@ -283,6 +293,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithClaimFinalizer(resource.FinalizerFns{
@@ -335,6 +346,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
 							return errBoom
@@ -413,8 +425,48 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"CompositeReadyTimeoutExceeded": {
+			reason: "We should tell the claim when the bound composite resource has exceeded its ready timeout",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								switch o := obj.(type) {
+								case *claim.Unstructured:
+									o.SetResourceReference(&corev1.ObjectReference{})
+								case *composite.Unstructured:
+									o.SetConditions(xr.ReadyTimeoutExceeded("too slow"))
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								got := obj.(resource.CompositeClaim).GetCondition(xr.TypeReadyTimeoutExceeded)
+								if diff := cmp.Diff(xr.ReadyTimeoutExceeded("too slow"), got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(ctx context.Context, obj resource.Object) error { return nil },
+					}),
+					WithCompositeConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return nil })),
+					WithBinder(BinderFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return nil })),
+					WithClaimConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"PropagateConnectionError": {
-			reason: "We should return any error we encounter while propagating the bound composite's connection details",
+			reason: "We should return any error we encounter while propagating the bound composite's connection details, and report that the claim is not synced",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -429,7 +481,13 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								got := obj.(resource.CompositeClaim).GetCondition(xpv1.TypeSynced)
+								if diff := cmp.Diff(xpv1.ReconcileError(errors.Wrap(errBoom, errPropagateCDs)), got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
 							return nil
@@ -451,7 +509,7 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 		"SuccessfulPropagate": {
-			reason: "We should not requeue if we successfully applied the composite resource and propagated its connection details",
+			reason: "We should report that the claim is synced, and not requeue, if we successfully applied the composite resource and propagated its connection details",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -466,7 +524,13 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
-							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								got := obj.(resource.CompositeClaim).GetCondition(xpv1.TypeSynced)
+								if diff := cmp.Diff(xpv1.ReconcileSuccess(), got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
 							return nil
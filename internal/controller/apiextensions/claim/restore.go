@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errListRestored = "cannot list composite resources restored for claim"
+	errGetRestored  = "cannot get composite resource restored for claim"
+)
+
+// IsRestored returns true if the supplied claim was annotated to indicate that
+// it was restored from a backup.
+func IsRestored(cm resource.CompositeClaim) bool {
+	return cm.GetAnnotations()[xcrd.AnnotationKeyRestored] == "true"
+}
+
+// AdoptRestored prepares the supplied composite resource, which does not yet
+// exist, to be bound to the supplied restored claim. If the claim references a
+// composite resource that has not been restored (yet) the composite resource
+// is given the referenced name, so that it may adopt any restored composed
+// resources. If the claim references no composite resource, but a composite
+// resource that references the claim was restored, the supplied composite
+// resource is replaced with the restored one. Composite resources are
+// identified by their claim name and namespace labels, which are preserved
+// when they are restored from a backup.
+func AdoptRestored(ctx context.Context, c client.Reader, cm resource.CompositeClaim, cp resource.Composite) error {
+	if meta.WasCreated(cp) {
+		return nil
+	}
+
+	if ref := cm.GetResourceReference(); ref != nil {
+		cp.SetName(ref.Name)
+		return nil
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(cp.GetObjectKind().GroupVersionKind().GroupVersion().WithKind(cp.GetObjectKind().GroupVersionKind().Kind + "List"))
+	if err := c.List(ctx, l, client.MatchingLabels{
+		xcrd.LabelKeyClaimName:      cm.GetName(),
+		xcrd.LabelKeyClaimNamespace: cm.GetNamespace(),
+	}); err != nil {
+		return errors.Wrap(err, errListRestored)
+	}
+
+	for i := range l.Items {
+		xr := &composite.Unstructured{Unstructured: l.Items[i]}
+		ref := xr.GetClaimReference()
+		if ref == nil || ref.Name != cm.GetName() || ref.Namespace != cm.GetNamespace() {
+			continue
+		}
+		return errors.Wrap(c.Get(ctx, types.NamespacedName{Name: xr.GetName()}, cp), errGetRestored)
+	}
+	return nil
+}
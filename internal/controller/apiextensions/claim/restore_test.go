@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAdoptRestored(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	cm := func(ref *corev1.ObjectReference) resource.CompositeClaim {
+		cm := claim.New()
+		cm.SetName("cool-claim")
+		cm.SetNamespace("default")
+		if ref != nil {
+			cm.SetResourceReference(ref)
+		}
+		return cm
+	}
+
+	// withXRs returns a MockListFn that lists composite resources bound to
+	// the supplied claims.
+	withXRs := func(claims ...string) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			l := obj.(*kunstructured.UnstructuredList)
+			for _, name := range claims {
+				xr := composite.New()
+				xr.SetName(name + "-xr")
+				xr.SetClaimReference(&corev1.ObjectReference{Name: name, Namespace: "default"})
+				l.Items = append(l.Items, xr.Unstructured)
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Reader
+		cm     resource.CompositeClaim
+		cp     resource.Composite
+	}
+	type want struct {
+		cp  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CompositeExists": {
+			reason: "We should not change a composite resource that exists.",
+			args: args{
+				cm: cm(nil),
+				cp: func() resource.Composite {
+					cp := composite.New()
+					cp.SetCreationTimestamp(now)
+					return cp
+				}(),
+			},
+			want: want{
+				cp: func() resource.Composite {
+					cp := composite.New()
+					cp.SetCreationTimestamp(now)
+					return cp
+				}(),
+			},
+		},
+		"ReferencedCompositeNotRestored": {
+			reason: "We should give a composite resource that has not been restored the name the claim references.",
+			args: args{
+				cm: cm(&corev1.ObjectReference{Name: "cool-xr"}),
+				cp: composite.New(),
+			},
+			want: want{
+				cp: func() resource.Composite {
+					cp := composite.New()
+					cp.SetName("cool-xr")
+					return cp
+				}(),
+			},
+		},
+		"RestoredComposite": {
+			reason: "We should get a restored composite resource that references the claim.",
+			args: args{
+				client: &test.MockClient{
+					MockList: withXRs("other-claim", "cool-claim"),
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff("cool-claim-xr", key.Name); diff != "" {
+							t.Errorf("Get(...): -want name, +got name:\n%s", diff)
+						}
+						obj.SetName(key.Name)
+						return nil
+					},
+				},
+				cm: cm(nil),
+				cp: composite.New(),
+			},
+			want: want{
+				cp: func() resource.Composite {
+					cp := composite.New()
+					cp.SetName("cool-claim-xr")
+					return cp
+				}(),
+			},
+		},
+		"NoRestoredComposite": {
+			reason: "We should not change the composite resource if none references the claim.",
+			args: args{
+				client: &test.MockClient{MockList: withXRs("other-claim")},
+				cm:     cm(nil),
+				cp:     composite.New(),
+			},
+			want: want{
+				cp: composite.New(),
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing composite resources.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cm:     cm(nil),
+				cp:     composite.New(),
+			},
+			want: want{
+				cp:  composite.New(),
+				err: errors.Wrap(errBoom, errListRestored),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := AdoptRestored(context.Background(), tc.args.client, tc.args.cm, tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAdoptRestored(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nAdoptRestored(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
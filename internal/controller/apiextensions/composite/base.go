@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errGetBaseConfigMap = "cannot get base resource ConfigMap"
+	errParseBase        = "cannot parse base resource"
+	errUnmarshalBase    = "cannot unmarshal base resource"
+
+	errFmtResolveBase     = "cannot resolve base resource of resource template at index %d"
+	errFmtBaseKeyNotFound = "ConfigMap %s/%s has no key %q"
+	errFmtBaseNamespace   = "cannot read ConfigMap %s/%s: base resources may only be read from ConfigMaps in namespace %q"
+	errFmtBaseMismatch    = "ConfigMap contains a %s %s, but the resource template's base is a %s %s"
+)
+
+// A BaseResolver resolves the base resources of composed templates.
+type BaseResolver interface {
+	ResolveBases(ctx context.Context, ct []v1.ComposedTemplate) ([]v1.ComposedTemplate, error)
+}
+
+// A BaseResolverFn resolves the base resources of composed templates.
+type BaseResolverFn func(ctx context.Context, ct []v1.ComposedTemplate) ([]v1.ComposedTemplate, error)
+
+// ResolveBases of the supplied composed templates.
+func (fn BaseResolverFn) ResolveBases(ctx context.Context, ct []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
+	return fn(ctx, ct)
+}
+
+// An APIBaseResolver resolves base resources stored in ConfigMaps.
+type APIBaseResolver struct {
+	client    client.Reader
+	namespace string
+}
+
+// NewAPIBaseResolver returns a BaseResolver that reads base resources from
+// ConfigMaps in the supplied namespace. ConfigMaps in any other namespace are
+// never read, so that a Composition can't be used to read arbitrary
+// ConfigMaps.
+func NewAPIBaseResolver(c client.Reader, namespace string) *APIBaseResolver {
+	return &APIBaseResolver{client: c, namespace: namespace}
+}
+
+// ResolveBases replaces the base of each template that is read from a ConfigMap
+// with the resource stored in that ConfigMap. The stored resource must be of
+// the same apiVersion and kind as the template's inline base. The supplied
+// templates are not modified.
+func (r *APIBaseResolver) ResolveBases(ctx context.Context, ct []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
+	out := make([]v1.ComposedTemplate, len(ct))
+	for i := range ct {
+		out[i] = ct[i]
+		if ct[i].BaseFrom == nil {
+			continue
+		}
+		base, err := r.resolve(ctx, ct[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtResolveBase, i)
+		}
+		out[i].Base = base
+	}
+	return out, nil
+}
+
+func (r *APIBaseResolver) resolve(ctx context.Context, t v1.ComposedTemplate) (runtime.RawExtension, error) {
+	ref := t.BaseFrom.ConfigMapKeyRef
+	if ref.Namespace != r.namespace {
+		return runtime.RawExtension{}, errors.Errorf(errFmtBaseNamespace, ref.Namespace, ref.Name, r.namespace)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return runtime.RawExtension{}, errors.Wrap(err, errGetBaseConfigMap)
+	}
+
+	data, ok := cm.Data[ref.Key]
+	if !ok {
+		return runtime.RawExtension{}, errors.Errorf(errFmtBaseKeyNotFound, ref.Namespace, ref.Name, ref.Key)
+	}
+
+	// ConfigMaps typically store YAML, which is a superset of JSON.
+	raw, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return runtime.RawExtension{}, errors.Wrap(err, errParseBase)
+	}
+
+	got, want := &metav1.TypeMeta{}, &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, got); err != nil {
+		return runtime.RawExtension{}, errors.Wrap(err, errParseBase)
+	}
+	if err := json.Unmarshal(t.Base.Raw, want); err != nil {
+		return runtime.RawExtension{}, errors.Wrap(err, errUnmarshalBase)
+	}
+	if got.APIVersion != want.APIVersion || got.Kind != want.Kind {
+		return runtime.RawExtension{}, errors.Errorf(errFmtBaseMismatch, got.APIVersion, got.Kind, want.APIVersion, want.Kind)
+	}
+
+	return runtime.RawExtension{Raw: raw}, nil
+}
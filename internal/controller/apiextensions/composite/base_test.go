@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestResolveBases(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	inline := runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)}
+	ref := &v1.BaseSource{ConfigMapKeyRef: v1.ConfigMapKeySelector{Name: "cool-cm", Namespace: "cool-ns", Key: "bucket"}}
+
+	withData := func(data map[string]string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.ConfigMap).Data = data
+			return nil
+		})
+	}
+
+	type args struct {
+		c  client.Reader
+		ct []v1.ComposedTemplate
+	}
+	type want struct {
+		ct  []v1.ComposedTemplate
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InlineBase": {
+			reason: "Templates that don't read their base from a ConfigMap should be returned unchanged.",
+			args: args{
+				ct: []v1.ComposedTemplate{{Base: inline}},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{{Base: inline}},
+			},
+		},
+		"NamespaceNotAllowed": {
+			reason: "We should return an error if the ConfigMap is not in the allowed namespace.",
+			args: args{
+				ct: []v1.ComposedTemplate{{Base: inline, BaseFrom: &v1.BaseSource{ConfigMapKeyRef: v1.ConfigMapKeySelector{Name: "cool-cm", Namespace: "kube-system", Key: "bucket"}}}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtBaseNamespace, "kube-system", "cool-cm", "cool-ns"), errFmtResolveBase, 0),
+			},
+		},
+		"GetConfigMapError": {
+			reason: "We should return any error encountered getting a ConfigMap.",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				ct: []v1.ComposedTemplate{{Base: inline, BaseFrom: ref}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errGetBaseConfigMap), errFmtResolveBase, 0),
+			},
+		},
+		"KeyNotFound": {
+			reason: "We should return an error if the ConfigMap does not contain the referenced key.",
+			args: args{
+				c:  &test.MockClient{MockGet: withData(map[string]string{"other": ""})},
+				ct: []v1.ComposedTemplate{{Base: inline, BaseFrom: ref}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtBaseKeyNotFound, "cool-ns", "cool-cm", "bucket"), errFmtResolveBase, 0),
+			},
+		},
+		"KindMismatch": {
+			reason: "We should return an error if the ConfigMap contains a different kind of resource than the inline base.",
+			args: args{
+				c:  &test.MockClient{MockGet: withData(map[string]string{"bucket": "apiVersion: example.org/v1\nkind: Database\n"})},
+				ct: []v1.ComposedTemplate{{Base: inline, BaseFrom: ref}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtBaseMismatch, "example.org/v1", "Database", "example.org/v1", "Bucket"), errFmtResolveBase, 0),
+			},
+		},
+		"Resolved": {
+			reason: "We should replace the base of a template with the resource read from its ConfigMap.",
+			args: args{
+				c: &test.MockClient{MockGet: withData(map[string]string{"bucket": "apiVersion: example.org/v1\nkind: Bucket\nspec:\n  region: us-east-1\n"})},
+				ct: []v1.ComposedTemplate{
+					{Base: inline},
+					{Base: inline, BaseFrom: ref},
+				},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{
+					{Base: inline},
+					{
+						Base:     runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket","spec":{"region":"us-east-1"}}`)},
+						BaseFrom: ref,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIBaseResolver(tc.args.c, "cool-ns")
+			got, err := r.ResolveBases(context.Background(), tc.args.ct)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ResolveBases(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ct, got); diff != "" {
+				t.Errorf("\n%s\nr.ResolveBases(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/denylist"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -54,8 +55,10 @@ const (
 	errNamePrefix  = "name prefix is not found in labels"
 	errKindChanged = "cannot change the kind of an existing composed resource"
 	errName        = "cannot use dry-run create to name composed resource"
+	errObserveName = "the name of an observe only composed resource must be specified by its base or patches"
 
 	errFmtPatch          = "cannot apply the patch at index %d"
+	errFmtDeniedKind     = "Compositions may not compose %s resources (denied by %q)"
 	errFmtConnDetailKey  = "connection detail of type %q key is not set"
 	errFmtConnDetailVal  = "connection detail of type %q value is not set"
 	errFmtConnDetailPath = "connection detail of type %q fromFieldPath is not set"
@@ -66,6 +69,12 @@ const (
 	AnnotationKeyCompositionResourceName = "crossplane.io/composition-resource-name"
 )
 
+// IsObserveOnly returns true if the composed resource rendered from the
+// supplied template should only be observed, not managed.
+func IsObserveOnly(t v1.ComposedTemplate) bool {
+	return t.ManagementPolicy != nil && *t.ManagementPolicy == v1.ManagementPolicyObserveOnly
+}
+
 // SetCompositionResourceName sets the name of the composition template used to
 // reconcile a composed resource as an annotation.
 func SetCompositionResourceName(o metav1.Object, name string) {
@@ -249,6 +258,14 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 		}
 
 		name := GetCompositionResourceName(cd)
+
+		// Observe only composed resources are never annotated with the name
+		// of their template, and are never garbage collected. Unlike resources
+		// that were composed before we annotated them, nothing controls them.
+		if name == "" && metav1.GetControllerOf(cd) == nil {
+			continue
+		}
+
 		if name == "" {
 			// All of our templates are named, but this existing composed
 			// resource is not associated with a named template. It's likely
@@ -302,14 +319,40 @@ func (c RenderFn) Render(cp resource.Composite, cd resource.Composed, t v1.Compo
 // create against an API server in order to name and validate the rendered
 // resource.
 type APIDryRunRenderer struct {
-	client client.Client
+	client   client.Client
+	metadata *v1.MetadataFilter
+	denied   denylist.List
+}
+
+// An APIDryRunRendererOption configures an APIDryRunRenderer.
+type APIDryRunRendererOption func(*APIDryRunRenderer)
+
+// WithPropagatedMetadata specifies which labels and annotations propagate
+// from a composite resource to its composed resources. No labels or
+// annotations propagate by default.
+func WithPropagatedMetadata(f *v1.MetadataFilter) APIDryRunRendererOption {
+	return func(r *APIDryRunRenderer) {
+		r.metadata = f
+	}
+}
+
+// WithDeniedKinds specifies kinds of resource that may not be composed.
+// Rendering a composed resource of a denied kind returns an error.
+func WithDeniedKinds(l denylist.List) APIDryRunRendererOption {
+	return func(r *APIDryRunRenderer) {
+		r.denied = l
+	}
 }
 
 // NewAPIDryRunRenderer returns a Renderer of composed resources that may
 // perform a dry-run create against an API server in order to name and validate
 // it.
-func NewAPIDryRunRenderer(c client.Client) *APIDryRunRenderer {
-	return &APIDryRunRenderer{client: c}
+func NewAPIDryRunRenderer(c client.Client, o ...APIDryRunRendererOption) *APIDryRunRenderer {
+	r := &APIDryRunRenderer{client: c}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
 }
 
 // Render the supplied composed resource using the supplied composite resource
@@ -332,6 +375,21 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 		return errors.New(errKindChanged)
 	}
 
+	// An observe only composed resource already exists, so it must be named
+	// by its template rather than by us. We never create it, so we don't
+	// label it or take ownership of it.
+	if IsObserveOnly(t) {
+		for i := range t.Patches {
+			if err := t.Patches[i].Apply(cp, cd, patchTypesFromXR()...); err != nil {
+				return errors.Wrapf(err, errFmtPatch, i)
+			}
+		}
+		if cd.GetName() == "" {
+			return errors.New(errObserveName)
+		}
+		return r.allowed(cd)
+	}
+
 	if cp.GetLabels()[xcrd.LabelKeyNamePrefixForComposed] == "" {
 		return errors.New(errNamePrefix)
 	}
@@ -343,18 +401,45 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	cd.SetName(name)
 	cd.SetNamespace(namespace)
 
+	// Propagated labels and annotations are rendered before patches are
+	// applied, so that patches may override them.
+	if r.metadata != nil {
+		meta.AddLabels(cd, r.metadata.Labels.Select(cp.GetLabels()))
+		meta.AddAnnotations(cd, r.metadata.Annotations.Select(withoutReservedAnnotations(cp.GetAnnotations())))
+	}
+
 	for i := range t.Patches {
+		// A composed resource adopts the external resource its external name
+		// identifies when it's created. Patching its external name once it
+		// exists could make it manage a different external resource, so we
+		// only patch the external name of a composed resource we haven't yet
+		// named (i.e. created).
+		if t.Patches[i].Type == v1.PatchTypeExternalNameFromComposite && name != "" {
+			continue
+		}
 		if err := t.Patches[i].Apply(cp, cd, patchTypesFromXR()...); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
 		}
 	}
 
+	// We check whether this kind of resource may be composed after patches
+	// are applied, because patches may change its namespace.
+	if err := r.allowed(cd); err != nil {
+		return err
+	}
+
 	// Composed labels and annotations should be rendered after patches are applied
 	meta.AddLabels(cd, map[string]string{
 		xcrd.LabelKeyNamePrefixForComposed: cp.GetLabels()[xcrd.LabelKeyNamePrefixForComposed],
 		xcrd.LabelKeyClaimName:             cp.GetLabels()[xcrd.LabelKeyClaimName],
 		xcrd.LabelKeyClaimNamespace:        cp.GetLabels()[xcrd.LabelKeyClaimNamespace],
 	})
+	if u := cp.GetAnnotations()[xcrd.AnnotationKeyClaimRequester]; u != "" {
+		meta.AddAnnotations(cd, map[string]string{xcrd.AnnotationKeyClaimRequester: u})
+	}
+	if l := cp.GetLabels()[xcrd.LabelKeyClaimRequester]; l != "" {
+		meta.AddLabels(cd, map[string]string{xcrd.LabelKeyClaimRequester: l})
+	}
 
 	if t.Name != nil {
 		SetCompositionResourceName(cd, *t.Name)
@@ -383,6 +468,31 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	return errors.Wrap(r.client.Create(ctx, cd, client.DryRunAll), errName)
 }
 
+// allowed returns an error if the supplied composed resource is of a kind that
+// may not be composed.
+func (r *APIDryRunRenderer) allowed(cd resource.Composed) error {
+	gk := cd.GetObjectKind().GroupVersionKind().GroupKind()
+	if rule, ok := r.denied.Denies(gk, cd.GetNamespace()); ok {
+		return errors.Errorf(errFmtDeniedKind, gk, rule)
+	}
+	return nil
+}
+
+// withoutReservedAnnotations returns the supplied annotations, less any that
+// must never propagate from a composite resource to its composed resources
+// because they are specific to the composite resource.
+func withoutReservedAnnotations(a map[string]string) map[string]string {
+	out := make(map[string]string, len(a))
+	for k, v := range a {
+		switch k {
+		case meta.AnnotationKeyExternalName, AnnotationKeyCompositionResourceName, corev1.LastAppliedConfigAnnotation:
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
 // RenderComposite renders the supplied composite resource using the supplied composed
 // resource and template.
 func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
@@ -33,12 +33,14 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/denylist"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -169,6 +171,18 @@ func TestRejectDuplicateNames(t *testing.T) {
 func TestRender(t *testing.T) {
 	ctrl := true
 	tmpl, _ := json.Marshal(&fake.Managed{})
+	named, _ := json.Marshal(&fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "shared"}})
+	observe := v1.ManagementPolicyObserveOnly
+	secret := []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"shared","namespace":"kube-system"}}`)
+	deny, _ := denylist.Parse([]string{"kube-system/Secret"})
+	now := metav1.Now()
+	lpt := fake.ConnectionDetailsLastPublishedTimer{Time: &now}
+	withSecret := func(name, namespace, generateName string) *composed.Unstructured {
+		cd := composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: namespace}))
+		cd.SetName(name)
+		cd.SetGenerateName(generateName)
+		return cd
+	}
 
 	type args struct {
 		ctx context.Context
@@ -183,6 +197,7 @@ func TestRender(t *testing.T) {
 	cases := map[string]struct {
 		reason string
 		client client.Client
+		o      []APIDryRunRendererOption
 		args
 		want
 	}{
@@ -234,6 +249,61 @@ func TestRender(t *testing.T) {
 				err: errors.Wrap(errBoom, errName),
 			},
 		},
+		"ObserveOnlyUnnamed": {
+			reason: "An observe only composed resource must be named by its template",
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+				}}},
+				cd: &fake.Composed{},
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: tmpl}, ManagementPolicy: &observe},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.New(errObserveName),
+			},
+		},
+		"ObserveOnly": {
+			reason: "An observe only composed resource should be named by its template, and neither labelled nor owned",
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+				}}},
+				cd: &fake.Composed{},
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: named}, ManagementPolicy: &observe},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+			},
+		},
+		"ObserveOnlyDeniedKind": {
+			reason: "We should return an error if an observe only composed resource is of a denied kind",
+			o:      []APIDryRunRendererOption{WithDeniedKinds(deny)},
+			args: args{
+				cp: &fake.Composite{},
+				cd: composed.New(),
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: secret}, ManagementPolicy: &observe},
+			},
+			want: want{
+				cd:  withSecret("shared", "kube-system", ""),
+				err: errors.Errorf(errFmtDeniedKind, "Secret", "kube-system/Secret"),
+			},
+		},
+		"DeniedKind": {
+			reason: "We should return an error if a composed resource is of a kind that is denied in its namespace",
+			o:      []APIDryRunRendererOption{WithDeniedKinds(deny)},
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+				}}},
+				cd: withSecret("", "kube-system", ""),
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: secret}},
+			},
+			want: want{
+				cd:  withSecret("", "kube-system", "ola-"),
+				err: errors.Errorf(errFmtDeniedKind, "Secret", "kube-system/Secret"),
+			},
+		},
 		"Success": {
 			reason: "Configuration should result in the right object with correct generateName",
 			client: &test.MockClient{MockCreate: test.NewMockCreateFn(nil)},
@@ -259,10 +329,143 @@ func TestRender(t *testing.T) {
 				}},
 			},
 		},
+		"PropagatedMetadata": {
+			reason: "The composite resource's labels and annotations selected by the propagation filter should be propagated, except those reserved for the composite resource",
+			client: &test.MockClient{MockCreate: test.NewMockCreateFn(nil)},
+			o: []APIDryRunRendererOption{WithPropagatedMetadata(&v1.MetadataFilter{
+				Labels:      &v1.KeyPrefixFilter{Include: []string{"example.org/"}},
+				Annotations: &v1.KeyPrefixFilter{},
+			})},
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						"example.org/team":                 "platform",
+						"app":                              "cool",
+					},
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName: "ola",
+						"example.org/owner":            "me",
+					},
+				}},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: tmpl}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name:         "cd",
+					GenerateName: "ola-",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+						"example.org/team":                 "platform",
+					},
+					Annotations: map[string]string{
+						"example.org/owner": "me",
+					},
+					OwnerReferences: []metav1.OwnerReference{{Controller: &ctrl}},
+				}},
+			},
+		},
+		"PropagatedRequester": {
+			reason: "The claim requester should always be propagated from the composite resource",
+			client: &test.MockClient{MockCreate: test.NewMockCreateFn(nil)},
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimRequester:        "alice_example.org",
+					},
+					Annotations: map[string]string{
+						xcrd.AnnotationKeyClaimRequester: "alice@example.org",
+					},
+				}},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				t:  v1.ComposedTemplate{Base: runtime.RawExtension{Raw: tmpl}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name:         "cd",
+					GenerateName: "ola-",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+						xcrd.LabelKeyClaimRequester:        "alice_example.org",
+					},
+					Annotations: map[string]string{
+						xcrd.AnnotationKeyClaimRequester: "alice@example.org",
+					},
+					OwnerReferences: []metav1.OwnerReference{{Controller: &ctrl}},
+				}},
+			},
+		},
+		"ExternalNameOnCreation": {
+			reason: "An ExternalNameFromComposite patch should set the external name of a composed resource that doesn't yet exist",
+			client: &test.MockClient{MockCreate: test.NewMockCreateFn(nil)},
+			args: args{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						"id":                               "existing",
+					}},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{},
+				t: v1.ComposedTemplate{
+					Base:    runtime.RawExtension{Raw: tmpl},
+					Patches: []v1.Patch{{Type: v1.PatchTypeExternalNameFromComposite, FromFieldPath: pointer.String("objectMeta.labels.id")}},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "ola-",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+					},
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName: "existing",
+					},
+					OwnerReferences: []metav1.OwnerReference{{Controller: &ctrl}},
+				}},
+			},
+		},
+		"ExternalNameAfterCreation": {
+			reason: "An ExternalNameFromComposite patch should not change the external name of a composed resource that already exists",
+			args: args{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						"id":                               "existing",
+					}},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				t: v1.ComposedTemplate{
+					Base:    runtime.RawExtension{Raw: tmpl},
+					Patches: []v1.Patch{{Type: v1.PatchTypeExternalNameFromComposite, FromFieldPath: pointer.String("objectMeta.labels.id")}},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name:         "cd",
+					GenerateName: "ola-",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+					},
+					OwnerReferences: []metav1.OwnerReference{{Controller: &ctrl}},
+				}},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewAPIDryRunRenderer(tc.client)
+			r := NewAPIDryRunRenderer(tc.client, tc.o...)
 			err := r.Render(tc.args.ctx, tc.args.cp, tc.args.cd, tc.args.t)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nRender(...): -want, +got:\n%s", tc.reason, diff)
@@ -397,8 +600,15 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 		"AnonymousResource": {
 			reason: "We should fall back to associating templates with references by order if any resource is not annotated with its template name.",
 			c: &test.MockClient{
-				// Return an empty (and thus unannotated) composed resource.
-				MockGet: test.NewMockGetFn(nil),
+				// Return an unannotated composed resource that we control.
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					ctrl := true
+					obj.SetOwnerReferences([]metav1.OwnerReference{{
+						Controller: &ctrl,
+						UID:        types.UID("very-unique"),
+					}})
+					return nil
+				}),
 			},
 			args: args{
 				cr: &fake.Composite{
@@ -410,6 +620,23 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0, Reference: r0}},
 			},
 		},
+		"ObservedResource": {
+			reason: "We should neither associate nor garbage collect an observe only composed resource.",
+			c: &test.MockClient{
+				// Return an unannotated composed resource that nothing controls.
+				MockGet:    test.NewMockGetFn(nil),
+				MockDelete: test.NewMockDeleteFn(errBoom),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
 		"AssociatedResource": {
 			reason: "We should associate referenced resources by their template name annotation.",
 			c: &test.MockClient{
@@ -25,5 +25,5 @@ func patchTypesToXR() []v1.PatchType {
 
 // Returns types of patches that are _from_ a composite resource to a composed resource.
 func patchTypesFromXR() []v1.PatchType {
-	return []v1.PatchType{v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite}
+	return []v1.PatchType{v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, v1.PatchTypeTemplateFromComposite, v1.PatchTypeExternalNameFromComposite}
 }
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const msgFmtWaitCRD = "Waiting for the CustomResourceDefinitions of composed resource kinds to be established: %s"
+
+// TypeWaitingForCRD resources compose kinds of resource whose
+// CustomResourceDefinitions are not yet established, for example because the
+// provider that defines them is still being installed.
+const TypeWaitingForCRD xpv1.ConditionType = "WaitingForCRD"
+
+// Reasons a composite resource is or is not waiting for CRDs.
+const (
+	ReasonComposedKindNotEstablished xpv1.ConditionReason = "ComposedKindNotEstablished"
+	ReasonComposedKindsEstablished   xpv1.ConditionReason = "ComposedKindsEstablished"
+)
+
+// WaitingForCRD indicates that a composite resource composes kinds of resource
+// whose CustomResourceDefinitions are not yet established.
+func WaitingForCRD(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeWaitingForCRD,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonComposedKindNotEstablished,
+		Message:            message,
+	}
+}
+
+// ComposedKindsEstablished indicates that a composite resource is no longer
+// waiting for CustomResourceDefinitions to be established.
+func ComposedKindsEstablished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeWaitingForCRD,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonComposedKindsEstablished,
+	}
+}
+
+// IsNoKindMatch returns true if the supplied error indicates that the API
+// server does not (yet) serve a kind of resource.
+func IsNoKindMatch(err error) bool {
+	var kind *kmeta.NoKindMatchError
+	var res *kmeta.NoResourceMatchError
+	return errors.As(err, &kind) || errors.As(err, &res)
+}
+
+// describeKinds returns a sorted, comma separated description of the supplied
+// kinds.
+func describeKinds(gks []schema.GroupKind) string {
+	s := make([]string, len(gks))
+	for i := range gks {
+		s[i] = gks[i].String()
+	}
+	sort.Strings(s)
+	return strings.Join(s, ", ")
+}
+
+// PendingKinds tracks composite resources that are waiting for the
+// CustomResourceDefinitions of the kinds they compose to be established.
+// Composite resources are tracked by the API group of the kinds they are
+// waiting for, because a CustomResourceDefinition's name is derived from its
+// plural resource name and its group, not from its kind.
+type PendingKinds struct {
+	mx      sync.Mutex
+	waiting map[string]map[types.NamespacedName]bool
+}
+
+// NewPendingKinds returns a new tracker of composite resources that are waiting
+// for CustomResourceDefinitions to be established.
+func NewPendingKinds() *PendingKinds {
+	return &PendingKinds{waiting: map[string]map[types.NamespacedName]bool{}}
+}
+
+// Wait records that the named composite resource is waiting for the supplied
+// kinds of resource.
+func (p *PendingKinds) Wait(nn types.NamespacedName, gks ...schema.GroupKind) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	for _, gk := range gks {
+		if p.waiting[gk.Group] == nil {
+			p.waiting[gk.Group] = map[types.NamespacedName]bool{}
+		}
+		p.waiting[gk.Group][nn] = true
+	}
+}
+
+// Done returns the composite resources that are waiting for kinds of resource
+// in the supplied API group, and stops tracking them. Composite resources that
+// are still waiting will be tracked again when they are next reconciled.
+func (p *PendingKinds) Done(group string) []types.NamespacedName {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	nns := make([]types.NamespacedName, 0, len(p.waiting[group]))
+	for nn := range p.waiting[group] {
+		nns = append(nns, nn)
+	}
+	delete(p.waiting, group)
+	return nns
+}
+
+// EnqueueRequestForPendingKinds enqueues a reconcile.Request for each
+// composite resource that is waiting for kinds of resource in the API group of
+// a CustomResourceDefinition that was created or updated.
+type EnqueueRequestForPendingKinds struct {
+	Pending *PendingKinds
+}
+
+// Create enqueues any composite resources waiting for the created
+// CustomResourceDefinition's API group.
+func (e *EnqueueRequestForPendingKinds) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Update enqueues any composite resources waiting for the updated
+// CustomResourceDefinition's API group, for example because it was
+// established.
+func (e *EnqueueRequestForPendingKinds) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.ObjectNew, q)
+}
+
+// Delete does nothing.
+func (e *EnqueueRequestForPendingKinds) Delete(_ event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+}
+
+// Generic does nothing.
+func (e *EnqueueRequestForPendingKinds) Generic(_ event.GenericEvent, _ workqueue.RateLimitingInterface) {
+}
+
+func (e *EnqueueRequestForPendingKinds) add(obj client.Object, q adder) {
+	if obj == nil || e.Pending == nil {
+		return
+	}
+	// CustomResourceDefinitions are named <plural>.<group>.
+	_, group, ok := strings.Cut(obj.GetName(), ".")
+	if !ok {
+		return
+	}
+	for _, nn := range e.Pending.Done(group) {
+		q.Add(reconcile.Request{NamespacedName: nn})
+	}
+}
+
+type adder interface {
+	Add(item any)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestIsNoKindMatch(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"NoKindMatch": {
+			reason: "A wrapped NoKindMatchError should be a no kind match.",
+			err:    errors.Wrap(&kmeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.org", Kind: "Bucket"}}, "boom"),
+			want:   true,
+		},
+		"NoResourceMatch": {
+			reason: "A NoResourceMatchError should be a no kind match.",
+			err:    &kmeta.NoResourceMatchError{},
+			want:   true,
+		},
+		"OtherError": {
+			reason: "Other errors should not be a no kind match.",
+			err:    errBoom,
+			want:   false,
+		},
+		"NoError": {
+			reason: "A nil error should not be a no kind match.",
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsNoKindMatch(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsNoKindMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type mockAdder struct {
+	items []any
+}
+
+func (m *mockAdder) Add(item any) {
+	m.items = append(m.items, item)
+}
+
+func TestEnqueueRequestForPendingKinds(t *testing.T) {
+	xr := types.NamespacedName{Name: "cool-xr"}
+	other := types.NamespacedName{Name: "other-xr"}
+
+	type args struct {
+		obj  client.Object
+		wait map[types.NamespacedName][]schema.GroupKind
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []any
+	}{
+		"NotWaiting": {
+			reason: "We should not enqueue composite resources that aren't waiting for a CRD's API group.",
+			args: args{
+				obj: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "buckets.example.org"}},
+				wait: map[types.NamespacedName][]schema.GroupKind{
+					other: {{Group: "other.example.org", Kind: "Bucket"}},
+				},
+			},
+		},
+		"Waiting": {
+			reason: "We should enqueue composite resources that are waiting for a CRD's API group.",
+			args: args{
+				obj: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "buckets.example.org"}},
+				wait: map[types.NamespacedName][]schema.GroupKind{
+					xr:    {{Group: "example.org", Kind: "Bucket"}},
+					other: {{Group: "other.example.org", Kind: "Bucket"}},
+				},
+			},
+			want: []any{reconcile.Request{NamespacedName: xr}},
+		},
+		"NotACRDName": {
+			reason: "We should ignore objects that aren't named like a CRD.",
+			args: args{
+				obj: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "buckets"}},
+				wait: map[types.NamespacedName][]schema.GroupKind{
+					xr: {{Group: "", Kind: "Bucket"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pk := NewPendingKinds()
+			for nn, gks := range tc.args.wait {
+				pk.Wait(nn, gks...)
+			}
+			e := &EnqueueRequestForPendingKinds{Pending: pk}
+			q := &mockAdder{}
+			e.add(tc.args.obj, q)
+			if diff := cmp.Diff(tc.want, q.items, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.add(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// Composite resources are no longer tracked once enqueued.
+			q = &mockAdder{}
+			e.add(tc.args.obj, q)
+			if len(q.items) > 0 {
+				t.Errorf("\n%s\ne.add(...): enqueued %v again", tc.reason, q.items)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+)
+
+// Error strings.
+const (
+	errGetRemaining   = "cannot get composed resource"
+	errUpdateOrphaned = "cannot remove owner reference from composed resource"
+
+	errFmtOrphaned = "orphaned %d composed resources that were not deleted within %s: %s"
+
+	msgFmtDeletionPending = "deletion pending for %s; waiting for %d composed resources to be deleted: %s"
+)
+
+// TypeDeletionProgress resources are waiting for their composed resources to
+// be deleted.
+const TypeDeletionProgress xpv1.ConditionType = "DeletionProgress"
+
+// Reasons a composite resource is waiting for its composed resources to be
+// deleted.
+const (
+	ReasonComposedResourcesRemain xpv1.ConditionReason = "ComposedResourcesRemain"
+)
+
+// DeletionPending indicates that a composite resource is waiting for some of
+// its composed resources to be deleted.
+func DeletionPending(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProgress,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonComposedResourcesRemain,
+		Message:            message,
+	}
+}
+
+// A DeletionObserver observes the deletion of a composite resource's composed
+// resources.
+type DeletionObserver interface {
+	// ObserveDeletion returns references to the supplied composite
+	// resource's composed resources that have not yet been deleted.
+	ObserveDeletion(ctx context.Context, cr resource.Composite) ([]corev1.ObjectReference, error)
+}
+
+// A DeletionObserverFn observes the deletion of a composite resource's
+// composed resources.
+type DeletionObserverFn func(ctx context.Context, cr resource.Composite) ([]corev1.ObjectReference, error)
+
+// ObserveDeletion of the supplied composite resource's composed resources.
+func (fn DeletionObserverFn) ObserveDeletion(ctx context.Context, cr resource.Composite) ([]corev1.ObjectReference, error) {
+	return fn(ctx, cr)
+}
+
+// An Orphaner orphans composed resources, such that they are no longer
+// deleted along with their composite resource.
+type Orphaner interface {
+	// Orphan the supplied composed resources of the supplied composite
+	// resource.
+	Orphan(ctx context.Context, cr resource.Composite, refs []corev1.ObjectReference) error
+}
+
+// An OrphanerFn orphans composed resources.
+type OrphanerFn func(ctx context.Context, cr resource.Composite, refs []corev1.ObjectReference) error
+
+// Orphan the supplied composed resources of the supplied composite resource.
+func (fn OrphanerFn) Orphan(ctx context.Context, cr resource.Composite, refs []corev1.ObjectReference) error {
+	return fn(ctx, cr, refs)
+}
+
+// An APIDeletionObserver observes the deletion of composed resources by
+// reading them from the API server.
+type APIDeletionObserver struct {
+	client client.Reader
+}
+
+// NewAPIDeletionObserver returns a DeletionObserver that reads composed
+// resources from the API server.
+func NewAPIDeletionObserver(c client.Reader) *APIDeletionObserver {
+	return &APIDeletionObserver{client: c}
+}
+
+// ObserveDeletion returns references to the supplied composite resource's
+// composed resources that still exist.
+func (o *APIDeletionObserver) ObserveDeletion(ctx context.Context, cr resource.Composite) ([]corev1.ObjectReference, error) {
+	remaining := make([]corev1.ObjectReference, 0)
+	for _, ref := range cr.GetResourceReferences() {
+		// A composed resource without a name has never been created.
+		if ref.Name == "" {
+			continue
+		}
+		cd := composed.New(composed.FromReference(ref))
+		err := o.client.Get(ctx, types.NamespacedName{Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetRemaining)
+		}
+		// Observe only composed resources aren't deleted along with
+		// their composite resource, because it doesn't control them.
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != cr.GetUID() {
+			continue
+		}
+		remaining = append(remaining, ref)
+	}
+	return remaining, nil
+}
+
+// An APIOrphaner orphans composed resources by removing the owner reference
+// to their composite resource.
+type APIOrphaner struct {
+	client client.Client
+}
+
+// NewAPIOrphaner returns an Orphaner that removes owner references from
+// composed resources using the API server.
+func NewAPIOrphaner(c client.Client) *APIOrphaner {
+	return &APIOrphaner{client: c}
+}
+
+// Orphan the supplied composed resources by removing their owner reference to
+// the supplied composite resource. Composed resources that no longer exist are
+// ignored.
+func (o *APIOrphaner) Orphan(ctx context.Context, cr resource.Composite, refs []corev1.ObjectReference) error {
+	for _, ref := range refs {
+		cd := composed.New(composed.FromReference(ref))
+		err := o.client.Get(ctx, types.NamespacedName{Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetRemaining)
+		}
+
+		owners := cd.GetOwnerReferences()
+		kept := make([]metav1.OwnerReference, 0, len(owners))
+		for _, or := range owners {
+			if or.UID == cr.GetUID() {
+				continue
+			}
+			kept = append(kept, or)
+		}
+		if len(kept) == len(owners) {
+			continue
+		}
+		cd.SetOwnerReferences(kept)
+		if err := o.client.Update(ctx, cd); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errUpdateOrphaned)
+		}
+	}
+	return nil
+}
+
+// describe returns a human readable list of the supplied composed resources.
+func describe(refs []corev1.ObjectReference) string {
+	s := make([]string, len(refs))
+	for i, ref := range refs {
+		s[i] = fmt.Sprintf("%s %q", ref.Kind, ref.Name)
+	}
+	return strings.Join(s, ", ")
+}
+
+// pendingFor returns how long the supplied composite resource has been
+// pending deletion, to the nearest second.
+func pendingFor(cr resource.Composite) time.Duration {
+	dt := cr.GetDeletionTimestamp()
+	if dt == nil {
+		return 0
+	}
+	return time.Since(dt.Time).Round(time.Second)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestObserveDeletion(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	gone := corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket", Name: "gone"}
+	stuck := corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket", Name: "stuck"}
+	unnamed := corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Bucket"}
+
+	observed := corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Network", Name: "shared"}
+
+	xr := composite.New()
+	xr.SetUID("xr-uid")
+	xr.SetResourceReferences([]corev1.ObjectReference{gone, stuck, unnamed, observed})
+
+	type want struct {
+		remaining []corev1.ObjectReference
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"GetError": {
+			reason: "We should return any error encountered while getting a composed resource.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRemaining),
+			},
+		},
+		"SomeRemaining": {
+			reason: "We should return references to composed resources that still exist, and that the composite resource controls.",
+			client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				if key.Name == gone.Name {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				if key.Name == "" {
+					t.Errorf("Get(...): we should not get a composed resource without a name")
+				}
+				if key.Name == observed.Name {
+					// Observe only composed resources aren't controlled by
+					// their composite resource.
+					return nil
+				}
+				ctrl := true
+				obj.SetOwnerReferences([]metav1.OwnerReference{{Name: "xr", UID: xr.GetUID(), Controller: &ctrl}})
+				return nil
+			}},
+			want: want{
+				remaining: []corev1.ObjectReference{stuck},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewAPIDeletionObserver(tc.client)
+			remaining, err := o.ObserveDeletion(context.Background(), xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserveDeletion(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.remaining, remaining); diff != "" {
+				t.Errorf("\n%s\nObserveDeletion(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOrphan(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New()
+	xr.SetUID("xr-uid")
+	other := metav1.OwnerReference{Name: "other", UID: "other-uid"}
+	refs := []corev1.ObjectReference{{APIVersion: "example.org/v1", Kind: "Bucket", Name: "stuck"}}
+
+	// withOwners returns a MockGetFn that gets a composed resource with the
+	// supplied owner references.
+	withOwners := func(or ...metav1.OwnerReference) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.SetOwnerReferences(or)
+			return nil
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   error
+	}{
+		"NotFound": {
+			reason: "We should ignore composed resources that no longer exist.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "stuck"))},
+		},
+		"GetError": {
+			reason: "We should return any error encountered while getting a composed resource.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrap(errBoom, errGetRemaining),
+		},
+		"NotOwned": {
+			reason: "We should not update a composed resource that is not owned by the composite resource.",
+			client: &test.MockClient{
+				MockGet:    withOwners(other),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+		},
+		"UpdateError": {
+			reason: "We should return any error encountered while updating a composed resource.",
+			client: &test.MockClient{
+				MockGet:    withOwners(other, metav1.OwnerReference{Name: "xr", UID: xr.GetUID()}),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: errors.Wrap(errBoom, errUpdateOrphaned),
+		},
+		"Success": {
+			reason: "We should remove only the composite resource's owner reference.",
+			client: &test.MockClient{
+				MockGet: withOwners(other, metav1.OwnerReference{Name: "xr", UID: xr.GetUID()}),
+				MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+					if diff := cmp.Diff([]metav1.OwnerReference{other}, obj.GetOwnerReferences()); diff != "" {
+						t.Errorf("Update(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewAPIOrphaner(tc.client)
+			err := o.Orphan(context.Background(), xr, refs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOrphan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
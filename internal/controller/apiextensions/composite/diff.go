@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	redacted = "<redacted>"
+	unset    = "<unset>"
+)
+
+// Field names whose values are redacted from diffs. A field is redacted if its
+// name contains any of these, ignoring case.
+var sensitive = []string{"password", "secret", "token"}
+
+// DescribeDiff returns a description of how the supplied observed composed
+// resource will change when the supplied desired composed resource is
+// applied, at each of the supplied field paths. Values that may be sensitive,
+// including all values of a Secret, are redacted.
+func DescribeDiff(desired, observed resource.Composed, paths []string) string {
+	gvk := desired.GetObjectKind().GroupVersionKind()
+	dp, derr := fieldpath.PaveObject(desired)
+	op, oerr := fieldpath.PaveObject(observed)
+	redact := (gvk.Group == "" && gvk.Kind == "Secret") || derr != nil || oerr != nil
+
+	diffs := make([]string, len(paths))
+	for i, p := range paths {
+		if redact || isSensitive(p) {
+			diffs[i] = fmt.Sprintf("%s: %s", p, redacted)
+			continue
+		}
+		diffs[i] = fmt.Sprintf("%s: %s → %s", p, valueAt(op, p), valueAt(dp, p))
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func isSensitive(path string) bool {
+	segments, err := fieldpath.Parse(path)
+	if err != nil || len(segments) == 0 {
+		return true
+	}
+	name := strings.ToLower(segments[len(segments)-1].Field)
+	for _, s := range sensitive {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueAt(p *fieldpath.Paved, path string) string {
+	v, err := p.GetValue(path)
+	if err != nil {
+		return unset
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
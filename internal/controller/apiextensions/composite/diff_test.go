@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+)
+
+func TestDescribeDiff(t *testing.T) {
+	cd := func(o map[string]any) *composed.Unstructured {
+		c := composed.New()
+		c.Object = o
+		return c
+	}
+
+	cases := map[string]struct {
+		reason   string
+		desired  *composed.Unstructured
+		observed *composed.Unstructured
+		paths    []string
+		want     string
+	}{
+		"Diff": {
+			reason: "We should describe the observed and desired value at each path.",
+			desired: cd(map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"spec":       map[string]any{"region": "us-west-2", "tags": []any{"a", "b"}},
+			}),
+			observed: cd(map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"spec":       map[string]any{"region": "us-east-1"},
+			}),
+			paths: []string{"spec.region", "spec.tags"},
+			want:  `spec.region: "us-east-1" → "us-west-2", spec.tags: <unset> → ["a","b"]`,
+		},
+		"SensitiveField": {
+			reason: "We should redact the values of fields that may be sensitive.",
+			desired: cd(map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"spec":       map[string]any{"masterPassword": "new"},
+			}),
+			observed: cd(map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"spec":       map[string]any{"masterPassword": "old"},
+			}),
+			paths: []string{"spec.masterPassword"},
+			want:  "spec.masterPassword: <redacted>",
+		},
+		"Secret": {
+			reason: "We should redact all values of a Secret.",
+			desired: cd(map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"data":       map[string]any{"user": "bmV3"},
+			}),
+			observed: cd(map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"data":       map[string]any{"user": "b2xk"},
+			}),
+			paths: []string{"data.user"},
+			want:  "data.user: <redacted>",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DescribeDiff(tc.desired, tc.observed, tc.paths)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDescribeDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errGetObserved = "cannot get composed resource"
+	errPave        = "cannot convert composed resource to unstructured data"
+)
+
+// TypeDrifted resources' composed resources have drifted from their desired
+// state.
+const TypeDrifted xpv1.ConditionType = "Drifted"
+
+// Reasons a composite resource has or has not drifted.
+const (
+	ReasonDrifted xpv1.ConditionReason = "ComposedResourcesDrifted"
+	ReasonNoDrift xpv1.ConditionReason = "NoDriftObserved"
+)
+
+// Drifted indicates that some of a composite resource's composed resources
+// have drifted from their desired state, and that the drift was not corrected.
+func Drifted(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDrifted,
+		Message:            message,
+	}
+}
+
+// NoDrift indicates that no drift was observed between a composite resource's
+// composed resources and their desired state.
+func NoDrift() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDrift,
+	}
+}
+
+// DriftPolicyOf returns the drift policy of the supplied composite resource.
+// A policy set by the composite resource takes precedence over one set by its
+// composition. Drift is corrected if neither sets a policy.
+func DriftPolicyOf(cr resource.Composite, comp *v1.Composition) v1.DriftPolicy {
+	if p, err := fieldpath.PaveObject(cr); err == nil {
+		if dp, err := p.GetString("spec.driftPolicy"); err == nil && dp != "" {
+			return v1.DriftPolicy(dp)
+		}
+	}
+	if comp.Spec.DriftPolicy != nil {
+		return *comp.Spec.DriftPolicy
+	}
+	return v1.DriftPolicyCorrect
+}
+
+// A DriftObserver observes how an existing composed resource has drifted from
+// its desired state.
+type DriftObserver interface {
+	// ObserveDrift returns the observed state of the supplied desired
+	// composed resource, and the field paths at which it differs from the
+	// desired state. It returns a nil composed resource if the desired
+	// composed resource does not yet exist.
+	ObserveDrift(ctx context.Context, desired resource.Composed) (resource.Composed, []string, error)
+}
+
+// A DriftObserverFn observes how an existing composed resource has drifted
+// from its desired state.
+type DriftObserverFn func(ctx context.Context, desired resource.Composed) (resource.Composed, []string, error)
+
+// ObserveDrift of the supplied composed resource.
+func (fn DriftObserverFn) ObserveDrift(ctx context.Context, desired resource.Composed) (resource.Composed, []string, error) {
+	return fn(ctx, desired)
+}
+
+// An APIDriftObserver observes drift by reading composed resources from the
+// API server.
+type APIDriftObserver struct {
+	client client.Reader
+}
+
+// NewAPIDriftObserver returns a DriftObserver that reads composed resources
+// from the API server.
+func NewAPIDriftObserver(c client.Reader) *APIDriftObserver {
+	return &APIDriftObserver{client: c}
+}
+
+// ObserveDrift reads the supplied desired composed resource from the API
+// server and compares it to its desired state.
+func (o *APIDriftObserver) ObserveDrift(ctx context.Context, desired resource.Composed) (resource.Composed, []string, error) {
+	// A composed resource without a name has never been created.
+	if desired.GetName() == "" {
+		return nil, nil, nil
+	}
+
+	observed := composed.New(composed.FromReference(*meta.ReferenceTo(desired, desired.GetObjectKind().GroupVersionKind())))
+	err := o.client.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, observed)
+	if kerrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetObserved)
+	}
+
+	d, err := fieldpath.PaveObject(desired)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errPave)
+	}
+	return observed, Drift(d.UnstructuredContent(), observed.UnstructuredContent()), nil
+}
+
+// Drift returns the sorted field paths at which the supplied observed object
+// differs from the supplied desired object. Only fields that are set in the
+// desired object are compared. Of the desired object's metadata only labels
+// and annotations are compared, and its status is ignored.
+func Drift(desired, observed map[string]any) []string {
+	paths := make([]string, 0)
+	for k, dv := range desired {
+		switch k {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			dm, _ := dv.(map[string]any)
+			om, _ := observed[k].(map[string]any)
+			for _, mk := range []string{"labels", "annotations"} {
+				if _, ok := dm[mk]; !ok {
+					continue
+				}
+				paths = drift(dm[mk], om[mk], fieldpath.Segments{fieldpath.Field(k), fieldpath.Field(mk)}, paths)
+			}
+			continue
+		}
+		paths = drift(dv, observed[k], fieldpath.Segments{fieldpath.Field(k)}, paths)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func drift(desired, observed any, path fieldpath.Segments, paths []string) []string {
+	dm, ok := desired.(map[string]any)
+	if !ok {
+		if !reflect.DeepEqual(desired, observed) {
+			paths = append(paths, path.String())
+		}
+		return paths
+	}
+	// An observed object that is missing entirely is compared as though it
+	// were empty, so that each desired field is reported.
+	om, ok := observed.(map[string]any)
+	if !ok && observed != nil {
+		return append(paths, path.String())
+	}
+	for k, dv := range dm {
+		p := append(append(make(fieldpath.Segments, 0, len(path)+1), path...), fieldpath.Field(k))
+		paths = drift(dv, om[k], p, paths)
+	}
+	return paths
+}
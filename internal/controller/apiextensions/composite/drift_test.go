@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestDrift(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		desired  map[string]any
+		observed map[string]any
+		want     []string
+	}{
+		"NoDrift": {
+			reason: "Fields that are only set in the observed object should not be considered drift.",
+			desired: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"metadata":   map[string]any{"name": "cool", "labels": map[string]any{"a": "b"}},
+				"spec":       map[string]any{"region": "us-west-2"},
+			},
+			observed: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Instance",
+				"metadata":   map[string]any{"name": "cool", "uid": "some-uid", "labels": map[string]any{"a": "b", "c": "d"}},
+				"spec":       map[string]any{"region": "us-west-2", "zone": "us-west-2a"},
+				"status":     map[string]any{"ready": true},
+			},
+			want: []string{},
+		},
+		"Drift": {
+			reason: "Fields that are set in the desired object should be compared to the observed object.",
+			desired: map[string]any{
+				"metadata": map[string]any{
+					"name":        "cool",
+					"annotations": map[string]any{"example.org/team": "cool"},
+				},
+				"spec": map[string]any{
+					"region": "us-west-2",
+					"tags":   []any{"a", "b"},
+					"config": map[string]any{"size": int64(10)},
+				},
+				"status": map[string]any{"ready": true},
+			},
+			observed: map[string]any{
+				"metadata": map[string]any{
+					"name": "cool",
+				},
+				"spec": map[string]any{
+					"region": "us-east-1",
+					"tags":   []any{"a"},
+					"config": "not-an-object",
+				},
+			},
+			want: []string{
+				"metadata.annotations[example.org/team]",
+				"spec.config",
+				"spec.region",
+				"spec.tags",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Drift(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDrift(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDriftPolicyOf(t *testing.T) {
+	report := v1.DriftPolicyReport
+
+	cases := map[string]struct {
+		reason string
+		cr     resource.Composite
+		comp   *v1.Composition
+		want   v1.DriftPolicy
+	}{
+		"Default": {
+			reason: "Drift should be corrected if neither the composite resource nor its composition specify a policy.",
+			cr:     composite.New(),
+			comp:   &v1.Composition{},
+			want:   v1.DriftPolicyCorrect,
+		},
+		"Composition": {
+			reason: "The composition's policy should be used if the composite resource doesn't specify one.",
+			cr:     composite.New(),
+			comp:   &v1.Composition{Spec: v1.CompositionSpec{DriftPolicy: &report}},
+			want:   v1.DriftPolicyReport,
+		},
+		"CompositeResource": {
+			reason: "The composite resource's policy should take precedence over its composition's.",
+			cr: func() resource.Composite {
+				cr := composite.New()
+				cr.Object["spec"] = map[string]any{"driftPolicy": string(v1.DriftPolicyCorrect)}
+				return cr
+			}(),
+			comp: &v1.Composition{Spec: v1.CompositionSpec{DriftPolicy: &report}},
+			want: v1.DriftPolicyCorrect,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DriftPolicyOf(tc.cr, tc.comp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDriftPolicyOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIDriftObserver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	desired := func() *composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Instance")
+		cd.SetName("cool")
+		cd.Object["spec"] = map[string]any{"region": "us-west-2"}
+		return cd
+	}
+
+	type want struct {
+		exists bool
+		drift  []string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		client  client.Reader
+		desired resource.Composed
+		want    want
+	}{
+		"Unnamed": {
+			reason:  "A composed resource without a name should not exist.",
+			client:  &test.MockClient{},
+			desired: composed.New(),
+			want:    want{},
+		},
+		"NotFound": {
+			reason:  "A composed resource that is not found should not exist.",
+			client:  &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool"))},
+			desired: desired(),
+			want:    want{},
+		},
+		"GetError": {
+			reason:  "Errors getting a composed resource should be returned.",
+			client:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			desired: desired(),
+			want:    want{err: errors.Wrap(errBoom, errGetObserved)},
+		},
+		"Drifted": {
+			reason: "Drift between the desired and observed composed resource should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*composed.Unstructured).Object["spec"] = map[string]any{"region": "us-east-1"}
+				return nil
+			})},
+			desired: desired(),
+			want:    want{exists: true, drift: []string{"spec.region"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewAPIDriftObserver(tc.client)
+			observed, drift, err := o.ObserveDrift(context.Background(), tc.desired)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserveDrift(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.exists, observed != nil); diff != "" {
+				t.Errorf("\n%s\nObserveDrift(...): -want exists, +got exists:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drift, drift); diff != "" {
+				t.Errorf("\n%s\nObserveDrift(...): -want drift, +got drift:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
+)
+
+// Error strings.
+const (
+	errImpersonate       = "cannot impersonate the Composition's ServiceAccount"
+	errFmtNewImpersonate = "cannot create a client that impersonates ServiceAccount %q"
+)
+
+// An Impersonator returns an Applicator that applies resources as the named
+// ServiceAccount.
+type Impersonator interface {
+	Impersonate(serviceAccount string) (resource.Applicator, error)
+}
+
+// An ImpersonatorFn returns an Applicator that applies resources as the named
+// ServiceAccount.
+type ImpersonatorFn func(serviceAccount string) (resource.Applicator, error)
+
+// Impersonate the named ServiceAccount.
+func (fn ImpersonatorFn) Impersonate(serviceAccount string) (resource.Applicator, error) {
+	return fn(serviceAccount)
+}
+
+// An APIImpersonator returns Applicators that apply resources to the API
+// server as a ServiceAccount in Crossplane's namespace. Requests made by the
+// Applicators are authorized as though the ServiceAccount had made them, so
+// Crossplane must be permitted to impersonate the ServiceAccount.
+type APIImpersonator struct {
+	cfg       *rest.Config
+	opts      client.Options
+	namespace string
+
+	mx          sync.Mutex
+	applicators map[string]resource.Applicator
+}
+
+// NewAPIImpersonator returns an Impersonator that impersonates ServiceAccounts
+// in the supplied namespace, using clients derived from the supplied config
+// and options. A client is created for each ServiceAccount the first time it
+// is impersonated, and reused thereafter.
+func NewAPIImpersonator(cfg *rest.Config, o client.Options, namespace string) *APIImpersonator {
+	return &APIImpersonator{cfg: cfg, opts: o, namespace: namespace, applicators: map[string]resource.Applicator{}}
+}
+
+// Impersonate the named ServiceAccount.
+func (i *APIImpersonator) Impersonate(serviceAccount string) (resource.Applicator, error) {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	if a, ok := i.applicators[serviceAccount]; ok {
+		return a, nil
+	}
+
+	cfg := rest.CopyConfig(i.cfg)
+	cfg.Impersonate = rest.ImpersonationConfig{UserName: ServiceAccountUsername(i.namespace, serviceAccount)}
+	c, err := client.New(cfg, i.opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtNewImpersonate, serviceAccount)
+	}
+
+	a := resource.NewAPIPatchingApplicator(unstructured.NewClient(c))
+	i.applicators[serviceAccount] = a
+	return a, nil
+}
+
+// ServiceAccountUsername returns the username the API server authenticates
+// the supplied ServiceAccount as.
+func ServiceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errMeasureComposed = "cannot measure the size of composed resource"
+
+	errFmtTooManyComposed  = "Composition has %d resource templates, more than the maximum of %d composed resources"
+	errFmtComposedTooLarge = "composed resource rendered from resource template at index %d is %d bytes, larger than the maximum of %d bytes"
+)
+
+// TypeLimitExceeded resources would compose more, or larger, composed resources
+// than the composite resource controller allows.
+const TypeLimitExceeded xpv1.ConditionType = "ComposedResourceLimitExceeded"
+
+// Reasons a composite resource's composed resources are or are not within
+// limits.
+const (
+	ReasonTooManyComposedResources xpv1.ConditionReason = "TooManyComposedResources"
+	ReasonComposedResourceTooLarge xpv1.ConditionReason = "ComposedResourceTooLarge"
+	ReasonWithinLimits             xpv1.ConditionReason = "WithinComposedResourceLimits"
+)
+
+// LimitExceeded indicates that a composite resource would compose too many
+// composed resources, or a composed resource that is too large. Crossplane
+// refuses to compose resources until the limit is no longer exceeded.
+func LimitExceeded(r xpv1.ConditionReason, message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeLimitExceeded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            message,
+	}
+}
+
+// WithinLimits indicates that a composite resource's composed resources are
+// no longer exceeding a limit.
+func WithinLimits() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeLimitExceeded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithinLimits,
+	}
+}
+
+// SizeOf returns the size in bytes of the supplied composed resource when it
+// is serialized to JSON, as it is when it is sent to the API server.
+func SizeOf(cd resource.Composed) (int, error) {
+	b, err := json.Marshal(cd)
+	return len(b), errors.Wrap(err, errMeasureComposed)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// maxNestingDepth is the maximum number of composite resources we'll walk up
+// while looking for a composition cycle. Composite resources are rarely nested
+// more than a few layers deep.
+const maxNestingDepth = 32
+
+// Error strings.
+const (
+	errGetParent      = "cannot get parent composite resource"
+	errParseParentRef = "cannot parse controller reference of composite resource"
+	errFmtMaxDepth    = "composite resource is nested more than %d layers deep"
+	errFmtCycle       = "composition reference cycle: %s"
+	errFmtNestedCycle = "composed resource %s %q is part of a composition reference cycle"
+)
+
+// TypeCompositionCycle resources are part of a composition reference cycle.
+const TypeCompositionCycle xpv1.ConditionType = "CompositionCycle"
+
+// Reasons a composite resource is or is not part of a composition cycle.
+const (
+	ReasonCompositionCycle   xpv1.ConditionReason = "CompositionReferenceCycle"
+	ReasonNoCompositionCycle xpv1.ConditionReason = "NoCompositionReferenceCycle"
+)
+
+// CompositionCycle indicates that a composite resource is, or composes a
+// composite resource that is, part of a composition reference cycle - i.e.
+// composing it would create an endless chain of nested composite resources.
+func CompositionCycle(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCompositionCycle,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCompositionCycle,
+		Message:            message,
+	}
+}
+
+// NoCompositionCycle indicates that a composite resource is no longer part of
+// a composition reference cycle.
+func NoCompositionCycle() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCompositionCycle,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoCompositionCycle,
+	}
+}
+
+// A CompositionCycleDetector detects composition reference cycles.
+type CompositionCycleDetector interface {
+	// DetectCycle returns the composition reference cycle the supplied
+	// composite resource is part of, if any. Each element of the returned
+	// cycle describes a kind of composite resource and the composition it
+	// uses, starting with the outermost.
+	DetectCycle(ctx context.Context, cr resource.Composite) ([]string, error)
+}
+
+// A CompositionCycleDetectorFn detects composition reference cycles.
+type CompositionCycleDetectorFn func(ctx context.Context, cr resource.Composite) ([]string, error)
+
+// DetectCycle the supplied composite resource is part of, if any.
+func (fn CompositionCycleDetectorFn) DetectCycle(ctx context.Context, cr resource.Composite) ([]string, error) {
+	return fn(ctx, cr)
+}
+
+// An APICompositionCycleDetector detects composition reference cycles by
+// walking up the controller references of nested composite resources.
+type APICompositionCycleDetector struct {
+	client client.Reader
+}
+
+// NewAPICompositionCycleDetector returns a CompositionCycleDetector that reads
+// the parents of nested composite resources from the API server.
+func NewAPICompositionCycleDetector(c client.Reader) *APICompositionCycleDetector {
+	return &APICompositionCycleDetector{client: c}
+}
+
+// DetectCycle the supplied composite resource is part of, if any. A composite
+// resource is part of a cycle when one of its ancestors is the same kind of
+// composite resource and uses the same composition. Composing it would cause
+// the same layers of composite resources to be composed again, forever.
+func (d *APICompositionCycleDetector) DetectCycle(ctx context.Context, cr resource.Composite) ([]string, error) {
+	want, ok := compositionUseOf(cr)
+	if !ok {
+		return nil, nil
+	}
+
+	path := []string{want}
+	var o metav1.Object = cr
+	for i := 0; i < maxNestingDepth; i++ {
+		ref := metav1.GetControllerOf(o)
+		if ref == nil {
+			return nil, nil
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseParentRef)
+		}
+
+		parent := composite.New(composite.WithGroupVersionKind(gv.WithKind(ref.Kind)))
+		if err := d.client.Get(ctx, types.NamespacedName{Name: ref.Name}, parent); err != nil {
+			return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetParent)
+		}
+
+		// The controller is not (or is no longer) the composite resource
+		// that composed this one. We've found the outermost layer.
+		if parent.GetUID() != ref.UID {
+			return nil, nil
+		}
+		use, ok := compositionUseOf(parent)
+		if !ok {
+			return nil, nil
+		}
+
+		path = append([]string{use}, path...)
+		if use == want {
+			return path, nil
+		}
+		o = parent
+	}
+
+	return nil, errors.Errorf(errFmtMaxDepth, maxNestingDepth)
+}
+
+// compositionUseOf describes the kind of the supplied composite resource and
+// the composition it uses. It returns false if the supplied object is not a
+// composite resource with a composition.
+func compositionUseOf(cr resource.Composite) (string, bool) {
+	ref := cr.GetCompositionReference()
+	if ref == nil || ref.Name == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s (%s)", cr.GetObjectKind().GroupVersionKind().GroupKind(), ref.Name), true
+}
+
+// cycleMessage returns a condition message describing the supplied cycle.
+func cycleMessage(cycle []string) string {
+	return fmt.Sprintf(errFmtCycle, strings.Join(cycle, " -> "))
+}
+
+// nestedCycle returns a condition message if the supplied composed resource is
+// a composite resource that is, or composes, part of a composition reference
+// cycle.
+func nestedCycle(cd resource.Composed) (string, bool) {
+	if !resource.IsConditionTrue(cd.GetCondition(TypeCompositionCycle)) {
+		return "", false
+	}
+	return fmt.Sprintf(errFmtNestedCycle, cd.GetObjectKind().GroupVersionKind().Kind, cd.GetName()), true
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDetectCycle(t *testing.T) {
+	errBoom := errors.New("boom")
+	ctrl := true
+
+	// xr returns a composite resource of the supplied kind that uses the
+	// supplied composition, and is controlled by the supplied parent.
+	xr := func(kind, name, comp string, parent resource.Composite) *composite.Unstructured {
+		cr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: kind}))
+		cr.SetName(name)
+		cr.SetUID(types.UID(name + "-uid"))
+		if comp != "" {
+			cr.SetCompositionReference(&corev1.ObjectReference{Name: comp})
+		}
+		if parent != nil {
+			cr.SetOwnerReferences([]metav1.OwnerReference{{
+				APIVersion: "example.org/v1",
+				Kind:       parent.GetObjectKind().GroupVersionKind().Kind,
+				Name:       parent.GetName(),
+				UID:        parent.GetUID(),
+				Controller: &ctrl,
+			}})
+		}
+		return cr
+	}
+
+	// withXRs returns a MockGetFn that gets the supplied composite resources
+	// by name.
+	withXRs := func(xrs ...*composite.Unstructured) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			for _, cr := range xrs {
+				if cr.GetName() == key.Name {
+					cr.Unstructured.DeepCopyInto(&obj.(*composite.Unstructured).Unstructured)
+					return nil
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	root := xr("A", "root", "a", nil)
+	middle := xr("B", "middle", "b", root)
+
+	type args struct {
+		client client.Reader
+		cr     resource.Composite
+	}
+	type want struct {
+		cycle []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoComposition": {
+			reason: "A composite resource that does not yet use a composition can't be part of a cycle.",
+			args: args{
+				cr: xr("A", "leaf", "", middle),
+			},
+		},
+		"NotNested": {
+			reason: "A composite resource that is not controlled by another can't be part of a cycle.",
+			args: args{
+				cr: root,
+			},
+		},
+		"NoCycle": {
+			reason: "A nested composite resource whose ancestors use different compositions is not part of a cycle.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRs(root, middle)},
+				cr:     xr("A", "leaf", "other", middle),
+			},
+		},
+		"Cycle": {
+			reason: "A nested composite resource that uses the same composition as one of its ancestors is part of a cycle.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRs(root, middle)},
+				cr:     xr("A", "leaf", "a", middle),
+			},
+			want: want{
+				cycle: []string{"A.example.org (a)", "B.example.org (b)", "A.example.org (a)"},
+			},
+		},
+		"ParentNotFound": {
+			reason: "A composite resource whose controller no longer exists is not part of a cycle.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRs()},
+				cr:     xr("A", "leaf", "a", middle),
+			},
+		},
+		"ParentReplaced": {
+			reason: "We should stop walking up when a controller has been replaced by a new object of the same name.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRs(root, xr("B", "middle", "a", nil))},
+				cr: func() resource.Composite {
+					cr := xr("B", "leaf", "a", nil)
+					cr.SetOwnerReferences([]metav1.OwnerReference{{
+						APIVersion: "example.org/v1",
+						Kind:       "B",
+						Name:       "middle",
+						UID:        "stale-uid",
+						Controller: &ctrl,
+					}})
+					return cr
+				}(),
+			},
+		},
+		"GetParentError": {
+			reason: "We should return any error encountered while getting a parent composite resource.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cr:     xr("A", "leaf", "a", middle),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetParent),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewAPICompositionCycleDetector(tc.args.client)
+			cycle, err := d.DetectCycle(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDetectCycle(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cycle, cycle); diff != "" {
+				t.Errorf("\n%s\nDetectCycle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
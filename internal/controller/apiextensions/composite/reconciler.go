@@ -18,11 +18,17 @@ package composite
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -39,12 +45,17 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 const (
 	timeout             = 2 * time.Minute
 	defaultPollInterval = 1 * time.Minute
 	finalizer           = "composite.apiextensions.crossplane.io"
+
+	// defaultMaxConcurrentApplies is the default maximum number of composed
+	// resources that are applied concurrently during a single reconcile.
+	defaultMaxConcurrentApplies = 5
 )
 
 // Error strings
@@ -58,23 +69,41 @@ const (
 	errFetchComp       = "cannot fetch Composition"
 	errConfigure       = "cannot configure composite resource"
 	errPublish         = "cannot publish connection details"
+	errRotate          = "cannot record connection details rotation"
 	errUnpublish       = "cannot unpublish connection details"
 	errRenderCD        = "cannot render composed resource"
 	errRenderCR        = "cannot render composite resource"
 	errValidate        = "refusing to use invalid Composition"
 	errInline          = "cannot inline Composition patch sets"
+	errResolveBases    = "cannot resolve composed resource base resources"
 	errAssociate       = "cannot associate composed resources with Composition resource templates"
-
-	errFmtRender = "cannot render composed resource from resource template at index %d"
+	errObserveDrift    = "cannot observe composed resource drift"
+	errObserveComposed = "cannot observe observe only composed resource"
+	errRestore         = "cannot associate restored composed resources with Composition resource templates"
+	errDetectCycle     = "cannot detect composition reference cycles"
+	errObserveDeletion = "cannot observe composed resource deletion"
+	errOrphan          = "cannot orphan composed resources"
+
+	errFmtRender         = "cannot render composed resource from resource template at index %d"
+	errFmtDrift          = "composed resources have drifted from their desired state: %s"
+	errFmtObserveMissing = "observe only composed resource %s %q does not exist"
+
+	msgFmtDiff     = "Updating %s %q: %s"
+	msgFmtWaitWave = "Waiting for composed resources in wave %d to become ready before creating composed resources in later waves"
 )
 
 // Event reasons.
 const (
 	reasonResolve event.Reason = "SelectComposition"
 	reasonCompose event.Reason = "ComposeResources"
+	reasonWave    event.Reason = "WaitForComposedResources"
 	reasonPublish event.Reason = "PublishConnectionSecret"
 	reasonInit    event.Reason = "InitializeCompositeResource"
 	reasonDelete  event.Reason = "DeleteCompositeResource"
+	reasonDrift   event.Reason = "ComposedResourceDrift"
+	reasonDiff    event.Reason = "ComposedResourceDiff"
+	reasonWaitCRD event.Reason = "WaitForCustomResourceDefinitions"
+	reasonTimeout event.Reason = "ReadyTimeoutExceeded"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -193,6 +222,68 @@ func WithPollInterval(after time.Duration) ReconcilerOption {
 	}
 }
 
+// WithMaxConcurrentApplies specifies the maximum number of composed resources
+// the Reconciler should apply concurrently while reconciling a composite
+// resource. Composed resources are applied one at a time if n is less than 1.
+func WithMaxConcurrentApplies(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		if n < 1 {
+			n = 1
+		}
+		r.maxConcurrentApplies = n
+	}
+}
+
+// WithMaxComposedResources specifies the maximum number of composed resources
+// the Reconciler will compose for a single composite resource. The Reconciler
+// refuses to compose any resources for a composite resource whose Composition
+// would exceed this limit. There is no limit if n is less than 1.
+func WithMaxComposedResources(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxComposed = n
+	}
+}
+
+// WithMaxComposedResourceSize specifies the maximum size in bytes of a
+// rendered composed resource. The Reconciler refuses to compose any resources
+// for a composite resource if any of its rendered composed resources would
+// exceed this limit. There is no limit if bytes is less than 1.
+func WithMaxComposedResourceSize(bytes int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxComposedSize = bytes
+	}
+}
+
+// WithPendingKinds specifies how the Reconciler should track composite
+// resources that are waiting for the CustomResourceDefinitions of the kinds
+// they compose to be established. Composite resources are only tracked if
+// this option is supplied.
+func WithPendingKinds(p *PendingKinds) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pending = p
+	}
+}
+
+// WithReadyTimeout specifies how long composite resources may take to become
+// ready before the Reconciler reports that they have exceeded their ready
+// timeout. Composite resources have no ready timeout unless this option is
+// supplied.
+func WithReadyTimeout(t time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.readyTimeout = t
+	}
+}
+
+// WithImpersonator specifies how the Reconciler should impersonate the
+// ServiceAccount a Composition names when it applies composed resources.
+// Composed resources are always applied as Crossplane unless this option is
+// supplied.
+func WithImpersonator(i Impersonator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.impersonator = i
+	}
+}
+
 // WithClientApplicator specifies how the Reconciler should interact with the
 // Kubernetes API.
 func WithClientApplicator(ca resource.ClientApplicator) ReconcilerOption {
@@ -217,6 +308,14 @@ func WithCompositionValidator(v CompositionValidator) ReconcilerOption {
 	}
 }
 
+// WithBaseResolver specifies how the Reconciler should resolve the base
+// resources of Composition resource templates.
+func WithBaseResolver(br BaseResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composition.BaseResolver = br
+	}
+}
+
 // WithCompositionTemplateAssociator specifies how the Reconciler should
 // associate composition templates with composed resources.
 func WithCompositionTemplateAssociator(a CompositionTemplateAssociator) ReconcilerOption {
@@ -282,6 +381,87 @@ func WithConnectionPublishers(p ...managed.ConnectionPublisher) ReconcilerOption
 	}
 }
 
+// WithConnectionRotator specifies how the Reconciler should record the
+// rotation of connection details.
+func WithConnectionRotator(cr ConnectionRotator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.ConnectionRotator = cr
+	}
+}
+
+// WithDriftObserver specifies how the Reconciler should observe the drift of
+// composed resources whose drift is reported rather than corrected.
+func WithDriftObserver(o DriftObserver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composed.DriftObserver = o
+	}
+}
+
+// WithRestoreMode configures the Reconciler to adopt composed resources that
+// were restored from a backup along with their composite resource. Composite
+// resources annotated as restored are treated this way regardless.
+func WithRestoreMode() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.restore = true
+	}
+}
+
+// WithDiffRecording configures the Reconciler to record an event describing
+// how each composed resource will change whenever it is updated. This can help
+// to debug composed resources that are updated on every reconcile, for example
+// because a provider late-initializes a field that is also patched.
+func WithDiffRecording() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.recordDiffs = true
+	}
+}
+
+// WithReconcileSummaries configures the Reconciler to identify each reconcile
+// by a unique ID that is included in its logs, and to record why it requeued a
+// composite resource in the resource's status. This can help to debug composite
+// resources that never become ready, because each requeue otherwise looks the
+// same.
+func WithReconcileSummaries() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.recordSummaries = true
+	}
+}
+
+// WithCompositionCycleDetector specifies how the Reconciler should detect
+// composition reference cycles between nested composite resources.
+func WithCompositionCycleDetector(d CompositionCycleDetector) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.CompositionCycleDetector = d
+	}
+}
+
+// WithDeletionObserver specifies how the Reconciler should observe which
+// composed resources remain while a composite resource is being deleted.
+func WithDeletionObserver(o DeletionObserver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.DeletionObserver = o
+	}
+}
+
+// WithOrphaner specifies how the Reconciler should orphan composed resources
+// that were not deleted within the deletion timeout.
+func WithOrphaner(o Orphaner) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.Orphaner = o
+	}
+}
+
+// WithDeletionTimeout configures the Reconciler to orphan any composed
+// resources that have not been deleted the supplied duration after their
+// composite resource was deleted in the foreground. Orphaned composed resources
+// no longer block deletion of their composite resource, but are left behind. A
+// zero duration, the default, disables orphaning.
+func WithDeletionTimeout(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.deletionTimeout = d
+	}
+}
+
 // WithCompositeRenderer specifies how the Reconciler should render composite resources.
 func WithCompositeRenderer(rd Renderer) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -292,6 +472,7 @@ func WithCompositeRenderer(rd Renderer) ReconcilerOption {
 type composition struct {
 	CompositionFetcher
 	CompositionValidator
+	BaseResolver
 	CompositionTemplateAssociator
 }
 
@@ -301,12 +482,17 @@ type compositeResource struct {
 	Configurator
 	Renderer
 	managed.ConnectionPublisher
+	ConnectionRotator
+	CompositionCycleDetector
+	DeletionObserver
+	Orphaner
 }
 
 type composedResource struct {
 	Renderer
 	ConnectionDetailsFetcher
 	ReadinessChecker
+	DriftObserver
 }
 
 // NewReconciler returns a new Reconciler of composite resources.
@@ -326,27 +512,34 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 				CompositionValidatorFn(RejectMixedTemplates),
 				CompositionValidatorFn(RejectDuplicateNames),
 			},
+			BaseResolver:                  NewAPIBaseResolver(kube, ""),
 			CompositionTemplateAssociator: NewGarbageCollectingAssociator(kube),
 		},
 
 		composite: compositeResource{
-			Finalizer:           resource.NewAPIFinalizer(kube, finalizer),
-			CompositionSelector: NewAPILabelSelectorResolver(kube),
-			Configurator:        NewConfiguratorChain(NewAPINamingConfigurator(kube), NewAPIConfigurator(kube)),
-			ConnectionPublisher: NewAPIFilteredSecretPublisher(kube, []string{}),
-			Renderer:            RendererFn(RenderComposite),
+			Finalizer:                resource.NewAPIFinalizer(kube, finalizer),
+			CompositionSelector:      NewAPILabelSelectorResolver(kube),
+			Configurator:             NewConfiguratorChain(NewAPINamingConfigurator(kube), NewAPIConfigurator(kube)),
+			ConnectionPublisher:      NewAPIFilteredSecretPublisher(kube, []string{}),
+			ConnectionRotator:        NewAPIConnectionRotator(kube),
+			Renderer:                 RendererFn(RenderComposite),
+			CompositionCycleDetector: NewAPICompositionCycleDetector(kube),
+			DeletionObserver:         NewAPIDeletionObserver(kube),
+			Orphaner:                 NewAPIOrphaner(kube),
 		},
 
 		composed: composedResource{
 			Renderer:                 NewAPIDryRunRenderer(kube),
 			ReadinessChecker:         ReadinessCheckerFn(IsReady),
 			ConnectionDetailsFetcher: NewAPIConnectionDetailsFetcher(kube),
+			DriftObserver:            NewAPIDriftObserver(kube),
 		},
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 
-		pollInterval: defaultPollInterval,
+		pollInterval:         defaultPollInterval,
+		maxConcurrentApplies: defaultMaxConcurrentApplies,
 	}
 
 	for _, f := range opts {
@@ -367,7 +560,18 @@ type Reconciler struct {
 	log    logging.Logger
 	record event.Recorder
 
-	pollInterval time.Duration
+	pending      *PendingKinds
+	impersonator Impersonator
+
+	pollInterval         time.Duration
+	maxConcurrentApplies int
+	maxComposed          int
+	maxComposedSize      int
+	deletionTimeout      time.Duration
+	readyTimeout         time.Duration
+	restore              bool
+	recordDiffs          bool
+	recordSummaries      bool
 }
 
 // composedRenderState is a wrapper around a composed resource that tracks whether
@@ -376,16 +580,25 @@ type Reconciler struct {
 type composedRenderState struct {
 	resource       resource.Composed
 	rendered       bool
+	observeOnly    bool
+	missing        bool
+	pending        bool
 	appliedPatches []v1.Patch
 }
 
 // Reconcile a composite resource.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) { //nolint:gocyclo
 	// NOTE(negz): Like most Reconcile methods, this one is over our cyclomatic
 	// complexity goal. Be wary when adding branches, and look for functionality
 	// that could be reasonably moved into an injected dependency.
 
 	log := r.log.WithValues("request", req)
+
+	id := ""
+	if r.recordSummaries {
+		id = string(uuid.NewUUID())
+		log = log.WithValues("reconcile-id", id)
+	}
 	log.Debug("Reconciling")
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -403,6 +616,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", cr.GetName(),
 	)
 
+	// Tell folks when we can't reconcile the composite resource, so that they
+	// (and tools like kubectl wait) can tell whether it's synced. This update
+	// is best effort; we'll try again when we're requeued.
+	defer func() {
+		if err == nil {
+			return
+		}
+		cr.SetConditions(xpv1.ReconcileError(err))
+		_ = r.client.Status().Update(ctx, cr)
+	}()
+
 	if meta.WasDeleted(cr) {
 		log = log.WithValues("deletion-timestamp", cr.GetDeletionTimestamp())
 
@@ -413,6 +637,39 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 
+		// Composite resources deleted in the foreground aren't removed from
+		// the API server until their composed resources are. Let folks know
+		// which composed resources they're waiting for, and optionally give
+		// up on them.
+		if meta.FinalizerExists(cr, metav1.FinalizerDeleteDependents) {
+			remaining, err := r.composite.ObserveDeletion(ctx, cr)
+			if err != nil {
+				log.Debug(errObserveDeletion, "error", err)
+				err = errors.Wrap(err, errObserveDeletion)
+				r.record.Event(cr, event.Warning(reasonDelete, err))
+				return reconcile.Result{}, err
+			}
+
+			pending := pendingFor(cr)
+			switch {
+			case len(remaining) == 0:
+			case r.deletionTimeout > 0 && pending >= r.deletionTimeout:
+				if err := r.composite.Orphan(ctx, cr, remaining); err != nil {
+					log.Debug(errOrphan, "error", err)
+					err = errors.Wrap(err, errOrphan)
+					r.record.Event(cr, event.Warning(reasonDelete, err))
+					return reconcile.Result{}, err
+				}
+				r.record.Event(cr, event.Warning(reasonDelete, errors.Errorf(errFmtOrphaned, len(remaining), r.deletionTimeout, describe(remaining))))
+			default:
+				log.Debug("Waiting for composed resources to be deleted", "remaining", len(remaining), "pending", pending)
+				msg := fmt.Sprintf(msgFmtDeletionPending, pending, len(remaining), describe(remaining))
+				cr.SetConditions(DeletionPending(msg), xpv1.ReconcileSuccess())
+				r.summarize(cr, id, RequeueDeletionPending, msg)
+				return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+			}
+		}
+
 		if err := r.composite.RemoveFinalizer(ctx, cr); err != nil {
 			log.Debug(errRemoveFinalizer, "error", err)
 			err = errors.Wrap(err, errRemoveFinalizer)
@@ -439,13 +696,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 	r.record.Event(cr, event.Normal(reasonResolve, "Successfully selected composition"))
 
+	// A composite resource that composes itself, directly or via other
+	// composite resources, would be nested forever.
+	cycle, err := r.composite.DetectCycle(ctx, cr)
+	if err != nil {
+		log.Debug(errDetectCycle, "error", err)
+		err = errors.Wrap(err, errDetectCycle)
+		r.record.Event(cr, event.Warning(reasonCompose, err))
+		return reconcile.Result{}, err
+	}
+	if len(cycle) > 0 {
+		msg := cycleMessage(cycle)
+		log.Debug("Refusing to compose resources", "cycle", msg)
+		r.record.Event(cr, event.Warning(reasonCompose, errors.New(msg)))
+		cr.SetConditions(CompositionCycle(msg), xpv1.ReconcileError(errors.New(msg)))
+		r.summarize(cr, id, RequeueCompositionCycle, msg)
+
+		// We poll because we won't be requeued when the cycle is broken
+		// by changing a Composition.
+		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
 	// Note that this 'Composition' will be derived from a
 	// CompositionRevision if the relevant feature flag is enabled.
 	comp, err := r.composition.Fetch(ctx, cr)
 	if err != nil {
 		log.Debug(errFetchComp, "error", err)
+		if kerrors.IsNotFound(err) {
+			err = reason.Wrap(err, reason.CompositionNotFound)
+		}
 		err = errors.Wrap(err, errFetchComp)
-		r.record.Event(cr, event.Warning(reasonCompose, err))
+		r.record.Event(cr, reason.Warning(reasonCompose, err))
 		return reconcile.Result{}, err
 	}
 
@@ -453,8 +734,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// webhook, not by this controller.
 	if err := r.composition.Validate(comp); err != nil {
 		log.Debug(errValidate, "error", err)
-		err = errors.Wrap(err, errValidate)
-		r.record.Event(cr, event.Warning(reasonCompose, err))
+		err = errors.Wrap(reason.Wrap(err, reason.CompositionInvalid), errValidate)
+		r.record.Event(cr, reason.Warning(reasonCompose, err))
 		return reconcile.Result{}, err
 	}
 
@@ -469,7 +750,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	ct, err := comp.Spec.ComposedTemplates()
 	if err != nil {
 		log.Debug(errInline, "error", err)
-		err = errors.Wrap(err, errInline)
+		err = errors.Wrap(reason.Wrap(err, reason.CompositionInvalid), errInline)
+		r.record.Event(cr, reason.Warning(reasonCompose, err))
+		return reconcile.Result{}, err
+	}
+
+	ct, err = r.composition.ResolveBases(ctx, ct)
+	if err != nil {
+		log.Debug(errResolveBases, "error", err)
+		err = errors.Wrap(err, errResolveBases)
 		r.record.Event(cr, event.Warning(reasonCompose, err))
 		return reconcile.Result{}, err
 	}
@@ -482,29 +771,77 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// A restored composite resource has a new UID, and may have been restored
+	// without references to its composed resources.
+	controllable := resource.MustBeControllableBy(cr.GetUID())
+	if r.restore || IsRestored(cr) {
+		controllable = MustBeControllableByOrRestored(cr)
+		if tas, err = AssociateRestored(ctx, r.client, cr, tas); err != nil {
+			log.Debug(errRestore, "error", err)
+			err = errors.Wrap(err, errRestore)
+			r.record.Event(cr, event.Warning(reasonCompose, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Refuse to compose more resources than we're allowed to, rather than
+	// flooding the API server with composed resources.
+	if r.maxComposed > 0 && len(tas) > r.maxComposed {
+		return r.limitExceeded(ctx, log, cr, id, ReasonTooManyComposedResources, errors.Errorf(errFmtTooManyComposed, len(tas), r.maxComposed))
+	}
+
 	// We optimistically render all composed resources that we are able to
 	// with the expectation that any that we fail to render will
 	// subsequently have their error corrected by manual intervention or
 	// propagation of a required input.
 	refs := make([]corev1.ObjectReference, len(tas))
 	cds := make([]composedRenderState, len(tas))
+	var tooLarge error
+	noKind := make([]bool, len(tas))
 	for i, ta := range tas {
 		cd := composed.New(composed.FromReference(ta.Reference))
 		rendered := true
-		if err := r.composed.Render(ctx, cr, cd, ta.Template); err != nil {
+		err := r.composed.Render(ctx, cr, cd, ta.Template)
+		switch {
+		case IsNoKindMatch(err):
+			// The composed resource's CRD isn't established yet. We'll
+			// tell folks we're waiting for it below.
+			log.Debug(errRenderCD, "error", err, "index", i)
+			noKind[i] = true
+			rendered = false
+		case err != nil:
 			log.Debug(errRenderCD, "error", err, "index", i)
-			r.record.Event(cr, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRender, i)))
+			r.record.Event(cr, reason.Warning(reasonCompose, errors.Wrapf(reason.Wrap(err, reason.RenderFailed), errFmtRender, i)))
 			rendered = false
 		}
 
+		if rendered && r.maxComposedSize > 0 && tooLarge == nil {
+			size, err := SizeOf(cd)
+			if err != nil {
+				log.Debug(errMeasureComposed, "error", err, "index", i)
+				r.record.Event(cr, event.Warning(reasonCompose, err))
+				return reconcile.Result{}, err
+			}
+			if size > r.maxComposedSize {
+				tooLarge = errors.Errorf(errFmtComposedTooLarge, i, size, r.maxComposedSize)
+			}
+		}
+
 		cds[i] = composedRenderState{
 			resource:       cd,
 			rendered:       rendered,
+			observeOnly:    IsObserveOnly(ta.Template),
 			appliedPatches: filterPatches(ta.Template.Patches, patchTypesFromXR()...),
 		}
 		refs[i] = *meta.ReferenceTo(cd, cd.GetObjectKind().GroupVersionKind())
 	}
 
+	// Refuse to compose a resource that is too large, for example because a
+	// patch has exploded its size.
+	if tooLarge != nil {
+		return r.limitExceeded(ctx, log, cr, id, ReasonComposedResourceTooLarge, tooLarge)
+	}
+
 	// We persist references to our composed resources before we create
 	// them. This way we can render composed resources with
 	// non-deterministic names, and also potentially recover from any errors
@@ -517,47 +854,197 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Composed resources are applied as the Composition's ServiceAccount, if
+	// it names one, so that they're limited to what it may compose.
+	var applicator resource.Applicator = r.client
+	if sa := comp.Spec.ServiceAccountName; sa != nil && r.impersonator != nil {
+		if applicator, err = r.impersonator.Impersonate(*sa); err != nil {
+			log.Debug(errImpersonate, "error", err)
+			err = errors.Wrap(err, errImpersonate)
+			r.record.Event(cr, event.Warning(reasonCompose, err))
+			return reconcile.Result{}, err
+		}
+	}
+
 	// We apply all of our composed resources before we observe them and
 	// update the composite resource accordingly in the loop below. This
 	// ensures that issues observing and processing one composed resource
 	// won't block the application of another.
-	for _, cd := range cds {
-		// If we were unable to render the composed resource we should not try
-		// and apply it.
-		if !cd.rendered {
-			continue
+	// Composed resources are applied concurrently, because composite
+	// resources that compose many resources would otherwise spend most of
+	// each reconcile waiting on the API server.
+	report := DriftPolicyOf(cr, comp) == v1.DriftPolicyReport
+	paths := make([][]string, len(cds))
+	diffs := make([]string, len(cds))
+
+	// Composed resources are applied in waves. Composed resources that
+	// don't exist yet are only created once all composed resources in
+	// earlier waves are ready.
+	waves := Waves(tas)
+	blocked := false
+	blockedWave := 0
+	for w, wave := range waves {
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(r.maxConcurrentApplies)
+		for _, i := range wave.Indexes {
+			i := i // Pin the range variable before using it in a Goroutine.
+
+			// If we were unable to render the composed resource we should
+			// not try and apply it.
+			if !cds[i].rendered {
+				continue
+			}
+
+			wait := blocked
+			g.Go(func() error {
+				cd := cds[i]
+
+				// Observe only composed resources are never created or updated.
+				// We observe them only so that we can patch from them.
+				if cd.observeOnly {
+					observed, _, err := r.composed.ObserveDrift(gctx, cd.resource)
+					if err != nil {
+						return errors.Wrap(err, errObserveComposed)
+					}
+					if observed == nil {
+						cds[i].missing = true
+						return nil
+					}
+					cds[i].resource = observed
+					return nil
+				}
+
+				// While we wait for an earlier wave to become ready we only
+				// update composed resources that already exist.
+				if wait {
+					exists, err := r.exists(gctx, cd.resource)
+					if err != nil {
+						return err
+					}
+					if !exists {
+						cds[i].pending = true
+						return nil
+					}
+				}
+
+				// If we're reporting rather than correcting drift we only observe
+				// composed resources that already exist. We continue to create
+				// those that don't.
+				if report {
+					observed, p, err := r.composed.ObserveDrift(gctx, cd.resource)
+					if err != nil {
+						return errors.Wrap(err, errObserveDrift)
+					}
+					if observed != nil {
+						cds[i].resource = observed
+						paths[i] = p
+						return nil
+					}
+				}
+
+				// If we're recording diffs we observe existing composed
+				// resources in order to describe how applying them will change
+				// them.
+				if r.recordDiffs && !report {
+					observed, p, err := r.composed.ObserveDrift(gctx, cd.resource)
+					if err != nil {
+						return errors.Wrap(err, errObserveDrift)
+					}
+					if observed != nil && len(p) > 0 {
+						diffs[i] = DescribeDiff(cd.resource, observed, p)
+					}
+				}
+
+				err := applicator.Apply(gctx, cd.resource, append(mergeOptions(cd.appliedPatches), controllable)...)
+				if IsNoKindMatch(err) {
+					// The composed resource's CRD isn't established yet.
+					// We neither apply nor observe it until it is.
+					noKind[i] = true
+					cds[i].pending = true
+					return nil
+				}
+				return errors.Wrap(reason.WrapAPIError(err, reason.ApplyFailed), errApply)
+			})
 		}
-		if err := r.client.Apply(ctx, cd.resource, append(mergeOptions(cd.appliedPatches), resource.MustBeControllableBy(cr.GetUID()))...); err != nil {
-			log.Debug(errApply, "error", err)
-			err = errors.Wrap(err, errApply)
-			r.record.Event(cr, event.Warning(reasonCompose, err))
+		if err := g.Wait(); err != nil {
+			log.Debug("Cannot apply composed resources", "error", err)
+			r.record.Event(cr, reason.Warning(reasonCompose, err))
 			return reconcile.Result{}, err
 		}
+
+		if !blocked && w < len(waves)-1 && !r.waveReady(ctx, cds, tas, wave) {
+			blocked = true
+			blockedWave = wave.Number
+			r.record.Event(cr, event.Normal(reasonWave, fmt.Sprintf(msgFmtWaitWave, wave.Number)))
+		}
+	}
+
+	// Composite resources that compose kinds whose CRDs aren't established
+	// yet are requeued as soon as a CRD in the relevant API group changes.
+	missing := make([]schema.GroupKind, 0)
+	seen := map[schema.GroupKind]bool{}
+	for i := range cds {
+		gk := cds[i].resource.GetObjectKind().GroupVersionKind().GroupKind()
+		if noKind[i] && !seen[gk] {
+			seen[gk] = true
+			missing = append(missing, gk)
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		msg := fmt.Sprintf(msgFmtWaitCRD, describeKinds(missing))
+		log.Debug("Waiting for CustomResourceDefinitions", "kinds", describeKinds(missing))
+		r.record.Event(cr, event.Normal(reasonWaitCRD, msg))
+		cr.SetConditions(WaitingForCRD(msg))
+		if r.pending != nil {
+			r.pending.Wait(types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}, missing...)
+		}
+	case resource.IsConditionTrue(cr.GetCondition(TypeWaitingForCRD)):
+		cr.SetConditions(ComposedKindsEstablished())
+	}
+
+	drifted := make([]string, 0)
+	for i := range cds {
+		kind, name := cds[i].resource.GetObjectKind().GroupVersionKind().Kind, cds[i].resource.GetName()
+		if cds[i].missing {
+			// The composed resource will never become ready if it doesn't
+			// exist, so we tell folks why.
+			r.record.Event(cr, event.Warning(reasonCompose, errors.Errorf(errFmtObserveMissing, kind, name)))
+		}
+		if len(paths[i]) > 0 {
+			drifted = append(drifted, fmt.Sprintf("%s %q (%s)", kind, name, strings.Join(paths[i], ", ")))
+		}
+		if diffs[i] != "" {
+			r.record.Event(cr, event.Normal(reasonDiff, fmt.Sprintf(msgFmtDiff, kind, name, diffs[i])))
+		}
 	}
 
 	conn := managed.ConnectionDetails{}
 	ready := 0
+	unready := make([]string, 0)
+	nested := ""
 	for i, tpl := range comp.Spec.Resources {
 		cd := cds[i]
 
 		// If we were unable to render the composed resource we should not try
-		// and to observe it.
-		if !cd.rendered {
+		// and to observe it. Nor should we observe a composed resource that
+		// is waiting for an earlier wave to become ready.
+		if !cd.rendered || cd.pending {
 			continue
 		}
 
 		if err := r.composite.Render(ctx, cr, cd.resource, tpl); err != nil {
 			log.Debug(errRenderCR, "error", err)
-			err = errors.Wrap(err, errRenderCR)
-			r.record.Event(cr, event.Warning(reasonCompose, err))
+			err = errors.Wrap(reason.Wrap(err, reason.RenderFailed), errRenderCR)
+			r.record.Event(cr, reason.Warning(reasonCompose, err))
 			return reconcile.Result{}, err
 		}
 
 		c, err := r.composed.FetchConnectionDetails(ctx, cd.resource, tpl)
 		if err != nil {
 			log.Debug(errFetchSecret, "error", err)
-			err = errors.Wrap(err, errFetchSecret)
-			r.record.Event(cr, event.Warning(reasonCompose, err))
+			err = errors.Wrap(reason.Wrap(err, reason.ConnectionDetailsFailed), errFetchSecret)
+			r.record.Event(cr, reason.Warning(reasonCompose, err))
 			return reconcile.Result{}, err
 		}
 
@@ -568,13 +1055,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		rdy, err := r.composed.IsReady(ctx, cd.resource, tpl)
 		if err != nil {
 			log.Debug(errReadiness, "error", err)
-			err = errors.Wrap(err, errReadiness)
-			r.record.Event(cr, event.Warning(reasonCompose, err))
+			err = errors.Wrap(reason.Wrap(err, reason.ReadinessCheckFailed), errReadiness)
+			r.record.Event(cr, reason.Warning(reasonCompose, err))
 			return reconcile.Result{}, err
 		}
 
 		if rdy {
 			ready++
+		} else {
+			unready = append(unready, fmt.Sprintf("%s %q", cd.resource.GetObjectKind().GroupVersionKind().Kind, cd.resource.GetName()))
+		}
+
+		// A nested composite resource that is part of a cycle will never
+		// become ready. We surface the cycle at each layer.
+		if msg, ok := nestedCycle(cd.resource); ok {
+			nested = msg
 		}
 	}
 
@@ -587,8 +1082,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	updated := cr.DeepCopyObject().(client.Object)
 	if err := r.client.Apply(ctx, updated, mergeOptions(filterToXRPatches(tas))...); err != nil {
 		log.Debug(errUpdate, "error", err)
-		err = errors.Wrap(err, errUpdate)
-		r.record.Event(cr, event.Warning(reasonCompose, err))
+		err = errors.Wrap(reason.WrapAPIError(err, reason.ApplyFailed), errUpdate)
+		r.record.Event(cr, reason.Warning(reasonCompose, err))
 		return reconcile.Result{}, err
 	}
 
@@ -606,6 +1101,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	r.record.Event(cr, event.Normal(reasonCompose, "Successfully composed resources"))
+	cr.SetConditions(xpv1.ReconcileSuccess())
+
+	if report {
+		cr.SetConditions(NoDrift())
+		if len(drifted) > 0 {
+			err := errors.Errorf(errFmtDrift, strings.Join(drifted, ", "))
+			log.Debug("Composed resources have drifted", "error", err)
+			cr.SetConditions(Drifted(err.Error()))
+			r.record.Event(cr, event.Warning(reasonDrift, err))
+		}
+	}
+
+	switch {
+	case nested != "":
+		cr.SetConditions(CompositionCycle(nested))
+	case resource.IsConditionTrue(cr.GetCondition(TypeCompositionCycle)):
+		cr.SetConditions(NoCompositionCycle())
+	}
+
+	if resource.IsConditionTrue(cr.GetCondition(TypeLimitExceeded)) {
+		cr.SetConditions(WithinLimits())
+	}
 
 	published, err := r.composite.PublishConnection(ctx, cr, conn)
 	if err != nil {
@@ -620,6 +1137,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(cr, event.Normal(reasonPublish, "Successfully published connection details"))
 	}
 
+	rotated, err := r.composite.RotateConnection(ctx, cr, conn, RotatingConnectionDetails(comp))
+	if err != nil {
+		log.Debug(errRotate, "error", err)
+		err = errors.Wrap(err, errRotate)
+		r.record.Event(cr, event.Warning(reasonPublish, err))
+		return reconcile.Result{}, err
+	}
+	if rotated {
+		log.Debug("Rotating connection details changed")
+		r.record.Event(cr, event.Normal(reasonPublish, "Successfully published rotated connection details"))
+	}
+
 	// TODO(muvaf):
 	// * Report which resources are not ready.
 	// * If a resource becomes Unavailable at some point, should we still report
@@ -628,16 +1157,98 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// We want to requeue to wait for our composed resources to
 		// become ready, since we can't watch them.
 		cr.SetConditions(xpv1.Creating())
+
+		// Tell folks when a composite resource is taking longer to become
+		// ready than expected, so that they can tell it's stuck.
+		if r.readyTimeout > 0 && NotReadyFor(cr, time.Now()) > r.readyTimeout && !resource.IsConditionTrue(cr.GetCondition(TypeReadyTimeoutExceeded)) {
+			msg := fmt.Sprintf(msgFmtReadyTimeout, r.readyTimeout)
+			log.Debug(msg)
+			r.record.Event(cr, event.Warning(reasonTimeout, errors.New(msg)))
+			cr.SetConditions(ReadyTimeoutExceeded(msg))
+		}
+
+		if len(missing) > 0 {
+			// We'll be requeued as soon as a relevant CRD changes, so
+			// there's no need to requeue sooner than our poll interval.
+			r.summarize(cr, id, RequeueWaitCRD, fmt.Sprintf(msgFmtWaitCRD, describeKinds(missing)))
+			return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		if blocked {
+			r.summarize(cr, id, RequeueWaitWave, fmt.Sprintf(msgFmtWaitWave, blockedWave))
+		} else {
+			msg := fmt.Sprintf(msgFmtNotReady, ready, len(refs))
+			if len(unready) > 0 {
+				msg = fmt.Sprintf(msgFmtUnready, msg, strings.Join(unready, ", "))
+			}
+			r.summarize(cr, id, RequeueNotReady, msg)
+		}
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
 	}
 
 	// We requeue after our poll interval because we can't watch composed
 	// resources - we can't know what type of resources we might compose
-	// when this controller is started.
+	// when this controller is started. We may requeue sooner to re-read
+	// rotating connection details.
+	after := ConnectionDetailsTTLOf(comp, r.pollInterval)
 	cr.SetConditions(xpv1.Available())
+	if resource.IsConditionTrue(cr.GetCondition(TypeReadyTimeoutExceeded)) {
+		cr.SetConditions(WithinReadyTimeout())
+	}
+	r.summarize(cr, id, RequeuePoll, fmt.Sprintf(msgFmtPoll, after))
+	return reconcile.Result{RequeueAfter: after}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+}
+
+// limitExceeded records that composing resources for the supplied composite
+// resource would exceed a limit, and refuses to compose them.
+func (r *Reconciler) limitExceeded(ctx context.Context, log logging.Logger, cr resource.Composite, id string, rs xpv1.ConditionReason, err error) (reconcile.Result, error) {
+	log.Debug("Refusing to compose resources", "error", err)
+	r.record.Event(cr, event.Warning(reasonCompose, err))
+	cr.SetConditions(LimitExceeded(rs, err.Error()), xpv1.ReconcileError(err))
+	r.summarize(cr, id, RequeueLimitExceeded, err.Error())
+
+	// We poll because we won't be requeued when the Composition, or the
+	// limit, changes.
 	return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
 }
 
+// summarize records why the supplied composite resource is being requeued in
+// its status, if the Reconciler records reconcile summaries.
+func (r *Reconciler) summarize(cr resource.Composite, id, reason, msg string) {
+	if !r.recordSummaries {
+		return
+	}
+	r.log.Debug("Requeueing composite resource", "reconcile-id", id, "name", cr.GetName(), "reason", reason, "message", msg)
+	SetReconcileSummary(cr, ReconcileSummary{ID: id, Time: metav1.Now(), Reason: reason, Message: msg})
+}
+
+// exists returns true if the supplied composed resource exists.
+func (r *Reconciler) exists(ctx context.Context, cd resource.Composed) (bool, error) {
+	if cd.GetName() == "" {
+		return false, nil
+	}
+	existing := composed.New(composed.FromReference(*meta.ReferenceTo(cd, cd.GetObjectKind().GroupVersionKind())))
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}, existing)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, errors.Wrap(err, errGetComposed)
+}
+
+// waveReady returns true if all of the composed resources in the supplied wave
+// exist and are ready.
+func (r *Reconciler) waveReady(ctx context.Context, cds []composedRenderState, tas []TemplateAssociation, wave Wave) bool {
+	for _, i := range wave.Indexes {
+		cd := cds[i]
+		if !cd.rendered || cd.missing || cd.pending {
+			return false
+		}
+		if rdy, err := r.composed.IsReady(ctx, cd.resource, tas[i].Template); err != nil || !rdy {
+			return false
+		}
+	}
+	return true
+}
+
 // filterToXRPatches selects patches defined in composed templates,
 // whose type is one of the XR-targeting patches
 // (e.g. v1.PatchTypeToCompositeFieldPath or v1.PatchTypeCombineToComposite)
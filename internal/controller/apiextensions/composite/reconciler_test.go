@@ -18,13 +18,20 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,11 +39,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
@@ -109,6 +119,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -137,6 +148,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.FinalizerFns{
@@ -169,6 +181,7 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.FinalizerFns{
@@ -187,6 +200,161 @@ func TestReconcile(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ObserveDeletionError": {
+			reason: "We should return any error encountered while observing the deletion of composed resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								now := metav1.Now()
+								obj.SetDeletionTimestamp(&now)
+								obj.SetFinalizers([]string{metav1.FinalizerDeleteDependents})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						UnpublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+							return nil
+						},
+					}),
+					WithDeletionObserver(DeletionObserverFn(func(_ context.Context, _ resource.Composite) ([]corev1.ObjectReference, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errObserveDeletion),
+			},
+		},
+		"DeletionPending": {
+			reason: "We should report which composed resources remain and poll rather than remove our finalizer while a foreground deletion is pending.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								now := metav1.Now()
+								obj.SetDeletionTimestamp(&now)
+								obj.SetFinalizers([]string{metav1.FinalizerDeleteDependents})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								// The message includes how long deletion has
+								// been pending, so we don't compare it exactly.
+								got := cr.GetCondition(TypeDeletionProgress)
+								want := DeletionPending(got.Message)
+								if diff := cmp.Diff(want, got); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								if !strings.Contains(got.Message, `Bucket "stuck"`) {
+									t.Errorf("Status().Update(...): message %q does not name the remaining composed resource", got.Message)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithCompositeFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(ctx context.Context, obj resource.Object) error {
+							t.Errorf("RemoveFinalizer(...): we should not remove our finalizer while composed resources remain")
+							return nil
+						},
+					}),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						UnpublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+							return nil
+						},
+					}),
+					WithDeletionObserver(DeletionObserverFn(func(_ context.Context, _ resource.Composite) ([]corev1.ObjectReference, error) {
+						return []corev1.ObjectReference{{Kind: "Bucket", Name: "stuck"}}, nil
+					})),
+					WithDeletionTimeout(time.Hour),
+					WithOrphaner(OrphanerFn(func(_ context.Context, _ resource.Composite, _ []corev1.ObjectReference) error {
+						t.Errorf("Orphan(...): we should not orphan composed resources before the deletion timeout")
+						return nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"OrphanError": {
+			reason: "We should return any error encountered while orphaning composed resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								then := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+								obj.SetDeletionTimestamp(&then)
+								obj.SetFinalizers([]string{metav1.FinalizerDeleteDependents})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						UnpublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+							return nil
+						},
+					}),
+					WithDeletionObserver(DeletionObserverFn(func(_ context.Context, _ resource.Composite) ([]corev1.ObjectReference, error) {
+						return []corev1.ObjectReference{{Kind: "Bucket", Name: "stuck"}}, nil
+					})),
+					WithDeletionTimeout(time.Hour),
+					WithOrphaner(OrphanerFn(func(_ context.Context, _ resource.Composite, _ []corev1.ObjectReference) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errOrphan),
+			},
+		},
+		"OrphanAfterTimeout": {
+			reason: "We should orphan any remaining composed resources and remove our finalizer once the deletion timeout has passed.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								then := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+								obj.SetDeletionTimestamp(&then)
+								obj.SetFinalizers([]string{metav1.FinalizerDeleteDependents})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						UnpublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+							return nil
+						},
+					}),
+					WithDeletionObserver(DeletionObserverFn(func(_ context.Context, _ resource.Composite) ([]corev1.ObjectReference, error) {
+						return []corev1.ObjectReference{{Kind: "Bucket", Name: "stuck"}}, nil
+					})),
+					WithDeletionTimeout(time.Hour),
+					WithOrphaner(OrphanerFn(func(_ context.Context, _ resource.Composite, _ []corev1.ObjectReference) error {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"AddFinalizerError": {
 			reason: "We should return any error encountered while adding finalizer.",
 			args: args{
@@ -194,7 +362,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.FinalizerFns{
@@ -215,7 +384,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -228,6 +398,67 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errSelectComp),
 			},
 		},
+		"DetectCycleError": {
+			reason: "We should return any error encountered while detecting composition cycles.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionCycleDetector(CompositionCycleDetectorFn(func(_ context.Context, _ resource.Composite) ([]string, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDetectCycle),
+			},
+		},
+		"CompositionCycle": {
+			reason: "We should report a composition cycle as a condition and poll rather than compose resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								want := CompositionCycle("composition reference cycle: A (a) -> B (b) -> A (a)")
+								if diff := cmp.Diff(want, cr.GetCondition(TypeCompositionCycle), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionCycleDetector(CompositionCycleDetectorFn(func(_ context.Context, _ resource.Composite) ([]string, error) {
+						return []string{"A (a)", "B (b)", "A (a)"}, nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						t.Errorf("Fetch(...): we should not fetch a Composition when a cycle was detected")
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"FetchCompositionError": {
 			reason: "We should return any error encountered while fetching a composition.",
 			args: args{
@@ -235,7 +466,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -259,7 +491,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -284,7 +517,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -312,7 +546,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
 							return nil
@@ -344,6 +579,41 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errors.New("cannot find PatchSet by name nonexistent-patchset"), errInline),
 			},
 		},
+		"ResolveBasesError": {
+			reason: "We should return any error encountered while resolving the base resources of Composition templates.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						return &v1.Composition{}, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithBaseResolver(BaseResolverFn(func(_ context.Context, _ []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveBases),
+			},
+		},
 		"AssociateTemplatesError": {
 			reason: "We should return any error encountered while associating Composition templates with composed resources.",
 			args: args{
@@ -351,7 +621,8 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet: test.NewMockGetFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
 							return nil
@@ -385,8 +656,9 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet:    test.NewMockGetFn(nil),
-							MockUpdate: test.NewMockUpdateFn(errBoom),
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(errBoom),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -413,19 +685,27 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUpdateComposite),
 			},
 		},
-		"ApplyComposedError": {
-			reason: "We should return any error encountered while applying a composed resource.",
+		"TooManyComposedResources": {
+			reason: "We should report exceeding the maximum number of composed resources as a condition and poll rather than compose resources.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet:    test.NewMockGetFn(nil),
-							MockUpdate: test.NewMockUpdateFn(nil),
+							MockGet: test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(_ client.Object) error {
+								t.Errorf("Update(...): we should not persist composed resource references when a limit is exceeded")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								want := LimitExceeded(ReasonTooManyComposedResources, fmt.Sprintf(errFmtTooManyComposed, 2, 1))
+								if diff := cmp.Diff(want, cr.GetCondition(TypeLimitExceeded), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
-						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
-							return errBoom
-						}),
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
 					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
@@ -434,7 +714,7 @@ func TestReconcile(t *testing.T) {
 					})),
 					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
 						c := &v1.Composition{Spec: v1.CompositionSpec{
-							Resources: []v1.ComposedTemplate{{}},
+							Resources: []v1.ComposedTemplate{{}, {}},
 						}}
 						return c, nil
 					})),
@@ -442,26 +722,43 @@ func TestReconcile(t *testing.T) {
 					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
 						return nil
 					})),
-					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, _ resource.Composed, _ v1.ComposedTemplate) error {
+						t.Errorf("Render(...): we should not render composed resources when a limit is exceeded")
 						return nil
 					})),
+					WithMaxComposedResources(1),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errApply),
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
-		"FetchConnectionDetailsError": {
-			reason: "We should return any error encountered while fetching a composed resource's connection details.",
+		"ComposedResourceTooLarge": {
+			reason: "We should report exceeding the maximum size of a composed resource as a condition and poll rather than compose resources.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet:    test.NewMockGetFn(nil),
-							MockUpdate: test.NewMockUpdateFn(nil),
+							MockGet: test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(_ client.Object) error {
+								t.Errorf("Update(...): we should not persist composed resource references when a limit is exceeded")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								cd := composed.New(composed.FromReference(corev1.ObjectReference{}))
+								cd.SetName(strings.Repeat("a", 64))
+								size, _ := SizeOf(cd)
+								want := LimitExceeded(ReasonComposedResourceTooLarge, fmt.Sprintf(errFmtComposedTooLarge, 0, size, 32))
+								if diff := cmp.Diff(want, cr.GetCondition(TypeLimitExceeded)); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
-						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we should not apply composed resources when a limit is exceeded")
 							return nil
 						}),
 					}),
@@ -480,20 +777,19 @@ func TestReconcile(t *testing.T) {
 					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
 						return nil
 					})),
-					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, cd resource.Composed, _ v1.ComposedTemplate) error {
+						cd.SetName(strings.Repeat("a", 64))
 						return nil
 					})),
-					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
-						return nil, errBoom
-					})),
+					WithMaxComposedResourceSize(32),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errFetchSecret),
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
-		"CheckReadinessError": {
-			reason: "We should return any error encountered while checking whether a composed resource is ready.",
+		"ApplyComposedError": {
+			reason: "We should return any error encountered while applying a composed resource.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -504,7 +800,7 @@ func TestReconcile(t *testing.T) {
 							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
-							return nil
+							return errBoom
 						}),
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -525,8 +821,186 @@ func TestReconcile(t *testing.T) {
 					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
 						return nil
 					})),
-					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
-						return nil, nil
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApply),
+			},
+		},
+		"ApplyComposedConcurrently": {
+			reason: "We should apply composed resources concurrently, up to the configured limit.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: func() resource.Applicator {
+							// Each apply waits until all three composed
+							// resources are being applied at once.
+							wg := &sync.WaitGroup{}
+							wg.Add(3)
+							return resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+								wg.Done()
+								all := make(chan struct{})
+								go func() { wg.Wait(); close(all) }()
+								select {
+								case <-all:
+									return errBoom
+								case <-time.After(5 * time.Second):
+									return errors.New("composed resources were not applied concurrently")
+								}
+							})
+						}(),
+					}),
+					WithMaxConcurrentApplies(3),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}, {}, {}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApply),
+			},
+		},
+		"ObserveDiffError": {
+			reason: "We should return any error encountered while observing a composed resource in order to record its diff.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithDiffRecording(),
+					WithDriftObserver(DriftObserverFn(func(_ context.Context, _ resource.Composed) (resource.Composed, []string, error) {
+						return nil, nil, errBoom
+					})),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errObserveDrift),
+			},
+		},
+		"FetchConnectionDetailsError": {
+			reason: "We should return any error encountered while fetching a composed resource's connection details.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchSecret),
+			},
+		},
+		"CheckReadinessError": {
+			reason: "We should return any error encountered while checking whether a composed resource is ready.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, nil
 					})),
 					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
 						return false, errBoom
@@ -697,7 +1171,52 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 		"PublishConnectionDetailsError": {
-			reason: "We should return any error encountered while publishing connection details.",
+			reason: "We should return any error encountered while publishing connection details, and report that the composite resource is not synced.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								want := xpv1.ReconcileError(errors.Wrap(errBoom, errPublish))
+								if diff := cmp.Diff(want, cr.GetCondition(xpv1.TypeSynced)); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						return &v1.Composition{}, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
+							return false, errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPublish),
+			},
+		},
+		"RotateConnectionError": {
+			reason: "We should return any error encountered while recording the rotation of connection details.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -725,13 +1244,16 @@ func TestReconcile(t *testing.T) {
 					})),
 					WithConnectionPublishers(managed.ConnectionPublisherFns{
 						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
-							return false, errBoom
+							return true, nil
 						},
 					}),
+					WithConnectionRotator(ConnectionRotatorFn(func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails, _ []string) (bool, error) {
+						return false, errBoom
+					})),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errPublish),
+				err: errors.Wrap(errBoom, errRotate),
 			},
 		},
 		"ComposedResourcesNotReady": {
@@ -785,8 +1307,8 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
-		"ComposedResourcesReady": {
-			reason: "We should requeue after our poll interval if all of our composed resources are ready.",
+		"ObserveOnlyComposedResourceMissing": {
+			reason: "We should neither create nor update an observe only composed resource, and requeue until it exists.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -797,6 +1319,9 @@ func TestReconcile(t *testing.T) {
 							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							if _, ok := r.(*composed.Unstructured); ok {
+								t.Errorf("Apply(...): we should not apply an observe only composed resource")
+							}
 							return nil
 						}),
 					}),
@@ -806,8 +1331,9 @@ func TestReconcile(t *testing.T) {
 						return nil
 					})),
 					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						mp := v1.ManagementPolicyObserveOnly
 						c := &v1.Composition{Spec: v1.CompositionSpec{
-							Resources: []v1.ComposedTemplate{{}},
+							Resources: []v1.ComposedTemplate{{ManagementPolicy: &mp}},
 						}}
 						return c, nil
 					})),
@@ -818,66 +1344,828 @@ func TestReconcile(t *testing.T) {
 					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
 						return nil
 					})),
-					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, _ resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
-						return cd, nil
+					WithDriftObserver(DriftObserverFn(func(_ context.Context, _ resource.Composed) (resource.Composed, []string, error) {
+						// Our observe only composed resource doesn't exist.
+						return nil, nil, nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, nil
 					})),
 					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
-						// Our one resource is ready.
-						return true, nil
+						return false, nil
 					})),
 					WithConnectionPublishers(managed.ConnectionPublisherFns{
-						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
-							want := cd
-							if diff := cmp.Diff(want, got); diff != "" {
-								t.Errorf("PublishConnection(...): -want, +got:\n%s", diff)
-							}
-							return true, nil
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
 						},
 					}),
 				},
 			},
 			want: want{
-				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+				r: reconcile.Result{Requeue: true},
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.mgr, tc.args.of, append(tc.args.opts, WithLogger(testLog))...)
-			got, err := r.Reconcile(context.Background(), reconcile.Request{})
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
-			}
-		})
-	}
-}
-
-func TestFilterToXRPatches(t *testing.T) {
-	toXR1 := v1.Patch{
-		Type: v1.PatchTypeToCompositeFieldPath,
-	}
-	toXR2 := v1.Patch{
-		Type: v1.PatchTypeCombineToComposite,
-	}
-	fromXR1 := v1.Patch{
-		Type: v1.PatchTypeFromCompositeFieldPath,
-	}
-	fromXR2 := v1.Patch{
-		Type: v1.PatchTypeCombineFromComposite,
-	}
-	type args struct {
-		tas []TemplateAssociation
-	}
-	tests := map[string]struct {
-		args args
-		want []v1.Patch
-	}{
-		"NonEmptyToXRPatches": {
+		"WaitingForCRD": {
+			reason: "We should report that we're waiting for the CRD of a composed resource kind that isn't established, and wait to be requeued when it is.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								want := WaitingForCRD(fmt.Sprintf(msgFmtWaitCRD, "Bucket.example.org"))
+								if diff := cmp.Diff(want, cr.GetCondition(TypeWaitingForCRD)); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*composed.Unstructured); ok {
+								t.Errorf("Apply(...): we should not apply a composed resource whose CRD isn't established")
+							}
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, cd resource.Composed, _ v1.ComposedTemplate) error {
+						gk := schema.GroupKind{Group: "example.org", Kind: "Bucket"}
+						cd.GetObjectKind().SetGroupVersionKind(gk.WithVersion("v1"))
+						return errors.Wrap(&kmeta.NoKindMatchError{GroupKind: gk}, errName)
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.Composed, _ v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"ImpersonateError": {
+			reason: "We should return any error encountered impersonating the Composition's ServiceAccount.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources:          []v1.ComposedTemplate{{}},
+							ServiceAccountName: pointer.String("composer"),
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, _ resource.Composed, _ v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithImpersonator(ImpersonatorFn(func(_ string) (resource.Applicator, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errImpersonate),
+			},
+		},
+		"ImpersonateServiceAccount": {
+			reason: "We should apply composed resources as the Composition's ServiceAccount.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*composed.Unstructured); ok {
+								t.Errorf("Apply(...): we should not apply a composed resource as Crossplane")
+							}
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources:          []v1.ComposedTemplate{{}},
+							ServiceAccountName: pointer.String("composer"),
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, _ resource.Composed, _ v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithImpersonator(ImpersonatorFn(func(sa string) (resource.Applicator, error) {
+						if sa != "composer" {
+							t.Errorf("Impersonate(...): want ServiceAccount %q, got %q", "composer", sa)
+						}
+						return resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return errBoom
+						}), nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApply),
+			},
+		},
+		"ComposedResourcesWaitForEarlierWave": {
+			reason: "We should not create a composed resource until all composed resources in earlier waves are ready.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								if _, ok := obj.(*composed.Unstructured); ok {
+									// None of our composed resources exist.
+									return kerrors.NewNotFound(schema.GroupResource{}, obj.GetName())
+								}
+								return nil
+							}),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							if r.GetName() == "late" {
+								t.Errorf("Apply(...): we should not create a composed resource before earlier waves are ready")
+							}
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{Wave: pointer.Int(1)}, {}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, cd resource.Composed, tpl v1.ComposedTemplate) error {
+						cd.SetName("early")
+						if WaveOf(tpl) > 0 {
+							cd.SetName("late")
+						}
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, cd resource.Composed, _ v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						if cd.GetName() == "late" {
+							t.Errorf("FetchConnectionDetails(...): we should not observe a composed resource that is waiting for an earlier wave")
+						}
+						return nil, nil
+					})),
+					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
+						return false, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposedResourcesReady": {
+			reason: "We should report that the composite resource is synced and ready, and requeue after our poll interval, if all of our composed resources are ready.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								for _, want := range []xpv1.Condition{xpv1.ReconcileSuccess(), xpv1.Available()} {
+									if diff := cmp.Diff(want, cr.GetCondition(want.Type)); diff != "" {
+										t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+									}
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, _ resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return cd, nil
+					})),
+					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
+						// Our one resource is ready.
+						return true, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
+							want := cd
+							if diff := cmp.Diff(want, got); diff != "" {
+								t.Errorf("PublishConnection(...): -want, +got:\n%s", diff)
+							}
+							return true, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"RotatingConnectionDetails": {
+			reason: "We should requeue after the Composition's connection details TTL if it has rotating connection details.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(nil),
+							MockUpdate:       test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{
+								ConnectionDetails: []v1.ConnectionDetail{{
+									FromConnectionSecretKey: pointer.String("password"),
+									Rotating:                pointer.Bool(true),
+								}},
+							}},
+							ConnectionDetailsTTL: &metav1.Duration{Duration: 10 * time.Second},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, _ resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return cd, nil
+					})),
+					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
+						return true, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
+							return true, nil
+						},
+					}),
+					WithConnectionRotator(ConnectionRotatorFn(func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails, rotating []string) (bool, error) {
+						if diff := cmp.Diff([]string{"password"}, rotating); diff != "" {
+							t.Errorf("RotateConnection(...): -want, +got:\n%s", diff)
+						}
+						return true, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 10 * time.Second},
+			},
+		},
+		"NestedCompositionCycle": {
+			reason: "We should report a composition cycle that a nested composite resource is part of.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+								cr := obj.(*composite.Unstructured)
+								want := CompositionCycle(`composed resource XNested "cool-nested" is part of a composition reference cycle`)
+								if diff := cmp.Diff(want, cr.GetCondition(TypeCompositionCycle), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							// Our nested composite resource reports a cycle.
+							if cd, ok := r.(*composed.Unstructured); ok {
+								cd.SetKind("XNested")
+								cd.SetName("cool-nested")
+								cd.SetConditions(CompositionCycle("composition reference cycle"))
+							}
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, _ resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
+						return false, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ReportDrift": {
+			reason: "We should report, but not correct, drift of existing composed resources if our drift policy is Report.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o client.Object) error {
+								cr := o.(resource.Composite)
+								if diff := cmp.Diff(Drifted(`composed resources have drifted from their desired state: Instance "cool-instance" (spec.region)`), cr.GetCondition(TypeDrifted), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+									t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							// The XR is applied as a deep copy, which
+							// isn't a resource.Composite.
+							if _, ok := r.(*composed.Unstructured); ok {
+								t.Errorf("Apply(...): should not apply an existing composed resource when reporting drift")
+							}
+							return nil
+						}),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+						report := v1.DriftPolicyReport
+						c := &v1.Composition{Spec: v1.CompositionSpec{
+							Resources:   []v1.ComposedTemplate{{}},
+							DriftPolicy: &report,
+						}}
+						return c, nil
+					})),
+					WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+						return nil
+					})),
+					WithRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate) error {
+						return nil
+					})),
+					WithDriftObserver(DriftObserverFn(func(_ context.Context, _ resource.Composed) (resource.Composed, []string, error) {
+						observed := composed.New()
+						observed.SetKind("Instance")
+						observed.SetName("cool-instance")
+						return observed, []string{"spec.region"}, nil
+					})),
+					WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, _ resource.Composed, t v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) (ready bool, err error) {
+						return true, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, tc.args.of, append(tc.args.opts, WithLogger(testLog))...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A captureRecorder captures the events it records.
+type captureRecorder struct {
+	mx     sync.Mutex
+	events []event.Event
+}
+
+func (c *captureRecorder) Event(_ runtime.Object, e event.Event) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.events = append(c.events, e)
+}
+
+func (c *captureRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return c
+}
+
+func TestReconcileWaves(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	// Our composed resources are named for their wave.
+	names := map[int]string{0: "first", 1: "second", 2: "third"}
+
+	type args struct {
+		// ready is the names of the composed resources that are ready.
+		ready map[string]bool
+	}
+	type want struct {
+		r reconcile.Result
+
+		// events are the wave events we expect to be recorded.
+		events []event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SecondWaveNotReady": {
+			reason: "We should create composed resources in a ready wave, and record that we're waiting for the first wave that is not ready.",
+			args: args{
+				ready: map[string]bool{"first": true},
+			},
+			want: want{
+				r:      reconcile.Result{Requeue: true},
+				events: []event.Event{event.Normal(reasonWave, fmt.Sprintf(msgFmtWaitWave, 1))},
+			},
+		},
+		"AllWavesReady": {
+			reason: "We should not record that we're waiting for a wave when all waves are ready.",
+			args: args{
+				ready: map[string]bool{"first": true, "second": true, "third": true},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &captureRecorder{}
+			r := NewReconciler(&fake.Manager{}, resource.CompositeKind(schema.GroupVersionKind{}),
+				WithLogger(testLog),
+				WithRecorder(rec),
+				WithClientApplicator(resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if _, ok := obj.(*composed.Unstructured); ok && !tc.args.ready[obj.GetName()] {
+								// Only ready composed resources exist.
+								return kerrors.NewNotFound(schema.GroupResource{}, obj.GetName())
+							}
+							return nil
+						}),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						if o.GetName() == names[2] && !tc.args.ready[names[1]] {
+							t.Errorf("Apply(...): we should not create a composed resource before earlier waves are ready")
+						}
+						return nil
+					}),
+				}),
+				WithCompositeFinalizer(resource.NewNopFinalizer()),
+				WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+					cr.SetCompositionReference(&corev1.ObjectReference{})
+					return nil
+				})),
+				WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+					c := &v1.Composition{Spec: v1.CompositionSpec{
+						Resources: []v1.ComposedTemplate{{Wave: pointer.Int(2)}, {Wave: pointer.Int(1)}, {}},
+					}}
+					return c, nil
+				})),
+				WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+				WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+					return nil
+				})),
+				WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, cd resource.Composed, tpl v1.ComposedTemplate) error {
+					cd.SetName(names[WaveOf(tpl)])
+					return nil
+				})),
+				WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.Composed, _ v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+					return nil, nil
+				})),
+				WithReadinessChecker(ReadinessCheckerFn(func(_ context.Context, cd resource.Composed, _ v1.ComposedTemplate) (bool, error) {
+					return tc.args.ready[cd.GetName()], nil
+				})),
+				WithConnectionPublishers(managed.ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+						return false, nil
+					},
+				}),
+			)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// The wave event must not share a reason with any other event,
+			// or events would be throttled together.
+			var events []event.Event
+			for _, e := range rec.events {
+				if e.Reason == reasonWave {
+					events = append(events, e)
+					continue
+				}
+				if e.Message == fmt.Sprintf(msgFmtWaitWave, 0) || e.Message == fmt.Sprintf(msgFmtWaitWave, 1) {
+					t.Errorf("\n%s\nr.Reconcile(...): wave event recorded with reason %q", tc.reason, e.Reason)
+				}
+			}
+			if diff := cmp.Diff(tc.want.events, events); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want wave events, +got wave events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileSummaries(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	// Our composed resources are named for their wave.
+	names := map[int]string{0: "first", 1: "second", 2: "third"}
+
+	type args struct {
+		// ready is the names of the composed resources that are ready.
+		ready map[string]bool
+		opts  []ReconcilerOption
+	}
+	type want struct {
+		r       reconcile.Result
+		summary *ReconcileSummary
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "We should not record a reconcile summary unless summaries are enabled.",
+			args: args{
+				ready: map[string]bool{"first": true},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WaitForWave": {
+			reason: "We should record that we're waiting for the first wave that is not ready.",
+			args: args{
+				ready: map[string]bool{"first": true},
+				opts:  []ReconcilerOption{WithReconcileSummaries()},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+				summary: &ReconcileSummary{
+					Reason:  RequeueWaitWave,
+					Message: fmt.Sprintf(msgFmtWaitWave, 1),
+				},
+			},
+		},
+		"WaitForReady": {
+			reason: "We should record which composed resources we're waiting for when every wave has been created.",
+			args: args{
+				ready: map[string]bool{"first": true, "second": true},
+				opts:  []ReconcilerOption{WithReconcileSummaries()},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+				summary: &ReconcileSummary{
+					Reason:  RequeueNotReady,
+					Message: fmt.Sprintf(msgFmtUnready, fmt.Sprintf(msgFmtNotReady, 2, 3), `Bucket "third"`),
+				},
+			},
+		},
+		"Poll": {
+			reason: "We should record that we're polling when all composed resources are ready.",
+			args: args{
+				ready: map[string]bool{"first": true, "second": true, "third": true},
+				opts:  []ReconcilerOption{WithReconcileSummaries()},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+				summary: &ReconcileSummary{
+					Reason:  RequeuePoll,
+					Message: fmt.Sprintf(msgFmtPoll, defaultPollInterval),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var summary *ReconcileSummary
+			opts := []ReconcilerOption{
+				WithLogger(testLog),
+				WithClientApplicator(resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if _, ok := obj.(*composed.Unstructured); ok && !tc.args.ready[obj.GetName()] {
+								// Only ready composed resources exist.
+								return kerrors.NewNotFound(schema.GroupResource{}, obj.GetName())
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+							summary = GetReconcileSummary(obj.(resource.Composite))
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				}),
+				WithCompositeFinalizer(resource.NewNopFinalizer()),
+				WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+					cr.SetCompositionReference(&corev1.ObjectReference{})
+					return nil
+				})),
+				WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+					c := &v1.Composition{Spec: v1.CompositionSpec{
+						Resources: []v1.ComposedTemplate{{Wave: pointer.Int(2)}, {Wave: pointer.Int(1)}, {}},
+					}}
+					return c, nil
+				})),
+				WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+				WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+					return nil
+				})),
+				WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, cd resource.Composed, tpl v1.ComposedTemplate) error {
+					cd.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{Kind: "Bucket"})
+					cd.SetName(names[WaveOf(tpl)])
+					return nil
+				})),
+				WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.Composed, _ v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+					return nil, nil
+				})),
+				WithReadinessChecker(ReadinessCheckerFn(func(_ context.Context, cd resource.Composed, _ v1.ComposedTemplate) (bool, error) {
+					return tc.args.ready[cd.GetName()], nil
+				})),
+				WithConnectionPublishers(managed.ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+						return false, nil
+					},
+				}),
+			}
+			r := NewReconciler(&fake.Manager{}, resource.CompositeKind(schema.GroupVersionKind{}), append(opts, tc.args.opts...)...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if summary != nil && (summary.ID == "" || summary.Time.IsZero()) {
+				t.Errorf("\n%s\nr.Reconcile(...): summary must have an ID and time, got %+v", tc.reason, summary)
+			}
+			if diff := cmp.Diff(tc.want.summary, summary, cmpopts.IgnoreFields(ReconcileSummary{}, "ID", "Time")); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want summary, +got summary:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFilterToXRPatches(t *testing.T) {
+	toXR1 := v1.Patch{
+		Type: v1.PatchTypeToCompositeFieldPath,
+	}
+	toXR2 := v1.Patch{
+		Type: v1.PatchTypeCombineToComposite,
+	}
+	fromXR1 := v1.Patch{
+		Type: v1.PatchTypeFromCompositeFieldPath,
+	}
+	fromXR2 := v1.Patch{
+		Type: v1.PatchTypeCombineFromComposite,
+	}
+	type args struct {
+		tas []TemplateAssociation
+	}
+	tests := map[string]struct {
+		args args
+		want []v1.Patch
+	}{
+		"NonEmptyToXRPatches": {
 			args: args{
 				tas: []TemplateAssociation{
 					{
@@ -914,3 +2202,155 @@ func TestFilterToXRPatches(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileReadyTimeout(t *testing.T) {
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+
+	since := func(c xpv1.Condition, d time.Duration) xpv1.Condition {
+		c.LastTransitionTime = metav1.NewTime(time.Now().Add(-d))
+		return c
+	}
+
+	type args struct {
+		// conditions the composite resource has before it is reconciled.
+		conditions []xpv1.Condition
+		ready      bool
+		opts       []ReconcilerOption
+	}
+	type want struct {
+		status corev1.ConditionStatus
+		events int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTimeout": {
+			reason: "We should not report a composite resource that has no ready timeout.",
+			args: args{
+				conditions: []xpv1.Condition{since(xpv1.Creating(), time.Hour)},
+			},
+			want: want{
+				status: corev1.ConditionUnknown,
+			},
+		},
+		"WithinTimeout": {
+			reason: "We should not report a composite resource that has not yet exceeded its ready timeout.",
+			args: args{
+				conditions: []xpv1.Condition{since(xpv1.Creating(), time.Minute)},
+				opts:       []ReconcilerOption{WithReadyTimeout(time.Hour)},
+			},
+			want: want{
+				status: corev1.ConditionUnknown,
+			},
+		},
+		"TimeoutExceeded": {
+			reason: "We should report a composite resource that has exceeded its ready timeout, and emit an event.",
+			args: args{
+				conditions: []xpv1.Condition{since(xpv1.Creating(), time.Hour)},
+				opts:       []ReconcilerOption{WithReadyTimeout(time.Minute)},
+			},
+			want: want{
+				status: corev1.ConditionTrue,
+				events: 1,
+			},
+		},
+		"TimeoutAlreadyExceeded": {
+			reason: "We should not emit another event for a composite resource we already reported.",
+			args: args{
+				conditions: []xpv1.Condition{since(xpv1.Creating(), time.Hour), ReadyTimeoutExceeded("")},
+				opts:       []ReconcilerOption{WithReadyTimeout(time.Minute)},
+			},
+			want: want{
+				status: corev1.ConditionTrue,
+			},
+		},
+		"ReadyAfterTimeout": {
+			reason: "We should report that a composite resource that exceeded its ready timeout is now ready.",
+			args: args{
+				conditions: []xpv1.Condition{since(xpv1.Creating(), time.Hour), ReadyTimeoutExceeded("")},
+				ready:      true,
+				opts:       []ReconcilerOption{WithReadyTimeout(time.Minute)},
+			},
+			want: want{
+				status: corev1.ConditionFalse,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status := corev1.ConditionUnknown
+			rec := &captureRecorder{}
+			opts := []ReconcilerOption{
+				WithLogger(testLog),
+				WithRecorder(rec),
+				WithClientApplicator(resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if cr, ok := obj.(*composite.Unstructured); ok {
+								cr.SetConditions(tc.args.conditions...)
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+							status = obj.(resource.Composite).GetCondition(TypeReadyTimeoutExceeded).Status
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				}),
+				WithCompositeFinalizer(resource.NewNopFinalizer()),
+				WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+					cr.SetCompositionReference(&corev1.ObjectReference{})
+					return nil
+				})),
+				WithCompositionFetcher(CompositionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.Composition, error) {
+					c := &v1.Composition{Spec: v1.CompositionSpec{
+						Resources: []v1.ComposedTemplate{{}},
+					}}
+					return c, nil
+				})),
+				WithCompositionValidator(CompositionValidatorFn(func(_ *v1.Composition) error { return nil })),
+				WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.Composition) error {
+					return nil
+				})),
+				WithRenderer(RendererFn(func(_ context.Context, _ resource.Composite, _ resource.Composed, _ v1.ComposedTemplate) error {
+					return nil
+				})),
+				WithConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.Composed, _ v1.ComposedTemplate) (managed.ConnectionDetails, error) {
+					return nil, nil
+				})),
+				WithReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ resource.Composed, _ v1.ComposedTemplate) (bool, error) {
+					return tc.args.ready, nil
+				})),
+				WithConnectionPublishers(managed.ConnectionPublisherFns{
+					PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+						return false, nil
+					},
+				}),
+			}
+			r := NewReconciler(&fake.Manager{}, resource.CompositeKind(schema.GroupVersionKind{}), append(opts, tc.args.opts...)...)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.status, status); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want %s status, +got %s status:\n%s", tc.reason, TypeReadyTimeoutExceeded, TypeReadyTimeoutExceeded, diff)
+			}
+			warnings := 0
+			for _, e := range rec.events {
+				if e.Reason == reasonTimeout {
+					warnings++
+				}
+			}
+			if diff := cmp.Diff(tc.want.events, warnings); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
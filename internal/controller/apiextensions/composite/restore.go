@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errFmtListRestored = "cannot list restored composed resources of kind %s"
+)
+
+// IsRestored returns true if the supplied object was annotated to indicate that
+// it was restored from a backup.
+func IsRestored(o metav1.Object) bool {
+	return o.GetAnnotations()[xcrd.AnnotationKeyRestored] == "true"
+}
+
+// AssociateRestored associates any of the supplied named templates that are
+// not yet associated with a composed resource with an existing composed
+// resource that was created for the supplied composite resource. Composed
+// resources are identified by their composite label and their composition
+// resource name annotation, which are preserved when they are restored from a
+// backup. This allows a composite resource that was restored without its
+// resource references to adopt its restored composed resources, rather than
+// creating duplicates.
+func AssociateRestored(ctx context.Context, c client.Reader, cr resource.Composite, tas []TemplateAssociation) ([]TemplateAssociation, error) {
+	for i, ta := range tas {
+		if ta.Reference.Name != "" || ta.Template.Name == nil {
+			continue
+		}
+
+		tm := &metav1.TypeMeta{}
+		if err := json.Unmarshal(ta.Template.Base.Raw, tm); err != nil {
+			// We'll fail to render this template, and report why.
+			continue
+		}
+		gvk := tm.GroupVersionKind()
+
+		l := &kunstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := c.List(ctx, l, client.MatchingLabels{xcrd.LabelKeyNamePrefixForComposed: cr.GetName()})
+		if kmeta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtListRestored, gvk.Kind)
+		}
+
+		for j := range l.Items {
+			cd := &l.Items[j]
+			if GetCompositionResourceName(cd) == *ta.Template.Name {
+				tas[i].Reference = *meta.ReferenceTo(cd, gvk)
+				break
+			}
+		}
+	}
+	return tas, nil
+}
+
+// MustBeControllableByOrRestored requires that the current object is
+// controllable by the supplied composite resource. Objects that have no
+// controller, that are controlled by the supplied composite resource, or that
+// are controlled by an object of the same kind and name as the composite
+// resource are controllable. The latter is the case when both the composite
+// resource and the object were restored from a backup, which gives the
+// composite resource a new UID.
+func MustBeControllableByOrRestored(cr resource.Composite) resource.ApplyOption {
+	return func(ctx context.Context, current, desired runtime.Object) error {
+		c := metav1.GetControllerOf(current.(metav1.Object))
+		if c == nil {
+			return nil
+		}
+		gk := schema.FromAPIVersionAndKind(c.APIVersion, c.Kind).GroupKind()
+		if gk == cr.GetObjectKind().GroupVersionKind().GroupKind() && c.Name == cr.GetName() {
+			return nil
+		}
+		return resource.MustBeControllableBy(cr.GetUID())(ctx, current, desired)
+	}
+}
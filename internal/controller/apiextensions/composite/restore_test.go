@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestAssociateRestored(t *testing.T) {
+	errBoom := errors.New("boom")
+	name := "cool"
+	tmpl := v1.ComposedTemplate{
+		Name: &name,
+		Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Instance"}`)},
+	}
+	xr := func() resource.Composite {
+		cr := composite.New()
+		cr.SetName("cool-xr")
+		return cr
+	}
+
+	type args struct {
+		client client.Reader
+		tas    []TemplateAssociation
+	}
+	type want struct {
+		tas []TemplateAssociation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AlreadyAssociated": {
+			reason: "Templates that are already associated with a composed resource should not be changed.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				tas:    []TemplateAssociation{{Template: tmpl, Reference: corev1.ObjectReference{Name: "cool-xr-abcde"}}},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: tmpl, Reference: corev1.ObjectReference{Name: "cool-xr-abcde"}}},
+			},
+		},
+		"Restored": {
+			reason: "Unassociated templates should be associated with a restored composed resource created from them.",
+			args: args{
+				client: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if diff := cmp.Diff("crossplane.io/composite=cool-xr", lo.LabelSelector.String()); diff != "" {
+						t.Errorf("List(...): -want selector, +got selector:\n%s", diff)
+					}
+					other := kunstructured.Unstructured{}
+					other.SetName("cool-xr-fghij")
+					SetCompositionResourceName(&other, "other")
+					cd := kunstructured.Unstructured{}
+					cd.SetName("cool-xr-abcde")
+					SetCompositionResourceName(&cd, name)
+					obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{other, cd}
+					return nil
+				}},
+				tas: []TemplateAssociation{{Template: tmpl}},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: tmpl, Reference: corev1.ObjectReference{
+					APIVersion: "example.org/v1",
+					Kind:       "Instance",
+					Name:       "cool-xr-abcde",
+				}}},
+			},
+		},
+		"ListError": {
+			reason: "Errors listing composed resources should be returned.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				tas:    []TemplateAssociation{{Template: tmpl}},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtListRestored, "Instance"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := AssociateRestored(context.Background(), tc.args.client, xr(), tc.args.tas)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAssociateRestored(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tas, got); diff != "" {
+				t.Errorf("\n%s\nAssociateRestored(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMustBeControllableByOrRestored(t *testing.T) {
+	ctrl := true
+	xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"}))
+	xr.SetName("cool-xr")
+	xr.SetUID("new-uid")
+
+	controlledBy := func(ref metav1.OwnerReference) runtime.Object {
+		cd := &kunstructured.Unstructured{}
+		ref.Controller = &ctrl
+		cd.SetOwnerReferences([]metav1.OwnerReference{ref})
+		return cd
+	}
+
+	cases := map[string]struct {
+		reason  string
+		current runtime.Object
+		want    bool
+	}{
+		"NoController": {
+			reason:  "An object without a controller should be controllable.",
+			current: &kunstructured.Unstructured{},
+			want:    true,
+		},
+		"SameUID": {
+			reason:  "An object controlled by the composite resource should be controllable.",
+			current: controlledBy(metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XR", Name: "other-xr", UID: types.UID("new-uid")}),
+			want:    true,
+		},
+		"Restored": {
+			reason:  "An object controlled by a previous composite resource of the same kind and name should be controllable.",
+			current: controlledBy(metav1.OwnerReference{APIVersion: "example.org/v1alpha1", Kind: "XR", Name: "cool-xr", UID: types.UID("old-uid")}),
+			want:    true,
+		},
+		"OtherComposite": {
+			reason:  "An object controlled by a different composite resource should not be controllable.",
+			current: controlledBy(metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XR", Name: "other-xr", UID: types.UID("old-uid")}),
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := MustBeControllableByOrRestored(xr)(context.Background(), tc.current, nil)
+			if diff := cmp.Diff(tc.want, err == nil); diff != "" {
+				t.Errorf("\n%s\nMustBeControllableByOrRestored(...): -want controllable, +got controllable:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsRestored(t *testing.T) {
+	xr := composite.New()
+	if IsRestored(xr) {
+		t.Errorf("IsRestored(...): want false for a composite resource without annotations")
+	}
+	xr.SetAnnotations(map[string]string{xcrd.AnnotationKeyRestored: "true"})
+	if !IsRestored(xr) {
+		t.Errorf("IsRestored(...): want true for a composite resource annotated as restored")
+	}
+}
@@ -41,12 +41,19 @@ func AsCompositionSpec(crs v1alpha1.CompositionRevisionSpec) v1.CompositionSpec
 		PatchSets:                         make([]v1.PatchSet, len(crs.PatchSets)),
 		Resources:                         make([]v1.ComposedTemplate, len(crs.Resources)),
 		WriteConnectionSecretsToNamespace: crs.WriteConnectionSecretsToNamespace,
+		ConnectionDetailsTTL:              crs.ConnectionDetailsTTL,
+		ServiceAccountName:                crs.ServiceAccountName,
 	}
 
 	if crs.PublishConnectionDetailsWithStoreConfigRef != nil {
 		cs.PublishConnectionDetailsWithStoreConfigRef = &v1.StoreConfigReference{Name: crs.PublishConnectionDetailsWithStoreConfigRef.Name}
 	}
 
+	if crs.DriftPolicy != nil {
+		dp := v1.DriftPolicy(*crs.DriftPolicy)
+		cs.DriftPolicy = &dp
+	}
+
 	for i := range crs.PatchSets {
 		cs.PatchSets[i] = AsCompositionPatchSet(crs.PatchSets[i])
 	}
@@ -81,6 +88,16 @@ func AsCompositionComposedTemplate(rct v1alpha1.ComposedTemplate) v1.ComposedTem
 		Patches:           make([]v1.Patch, len(rct.Patches)),
 		ConnectionDetails: make([]v1.ConnectionDetail, len(rct.ConnectionDetails)),
 		ReadinessChecks:   make([]v1.ReadinessCheck, len(rct.ReadinessChecks)),
+		Wave:              rct.Wave,
+	}
+
+	if rct.ManagementPolicy != nil {
+		mp := v1.ManagementPolicy(*rct.ManagementPolicy)
+		ct.ManagementPolicy = &mp
+	}
+
+	if rct.BaseFrom != nil {
+		ct.BaseFrom = &v1.BaseSource{ConfigMapKeyRef: v1.ConfigMapKeySelector(rct.BaseFrom.ConfigMapKeyRef)}
 	}
 
 	for i := range rct.Patches {
@@ -104,6 +121,7 @@ func AsCompositionPatch(rp v1alpha1.Patch) v1.Patch {
 	p := v1.Patch{
 		Type:          v1.PatchType(rp.Type),
 		FromFieldPath: rp.FromFieldPath,
+		Template:      rp.Template,
 		ToFieldPath:   rp.ToFieldPath,
 		PatchSetName:  rp.PatchSetName,
 		Transforms:    make([]v1.Transform, len(rp.Transforms)),
@@ -188,6 +206,7 @@ func AsCompositionConnectionDetail(rcd v1alpha1.ConnectionDetail) v1.ConnectionD
 		FromConnectionSecretKey: rcd.FromConnectionSecretKey,
 		FromFieldPath:           rcd.FromFieldPath,
 		Value:                   rcd.Value,
+		Rotating:                rcd.Rotating,
 	}
 }
 
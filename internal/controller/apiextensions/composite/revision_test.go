@@ -18,8 +18,10 @@ package composite
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
@@ -35,6 +37,8 @@ func TestAsComposition(t *testing.T) {
 				APIVersion: "v",
 				Kind:       "k",
 			},
+			ConnectionDetailsTTL: &metav1.Duration{Duration: time.Minute},
+			ServiceAccountName:   pointer.String("composer"),
 			PatchSets: []v1alpha1.PatchSet{{
 				Name: "p",
 				Patches: []v1alpha1.Patch{{
@@ -49,6 +53,7 @@ func TestAsComposition(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1alpha1.Transform{{
@@ -78,6 +83,14 @@ func TestAsComposition(t *testing.T) {
 			Resources: []v1alpha1.ComposedTemplate{{
 				Name: pointer.String("t"),
 				Base: runtime.RawExtension{Raw: []byte("bytes")},
+				BaseFrom: &v1alpha1.BaseSource{
+					ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector{Name: "n", Namespace: "ns", Key: "k"},
+				},
+				ManagementPolicy: func() *v1alpha1.ManagementPolicy {
+					mp := v1alpha1.ManagementPolicyObserveOnly
+					return &mp
+				}(),
+				Wave: pointer.Int(1),
 				Patches: []v1alpha1.Patch{{
 					Type:          v1alpha1.PatchType("t"),
 					FromFieldPath: pointer.String("from"),
@@ -90,6 +103,7 @@ func TestAsComposition(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1alpha1.Transform{
@@ -162,6 +176,7 @@ func TestAsComposition(t *testing.T) {
 					FromConnectionSecretKey: pointer.String("k"),
 					FromFieldPath:           pointer.String("p"),
 					Value:                   pointer.String("v"),
+					Rotating:                pointer.Bool(true),
 				}},
 				ReadinessChecks: []v1alpha1.ReadinessCheck{{
 					Type:         v1alpha1.ReadinessCheckType("c"),
@@ -179,6 +194,8 @@ func TestAsComposition(t *testing.T) {
 				APIVersion: "v",
 				Kind:       "k",
 			},
+			ConnectionDetailsTTL: &metav1.Duration{Duration: time.Minute},
+			ServiceAccountName:   pointer.String("composer"),
 			PatchSets: []v1.PatchSet{{
 				Name: "p",
 				Patches: []v1.Patch{{
@@ -193,6 +210,7 @@ func TestAsComposition(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1.Transform{{
@@ -222,6 +240,14 @@ func TestAsComposition(t *testing.T) {
 			Resources: []v1.ComposedTemplate{{
 				Name: pointer.String("t"),
 				Base: runtime.RawExtension{Raw: []byte("bytes")},
+				BaseFrom: &v1.BaseSource{
+					ConfigMapKeyRef: v1.ConfigMapKeySelector{Name: "n", Namespace: "ns", Key: "k"},
+				},
+				ManagementPolicy: func() *v1.ManagementPolicy {
+					mp := v1.ManagementPolicyObserveOnly
+					return &mp
+				}(),
+				Wave: pointer.Int(1),
 				Patches: []v1.Patch{{
 					Type:          v1.PatchType("t"),
 					FromFieldPath: pointer.String("from"),
@@ -234,6 +260,7 @@ func TestAsComposition(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1.Transform{
@@ -306,6 +333,7 @@ func TestAsComposition(t *testing.T) {
 					FromConnectionSecretKey: pointer.String("k"),
 					FromFieldPath:           pointer.String("p"),
 					Value:                   pointer.String("v"),
+					Rotating:                pointer.Bool(true),
 				}},
 				ReadinessChecks: []v1.ReadinessCheck{{
 					Type:         v1.ReadinessCheckType("c"),
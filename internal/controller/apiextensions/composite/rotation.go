@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errGetPublished   = "cannot get published connection secret"
+	errPatchPublished = "cannot annotate published connection secret"
+)
+
+const (
+	// AnnotationKeyRotationGeneration is incremented each time the value of
+	// a rotating connection detail of a published connection secret changes.
+	// Consumers of the connection secret may watch it to determine when to
+	// reload rotated credentials.
+	AnnotationKeyRotationGeneration = "crossplane.io/rotation-generation"
+
+	// AnnotationKeyRotationDigest is a digest of the values of the rotating
+	// connection details of a published connection secret.
+	AnnotationKeyRotationDigest = "crossplane.io/rotation-digest"
+)
+
+// A ConnectionRotator records the rotation of a composite resource's
+// connection details.
+type ConnectionRotator interface {
+	// RotateConnection records whether the values of the supplied rotating
+	// connection details of the supplied composite resource have changed
+	// since they were last published. It returns true if they have.
+	RotateConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails, rotating []string) (bool, error)
+}
+
+// A ConnectionRotatorFn records the rotation of a composite resource's
+// connection details.
+type ConnectionRotatorFn func(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails, rotating []string) (bool, error)
+
+// RotateConnection records the rotation of the supplied connection details.
+func (fn ConnectionRotatorFn) RotateConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails, rotating []string) (bool, error) {
+	return fn(ctx, o, c, rotating)
+}
+
+// An APIConnectionRotator records the rotation of connection details by
+// annotating the connection secret published to the API server.
+type APIConnectionRotator struct {
+	client client.Client
+}
+
+// NewAPIConnectionRotator returns a ConnectionRotator that annotates the
+// connection secrets published to the API server.
+func NewAPIConnectionRotator(c client.Client) *APIConnectionRotator {
+	return &APIConnectionRotator{client: c}
+}
+
+// RotateConnection increments the rotation generation annotation of the
+// supplied composite resource's connection secret if the values of any of
+// the supplied rotating connection details have changed. It should be called
+// after the connection details are published, so that consumers never observe
+// a new generation with stale values.
+func (r *APIConnectionRotator) RotateConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails, rotating []string) (bool, error) {
+	ref := o.GetWriteConnectionSecretToReference()
+	if ref == nil || len(rotating) == 0 {
+		return false, nil
+	}
+
+	s := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s)
+	if resource.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, errGetPublished)
+	}
+	if err != nil {
+		// The connection secret hasn't been published yet.
+		return false, nil
+	}
+
+	d := digest(c, rotating)
+	prev, recorded := s.GetAnnotations()[AnnotationKeyRotationDigest]
+	if prev == d {
+		return false, nil
+	}
+
+	a := map[string]string{AnnotationKeyRotationDigest: d}
+	if recorded {
+		// We only consider the connection details rotated if we recorded
+		// their previous values; the first time we see them they're new.
+		gen, _ := strconv.ParseInt(s.GetAnnotations()[AnnotationKeyRotationGeneration], 10, 64)
+		a[AnnotationKeyRotationGeneration] = strconv.FormatInt(gen+1, 10)
+	}
+
+	// We patch rather than update because we may have read a stale
+	// connection secret from our cache, just after we published it.
+	orig := s.DeepCopy()
+	meta.AddAnnotations(s, a)
+	if err := r.client.Patch(ctx, s, client.MergeFrom(orig)); err != nil {
+		return false, errors.Wrap(err, errPatchPublished)
+	}
+	return recorded, nil
+}
+
+// RotatingConnectionDetails returns the keys of the connection details of
+// the supplied Composition that are marked as rotating.
+func RotatingConnectionDetails(comp *v1.Composition) []string {
+	keys := make([]string, 0)
+	for _, t := range comp.Spec.Resources {
+		for _, d := range t.ConnectionDetails {
+			if d.Rotating == nil || !*d.Rotating {
+				continue
+			}
+			switch {
+			case d.Name != nil:
+				keys = append(keys, *d.Name)
+			case d.FromConnectionSecretKey != nil:
+				keys = append(keys, *d.FromConnectionSecretKey)
+			}
+		}
+	}
+	return keys
+}
+
+// ConnectionDetailsTTLOf returns how often a composite resource using the
+// supplied Composition should be reconciled, given its poll interval. A
+// Composition with rotating connection details may ask to be reconciled more
+// often than the poll interval, but never less often.
+func ConnectionDetailsTTLOf(comp *v1.Composition, poll time.Duration) time.Duration {
+	ttl := comp.Spec.ConnectionDetailsTTL
+	if ttl == nil || ttl.Duration <= 0 || ttl.Duration >= poll {
+		return poll
+	}
+	if len(RotatingConnectionDetails(comp)) == 0 {
+		return poll
+	}
+	return ttl.Duration
+}
+
+// digest returns a digest of the values of the supplied keys of the supplied
+// connection details.
+func digest(c managed.ConnectionDetails, keys []string) string {
+	sorted := make([]string, len(keys))
+	copy(sorted, keys)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, k := range sorted {
+		v, ok := c[k]
+		if !ok {
+			continue
+		}
+		// Length prefixes ensure distinct keys and values can't produce the
+		// same digest.
+		fmt.Fprintf(h, "%d:%s%d:", len(k), k, len(v))
+		h.Write(v) //nolint:errcheck // Writing to a hash never errors.
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
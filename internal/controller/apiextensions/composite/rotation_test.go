@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestRotateConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New()
+	xr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Namespace: "ns", Name: "conn"})
+
+	rotating := []string{"password"}
+	conn := managed.ConnectionDetails{"password": []byte("new"), "username": []byte("admin")}
+
+	// withAnnotations returns a MockGetFn that gets a connection secret with
+	// the supplied annotations.
+	withAnnotations := func(a map[string]string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.SetAnnotations(a)
+			return nil
+		})
+	}
+
+	// wantAnnotations returns a MockPatchFn that expects the supplied
+	// annotations.
+	wantAnnotations := func(a map[string]string) test.MockPatchFn {
+		return test.NewMockPatchFn(nil, func(obj client.Object) error {
+			if diff := cmp.Diff(a, obj.GetAnnotations()); diff != "" {
+				t.Errorf("Patch(...): -want, +got:\n%s", diff)
+			}
+			return nil
+		})
+	}
+
+	type args struct {
+		o        *composite.Unstructured
+		rotating []string
+	}
+	type want struct {
+		rotated bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		args   args
+		want   want
+	}{
+		"NoConnectionSecret": {
+			reason: "We should do nothing if the composite resource doesn't publish a connection secret.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				o:        composite.New(),
+				rotating: rotating,
+			},
+		},
+		"NoRotatingDetails": {
+			reason: "We should do nothing if no connection details are rotating.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				o: xr,
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered while getting the connection secret.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPublished),
+			},
+		},
+		"NotPublished": {
+			reason: "We should do nothing if the connection secret hasn't been published yet.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "conn"))},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+		},
+		"Unchanged": {
+			reason: "We should not annotate the connection secret if the rotating connection details haven't changed.",
+			client: &test.MockClient{
+				MockGet:   withAnnotations(map[string]string{AnnotationKeyRotationDigest: digest(conn, rotating)}),
+				MockPatch: test.NewMockPatchFn(errBoom),
+			},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+		},
+		"FirstObserved": {
+			reason: "We should record the digest, but not increment the generation, the first time we observe rotating connection details.",
+			client: &test.MockClient{
+				MockGet:   withAnnotations(nil),
+				MockPatch: wantAnnotations(map[string]string{AnnotationKeyRotationDigest: digest(conn, rotating)}),
+			},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+		},
+		"PatchError": {
+			reason: "We should return any error encountered while annotating the connection secret.",
+			client: &test.MockClient{
+				MockGet:   withAnnotations(nil),
+				MockPatch: test.NewMockPatchFn(errBoom),
+			},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPatchPublished),
+			},
+		},
+		"Rotated": {
+			reason: "We should increment the generation when rotating connection details change.",
+			client: &test.MockClient{
+				MockGet: withAnnotations(map[string]string{
+					AnnotationKeyRotationDigest:     "old",
+					AnnotationKeyRotationGeneration: "2",
+				}),
+				MockPatch: wantAnnotations(map[string]string{
+					AnnotationKeyRotationDigest:     digest(conn, rotating),
+					AnnotationKeyRotationGeneration: "3",
+				}),
+			},
+			args: args{
+				o:        xr,
+				rotating: rotating,
+			},
+			want: want{
+				rotated: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIConnectionRotator(tc.client)
+			rotated, err := r.RotateConnection(context.Background(), tc.args.o, conn, tc.args.rotating)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRotateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rotated, rotated); diff != "" {
+				t.Errorf("\n%s\nRotateConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectionDetailsTTLOf(t *testing.T) {
+	poll := time.Minute
+	rotating := []v1.ComposedTemplate{{
+		ConnectionDetails: []v1.ConnectionDetail{
+			{Name: pointer.String("user"), FromConnectionSecretKey: pointer.String("username")},
+			{FromConnectionSecretKey: pointer.String("password"), Rotating: pointer.Bool(true)},
+		},
+	}}
+
+	cases := map[string]struct {
+		reason string
+		comp   *v1.Composition
+		want   time.Duration
+	}{
+		"NoTTL": {
+			reason: "We should use the poll interval if the Composition doesn't specify a TTL.",
+			comp:   &v1.Composition{Spec: v1.CompositionSpec{Resources: rotating}},
+			want:   poll,
+		},
+		"NoRotatingDetails": {
+			reason: "We should use the poll interval if the Composition has no rotating connection details.",
+			comp: &v1.Composition{Spec: v1.CompositionSpec{
+				Resources:            []v1.ComposedTemplate{{}},
+				ConnectionDetailsTTL: &metav1.Duration{Duration: time.Second},
+			}},
+			want: poll,
+		},
+		"LongerThanPollInterval": {
+			reason: "We should never poll less often than the poll interval.",
+			comp: &v1.Composition{Spec: v1.CompositionSpec{
+				Resources:            rotating,
+				ConnectionDetailsTTL: &metav1.Duration{Duration: time.Hour},
+			}},
+			want: poll,
+		},
+		"ShorterThanPollInterval": {
+			reason: "We should use the TTL if it is shorter than the poll interval.",
+			comp: &v1.Composition{Spec: v1.CompositionSpec{
+				Resources:            rotating,
+				ConnectionDetailsTTL: &metav1.Duration{Duration: time.Second},
+			}},
+			want: time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ConnectionDetailsTTLOf(tc.comp, poll)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConnectionDetailsTTLOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Reasons a composite resource may be requeued.
+const (
+	RequeueDeletionPending  = "WaitForComposedResourceDeletion"
+	RequeueCompositionCycle = "CompositionCycle"
+	RequeueLimitExceeded    = "ComposedResourceLimitExceeded"
+	RequeueWaitWave         = "WaitForComposedResourceWave"
+	RequeueWaitCRD          = "WaitForCustomResourceDefinitions"
+	RequeueNotReady         = "WaitForComposedResourcesReady"
+	RequeuePoll             = "PollComposedResources"
+)
+
+const (
+	msgFmtNotReady = "%d of %d composed resources are ready"
+	msgFmtUnready  = "%s; waiting for %s"
+	msgFmtPoll     = "Polling composed resources again in %s"
+)
+
+// A ReconcileSummary summarises the most recent reconcile of a composite
+// resource, including why it was requeued.
+type ReconcileSummary struct {
+	// ID uniquely identifies the reconcile. Crossplane logs the ID with each
+	// message it logs during the reconcile.
+	ID string `json:"id"`
+
+	// Time at which the reconcile was requeued.
+	Time metav1.Time `json:"time"`
+
+	// Reason the reconcile was requeued.
+	Reason string `json:"reason"`
+
+	// Message is a human readable description of why the reconcile was
+	// requeued.
+	Message string `json:"message,omitempty"`
+}
+
+// GetReconcileSummary returns the summary of the most recent reconcile of the
+// supplied composite resource, if any.
+func GetReconcileSummary(cr resource.Composite) *ReconcileSummary {
+	u, ok := cr.(interface{ UnstructuredContent() map[string]interface{} })
+	if !ok {
+		return nil
+	}
+	s := &ReconcileSummary{}
+	if err := fieldpath.Pave(u.UnstructuredContent()).GetValueInto("status.lastReconcile", s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// SetReconcileSummary sets the summary of the most recent reconcile of the
+// supplied composite resource. Only unstructured composite resources are
+// supported.
+func SetReconcileSummary(cr resource.Composite, s ReconcileSummary) {
+	u, ok := cr.(interface{ UnstructuredContent() map[string]interface{} })
+	if !ok {
+		return
+	}
+	_ = fieldpath.Pave(u.UnstructuredContent()).SetValue("status.lastReconcile", s)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const msgFmtReadyTimeout = "Composite resource did not become ready within %s"
+
+// TypeReadyTimeoutExceeded resources took longer to become ready than their
+// CompositeResourceDefinition expects.
+const TypeReadyTimeoutExceeded xpv1.ConditionType = "ReadyTimeoutExceeded"
+
+// Reasons a composite resource has or has not exceeded its ready timeout.
+const (
+	ReasonNotReadyInTime xpv1.ConditionReason = "NotReadyWithinTimeout"
+	ReasonReadyInTime    xpv1.ConditionReason = "ReadyWithinTimeout"
+)
+
+// ReadyTimeoutExceeded indicates that a composite resource has taken longer to
+// become ready than its CompositeResourceDefinition expects, and may be stuck.
+func ReadyTimeoutExceeded(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReadyTimeoutExceeded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotReadyInTime,
+		Message:            message,
+	}
+}
+
+// WithinReadyTimeout indicates that a composite resource that previously
+// exceeded its ready timeout has since become ready.
+func WithinReadyTimeout() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReadyTimeoutExceeded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReadyInTime,
+	}
+}
+
+// NotReadyFor returns how long the supplied resource has not been ready, i.e.
+// the time since its Ready condition last transitioned to a status other than
+// True. It returns zero if the resource is ready, or if its Ready condition has
+// never been set.
+func NotReadyFor(o resource.Conditioned, now time.Time) time.Duration {
+	c := o.GetCondition(xpv1.TypeReady)
+	if c.Status == corev1.ConditionTrue || c.LastTransitionTime.IsZero() {
+		return 0
+	}
+	return now.Sub(c.LastTransitionTime.Time)
+}
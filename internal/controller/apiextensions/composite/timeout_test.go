@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestNotReadyFor(t *testing.T) {
+	// Conditions of unstructured resources are only precise to the second.
+	now := time.Now().Truncate(time.Second)
+	since := func(c xpv1.Condition, d time.Duration) xpv1.Condition {
+		c.LastTransitionTime = metav1.NewTime(now.Add(-d))
+		return c
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      []xpv1.Condition
+		want   time.Duration
+	}{
+		"NoReadyCondition": {
+			reason: "A resource whose Ready condition was never set has not been unready for any time.",
+			want:   0,
+		},
+		"Ready": {
+			reason: "A ready resource has not been unready for any time.",
+			c:      []xpv1.Condition{since(xpv1.Available(), time.Hour)},
+			want:   0,
+		},
+		"NotReady": {
+			reason: "A resource that is not ready has been unready since its Ready condition last transitioned.",
+			c:      []xpv1.Condition{since(xpv1.Creating(), time.Hour)},
+			want:   time.Hour,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := composite.New()
+			cr.SetConditions(tc.c...)
+			got := NotReadyFor(cr, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNotReadyFor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sort"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// A Wave of composed resources, which are created only once all composed
+// resources in earlier waves are ready.
+type Wave struct {
+	// Number of this wave.
+	Number int
+
+	// Indexes of the templates of the composed resources in this wave.
+	Indexes []int
+}
+
+// WaveOf returns the wave of the supplied template.
+func WaveOf(t v1.ComposedTemplate) int {
+	if t.Wave == nil {
+		return 0
+	}
+	return *t.Wave
+}
+
+// Waves groups the supplied template associations by wave, in ascending order.
+// Associations within a wave retain their order.
+func Waves(tas []TemplateAssociation) []Wave {
+	idx := map[int]int{}
+	waves := make([]Wave, 0, 1)
+	for i, ta := range tas {
+		n := WaveOf(ta.Template)
+		j, ok := idx[n]
+		if !ok {
+			j = len(waves)
+			idx[n] = j
+			waves = append(waves, Wave{Number: n})
+		}
+		waves[j].Indexes = append(waves[j].Indexes, i)
+	}
+	sort.SliceStable(waves, func(i, j int) bool { return waves[i].Number < waves[j].Number })
+	return waves
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestWaves(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		tas    []TemplateAssociation
+		want   []Wave
+	}{
+		"NoWaves": {
+			reason: "Templates that don't specify a wave should all be in wave 0.",
+			tas:    []TemplateAssociation{{}, {}},
+			want:   []Wave{{Number: 0, Indexes: []int{0, 1}}},
+		},
+		"Waves": {
+			reason: "Templates should be grouped by wave in ascending order, retaining their order within each wave.",
+			tas: []TemplateAssociation{
+				{Template: v1.ComposedTemplate{Wave: pointer.Int(2)}},
+				{},
+				{Template: v1.ComposedTemplate{Wave: pointer.Int(-1)}},
+				{Template: v1.ComposedTemplate{Wave: pointer.Int(2)}},
+			},
+			want: []Wave{
+				{Number: -1, Indexes: []int{2}},
+				{Number: 0, Indexes: []int{1}},
+				{Number: 2, Indexes: []int{0, 3}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Waves(tc.tas)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWaves(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -39,6 +39,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/throttle"
 )
 
 const (
@@ -66,7 +67,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -58,12 +58,19 @@ func NewCompositionRevisionSpec(cs v1.CompositionSpec, revision int64) v1alpha1.
 		PatchSets:                         make([]v1alpha1.PatchSet, len(cs.PatchSets)),
 		Resources:                         make([]v1alpha1.ComposedTemplate, len(cs.Resources)),
 		WriteConnectionSecretsToNamespace: cs.WriteConnectionSecretsToNamespace,
+		ConnectionDetailsTTL:              cs.ConnectionDetailsTTL,
+		ServiceAccountName:                cs.ServiceAccountName,
 	}
 
 	if cs.PublishConnectionDetailsWithStoreConfigRef != nil {
 		rs.PublishConnectionDetailsWithStoreConfigRef = &v1alpha1.StoreConfigReference{Name: cs.PublishConnectionDetailsWithStoreConfigRef.Name}
 	}
 
+	if cs.DriftPolicy != nil {
+		dp := v1alpha1.DriftPolicy(*cs.DriftPolicy)
+		rs.DriftPolicy = &dp
+	}
+
 	for i := range cs.PatchSets {
 		rs.PatchSets[i] = NewCompositionRevisionPatchSet(cs.PatchSets[i])
 	}
@@ -98,6 +105,16 @@ func NewCompositionRevisionComposedTemplate(ct v1.ComposedTemplate) v1alpha1.Com
 		Patches:           make([]v1alpha1.Patch, len(ct.Patches)),
 		ConnectionDetails: make([]v1alpha1.ConnectionDetail, len(ct.ConnectionDetails)),
 		ReadinessChecks:   make([]v1alpha1.ReadinessCheck, len(ct.ReadinessChecks)),
+		Wave:              ct.Wave,
+	}
+
+	if ct.ManagementPolicy != nil {
+		mp := v1alpha1.ManagementPolicy(*ct.ManagementPolicy)
+		rct.ManagementPolicy = &mp
+	}
+
+	if ct.BaseFrom != nil {
+		rct.BaseFrom = &v1alpha1.BaseSource{ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector(ct.BaseFrom.ConfigMapKeyRef)}
 	}
 
 	for i := range ct.Patches {
@@ -121,6 +138,7 @@ func NewCompositionRevisionPatch(p v1.Patch) v1alpha1.Patch {
 	rp := v1alpha1.Patch{
 		Type:          v1alpha1.PatchType(p.Type),
 		FromFieldPath: p.FromFieldPath,
+		Template:      p.Template,
 		ToFieldPath:   p.ToFieldPath,
 		PatchSetName:  p.PatchSetName,
 		Transforms:    make([]v1alpha1.Transform, len(p.Transforms)),
@@ -205,6 +223,7 @@ func NewCompositionRevisionConnectionDetail(cd v1.ConnectionDetail) v1alpha1.Con
 		FromConnectionSecretKey: cd.FromConnectionSecretKey,
 		FromFieldPath:           cd.FromFieldPath,
 		Value:                   cd.Value,
+		Rotating:                cd.Rotating,
 	}
 }
 
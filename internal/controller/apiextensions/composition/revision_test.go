@@ -18,6 +18,7 @@ package composition
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +41,8 @@ func TestNewCompositionRevision(t *testing.T) {
 				APIVersion: "v",
 				Kind:       "k",
 			},
+			ConnectionDetailsTTL: &metav1.Duration{Duration: time.Minute},
+			ServiceAccountName:   pointer.String("composer"),
 			PatchSets: []v1.PatchSet{{
 				Name: "p",
 				Patches: []v1.Patch{{
@@ -54,6 +57,7 @@ func TestNewCompositionRevision(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1.Transform{{
@@ -82,6 +86,14 @@ func TestNewCompositionRevision(t *testing.T) {
 			Resources: []v1.ComposedTemplate{{
 				Name: pointer.String("t"),
 				Base: runtime.RawExtension{Raw: []byte("bytes")},
+				BaseFrom: &v1.BaseSource{
+					ConfigMapKeyRef: v1.ConfigMapKeySelector{Name: "n", Namespace: "ns", Key: "k"},
+				},
+				ManagementPolicy: func() *v1.ManagementPolicy {
+					mp := v1.ManagementPolicyObserveOnly
+					return &mp
+				}(),
+				Wave: pointer.Int(1),
 				Patches: []v1.Patch{{
 					Type:          v1.PatchType("t"),
 					FromFieldPath: pointer.String("from"),
@@ -94,6 +106,7 @@ func TestNewCompositionRevision(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1.Transform{
@@ -166,6 +179,7 @@ func TestNewCompositionRevision(t *testing.T) {
 					FromConnectionSecretKey: pointer.String("k"),
 					FromFieldPath:           pointer.String("p"),
 					Value:                   pointer.String("v"),
+					Rotating:                pointer.Bool(true),
 				}},
 				ReadinessChecks: []v1.ReadinessCheck{{
 					Type:         v1.ReadinessCheckType("c"),
@@ -203,6 +217,8 @@ func TestNewCompositionRevision(t *testing.T) {
 				APIVersion: "v",
 				Kind:       "k",
 			},
+			ConnectionDetailsTTL: &metav1.Duration{Duration: time.Minute},
+			ServiceAccountName:   pointer.String("composer"),
 			PatchSets: []v1alpha1.PatchSet{{
 				Name: "p",
 				Patches: []v1alpha1.Patch{{
@@ -217,6 +233,7 @@ func TestNewCompositionRevision(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1alpha1.Transform{{
@@ -245,6 +262,14 @@ func TestNewCompositionRevision(t *testing.T) {
 			Resources: []v1alpha1.ComposedTemplate{{
 				Name: pointer.String("t"),
 				Base: runtime.RawExtension{Raw: []byte("bytes")},
+				BaseFrom: &v1alpha1.BaseSource{
+					ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector{Name: "n", Namespace: "ns", Key: "k"},
+				},
+				ManagementPolicy: func() *v1alpha1.ManagementPolicy {
+					mp := v1alpha1.ManagementPolicyObserveOnly
+					return &mp
+				}(),
+				Wave: pointer.Int(1),
 				Patches: []v1alpha1.Patch{{
 					Type:          v1alpha1.PatchType("t"),
 					FromFieldPath: pointer.String("from"),
@@ -257,6 +282,7 @@ func TestNewCompositionRevision(t *testing.T) {
 							Format: "f",
 						},
 					},
+					Template:     pointer.String("tmpl"),
 					ToFieldPath:  pointer.String("to"),
 					PatchSetName: pointer.String("n"),
 					Transforms: []v1alpha1.Transform{
@@ -329,6 +355,7 @@ func TestNewCompositionRevision(t *testing.T) {
 					FromConnectionSecretKey: pointer.String("k"),
 					FromFieldPath:           pointer.String("p"),
 					Value:                   pointer.String("v"),
+					Rotating:                pointer.Bool(true),
 				}},
 				ReadinessChecks: []v1alpha1.ReadinessCheck{{
 					Type:         v1alpha1.ReadinessCheckType("c"),
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controller contains options specific to apiextensions controllers.
+package controller
+
+import (
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane/internal/denylist"
+)
+
+// An OrphanPolicy specifies what should happen to composed resources whose
+// composite resource no longer exists.
+type OrphanPolicy string
+
+const (
+	// OrphanPolicyReport indicates that orphaned composed resources should be
+	// reported, but not deleted.
+	OrphanPolicyReport OrphanPolicy = "Report"
+
+	// OrphanPolicyDelete indicates that orphaned composed resources should be
+	// deleted.
+	OrphanPolicyDelete OrphanPolicy = "Delete"
+)
+
+// Options specific to apiextensions controllers.
+type Options struct {
+	controller.Options
+
+	// OrphanPolicy specifies what should happen to composed resources whose
+	// composite resource no longer exists.
+	OrphanPolicy OrphanPolicy
+
+	// OrphanCheckInterval specifies how often composed resources should be
+	// checked to determine whether they have been orphaned.
+	OrphanCheckInterval time.Duration
+
+	// MaxConcurrentApplies specifies the maximum number of composed resources
+	// that are applied concurrently while reconciling a composite resource.
+	MaxConcurrentApplies int
+
+	// MaxComposedResources specifies the maximum number of composed
+	// resources a composite resource may compose. Zero means no limit.
+	MaxComposedResources int
+
+	// MaxComposedResourceSize specifies the maximum size in bytes of a
+	// rendered composed resource. Zero means no limit.
+	MaxComposedResourceSize int
+
+	// DeletionTimeout specifies how long a composite resource that was
+	// deleted in the foreground waits for its composed resources to be
+	// deleted before orphaning them. Zero means wait forever.
+	DeletionTimeout time.Duration
+
+	// DeniedComposedKinds specifies kinds of resource that may not be
+	// composed.
+	DeniedComposedKinds denylist.List
+
+	// Namespace is the namespace Crossplane runs in. Composed resource bases
+	// may only be read from ConfigMaps in this namespace.
+	Namespace string
+}
@@ -19,6 +19,7 @@ package definition
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -28,6 +29,7 @@ import (
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -47,7 +49,10 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/denylist"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/throttle"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -107,13 +112,18 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 
 // Setup adds a controller that reconciles CompositeResourceDefinitions by
 // defining a composite resource and starting a controller to reconcile it.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		WithOptions(o))
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		WithOptions(o.Options),
+		WithMaxConcurrentApplies(o.MaxConcurrentApplies),
+		WithComposedResourceLimits(o.MaxComposedResources, o.MaxComposedResourceSize),
+		WithDeletionTimeout(o.DeletionTimeout),
+		WithDeniedComposedKinds(o.DeniedComposedKinds),
+		WithNamespace(o.Namespace))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -148,6 +158,52 @@ func WithOptions(o controller.Options) ReconcilerOption {
 	}
 }
 
+// WithMaxConcurrentApplies specifies the maximum number of composed resources
+// new composite resource controllers should apply concurrently. Composite
+// resource controllers use their default if n is less than 1.
+func WithMaxConcurrentApplies(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxConcurrentApplies = n
+	}
+}
+
+// WithComposedResourceLimits specifies the maximum number of composed resources
+// new composite resource controllers should compose for a composite resource,
+// and the maximum size in bytes of each rendered composed resource. Composite
+// resource controllers don't enforce a limit that is less than 1.
+func WithComposedResourceLimits(n, bytes int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxComposed = n
+		r.maxComposedSize = bytes
+	}
+}
+
+// WithDeletionTimeout specifies how long new composite resource controllers
+// should wait for composed resources to be deleted before orphaning them.
+// Composite resource controllers never orphan composed resources if d is zero.
+func WithDeletionTimeout(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.deletionTimeout = d
+	}
+}
+
+// WithDeniedComposedKinds specifies kinds of resource that new composite
+// resource controllers may not compose.
+func WithDeniedComposedKinds(l denylist.List) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.deniedComposedKinds = l
+	}
+}
+
+// WithNamespace specifies the namespace Crossplane runs in. New composite
+// resource controllers only read composed resource bases from ConfigMaps in
+// this namespace.
+func WithNamespace(ns string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.namespace = ns
+	}
+}
+
 // WithFinalizer specifies how the Reconciler should finalize
 // CompositeResourceDefinitions.
 func WithFinalizer(f resource.Finalizer) ReconcilerOption {
@@ -207,7 +263,8 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 
-		options: controller.DefaultOptions(),
+		options:       controller.DefaultOptions(),
+		pollIntervals: &pollIntervals{started: map[string]time.Duration{}},
 	}
 
 	for _, f := range opts {
@@ -226,7 +283,17 @@ type Reconciler struct {
 	log    logging.Logger
 	record event.Recorder
 
-	options controller.Options
+	options              controller.Options
+	maxConcurrentApplies int
+	maxComposed          int
+	maxComposedSize      int
+	deletionTimeout      time.Duration
+	deniedComposedKinds  denylist.List
+	namespace            string
+
+	// pollIntervals tracks the poll interval each composite resource
+	// controller was started with.
+	pollIntervals *pollIntervals
 }
 
 // Reconcile a CompositeResourceDefinition by defining a new kind of composite
@@ -403,6 +470,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion))
 	}
 
+	// Composite resource controllers only read their poll interval when they
+	// start, so we must restart them for a new poll interval to take effect.
+	pi := pollInterval(r.options.PollInterval, d)
+	if started, ok := r.pollIntervals.Get(composite.ControllerName(d.GetName())); ok && started != pi {
+		r.composite.Stop(composite.ControllerName(d.GetName()))
+		log.Debug("Poll interval changed; stopped composite resource controller",
+			"observed-interval", started.String(),
+			"desired-interval", pi.String())
+		r.record.Event(d, event.Normal(reasonEstablishXR, "Poll interval changed; stopped composite resource controller",
+			"observed-interval", started.String(),
+			"desired-interval", pi.String()))
+	}
+
 	recorder := r.record.WithAnnotations("controller", composite.ControllerName(d.GetName()))
 
 	o := []composite.ReconcilerOption{
@@ -414,6 +494,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		)),
 		composite.WithLogger(log.WithValues("controller", composite.ControllerName(d.GetName()))),
 		composite.WithRecorder(recorder),
+		composite.WithBaseResolver(composite.NewAPIBaseResolver(r.client, r.namespace)),
+	}
+
+	if pi > 0 {
+		o = append(o, composite.WithPollInterval(pi))
+	}
+
+	if d.Spec.ReadyTimeout != nil && d.Spec.ReadyTimeout.Duration > 0 {
+		o = append(o, composite.WithReadyTimeout(d.Spec.ReadyTimeout.Duration))
+	}
+
+	ro := make([]composite.APIDryRunRendererOption, 0)
+	if mp := d.Spec.MetadataPropagation; mp != nil && mp.Composed != nil {
+		ro = append(ro, composite.WithPropagatedMetadata(mp.Composed))
+	}
+	if len(r.deniedComposedKinds) > 0 {
+		ro = append(ro, composite.WithDeniedKinds(r.deniedComposedKinds))
+	}
+	if len(ro) > 0 {
+		o = append(o, composite.WithRenderer(composite.NewAPIDryRunRenderer(r.client, ro...)))
 	}
 
 	// We only want to enable CompositionRevision support if the relevant
@@ -451,6 +551,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		o = append(o, composite.WithConfigurator(cc))
 	}
 
+	if r.options.Features.Enabled(features.RestoreMode) {
+		o = append(o, composite.WithRestoreMode())
+	}
+
+	if r.options.Features.Enabled(features.RecordComposedResourceDiffs) {
+		o = append(o, composite.WithDiffRecording())
+	}
+
+	if r.options.Features.Enabled(features.RecordReconcileSummaries) {
+		o = append(o, composite.WithReconcileSummaries())
+	}
+
+	if r.options.Features.Enabled(features.CompositionServiceAccounts) {
+		co := client.Options{Scheme: r.mgr.GetScheme(), Mapper: r.mgr.GetRESTMapper()}
+		o = append(o, composite.WithImpersonator(composite.NewAPIImpersonator(r.mgr.GetConfig(), co, r.namespace)))
+	}
+
+	if r.maxConcurrentApplies > 0 {
+		o = append(o, composite.WithMaxConcurrentApplies(r.maxConcurrentApplies))
+	}
+
+	if r.maxComposed > 0 {
+		o = append(o, composite.WithMaxComposedResources(r.maxComposed))
+	}
+
+	if r.maxComposedSize > 0 {
+		o = append(o, composite.WithMaxComposedResourceSize(r.maxComposedSize))
+	}
+
+	if r.deletionTimeout > 0 {
+		o = append(o, composite.WithDeletionTimeout(r.deletionTimeout))
+	}
+
+	// Composite resources that compose kinds whose CRDs aren't established
+	// yet are requeued when a CRD in the relevant API group changes.
+	pk := composite.NewPendingKinds()
+	o = append(o, composite.WithPendingKinds(pk))
+
 	cr := composite.NewReconciler(r.mgr, resource.CompositeKind(d.GetCompositeGroupVersionKind()), o...)
 	ko := r.options.ForControllerRuntime()
 	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), cr, r.options.GlobalRateLimiter)
@@ -458,15 +596,53 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	u := &kunstructured.Unstructured{}
 	u.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
 
-	if err := r.composite.Start(composite.ControllerName(d.GetName()), ko, controller.For(u, &handler.EnqueueRequestForObject{})); err != nil {
+	// We only watch the metadata of CRDs, to avoid caching their schemas.
+	crds := &metav1.PartialObjectMetadata{}
+	crds.SetGroupVersionKind(extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+
+	if err := r.composite.Start(composite.ControllerName(d.GetName()), ko,
+		controller.For(u, &handler.EnqueueRequestForObject{}),
+		controller.For(crds, &composite.EnqueueRequestForPendingKinds{Pending: pk}),
+	); err != nil {
 		log.Debug(errStartController, "error", err)
 		err = errors.Wrap(err, errStartController)
 		r.record.Event(d, event.Warning(reasonEstablishXR, err))
 		return reconcile.Result{}, err
 	}
+	r.pollIntervals.Set(composite.ControllerName(d.GetName()), pi)
 
 	d.Status.Controllers.CompositeResourceTypeRef = v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
 	d.Status.SetConditions(v1.WatchingComposite())
 	r.record.Event(d, event.Normal(reasonEstablishXR, "(Re)started composite resource controller"))
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
+
+// pollInterval returns the interval at which composite resources of the
+// supplied definition are polled. Composite resources are polled at the
+// supplied global poll interval unless their definition overrides it.
+func pollInterval(global time.Duration, d *v1.CompositeResourceDefinition) time.Duration {
+	if d.Spec.PollInterval != nil && d.Spec.PollInterval.Duration > 0 {
+		return d.Spec.PollInterval.Duration
+	}
+	return global
+}
+
+type pollIntervals struct {
+	mx      sync.Mutex
+	started map[string]time.Duration
+}
+
+// Get the poll interval the named controller was last started with, if any.
+func (p *pollIntervals) Get(name string) (time.Duration, bool) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	d, ok := p.started[name]
+	return d, ok
+}
+
+// Set the poll interval the named controller was started with.
+func (p *pollIntervals) Set(name string, d time.Duration) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.started[name] = d
+}
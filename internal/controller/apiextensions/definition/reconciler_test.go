@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -672,3 +673,95 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePollIntervalChanged(t *testing.T) {
+	interval := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	type args struct {
+		started *metav1.Duration
+		desired *metav1.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   int
+	}{
+		"Unchanged": {
+			reason: "We should not restart our controller if its poll interval did not change.",
+			args: args{
+				started: interval(5 * time.Minute),
+				desired: interval(5 * time.Minute),
+			},
+			want: 0,
+		},
+		"Changed": {
+			reason: "We should restart our controller if its poll interval changed.",
+			args: args{
+				started: interval(5 * time.Minute),
+				desired: interval(10 * time.Minute),
+			},
+			want: 1,
+		},
+		"Unset": {
+			reason: "We should restart our controller if its poll interval reverted to the global poll interval.",
+			args: args{
+				started: interval(5 * time.Minute),
+			},
+			want: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pi := tc.args.started
+			stopped := 0
+
+			r := NewReconciler(&fake.Manager{},
+				WithClientApplicator(resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.(*v1.CompositeResourceDefinition).Spec.PollInterval = pi
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				}),
+				WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+					return &extv1.CustomResourceDefinition{
+						Status: extv1.CustomResourceDefinitionStatus{
+							Conditions: []extv1.CustomResourceDefinitionCondition{
+								{Type: extv1.Established, Status: extv1.ConditionTrue},
+							},
+						},
+					}, nil
+				})),
+				WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+					return nil
+				}}),
+				WithControllerEngine(&MockEngine{
+					MockErr:   func(_ string) error { return nil },
+					MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return nil },
+					MockStop:  func(_ string) { stopped++ },
+				}),
+			)
+
+			// Start the controller, then reconcile again with the desired
+			// poll interval.
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+			pi = tc.args.desired
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want, stopped); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want stops, +got stops:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc implements a controller that garbage collects composed resources
+// whose composite resource no longer exists.
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/throttle"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	timeout             = 2 * time.Minute
+	defaultPollInterval = 1 * time.Hour
+)
+
+// Error strings.
+const (
+	errNewController = "cannot create controller"
+	errWatch         = "cannot watch Compositions"
+	errListComps     = "cannot list Compositions"
+	errFmtList       = "cannot list composed resources of kind %q"
+	errGetComposite  = "cannot get composite resource"
+	errCheckOrphan   = "cannot determine whether composed resource is orphaned"
+	errDelete        = "cannot delete orphaned composed resource"
+
+	errOrphaned = "composed resource is orphaned; its composite resource no longer exists"
+)
+
+// Event reasons.
+const (
+	reasonOrphaned event.Reason = "OrphanedComposedResource"
+)
+
+// An OrphanChecker determines whether a composed resource is orphaned.
+type OrphanChecker interface {
+	// IsOrphaned returns true if the supplied composed resource is orphaned.
+	IsOrphaned(ctx context.Context, cd *kunstructured.Unstructured) (bool, error)
+}
+
+// An OrphanCheckerFn determines whether a composed resource is orphaned.
+type OrphanCheckerFn func(ctx context.Context, cd *kunstructured.Unstructured) (bool, error)
+
+// IsOrphaned returns true if the supplied composed resource is orphaned.
+func (fn OrphanCheckerFn) IsOrphaned(ctx context.Context, cd *kunstructured.Unstructured) (bool, error) {
+	return fn(ctx, cd)
+}
+
+// Setup adds a controller that periodically checks each kind of resource that
+// is composed by a Composition for resources that have been orphaned, i.e.
+// whose composite resource no longer exists. Each kind is checked once, no
+// matter how many Compositions compose it.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "gc/composed"
+
+	oco := []APIOrphanCheckerOption{}
+	if o.Features.Enabled(features.RestoreMode) {
+		oco = append(oco, WithRestoreMode())
+	}
+
+	// We read composed and composite resources directly from the API server.
+	// Reading them from the manager's cache would start an informer, and thus
+	// cache every resource, for each kind of composed resource.
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		WithReader(mgr.GetAPIReader()),
+		WithOrphanChecker(NewAPIOrphanChecker(mgr.GetAPIReader(), oco...)),
+		WithOrphanPolicy(o.OrphanPolicy),
+		WithPollInterval(o.OrphanCheckInterval))
+
+	ko := o.ForControllerRuntime()
+	ko.Reconciler = ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter)
+
+	c, err := kcontroller.New(name, mgr, ko)
+	if err != nil {
+		return errors.Wrap(err, errNewController)
+	}
+	return errors.Wrap(c.Watch(&source.Kind{Type: &v1.Composition{}}, &EnqueueRequestForComposedKinds{}), errWatch)
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithClient specifies how the Reconciler should interact with the Kubernetes
+// API.
+func WithClient(c client.Client) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = c
+	}
+}
+
+// WithReader specifies how the Reconciler should read composed resources. It
+// is used only to list composed resources.
+func WithReader(c client.Reader) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.reader = c
+	}
+}
+
+// WithOrphanChecker specifies how the Reconciler should determine whether a
+// composed resource is orphaned.
+func WithOrphanChecker(oc OrphanChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.orphan = oc
+	}
+}
+
+// WithOrphanPolicy specifies what the Reconciler should do with orphaned
+// composed resources.
+func WithOrphanPolicy(p controller.OrphanPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		if p == "" {
+			return
+		}
+		r.policy = p
+	}
+}
+
+// WithPollInterval specifies how often the Reconciler should check for
+// orphaned composed resources. A composed resource must have been orphaned for
+// at least this long before it will be deleted.
+func WithPollInterval(after time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		if after <= 0 {
+			return
+		}
+		r.pollInterval = after
+	}
+}
+
+// NewReconciler returns a Reconciler that garbage collects orphaned composed
+// resources.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:       mgr.GetClient(),
+		reader:       mgr.GetClient(),
+		orphan:       NewAPIOrphanChecker(mgr.GetClient()),
+		policy:       controller.OrphanPolicyReport,
+		pollInterval: defaultPollInterval,
+		suspects:     &suspects{seen: map[types.UID]time.Time{}},
+
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler garbage collects orphaned composed resources.
+type Reconciler struct {
+	client       client.Client
+	reader       client.Reader
+	orphan       OrphanChecker
+	policy       controller.OrphanPolicy
+	pollInterval time.Duration
+
+	// suspects tracks when each composed resource was first observed to be
+	// orphaned. A resource is only deleted once it has been observed to be
+	// orphaned by at least two checks, at least one poll interval apart.
+	suspects *suspects
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a kind of composed resource by checking whether any resources of
+// that kind have been orphaned, and reporting or deleting them per the
+// configured orphan policy.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	gvk := KindFor(req)
+	log = log.WithValues("kind", gvk.String(), "policy", r.policy)
+
+	cl := &v1.CompositionList{}
+	if err := r.client.List(ctx, cl); err != nil {
+		log.Debug(errListComps, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errListComps)
+	}
+	if !composed(cl.Items, gvk) {
+		// No Composition composes this kind anymore. We'll start checking it
+		// again if a Composition starts composing it.
+		log.Debug("Kind is no longer composed by any Composition")
+		return reconcile.Result{}, nil
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := r.reader.List(ctx, l, client.HasLabels{xcrd.LabelKeyNamePrefixForComposed})
+	if kmeta.IsNoMatchError(err) {
+		// This kind of composed resource isn't served by the API server
+		// (yet), so there can't be any orphans of this kind.
+		return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+	}
+	if err != nil {
+		log.Debug("Cannot list composed resources", "error", err)
+		return reconcile.Result{}, errors.Wrapf(err, errFmtList, gvk.String())
+	}
+
+	for i := range l.Items {
+		r.collect(ctx, log, &l.Items[i])
+	}
+
+	return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+}
+
+// collect the supplied composed resource if it is orphaned. Errors are logged
+// and recorded on the composed resource rather than returned, so that one
+// resource can't prevent the rest from being checked.
+func (r *Reconciler) collect(ctx context.Context, log logging.Logger, cd *kunstructured.Unstructured) {
+	log = log.WithValues("resource-name", cd.GetName(), "resource-uid", cd.GetUID())
+
+	orphaned, err := r.orphan.IsOrphaned(ctx, cd)
+	if err != nil {
+		log.Debug(errCheckOrphan, "error", err)
+		r.record.Event(cd, event.Warning(reasonOrphaned, errors.Wrap(err, errCheckOrphan)))
+		return
+	}
+	if !orphaned {
+		r.suspects.Forget(cd.GetUID())
+		return
+	}
+
+	first, suspected := r.suspects.Observe(cd.GetUID())
+	if !suspected {
+		// Only report each orphaned resource once, when we first notice it.
+		log.Info("Found orphaned composed resource")
+		r.record.Event(cd, event.Warning(reasonOrphaned, errors.New(errOrphaned)))
+	}
+
+	if r.policy != controller.OrphanPolicyDelete {
+		return
+	}
+
+	// Don't delete a resource until we've seen it orphaned for a full poll
+	// interval, in case we happened to observe it mid-way through being
+	// created or adopted.
+	if !suspected || time.Since(first) < r.pollInterval {
+		return
+	}
+
+	// Only delete the resource we checked, not a newer one of the same name.
+	uid := cd.GetUID()
+	if err := r.client.Delete(ctx, cd, client.Preconditions{UID: &uid}); client.IgnoreNotFound(err) != nil {
+		log.Debug(errDelete, "error", err)
+		r.record.Event(cd, event.Warning(reasonOrphaned, errors.Wrap(err, errDelete)))
+		return
+	}
+	r.suspects.Forget(uid)
+	log.Info("Deleted orphaned composed resource")
+	r.record.Event(cd, event.Normal(reasonOrphaned, "Deleted orphaned composed resource"))
+}
+
+type suspects struct {
+	mx   sync.Mutex
+	seen map[types.UID]time.Time
+}
+
+// Observe that the supplied UID is orphaned. Returns when it was first
+// observed, and whether it had been observed before.
+func (s *suspects) Observe(uid types.UID) (time.Time, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if t, ok := s.seen[uid]; ok {
+		return t, true
+	}
+	t := time.Now()
+	s.seen[uid] = t
+	return t, false
+}
+
+// Forget the supplied UID.
+func (s *suspects) Forget(uid types.UID) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	delete(s.seen, uid)
+}
+
+func composed(comps []v1.Composition, gvk schema.GroupVersionKind) bool {
+	for i := range comps {
+		for _, k := range ComposedKinds(&comps[i]) {
+			if k == gvk {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ComposedKinds returns the distinct kinds of resource the supplied Composition
+// composes. Templates whose base cannot be decoded are ignored.
+func ComposedKinds(comp *v1.Composition) []schema.GroupVersionKind {
+	seen := map[schema.GroupVersionKind]bool{}
+	gvks := make([]schema.GroupVersionKind, 0, len(comp.Spec.Resources))
+	for _, t := range comp.Spec.Resources {
+		tm := &metav1.TypeMeta{}
+		if err := json.Unmarshal(t.Base.Raw, tm); err != nil {
+			continue
+		}
+		gvk := tm.GroupVersionKind()
+		if gvk.Kind == "" || seen[gvk] {
+			continue
+		}
+		seen[gvk] = true
+		gvks = append(gvks, gvk)
+	}
+	return gvks
+}
+
+// RequestFor returns a reconcile request for the supplied kind of composed
+// resource. Kinds are encoded as Kind.version.group, which is unambiguous
+// because neither kinds nor versions may contain a dot.
+func RequestFor(gvk schema.GroupVersionKind) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: strings.Join([]string{gvk.Kind, gvk.Version, gvk.Group}, ".")}}
+}
+
+// KindFor returns the kind of composed resource encoded in the supplied
+// reconcile request.
+func KindFor(req reconcile.Request) schema.GroupVersionKind {
+	s := strings.SplitN(req.Name, ".", 3)
+	for len(s) < 3 {
+		s = append(s, "")
+	}
+	return schema.GroupVersionKind{Kind: s[0], Version: s[1], Group: s[2]}
+}
+
+// An APIOrphanChecker determines whether a composed resource is orphaned by
+// reading its controlling composite resource from the API server.
+type APIOrphanChecker struct {
+	client  client.Reader
+	restore bool
+}
+
+// An APIOrphanCheckerOption configures an APIOrphanChecker.
+type APIOrphanCheckerOption func(*APIOrphanChecker)
+
+// WithRestoreMode configures the APIOrphanChecker to consider composed
+// resources whose controller was replaced by a composite resource of the same
+// name not to be orphaned. Such composite resources were likely restored from
+// a backup, and will adopt the composed resource. Composite resources annotated
+// as restored are treated this way regardless.
+func WithRestoreMode() APIOrphanCheckerOption {
+	return func(c *APIOrphanChecker) {
+		c.restore = true
+	}
+}
+
+// NewAPIOrphanChecker returns an OrphanChecker that determines whether a
+// composed resource is orphaned by reading its controlling composite resource
+// from the API server.
+func NewAPIOrphanChecker(c client.Reader, opts ...APIOrphanCheckerOption) *APIOrphanChecker {
+	oc := &APIOrphanChecker{client: c}
+	for _, f := range opts {
+		f(oc)
+	}
+	return oc
+}
+
+// IsOrphaned returns true if the supplied composed resource is controlled by a
+// composite resource that no longer exists.
+func (c *APIOrphanChecker) IsOrphaned(ctx context.Context, cd *kunstructured.Unstructured) (bool, error) {
+	// Composed resources are always created with a controller reference to
+	// their composite resource. A resource without one was deliberately
+	// detached from its composite resource, e.g. by deleting the composite
+	// resource with an orphan deletion propagation policy, so we leave it be.
+	// Top-level composite resources are labelled as composed resources, but
+	// have no controller. Nested composite resources are controlled by their
+	// parent composite resource, and are orphaned if it no longer exists.
+	ref := metav1.GetControllerOf(cd)
+	if ref == nil {
+		return false, nil
+	}
+
+	cp := &kunstructured.Unstructured{}
+	cp.SetAPIVersion(ref.APIVersion)
+	cp.SetKind(ref.Kind)
+	err := c.client.Get(ctx, types.NamespacedName{Name: ref.Name}, cp)
+	if kmeta.IsNoMatchError(err) {
+		// The kind of composite resource isn't served, perhaps temporarily.
+		// We can't tell whether the composite resource exists, so we assume
+		// it does.
+		return false, nil
+	}
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetComposite)
+	}
+
+	if cp.GetUID() == ref.UID {
+		return false, nil
+	}
+
+	// A composite resource with the same name exists, but it's not the one
+	// that created this composed resource, e.g. after an etcd restore. If it
+	// was restored from a backup it will adopt this composed resource.
+	return !c.restore && cp.GetAnnotations()[xcrd.AnnotationKeyRestored] != "true", nil
+}
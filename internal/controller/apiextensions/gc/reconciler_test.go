@@ -0,0 +1,495 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	interval := 5 * time.Minute
+	uid := types.UID("composed-uid")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Composed"}
+
+	// list returns a MockListFn that lists a Composition that composes our
+	// kind.
+	list := func(composes bool) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			if !composes {
+				return nil
+			}
+			l := obj.(*v1.CompositionList)
+			l.Items = []v1.Composition{{Spec: v1.CompositionSpec{Resources: []v1.ComposedTemplate{{
+				Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Composed"}`)},
+			}}}}}
+			return nil
+		})
+	}
+
+	// listComposed returns a MockListFn that lists a composed resource of our
+	// kind. Composed resources are listed using the reader, not the client.
+	listComposed := func(err error) test.MockListFn {
+		return test.NewMockListFn(err, func(obj client.ObjectList) error {
+			cd := kunstructured.Unstructured{}
+			cd.SetUID(uid)
+			obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{cd}
+			return nil
+		})
+	}
+	noDelete := func(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+		t.Errorf("client.Delete(...): called unexpectedly")
+		return nil
+	}
+	orphaned := OrphanCheckerFn(func(_ context.Context, _ *kunstructured.Unstructured) (bool, error) { return true, nil })
+
+	type args struct {
+		opts []ReconcilerOption
+
+		// observations is how many times to reconcile. Defaults to once.
+		observations int
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListCompositionsError": {
+			reason: "We should return any error encountered while listing Compositions.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList: test.NewMockListFn(errBoom),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListComps),
+			},
+		},
+		"KindNotComposed": {
+			reason: "We should stop checking a kind that is no longer composed by any Composition.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList: list(false),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ListComposedError": {
+			reason: "We should return any error encountered while listing composed resources.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList: list(true),
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(errBoom),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, gvk.String()),
+			},
+		},
+		"KindNotServed": {
+			reason: "We should keep polling a kind that is not yet served by the API server.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList: list(true),
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(&kmeta.NoKindMatchError{}),
+					}),
+					WithPollInterval(interval),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: interval},
+			},
+		},
+		"CheckOrphanError": {
+			reason: "We should keep checking other composed resources if we can't determine whether one is orphaned.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList:   list(true),
+						MockDelete: noDelete,
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(OrphanCheckerFn(func(_ context.Context, _ *kunstructured.Unstructured) (bool, error) {
+						return false, errBoom
+					})),
+					WithOrphanPolicy(controller.OrphanPolicyDelete),
+					WithPollInterval(interval),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: interval},
+			},
+		},
+		"ReportOrphan": {
+			reason: "We should not delete orphaned composed resources when our policy is to report them.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList:   list(true),
+						MockDelete: noDelete,
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(orphaned),
+					WithOrphanPolicy(controller.OrphanPolicyReport),
+					WithPollInterval(time.Nanosecond),
+				},
+				observations: 2,
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Nanosecond},
+			},
+		},
+		"FirstObservation": {
+			reason: "We should not delete an orphaned composed resource the first time we observe it.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList:   list(true),
+						MockDelete: noDelete,
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(orphaned),
+					WithOrphanPolicy(controller.OrphanPolicyDelete),
+					WithPollInterval(time.Nanosecond),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Nanosecond},
+			},
+		},
+		"WithinGracePeriod": {
+			reason: "We should not delete an orphaned composed resource until it has been orphaned for a poll interval.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList:   list(true),
+						MockDelete: noDelete,
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(orphaned),
+					WithOrphanPolicy(controller.OrphanPolicyDelete),
+					WithPollInterval(interval),
+				},
+				observations: 2,
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: interval},
+			},
+		},
+		"DeleteOrphanError": {
+			reason: "We should not return an error encountered while deleting an orphaned composed resource.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList:   list(true),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(orphaned),
+					WithOrphanPolicy(controller.OrphanPolicyDelete),
+					WithPollInterval(time.Nanosecond),
+				},
+				observations: 2,
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Nanosecond},
+			},
+		},
+		"DeleteOrphan": {
+			reason: "We should delete a composed resource that we have observed to be orphaned for a poll interval, but only if its UID has not changed.",
+			args: args{
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockList: list(true),
+						MockDelete: func(_ context.Context, _ client.Object, opts ...client.DeleteOption) error {
+							want := &client.DeleteOptions{}
+							want.ApplyOptions([]client.DeleteOption{client.Preconditions{UID: &uid}})
+							got := &client.DeleteOptions{}
+							got.ApplyOptions(opts)
+							if diff := cmp.Diff(want, got); diff != "" {
+								t.Errorf("client.Delete(...): -want options, +got options:\n%s", diff)
+							}
+							return nil
+						},
+					}),
+					WithReader(&test.MockClient{
+						MockList: listComposed(nil),
+					}),
+					WithOrphanChecker(orphaned),
+					WithOrphanPolicy(controller.OrphanPolicyDelete),
+					WithPollInterval(time.Nanosecond),
+				},
+				observations: 2,
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Nanosecond},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{}, tc.args.opts...)
+
+			var got reconcile.Result
+			var err error
+			for i := 0; i < tc.args.observations || i == 0; i++ {
+				got, err = r.Reconcile(context.Background(), RequestFor(gvk))
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKindFor(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		gvk    schema.GroupVersionKind
+	}{
+		"NamedGroup": {
+			reason: "We should be able to round-trip a kind in a named API group.",
+			gvk:    schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Composed"},
+		},
+		"CoreGroup": {
+			reason: "We should be able to round-trip a kind in the core API group.",
+			gvk:    schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := KindFor(RequestFor(tc.gvk))
+			if diff := cmp.Diff(tc.gvk, got); diff != "" {
+				t.Errorf("\n%s\nKindFor(RequestFor(...)): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComposedKinds(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		comp   *v1.Composition
+		want   []schema.GroupVersionKind
+	}{
+		"DistinctKinds": {
+			reason: "We should return each distinct kind of composed resource, ignoring templates we can't decode.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"A"}`)}},
+						{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"B"}`)}},
+						{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"A"}`)}},
+						{Base: runtime.RawExtension{Raw: []byte(`{`)}},
+					},
+				},
+			},
+			want: []schema.GroupVersionKind{
+				{Group: "example.org", Version: "v1", Kind: "A"},
+				{Group: "example.org", Version: "v1", Kind: "B"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ComposedKinds(tc.comp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nComposedKinds(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsOrphaned(t *testing.T) {
+	errBoom := errors.New("boom")
+	ctrl := true
+	uid := types.UID("composite-uid")
+
+	composed := func(refs ...metav1.OwnerReference) *kunstructured.Unstructured {
+		cd := &kunstructured.Unstructured{}
+		cd.SetName("cool-xr-abcde")
+		cd.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "cool-xr"})
+		cd.SetOwnerReferences(refs)
+		return cd
+	}
+	controlledBy := metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XR", Name: "cool-xr", UID: uid, Controller: &ctrl}
+
+	type want struct {
+		orphaned bool
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		opts   []APIOrphanCheckerOption
+		cd     *kunstructured.Unstructured
+		want   want
+	}{
+		"TopLevelComposite": {
+			reason: "A top-level composite resource has no controller reference, and is not orphaned.",
+			cd: func() *kunstructured.Unstructured {
+				cd := composed()
+				cd.SetName("cool-xr")
+				return cd
+			}(),
+			want: want{orphaned: false},
+		},
+		"NestedComposite": {
+			reason: "A nested composite resource whose parent composite resource does not exist is orphaned, even if it is labelled with its own name.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			cd: func() *kunstructured.Unstructured {
+				cd := composed(controlledBy)
+				cd.SetName("nested-xr")
+				cd.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "nested-xr"})
+				return cd
+			}(),
+			want: want{orphaned: true},
+		},
+		"NoController": {
+			reason: "A composed resource without a controller reference was deliberately detached, and is not orphaned.",
+			cd:     composed(),
+			want:   want{orphaned: false},
+		},
+		"CompositeKindNotServed": {
+			reason: "A composed resource whose controller's kind is not served is not considered orphaned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(&kmeta.NoKindMatchError{})},
+			cd:     composed(controlledBy),
+			want:   want{orphaned: false},
+		},
+		"CompositeNotFound": {
+			reason: "A composed resource whose controller does not exist is orphaned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			cd:     composed(controlledBy),
+			want:   want{orphaned: true},
+		},
+		"GetCompositeError": {
+			reason: "We should return any other error encountered while getting the composite resource.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cd:     composed(controlledBy),
+			want:   want{err: errors.Wrap(errBoom, errGetComposite)},
+		},
+		"CompositeReplaced": {
+			reason: "A composed resource whose controller has been replaced by a different resource of the same name is orphaned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.SetUID("some-other-uid")
+				return nil
+			})},
+			cd:   composed(controlledBy),
+			want: want{orphaned: true},
+		},
+		"CompositeRestoreMode": {
+			reason: "A composed resource whose controller has been replaced by a different resource of the same name is not orphaned in restore mode.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.SetUID("some-other-uid")
+				return nil
+			})},
+			opts: []APIOrphanCheckerOption{WithRestoreMode()},
+			cd:   composed(controlledBy),
+			want: want{orphaned: false},
+		},
+		"CompositeRestored": {
+			reason: "A composed resource whose controller has been replaced by a restored resource of the same name is not orphaned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.SetUID("some-other-uid")
+				o.SetAnnotations(map[string]string{xcrd.AnnotationKeyRestored: "true"})
+				return nil
+			})},
+			cd:   composed(controlledBy),
+			want: want{orphaned: false},
+		},
+		"CompositeExists": {
+			reason: "A composed resource whose controller exists is not orphaned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.SetUID(uid)
+				return nil
+			})},
+			cd:   composed(controlledBy),
+			want: want{orphaned: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewAPIOrphanChecker(tc.client, tc.opts...)
+			got, err := c.IsOrphaned(context.Background(), tc.cd)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.IsOrphaned(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.orphaned, got); diff != "" {
+				t.Errorf("\n%s\nc.IsOrphaned(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+type adder interface {
+	Add(item any)
+}
+
+// EnqueueRequestForComposedKinds enqueues a reconcile for each kind of
+// resource a Composition composes. Compositions that compose the same kind
+// enqueue the same request, so each kind is checked only once.
+type EnqueueRequestForComposedKinds struct{}
+
+// Create adds a request for each kind composed by the Composition in the
+// supplied CreateEvent.
+func (e *EnqueueRequestForComposedKinds) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Update adds a request for each kind composed by the Composition in the
+// supplied UpdateEvent.
+func (e *EnqueueRequestForComposedKinds) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.ObjectNew, q)
+}
+
+// Delete adds a request for each kind composed by the Composition in the
+// supplied DeleteEvent. Kinds that are no longer composed by any Composition
+// stop being checked.
+func (e *EnqueueRequestForComposedKinds) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Generic adds a request for each kind composed by the Composition in the
+// supplied GenericEvent.
+func (e *EnqueueRequestForComposedKinds) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+func (e *EnqueueRequestForComposedKinds) add(obj runtime.Object, queue adder) {
+	comp, ok := obj.(*v1.Composition)
+	if !ok {
+		return
+	}
+	for _, gvk := range ComposedKinds(comp) {
+		queue.Add(RequestFor(gvk))
+	}
+}
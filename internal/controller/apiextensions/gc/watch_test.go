@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var (
+	_ handler.EventHandler = &EnqueueRequestForComposedKinds{}
+)
+
+type addFn func(item any)
+
+func (fn addFn) Add(item any) {
+	fn(item)
+}
+
+func TestAdd(t *testing.T) {
+	cases := map[string]struct {
+		obj   runtime.Object
+		queue adder
+	}{
+		"ObjectIsNotAComposition": {
+			queue: addFn(func(_ any) { t.Errorf("queue.Add() called unexpectedly") }),
+		},
+		"SuccessfulEnqueue": {
+			obj: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Composed"}`)}},
+						{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Composed"}`)}},
+					},
+				},
+			},
+			queue: addFn(func(got any) {
+				want := RequestFor(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Composed"})
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("-want, +got:\n%s\n", diff)
+				}
+			}),
+		},
+	}
+
+	for _, tc := range cases {
+		e := &EnqueueRequestForComposedKinds{}
+		e.add(tc.obj, tc.queue)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
@@ -46,6 +47,7 @@ import (
 	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/throttle"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -59,6 +61,7 @@ const (
 	errGetXRD          = "cannot get CompositeResourceDefinition"
 	errRenderCRD       = "cannot render composite resource claim CustomResourceDefinition"
 	errGetCRD          = "cannot get composite resource claim CustomResourceDefinition"
+	errListCRDs        = "cannot list CustomResourceDefinitions"
 	errApplyCRD        = "cannot apply rendered composite resource claim CustomResourceDefinition"
 	errUpdateStatus    = "cannot update status of CompositeResourceDefinition"
 	errStartController = "cannot start composite resource claim controller"
@@ -67,19 +70,30 @@ const (
 	errDeleteCRD       = "cannot delete composite resource claim CustomResourceDefinition"
 	errListCRs         = "cannot list defined composite resource claims"
 	errDeleteCR        = "cannot delete defined composite resource claim"
+
+	errRenderPreviousCRD = "cannot render previous composite resource claim CustomResourceDefinition"
+	errApplyPreviousCRD  = "cannot apply rendered previous composite resource claim CustomResourceDefinition"
+	errDeletePreviousCRD = "cannot delete previous composite resource claim CustomResourceDefinition"
+	errListPreviousCRs   = "cannot list previous composite resource claims"
+	errStartMigrator     = "cannot start composite resource claim migration controller"
 )
 
 // Wait strings.
 const (
 	waitCRDelete     = "waiting for defined composite resource claims to be deleted"
 	waitCRDEstablish = "waiting for composite resource claim CustomResourceDefinition to be established"
+	waitCRWithdraw   = "waiting for defined composite resource claims to be deleted before withdrawing the composite resource claim"
+
+	waitPreviousCRDEstablish = "waiting for previous composite resource claim CustomResourceDefinitions to be established"
 )
 
 // Event reasons.
 const (
-	reasonRenderCRD event.Reason = "RenderCRD"
-	reasonOfferXRC  event.Reason = "OfferClaim"
-	reasonRedactXRC event.Reason = "RedactClaim"
+	reasonRenderCRD   event.Reason = "RenderCRD"
+	reasonOfferXRC    event.Reason = "OfferClaim"
+	reasonRedactXRC   event.Reason = "RedactClaim"
+	reasonWithdrawXRC event.Reason = "WithdrawClaim"
+	reasonMigrateXRC  event.Reason = "MigrateClaim"
 )
 
 // A ControllerEngine can start and stop Kubernetes controllers on demand.
@@ -106,6 +120,21 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 	return fn(d)
 }
 
+// A PreviousCRDRenderer renders the CustomResourceDefinition of a composite
+// resource claim that a CompositeResourceDefinition previously offered.
+type PreviousCRDRenderer interface {
+	Render(d *v1.CompositeResourceDefinition, n extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error)
+}
+
+// A PreviousCRDRenderFn renders the CustomResourceDefinition of a composite
+// resource claim that a CompositeResourceDefinition previously offered.
+type PreviousCRDRenderFn func(d *v1.CompositeResourceDefinition, n extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error)
+
+// Render the CustomResourceDefinition of the supplied previous claim names.
+func (fn PreviousCRDRenderFn) Render(d *v1.CompositeResourceDefinition, n extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error) {
+	return fn(d, n)
+}
+
 // Setup adds a controller that reconciles CompositeResourceDefinitions by
 // defining a composite resource claim and starting a controller to reconcile
 // it.
@@ -114,14 +143,14 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
 		WithOptions(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.CompositeResourceDefinition{}).
 		Owns(&extv1.CustomResourceDefinition{}).
-		WithEventFilter(resource.NewPredicates(OffersClaim())).
+		WithEventFilter(resource.NewPredicates(resource.AnyOf(OffersClaim(), OfferedClaim()))).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
@@ -175,6 +204,15 @@ func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
 	}
 }
 
+// WithPreviousCRDRenderer specifies how the Reconciler should render the
+// CustomResourceDefinitions of composite resource claims that a
+// CompositeResourceDefinition previously offered.
+func WithPreviousCRDRenderer(c PreviousCRDRenderer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.previous = c
+	}
+}
+
 // WithClientApplicator specifies how the Reconciler should interact with the
 // Kubernetes API.
 func WithClientApplicator(ca resource.ClientApplicator) ReconcilerOption {
@@ -200,6 +238,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 			ControllerEngine: controller.NewEngine(mgr),
 			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
 		},
+		previous: PreviousCRDRenderFn(xcrd.ForPreviousCompositeResourceClaim),
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
@@ -224,7 +263,8 @@ type Reconciler struct {
 	mgr    manager.Manager
 	client resource.ClientApplicator
 
-	claim definition
+	claim    definition
+	previous PreviousCRDRenderer
 
 	log    logging.Logger
 	record event.Recorder
@@ -256,6 +296,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", d.GetName(),
 	)
 
+	// An XRD that stops offering a claim continues to reconcile any
+	// existing claims until they're deleted. We never delete them
+	// ourselves, because doing so would delete the composite resources
+	// they claim.
+	if withdrawsClaim(d) {
+		n, err := r.countClaims(ctx, d)
+		if err != nil {
+			log.Debug(errListCRs, "error", err)
+			err = errors.Wrap(err, errListCRs)
+			r.record.Event(d, event.Warning(reasonWithdrawXRC, err))
+			return reconcile.Result{}, err
+		}
+		// We can't reconcile claims without their names, so we withdraw
+		// (or wait to withdraw) the claim regardless.
+		if n == 0 || d.Spec.ClaimNames == nil {
+			return r.withdraw(ctx, log, d, n)
+		}
+	}
+
 	crd, err := r.claim.Render(d)
 	if err != nil {
 		log.Debug(errRenderCRD, "error", err)
@@ -296,6 +355,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// just in case. This is a no-op if the controller was
 			// already stopped.
 			r.claim.Stop(claim.ControllerName(d.GetName()))
+			r.stopMigrators(d)
 			log.Debug("Stopped composite resource claim controller")
 			r.record.Event(d, event.Normal(reasonRedactXRC, "Stopped composite resource claim controller"))
 
@@ -351,6 +411,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// The controller should be stopped before the deletion of CRD
 		// so that it doesn't crash.
 		r.claim.Stop(claim.ControllerName(d.GetName()))
+		r.stopMigrators(d)
 		log.Debug("Stopped composite resource claim controller")
 		r.record.Event(d, event.Normal(reasonRedactXRC, "Stopped composite resource claim controller"))
 
@@ -408,6 +469,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.client, secretsv1alpha1.StoreConfigGroupVersionKind))))
 	}
 
+	if r.options.Features.Enabled(features.RestoreMode) {
+		o = append(o, claim.WithRestoreMode())
+	}
+
+	if mp := d.Spec.MetadataPropagation; mp != nil && mp.Claim != nil {
+		o = append(o, claim.WithCompositeConfigurator(claim.NewAPIDryRunCompositeConfigurator(r.client, claim.WithPropagatedMetadata(mp.Claim))))
+	}
+
 	cr := claim.NewReconciler(r.mgr,
 		resource.CompositeClaimKind(d.GetClaimGroupVersionKind()),
 		resource.CompositeKind(d.GetCompositeGroupVersionKind()), o...)
@@ -422,7 +491,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	observed := d.Status.Controllers.CompositeResourceClaimTypeRef
 	desired := v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
 	if observed.APIVersion != "" && observed != desired {
+		// Migration controllers migrate claims to the observed type,
+		// so they must be restarted too.
 		r.claim.Stop(claim.ControllerName(d.GetName()))
+		r.stopMigrators(d)
 		log.Debug("Referenceable version changed; stopped composite resource claim controller",
 			"observed-version", observed.APIVersion,
 			"desired-version", desired.APIVersion)
@@ -448,7 +520,254 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 	r.record.Event(d, event.Normal(reasonOfferXRC, "(Re)started composite resource claim controller"))
 
+	previous, established, err := r.servePreviousClaims(ctx, d)
+	if err != nil {
+		log.Debug("Cannot serve previous composite resource claims", "error", err)
+		r.record.Event(d, event.Warning(reasonMigrateXRC, err))
+		return reconcile.Result{}, err
+	}
+	if !established {
+		log.Debug(waitPreviousCRDEstablish)
+		r.record.Event(d, event.Normal(reasonMigrateXRC, waitPreviousCRDEstablish))
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	d.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
-	d.Status.SetConditions(v1.WatchingClaim())
-	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+	d.Status.Controllers.PreviousCompositeResourceClaimTypeRefs = previous
+	if !withdrawsClaim(d) {
+		d.Status.SetConditions(v1.WatchingClaim())
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+	}
+
+	// We won't be requeued implicitly when the remaining claims are
+	// deleted, so we poll to see whether we can withdraw the claim.
+	log.Debug(waitCRWithdraw)
+	r.record.Event(d, event.Normal(reasonWithdrawXRC, waitCRWithdraw))
+	d.Status.SetConditions(v1.TerminatingClaim().WithMessage(waitCRWithdraw))
+	return reconcile.Result{RequeueAfter: r.options.PollInterval}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+}
+
+// withdrawsClaim returns true if the supplied XRD is withdrawing a composite
+// resource claim that it previously offered.
+func withdrawsClaim(d *v1.CompositeResourceDefinition) bool {
+	if d.Spec.ClaimNames == nil {
+		// The claim names were removed while the claim was offered. This
+		// is only possible if the XRD was not validated by our webhook.
+		return d.Status.Controllers.CompositeResourceClaimTypeRef.Kind != ""
+	}
+	return !d.OffersClaim() && !meta.WasDeleted(d)
+}
+
+// countClaims returns the number of composite resource claims of the kind the
+// supplied XRD offers, or offered.
+func (r *Reconciler) countClaims(ctx context.Context, d *v1.CompositeResourceDefinition) (int, error) {
+	gvk := d.GetClaimGroupVersionKind()
+	if d.Spec.ClaimNames == nil {
+		ref := d.Status.Controllers.CompositeResourceClaimTypeRef
+		gvk = schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk)
+	err := r.client.List(ctx, l)
+	return len(l.Items), resource.Ignore(kmeta.IsNoMatchError, err)
+}
+
+// getClaimCRD returns the composite resource claim CRD of the supplied XRD. The
+// returned CRD has no creation timestamp if it does not exist.
+func (r *Reconciler) getClaimCRD(ctx context.Context, d *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+	if d.Spec.ClaimNames != nil {
+		crd, err := r.claim.Render(d)
+		if err != nil {
+			return nil, errors.Wrap(err, errRenderCRD)
+		}
+		err = r.client.Get(ctx, types.NamespacedName{Name: crd.GetName()}, crd)
+		return crd, errors.Wrap(resource.IgnoreNotFound(err), errGetCRD)
+	}
+
+	// We can't render the CRD without claim names, so we find it using the
+	// type of claim we were last watching.
+	ref := d.Status.Controllers.CompositeResourceClaimTypeRef
+	return r.getCRD(ctx, d, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind())
+}
+
+// getCRD returns the CRD of the supplied kind that is controlled by the
+// supplied XRD. The returned CRD has no creation timestamp if it does not
+// exist.
+func (r *Reconciler) getCRD(ctx context.Context, d *v1.CompositeResourceDefinition, gk schema.GroupKind) (*extv1.CustomResourceDefinition, error) {
+	l := &extv1.CustomResourceDefinitionList{}
+	if err := r.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListCRDs)
+	}
+	for i := range l.Items {
+		crd := &l.Items[i]
+		if crd.Spec.Group == gk.Group && crd.Spec.Names.Kind == gk.Kind && metav1.IsControlledBy(crd, d) {
+			return crd, nil
+		}
+	}
+	return &extv1.CustomResourceDefinition{}, nil
+}
+
+// withdraw the composite resource claim the supplied XRD no longer offers by
+// stopping its controller and deleting its CRD, once the supplied number of
+// remaining claims is zero.
+func (r *Reconciler) withdraw(ctx context.Context, log logging.Logger, d *v1.CompositeResourceDefinition, claims int) (reconcile.Result, error) {
+	if claims > 0 {
+		log.Debug(waitCRWithdraw, "claims", claims)
+		r.record.Event(d, event.Normal(reasonWithdrawXRC, waitCRWithdraw))
+		d.Status.SetConditions(v1.TerminatingClaim().WithMessage(waitCRWithdraw))
+		return reconcile.Result{RequeueAfter: r.options.PollInterval}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+	}
+
+	crd, err := r.getClaimCRD(ctx, d)
+	if err != nil {
+		log.Debug("Cannot get composite resource claim CustomResourceDefinition", "error", err)
+		r.record.Event(d, event.Warning(reasonWithdrawXRC, err))
+		return reconcile.Result{}, err
+	}
+
+	// The controller should be stopped before the deletion of CRD so that
+	// it doesn't crash. This is a no-op if the controller was already
+	// stopped.
+	r.claim.Stop(claim.ControllerName(d.GetName()))
+	r.stopMigrators(d)
+	log.Debug("Stopped composite resource claim controller")
+
+	if meta.WasCreated(crd) && metav1.IsControlledBy(crd, d) {
+		if err := r.client.Delete(ctx, crd); resource.IgnoreNotFound(err) != nil {
+			log.Debug(errDeleteCRD, "error", err)
+			err = errors.Wrap(err, errDeleteCRD)
+			r.record.Event(d, event.Warning(reasonWithdrawXRC, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Deleted composite resource claim CustomResourceDefinition")
+		r.record.Event(d, event.Normal(reasonWithdrawXRC, "Deleted composite resource claim CustomResourceDefinition"))
+
+		// We requeue to confirm the CRD is gone before we remove our
+		// finalizer.
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	d.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReference{}
+	d.Status.SetConditions(v1.WithdrawnClaim())
+	if err := r.client.Status().Update(ctx, d); err != nil {
+		log.Debug(errUpdateStatus, "error", err)
+		err = errors.Wrap(err, errUpdateStatus)
+		r.record.Event(d, event.Warning(reasonWithdrawXRC, err))
+		return reconcile.Result{}, err
+	}
+
+	if err := r.claim.RemoveFinalizer(ctx, d); err != nil {
+		log.Debug(errRemoveFinalizer, "error", err)
+		err = errors.Wrap(err, errRemoveFinalizer)
+		r.record.Event(d, event.Warning(reasonWithdrawXRC, err))
+		return reconcile.Result{}, err
+	}
+	r.record.Event(d, event.Normal(reasonWithdrawXRC, "Withdrew composite resource claim"))
+
+	// We're done withdrawing the claim. There's no need to requeue because
+	// there's nothing left to do.
+	return reconcile.Result{Requeue: false}, nil
+}
+
+// servePreviousClaims serves each kind of composite resource claim that the
+// supplied XRD previously offered, and starts a controller to migrate claims of
+// each kind to the kind it now offers. A previous kind of claim that is no
+// longer listed continues to be served and migrated until no claims of that
+// kind remain. It returns the previous kinds of claim that are served, and
+// whether all of the listed previous kinds are established.
+func (r *Reconciler) servePreviousClaims(ctx context.Context, d *v1.CompositeResourceDefinition) ([]v1.TypeReference, bool, error) {
+	var served []v1.TypeReference
+	listed := map[string]bool{}
+	established := true
+
+	for _, n := range d.Spec.PreviousClaimNames {
+		listed[n.Kind] = true
+
+		crd, err := r.previous.Render(d, n)
+		if err != nil {
+			return nil, false, errors.Wrap(err, errRenderPreviousCRD)
+		}
+		if err := r.client.Apply(ctx, crd, resource.MustBeControllableBy(d.GetUID())); err != nil {
+			return nil, false, errors.Wrap(err, errApplyPreviousCRD)
+		}
+		if !xcrd.IsEstablished(crd.Status) {
+			established = false
+			continue
+		}
+
+		gvk := d.GetClaimGroupVersionKind().GroupVersion().WithKind(n.Kind)
+		if err := r.startMigrator(d, gvk); err != nil {
+			return nil, false, err
+		}
+		served = append(served, v1.TypeReferenceTo(gvk))
+	}
+
+	for _, ref := range d.Status.Controllers.PreviousCompositeResourceClaimTypeRefs {
+		if listed[ref.Kind] {
+			continue
+		}
+
+		gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+		l := &kunstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := r.client.List(ctx, l); resource.Ignore(kmeta.IsNoMatchError, err) != nil {
+			return nil, false, errors.Wrap(err, errListPreviousCRs)
+		}
+		if len(l.Items) > 0 {
+			if err := r.startMigrator(d, gvk); err != nil {
+				return nil, false, err
+			}
+			served = append(served, ref)
+			continue
+		}
+
+		// The controller should be stopped before the deletion of CRD
+		// so that it doesn't crash.
+		r.claim.Stop(claim.MigratorControllerName(d.GetName(), ref.Kind))
+		crd, err := r.getCRD(ctx, d, gvk.GroupKind())
+		if err != nil {
+			return nil, false, err
+		}
+		if meta.WasCreated(crd) {
+			if err := r.client.Delete(ctx, crd); resource.IgnoreNotFound(err) != nil {
+				return nil, false, errors.Wrap(err, errDeletePreviousCRD)
+			}
+		}
+	}
+
+	return served, established, nil
+}
+
+// startMigrator starts a controller that migrates composite resource claims of
+// the supplied previous kind to the kind of claim the supplied XRD offers. This
+// is a no-op if the controller is already running.
+func (r *Reconciler) startMigrator(d *v1.CompositeResourceDefinition, from schema.GroupVersionKind) error {
+	name := claim.MigratorControllerName(d.GetName(), from.Kind)
+
+	m := claim.NewMigrator(r.mgr,
+		resource.CompositeClaimKind(from),
+		resource.CompositeClaimKind(d.GetClaimGroupVersionKind()),
+		claim.WithMigratorLogger(r.log.WithValues("controller", name)),
+		claim.WithMigratorRecorder(r.record.WithAnnotations("controller", name)))
+
+	ko := r.options.ForControllerRuntime()
+	ko.Reconciler = ratelimiter.NewReconciler(name, m, r.options.GlobalRateLimiter)
+
+	cm := &kunstructured.Unstructured{}
+	cm.SetGroupVersionKind(from)
+
+	return errors.Wrap(r.claim.Start(name, ko, controller.For(cm, &handler.EnqueueRequestForObject{})), errStartMigrator)
+}
+
+// stopMigrators stops the controllers that migrate each previous kind of
+// composite resource claim of the supplied XRD. This is a no-op for
+// controllers that aren't running.
+func (r *Reconciler) stopMigrators(d *v1.CompositeResourceDefinition) {
+	for _, n := range d.Spec.PreviousClaimNames {
+		r.claim.Stop(claim.MigratorControllerName(d.GetName(), n.Kind))
+	}
+	for _, ref := range d.Status.Controllers.PreviousCompositeResourceClaimTypeRefs {
+		r.claim.Stop(claim.MigratorControllerName(d.GetName(), ref.Kind))
+	}
 }
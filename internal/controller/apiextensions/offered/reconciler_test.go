@@ -41,6 +41,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
 )
 
 type MockEngine struct {
@@ -68,6 +69,36 @@ func TestReconcile(t *testing.T) {
 	now := metav1.Now()
 	owner := types.UID("definitely-a-uuid")
 	ctrlr := true
+	offer := false
+
+	// withdrawn returns an XRD that no longer offers the claim it was
+	// watching.
+	withdrawn := func(names *extv1.CustomResourceDefinitionNames) *v1.CompositeResourceDefinition {
+		d := &v1.CompositeResourceDefinition{}
+		d.SetUID(owner)
+		d.Spec.ClaimNames = names
+		d.Spec.OfferClaim = &offer
+		d.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReference{APIVersion: "example.org/v1", Kind: "Claim"}
+		return d
+	}
+
+	// renamed returns an XRD whose claim kind was renamed from DB to Database.
+	renamed := func() *v1.CompositeResourceDefinition {
+		d := &v1.CompositeResourceDefinition{}
+		d.Spec.Group = "example.org"
+		d.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Kind: "Database", Plural: "databases"}
+		d.Spec.PreviousClaimNames = []extv1.CustomResourceDefinitionNames{{Kind: "DB", Plural: "dbs"}}
+		d.Spec.Versions = []v1.CompositeResourceDefinitionVersion{{Name: "v1", Referenceable: true}}
+		return d
+	}
+
+	// established returns an established CRD with the supplied name.
+	established := func(name string) *extv1.CustomResourceDefinition {
+		crd := &extv1.CustomResourceDefinition{}
+		crd.SetName(name)
+		crd.Status.Conditions = []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: extv1.ConditionTrue}}
+		return crd
+	}
 
 	type args struct {
 		mgr  manager.Manager
@@ -452,6 +483,170 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"WithdrawClaimListClaimsError": {
+			reason: "We should return any error we encounter while listing claims we're withdrawing.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								*o.(*v1.CompositeResourceDefinition) = *withdrawn(&extv1.CustomResourceDefinitionNames{Kind: "Claim"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(errBoom),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCRs),
+			},
+		},
+		"WithdrawClaimWithoutNamesClaimsExist": {
+			reason: "We should wait for claims to be deleted before withdrawing a claim whose names were removed.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								*o.(*v1.CompositeResourceDefinition) = *withdrawn(nil)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								o.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{{}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(got client.Object) error {
+								want := withdrawn(nil)
+								want.Status.SetConditions(v1.TerminatingClaim().WithMessage(waitCRWithdraw))
+								if diff := cmp.Diff(want, got); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s\n", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: controller.DefaultOptions().PollInterval},
+			},
+		},
+		"WithdrawClaimDeleteCRD": {
+			reason: "We should stop the claim controller, delete the claim CRD, and requeue once no claims exist.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch v := o.(type) {
+								case *v1.CompositeResourceDefinition:
+									*v = *withdrawn(&extv1.CustomResourceDefinitionNames{Kind: "Claim"})
+								case *extv1.CustomResourceDefinition:
+									crd := extv1.CustomResourceDefinition{}
+									crd.SetCreationTimestamp(now)
+									crd.SetOwnerReferences([]metav1.OwnerReference{{UID: owner, Controller: &ctrlr}})
+									*v = crd
+								}
+								return nil
+							}),
+							MockList:   test.NewMockListFn(nil),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithControllerEngine(&MockEngine{
+						MockStop: func(_ string) {},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WithdrawClaimDeleteCRDError": {
+			reason: "We should return any error we encounter while deleting the claim CRD.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch v := o.(type) {
+								case *v1.CompositeResourceDefinition:
+									*v = *withdrawn(&extv1.CustomResourceDefinitionNames{Kind: "Claim"})
+								case *extv1.CustomResourceDefinition:
+									crd := extv1.CustomResourceDefinition{}
+									crd.SetCreationTimestamp(now)
+									crd.SetOwnerReferences([]metav1.OwnerReference{{UID: owner, Controller: &ctrlr}})
+									*v = crd
+								}
+								return nil
+							}),
+							MockList:   test.NewMockListFn(nil),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithControllerEngine(&MockEngine{
+						MockStop: func(_ string) {},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteCRD),
+			},
+		},
+		"WithdrawClaimSuccess": {
+			reason: "We should clear our status and remove our finalizer once the claim CRD is gone.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch v := o.(type) {
+								case *v1.CompositeResourceDefinition:
+									*v = *withdrawn(&extv1.CustomResourceDefinitionNames{Kind: "Claim"})
+								case *extv1.CustomResourceDefinition:
+									return kerrors.NewNotFound(schema.GroupResource{}, "")
+								}
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(got client.Object) error {
+								want := withdrawn(&extv1.CustomResourceDefinitionNames{Kind: "Claim"})
+								want.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReference{}
+								want.Status.SetConditions(v1.WithdrawnClaim())
+								if diff := cmp.Diff(want, got); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s\n", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithControllerEngine(&MockEngine{
+						MockStop: func(_ string) {},
+					}),
+					WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"AddFinalizerError": {
 			reason: "We should return any error we encounter while adding a finalizer.",
 			args: args{
@@ -663,6 +858,199 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ApplyPreviousCRDError": {
+			reason: "We should return any error we encounter while applying the CRD of a previous kind of claim.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								obj.(*v1.CompositeResourceDefinition).Spec = renamed().Spec
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, obj client.Object, _ ...resource.ApplyOption) error {
+							if obj.GetName() == "dbs.example.org" {
+								return errBoom
+							}
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return established("databases.example.org"), nil
+					})),
+					WithPreviousCRDRenderer(PreviousCRDRenderFn(func(_ *v1.CompositeResourceDefinition, _ extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error) {
+						return established("dbs.example.org"), nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockErr:   func(_ string) error { return nil },
+						MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return nil },
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyPreviousCRD),
+			},
+		},
+		"PreviousCRDIsNotEstablished": {
+			reason: "We should requeue if we're waiting for the CRD of a previous kind of claim to become established.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								obj.(*v1.CompositeResourceDefinition).Spec = renamed().Spec
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return established("databases.example.org"), nil
+					})),
+					WithPreviousCRDRenderer(PreviousCRDRenderFn(func(_ *v1.CompositeResourceDefinition, _ extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockErr: func(_ string) error { return nil },
+						MockStart: func(name string, _ kcontroller.Options, _ ...controller.Watch) error {
+							if name != claim.ControllerName("") {
+								t.Errorf("Start(...): unexpected controller %q", name)
+							}
+							return nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"SuccessfulStartWithPreviousClaims": {
+			reason: "We should serve and migrate each previous kind of claim, and record them in our status.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								obj.(*v1.CompositeResourceDefinition).Spec = renamed().Spec
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o client.Object) error {
+								want := renamed()
+								want.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReference{APIVersion: "example.org/v1", Kind: "Database"}
+								want.Status.Controllers.PreviousCompositeResourceClaimTypeRefs = []v1.TypeReference{{APIVersion: "example.org/v1", Kind: "DB"}}
+								want.Status.SetConditions(v1.WatchingClaim())
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return established("databases.example.org"), nil
+					})),
+					WithPreviousCRDRenderer(PreviousCRDRenderFn(func(_ *v1.CompositeResourceDefinition, _ extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error) {
+						return established("dbs.example.org"), nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockErr: func(_ string) error { return nil },
+						MockStart: func(name string, _ kcontroller.Options, _ ...controller.Watch) error {
+							if name != claim.ControllerName("") && name != claim.MigratorControllerName("", "DB") {
+								t.Errorf("Start(...): unexpected controller %q", name)
+							}
+							return nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"StopServingPreviousClaims": {
+			reason: "We should stop migrating and delete the CRD of a previous kind of claim that is no longer listed once no claims of that kind remain.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								d := obj.(*v1.CompositeResourceDefinition)
+								d.SetUID(owner)
+								d.Spec = renamed().Spec
+								d.Spec.PreviousClaimNames = nil
+								d.Status.Controllers.PreviousCompositeResourceClaimTypeRefs = []v1.TypeReference{{APIVersion: "example.org/v1", Kind: "DB"}}
+								return nil
+							}),
+							MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+								if l, ok := obj.(*extv1.CustomResourceDefinitionList); ok {
+									crd := established("dbs.example.org")
+									crd.Spec.Group = "example.org"
+									crd.Spec.Names.Kind = "DB"
+									crd.SetCreationTimestamp(now)
+									crd.SetOwnerReferences([]metav1.OwnerReference{{UID: owner, Controller: &ctrlr}})
+									l.Items = []extv1.CustomResourceDefinition{*crd}
+								}
+								return nil
+							},
+							MockDelete: test.NewMockDeleteFn(nil, func(obj client.Object) error {
+								if obj.GetName() != "dbs.example.org" {
+									t.Errorf("Delete(...): unexpected CRD %q", obj.GetName())
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o client.Object) error {
+								if refs := o.(*v1.CompositeResourceDefinition).Status.Controllers.PreviousCompositeResourceClaimTypeRefs; len(refs) != 0 {
+									t.Errorf("StatusUpdate(...): unexpected previous claim types %v", refs)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return established("databases.example.org"), nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockErr:   func(_ string) error { return nil },
+						MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return nil },
+						MockStop: func(name string) {
+							if name != claim.MigratorControllerName("", "DB") {
+								t.Errorf("Stop(...): unexpected controller %q", name)
+							}
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -41,6 +41,19 @@ func OffersClaim() resource.PredicateFn {
 	}
 }
 
+// OfferedClaim accepts objects that are a CompositeResourceDefinition and have
+// offered a composite resource claim, which may since have been withdrawn. Our
+// finalizer is removed once the claim is fully withdrawn.
+func OfferedClaim() resource.PredicateFn {
+	return func(obj runtime.Object) bool {
+		d, ok := obj.(*v1.CompositeResourceDefinition)
+		if !ok {
+			return false
+		}
+		return meta.FinalizerExists(d, finalizer)
+	}
+}
+
 type adder interface {
 	Add(item any)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -56,6 +57,15 @@ func TestOffersClaim(t *testing.T) {
 			},
 			want: true,
 		},
+		"StoppedOfferingClaim": {
+			obj: &v1.CompositeResourceDefinition{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					ClaimNames: &extv1.CustomResourceDefinitionNames{},
+					OfferClaim: func() *bool { f := false; return &f }(),
+				},
+			},
+			want: false,
+		},
 	}
 
 	for name, tc := range cases {
@@ -68,6 +78,40 @@ func TestOffersClaim(t *testing.T) {
 	}
 }
 
+func TestOfferedClaim(t *testing.T) {
+	cases := map[string]struct {
+		obj  runtime.Object
+		want bool
+	}{
+		"NotAnXRD": {
+			want: false,
+		},
+		"NeverOfferedClaim": {
+			obj:  &v1.CompositeResourceDefinition{},
+			want: false,
+		},
+		"OfferedClaim": {
+			obj: &v1.CompositeResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					// An XRD with our finalizer has offered a claim that
+					// it has not yet finished withdrawing.
+					Finalizers: []string{finalizer},
+				},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := OfferedClaim()(tc.obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("OfferedClaim(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 type addFn func(item any)
 
 func (fn addFn) Add(item any) {
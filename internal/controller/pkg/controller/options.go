@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/crossplane/crossplane/internal/xpkg"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -46,6 +48,22 @@ type Options struct {
 	// injected to CRDs so that API server can make calls to the providers.
 	WebhookTLSSecretName string
 
+	// DeploymentDefaults are applied to every provider Deployment.
+	DeploymentDefaults DeploymentDefaults
+
 	// Features that should be enabled.
 	Features *feature.Flags
 }
+
+// DeploymentDefaults configure where the package manager runs provider Pods.
+// A provider's ControllerConfig takes precedence over these defaults.
+type DeploymentDefaults struct {
+	// NodeSelector of provider Pods.
+	NodeSelector map[string]string
+
+	// Tolerations of provider Pods.
+	Tolerations []corev1.Toleration
+
+	// RuntimeClassName of provider Pods.
+	RuntimeClassName *string
+}
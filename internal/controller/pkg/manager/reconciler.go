@@ -38,6 +38,9 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/reason"
+	"github.com/crossplane/crossplane/internal/throttle"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -165,9 +168,9 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(revisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry)), o)),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
 	}
 	if o.WebhookTLSSecretName != "" {
 		opts = append(opts, WithWebhookTLSSecretName(o.WebhookTLSSecretName))
@@ -200,9 +203,9 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(revisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry)), o)),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -213,6 +216,16 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// revisioner returns the supplied Revisioner, wrapped so that packages
+// restored from a snapshot keep their snapshot revisions if restore mode is
+// enabled.
+func revisioner(r Revisioner, o controller.Options) Revisioner {
+	if o.Features.Enabled(features.RestoreMode) {
+		return NewSnapshotRevisioner(r)
+	}
+	return r
+}
+
 // NewReconciler creates a new package reconciler.
 func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
@@ -263,11 +276,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	revisionName, err := r.pkg.Revision(ctx, p)
+	revisionName, digest, err := r.pkg.Revision(ctx, p)
 	if err != nil {
 		log.Debug(errUnpack, "error", err)
-		err = errors.Wrap(err, errUnpack)
-		r.record.Event(p, event.Warning(reasonUnpack, err))
+		err = errors.Wrap(reason.Wrap(err, reason.ImagePullFailed), errUnpack)
+		r.record.Event(p, reason.Warning(reasonUnpack, err))
 		return reconcile.Result{}, err
 	}
 
@@ -277,9 +290,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
 
-	// Set the current revision and identifier.
+	// Set the current revision, identifier, and digest.
 	p.SetCurrentRevision(revisionName)
 	p.SetCurrentIdentifier(p.GetSource())
+	p.SetCurrentDigest(digest)
 
 	pr := r.newPackageRevision()
 	maxRevision := int64(0)
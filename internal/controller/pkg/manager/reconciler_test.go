@@ -50,8 +50,9 @@ func NewMockRevisionFn(hash string, err error) func() (string, error) {
 		return hash, err
 	}
 }
-func (m *MockRevisioner) Revision(context.Context, v1.Package) (string, error) {
-	return m.MockRevision()
+func (m *MockRevisioner) Revision(context.Context, v1.Package) (string, string, error) {
+	r, err := m.MockRevision()
+	return r, "", err
 }
 
 func TestReconcile(t *testing.T) {
@@ -34,7 +34,9 @@ const (
 
 // Revisioner extracts a revision name for a package source.
 type Revisioner interface {
-	Revision(context.Context, v1.Package) (string, error)
+	// Revision returns the name of the revision of the supplied package,
+	// and the digest of the package image it was produced from, if known.
+	Revision(context.Context, v1.Package) (name, digest string, err error)
 }
 
 // PackageRevisioner extracts a revision name for a package source.
@@ -64,26 +66,52 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 	return r
 }
 
-// Revision extracts a revision name for a package source.
-func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string, error) {
+// Revision extracts a revision name and image digest for a package source.
+// No digest is returned for packages that are never pulled.
+func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string, string, error) {
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
-		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
+		return xpkg.FriendlyID(p.GetName(), p.GetSource()), "", nil
 	}
 	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
 		if p.GetCurrentIdentifier() == p.GetSource() {
-			return p.GetCurrentRevision(), nil
+			return p.GetCurrentRevision(), p.GetCurrentDigest(), nil
 		}
 	}
 	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		return "", errors.Wrap(err, errBadReference)
+		return "", "", errors.Wrap(err, errBadReference)
 	}
 	d, err := r.fetcher.Head(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
 	if err != nil || d == nil {
-		return "", errors.Wrap(err, errFetchPackage)
+		return "", "", errors.Wrap(err, errFetchPackage)
 	}
-	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), d.Digest.String(), nil
+}
+
+// SnapshotRevisioner returns the revision and digest a package had when a
+// snapshot of the package manager's state was taken, if the package was
+// restored from that snapshot and its source has not changed since. This
+// ensures a restored package uses its restored revisions, even if its tag now
+// refers to a different image.
+type SnapshotRevisioner struct {
+	wrapped Revisioner
+}
+
+// NewSnapshotRevisioner returns a SnapshotRevisioner that falls back to the
+// supplied Revisioner for packages that were not restored from a snapshot.
+func NewSnapshotRevisioner(r Revisioner) *SnapshotRevisioner {
+	return &SnapshotRevisioner{wrapped: r}
+}
+
+// Revision returns the revision name and image digest recorded in a package's
+// snapshot annotations, or those returned by the wrapped Revisioner.
+func (r *SnapshotRevisioner) Revision(ctx context.Context, p v1.Package) (string, string, error) {
+	a := p.GetAnnotations()
+	if a[v1.AnnotationSnapshotRevision] != "" && a[v1.AnnotationSnapshotSource] == p.GetSource() {
+		return a[v1.AnnotationSnapshotRevision], a[v1.AnnotationSnapshotDigest], nil
+	}
+	return r.wrapped.Revision(ctx, p)
 }
 
 // NopRevisioner returns an empty revision name.
@@ -94,7 +122,7 @@ func NewNopRevisioner() *NopRevisioner {
 	return &NopRevisioner{}
 }
 
-// Revision returns an empty revision name and no error.
-func (d *NopRevisioner) Revision(context.Context, v1.Package) (string, error) {
-	return "", nil
+// Revision returns an empty revision name and digest, and no error.
+func (d *NopRevisioner) Revision(context.Context, v1.Package) (string, string, error) {
+	return "", "", nil
 }
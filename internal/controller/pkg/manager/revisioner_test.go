@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -42,8 +43,9 @@ func TestPackageRevisioner(t *testing.T) {
 	}
 
 	type want struct {
-		err    error
-		digest string
+		err         error
+		digest      string
+		imageDigest string
 	}
 
 	cases := map[string]struct {
@@ -87,12 +89,36 @@ func TestPackageRevisioner(t *testing.T) {
 						PackageStatus: v1.PackageStatus{
 							CurrentRevision:   "return-me",
 							CurrentIdentifier: "crossplane/provider-aws:latest",
+							CurrentDigest:     "sha256:return-me-too",
 						},
 					},
 				},
 			},
 			want: want{
-				digest: "return-me",
+				digest:      "return-me",
+				imageDigest: "sha256:return-me-too",
+			},
+		},
+		"SuccessfulPull": {
+			reason: "Should return a revision name and image digest derived from the package image's digest.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(&ociv1.Descriptor{Digest: ociv1.Hash{Algorithm: "sha256", Hex: "0123456789abcdef"}}, nil),
+				},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-aws",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:latest",
+						},
+					},
+				},
+			},
+			want: want{
+				digest:      "provider-aws-0123456789ab",
+				imageDigest: "sha256:0123456789abcdef",
 			},
 		},
 		"ErrParseRef": {
@@ -133,7 +159,7 @@ func TestPackageRevisioner(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			r := NewPackageRevisioner(tc.args.f)
-			h, err := r.Revision(context.TODO(), tc.args.pkg)
+			h, d, err := r.Revision(context.TODO(), tc.args.pkg)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -141,6 +167,111 @@ func TestPackageRevisioner(t *testing.T) {
 			if diff := cmp.Diff(tc.want.digest, h, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.imageDigest, d); diff != "" {
+				t.Errorf("\n%s\nr.Name(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSnapshotRevisioner(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		r   Revisioner
+		pkg v1.Package
+	}
+
+	type want struct {
+		err      error
+		revision string
+		digest   string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotRestored": {
+			reason: "Should use the wrapped revisioner if the package was not restored from a snapshot.",
+			args: args{
+				r: &MockRevisioner{MockRevision: NewMockRevisionFn("", errBoom)},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:latest",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"SourceChanged": {
+			reason: "Should use the wrapped revisioner if the package's source changed since the snapshot was taken.",
+			args: args{
+				r: &MockRevisioner{MockRevision: NewMockRevisionFn("provider-aws-5678", nil)},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							v1.AnnotationSnapshotSource:   "crossplane/provider-aws:v0.1.0",
+							v1.AnnotationSnapshotRevision: "provider-aws-1234",
+							v1.AnnotationSnapshotDigest:   "sha256:1234",
+						},
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:v0.2.0",
+						},
+					},
+				},
+			},
+			want: want{
+				revision: "provider-aws-5678",
+			},
+		},
+		"Restored": {
+			reason: "Should return the snapshot revision and digest if the package was restored from a snapshot.",
+			args: args{
+				r: &MockRevisioner{MockRevision: NewMockRevisionFn("", errBoom)},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							v1.AnnotationSnapshotSource:   "crossplane/provider-aws:latest",
+							v1.AnnotationSnapshotRevision: "provider-aws-1234",
+							v1.AnnotationSnapshotDigest:   "sha256:1234",
+						},
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane/provider-aws:latest",
+						},
+					},
+				},
+			},
+			want: want{
+				revision: "provider-aws-1234",
+				digest:   "sha256:1234",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewSnapshotRevisioner(tc.args.r)
+			rev, d, err := r.Revision(context.TODO(), tc.args.pkg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Revision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.revision, rev); diff != "" {
+				t.Errorf("\n%s\nr.Revision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, d); diff != "" {
+				t.Errorf("\n%s\nr.Revision(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
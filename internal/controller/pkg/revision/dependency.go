@@ -21,6 +21,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -43,8 +44,24 @@ const (
 	errMissingDependenciesFmt    = "missing dependencies: %+v"
 	errDependencyNotInGraph      = "dependency is not present in graph"
 	errDependencyNotLockPackage  = "dependency in graph is not a lock package"
+	errFmtGetDependencyRevision  = "cannot get revision %s of dependency"
+	errFmtWaitingForDependencies = "waiting for dependencies to become healthy: %+v"
 )
 
+// A waitingError indicates that a package revision is waiting for its
+// dependencies to become healthy.
+type waitingError struct {
+	error
+}
+
+// IsWaitingForDependencies returns true if the supplied error indicates that a
+// package revision is waiting for its dependencies to become healthy before it
+// may become active.
+func IsWaitingForDependencies(err error) bool {
+	w := &waitingError{}
+	return errors.As(err, &w)
+}
+
 // DependencyManager is a lock on packages.
 type DependencyManager interface {
 	Resolve(ctx context.Context, pkg runtime.Object, pr v1.PackageRevision) (found, installed, invalid int, err error)
@@ -86,6 +103,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 			pdep.Type = v1beta1.ProviderPackageType
 		}
 		pdep.Constraints = dep.Version
+		pdep.WaitForHealthy = dep.WaitForHealthy
 		sources[i] = pdep
 	}
 
@@ -185,6 +203,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 	// All of our dependencies and transitive dependencies must exist. Check
 	// that neighbors have valid versions.
 	var invalidDeps []string
+	var wait []*v1beta1.LockPackage
 	for _, dep := range self.Dependencies {
 		n, err := d.GetNode(dep.Package)
 		if err != nil {
@@ -205,14 +224,50 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		if !c.Check(v) {
 			invalidDeps = append(invalidDeps, lp.Identifier())
 		}
+		if dep.WaitForHealthy {
+			wait = append(wait, lp)
+		}
 	}
 	invalid = len(invalidDeps)
 	if invalid > 0 {
 		return found, installed, invalid, errors.Errorf(errIncompatibleDependencyFmt, invalidDeps)
 	}
+
+	// Our dependencies are valid, but we may not become active until some of
+	// them are healthy.
+	var unhealthy []string
+	for _, lp := range wait {
+		ok, err := m.healthy(ctx, lp)
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		if !ok {
+			unhealthy = append(unhealthy, lp.Identifier())
+		}
+	}
+	if len(unhealthy) > 0 {
+		return found, installed, invalid, &waitingError{errors.Errorf(errFmtWaitingForDependencies, unhealthy)}
+	}
 	return found, installed, invalid, nil
 }
 
+// healthy returns true if the package revision of the supplied lock package is
+// healthy.
+func (m *PackageDependencyManager) healthy(ctx context.Context, lp *v1beta1.LockPackage) (bool, error) {
+	var rev v1.PackageRevision = &v1.ProviderRevision{}
+	if lp.Type == v1beta1.ConfigurationPackageType {
+		rev = &v1.ConfigurationRevision{}
+	}
+	err := m.client.Get(ctx, types.NamespacedName{Name: lp.Name}, rev)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtGetDependencyRevision, lp.Name)
+	}
+	return rev.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue, nil
+}
+
 // RemoveSelf removes a package from the lock.
 func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.PackageRevision) error {
 	prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
@@ -654,6 +654,86 @@ func TestResolve(t *testing.T) {
 				invalid:   0,
 			},
 		},
+		"ErrorSelfExistWaitingForDependencies": {
+			reason: "Should return a waiting error if self exists and all dependencies are valid, but one we must wait for is not healthy.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Source: "hasheddan/config-nop-a",
+										Dependencies: []v1beta1.Dependency{
+											{
+												Package:        "not-here-1",
+												Type:           v1beta1.ProviderPackageType,
+												WaitForHealthy: true,
+											},
+										},
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetConditions(v1.Unhealthy())
+							}
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(nodes []dag.Node, fns ...dag.NodeFn) ([]dag.Node, error) {
+								for i, n := range nodes {
+									for _, f := range fns {
+										f(i, n)
+									}
+								}
+								return nil, nil
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"not-here-1": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(s string) (dag.Node, error) {
+								return &v1beta1.LockPackage{
+									Name:    "not-here-1-abc",
+									Type:    v1beta1.ProviderPackageType,
+									Source:  "not-here-1",
+									Version: "v0.20.0",
+								}, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider:       pointer.StringPtr("not-here-1"),
+									Version:        ">=v0.1.0",
+									WaitForHealthy: true,
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package:      "hasheddan/config-nop-a:v0.0.1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 1,
+				invalid:   0,
+				err:       &waitingError{errors.Errorf(errFmtWaitingForDependencies, []string{"not-here-1"})},
+			},
+		},
 	}
 
 	for name, tc := range cases {
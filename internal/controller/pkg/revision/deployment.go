@@ -17,16 +17,23 @@ limitations under the License.
 package revision
 
 import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 )
 
 var (
@@ -49,8 +56,105 @@ const (
 	webhookTLSCertDir       = "/webhook/tls"
 	webhookPortName         = "webhook"
 	webhookPort             = 9443
+
+	leaderElectionArg = "--leader-election"
 )
 
+// AnnotationKeyControllerConfigHash is a hash of the ControllerConfig that a
+// provider's pods were rendered from. Provider pods are restarted when it
+// changes.
+const AnnotationKeyControllerConfigHash = "pkg.crossplane.io/controller-config-hash"
+
+// controllerConfigHash returns a hash of the supplied ControllerConfig's
+// labels, annotations, and spec.
+func controllerConfigHash(cc *v1alpha1.ControllerConfig) string {
+	h := fnv.New64a()
+	y, err := yaml.Marshal(struct {
+		Labels      map[string]string             `json:"labels,omitempty"`
+		Annotations map[string]string             `json:"annotations,omitempty"`
+		Spec        v1alpha1.ControllerConfigSpec `json:"spec"`
+	}{cc.GetLabels(), cc.GetAnnotations(), cc.Spec})
+	if err != nil {
+		// This should be impossible given we're marshalling a known,
+		// strongly typed struct.
+		return "unknown"
+	}
+	h.Write(y) //nolint:errcheck // Writing to a hash never errors.
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// declaredImage returns the controller image declared by the supplied
+// provider, or the package image if the provider declares no controller image.
+func declaredImage(provider *pkgmetav1.Provider, revision v1.PackageRevision) string {
+	if provider.Spec.Controller.Image != nil {
+		return *provider.Spec.Controller.Image
+	}
+	return revision.GetSource()
+}
+
+// pinImage returns the supplied image, referenced by the supplied digest rather
+// than by any tag or digest it already specifies.
+func pinImage(image, digest string) string {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		// Let the container runtime report that the image is invalid.
+		return image + "@" + digest
+	}
+	return ref.Context().Name() + "@" + digest
+}
+
+// workloadType returns the type of workload the supplied ControllerConfig
+// requests. Providers are run as a Deployment unless otherwise requested.
+func workloadType(cc *v1alpha1.ControllerConfig) v1alpha1.WorkloadType {
+	if cc == nil || cc.Spec.WorkloadType == nil {
+		return v1alpha1.WorkloadTypeDeployment
+	}
+	return *cc.Spec.WorkloadType
+}
+
+// withLeaderElection passes the leader election argument to the supplied
+// Deployment's controller if it runs more than one replica, unless it already
+// configures leader election.
+func withLeaderElection(d *appsv1.Deployment) {
+	if d.Spec.Replicas == nil || *d.Spec.Replicas < 2 {
+		return
+	}
+	c := &d.Spec.Template.Spec.Containers[0]
+	for _, a := range c.Args {
+		if strings.HasPrefix(a, leaderElectionArg) {
+			return
+		}
+	}
+	c.Args = append(c.Args, leaderElectionArg)
+}
+
+// buildProviderDaemonSet returns a DaemonSet that runs the same pods as the
+// supplied Deployment, one on each eligible node.
+func buildProviderDaemonSet(d *appsv1.Deployment) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: *d.ObjectMeta.DeepCopy(),
+		Spec: appsv1.DaemonSetSpec{
+			Selector: d.Spec.Selector.DeepCopy(),
+			Template: *d.Spec.Template.DeepCopy(),
+		},
+	}
+}
+
+// withDeploymentDefaults applies the supplied defaults to the supplied
+// Deployment, unless they were already set by a ControllerConfig.
+func withDeploymentDefaults(d *appsv1.Deployment, defaults controller.DeploymentDefaults) {
+	ps := &d.Spec.Template.Spec
+	if ps.NodeSelector == nil {
+		ps.NodeSelector = defaults.NodeSelector
+	}
+	if len(ps.Tolerations) == 0 {
+		ps.Tolerations = defaults.Tolerations
+	}
+	if ps.RuntimeClassName == nil {
+		ps.RuntimeClassName = defaults.RuntimeClassName
+	}
+}
+
 func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRevision, cc *v1alpha1.ControllerConfig, namespace string) (*corev1.ServiceAccount, *appsv1.Deployment, *corev1.Service) { // nolint:gocyclo
 	s := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -63,10 +167,7 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 	if revision.GetPackagePullPolicy() != nil {
 		pullPolicy = *revision.GetPackagePullPolicy()
 	}
-	image := revision.GetSource()
-	if provider.Spec.Controller.Image != nil {
-		image = *provider.Spec.Controller.Image
-	}
+	image := declaredImage(provider, revision)
 	d := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            revision.GetName(),
@@ -175,8 +276,15 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 		s.Annotations = cc.Annotations
 		d.Labels = cc.Labels
 		d.Annotations = cc.Annotations
+		// We restart provider pods whenever their ControllerConfig changes,
+		// including changes that don't otherwise affect the pod template.
+		// For example the ServiceAccount's annotations are typically only
+		// read when a pod is admitted, e.g. to inject cloud credentials.
+		d.Spec.Template.Annotations = map[string]string{AnnotationKeyControllerConfigHash: controllerConfigHash(cc)}
 		if cc.Spec.Metadata != nil {
-			d.Spec.Template.Annotations = cc.Spec.Metadata.Annotations
+			for k, v := range cc.Spec.Metadata.Annotations {
+				d.Spec.Template.Annotations[k] = v
+			}
 		}
 
 		if cc.Spec.Metadata != nil {
@@ -191,6 +299,9 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 		if cc.Spec.Image != nil {
 			d.Spec.Template.Spec.Containers[0].Image = *cc.Spec.Image
 		}
+		if cc.Spec.ImageDigest != nil {
+			d.Spec.Template.Spec.Containers[0].Image = pinImage(d.Spec.Template.Spec.Containers[0].Image, *cc.Spec.ImageDigest)
+		}
 		if cc.Spec.ImagePullPolicy != nil {
 			d.Spec.Template.Spec.Containers[0].ImagePullPolicy = *cc.Spec.ImagePullPolicy
 		}
@@ -243,6 +354,9 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 			d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, cc.Spec.Env...)
 		}
 	}
+	if workloadType(cc) == v1alpha1.WorkloadTypeDeployment {
+		withLeaderElection(d)
+	}
 	for k, v := range d.Spec.Selector.MatchLabels { // ensure the template matches the selector
 		templateLabels[k] = v
 	}
@@ -29,6 +29,7 @@ import (
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 )
 
 type deploymentModifier func(*appsv1.Deployment)
@@ -39,6 +40,12 @@ func withPodTemplateLabels(labels map[string]string) deploymentModifier {
 	}
 }
 
+func withPodTemplateAnnotations(annotations map[string]string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Annotations = annotations
+	}
+}
+
 func withAdditionalVolume(v corev1.Volume) deploymentModifier {
 	return func(d *appsv1.Deployment) {
 		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, v)
@@ -57,6 +64,18 @@ func withAdditionalEnvVar(env corev1.EnvVar) deploymentModifier {
 	}
 }
 
+func withReplicas(r int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Replicas = &r
+	}
+}
+
+func withArgs(args ...string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Containers[0].Args = args
+	}
+}
+
 func withAdditionalPort(port corev1.ContainerPort) deploymentModifier {
 	return func(d *appsv1.Deployment) {
 		d.Spec.Template.Spec.Containers[0].Ports = append(d.Spec.Template.Spec.Containers[0].Ports, port)
@@ -249,6 +268,46 @@ func TestBuildProviderDeployment(t *testing.T) {
 		},
 	}
 
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	ccDigest := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Image:       &ccImg,
+			ImageDigest: &digest,
+		},
+	}
+
+	three := int32(3)
+	daemonSet := v1alpha1.WorkloadTypeDaemonSet
+	ccReplicas := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Replicas: &three,
+		},
+	}
+	ccReplicasLeaderElection := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Replicas: &three,
+			Args:     []string{"--debug", "--leader-election=true"},
+		},
+	}
+	ccDaemonSet := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Replicas:     &three,
+			WorkloadType: &daemonSet,
+		},
+	}
+
 	cases := map[string]struct {
 		reason string
 		fields args
@@ -322,11 +381,89 @@ func TestBuildProviderDeployment(t *testing.T) {
 			},
 			want: want{
 				sa: serviceaccount(revisionWithCC),
-				d: deployment(providerWithImage, revisionWithCC.GetName(), ccImg, withPodTemplateLabels(map[string]string{
-					"pkg.crossplane.io/revision": revisionWithCC.GetName(),
-					"pkg.crossplane.io/provider": providerWithImage.GetName(),
-					"k":                          "v",
-				})),
+				d: deployment(providerWithImage, revisionWithCC.GetName(), ccImg,
+					withPodTemplateLabels(map[string]string{
+						"pkg.crossplane.io/revision": revisionWithCC.GetName(),
+						"pkg.crossplane.io/provider": providerWithImage.GetName(),
+						"k":                          "v",
+					}),
+					withPodTemplateAnnotations(map[string]string{
+						AnnotationKeyControllerConfigHash: controllerConfigHash(cc),
+					}),
+				),
+				svc: service(providerWithImage, revisionWithCC),
+			},
+		},
+		"ImgCCDigest": {
+			reason: "If a ControllerConfig is referenced and it specifies an image digest the controller image should be pinned to it.",
+			fields: args{
+				provider: providerWithImage,
+				revision: revisionWithCC,
+				cc:       ccDigest,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCC),
+				d: deployment(providerWithImage, revisionWithCC.GetName(), "index.docker.io/library/cc-img@"+digest,
+					withPodTemplateAnnotations(map[string]string{
+						AnnotationKeyControllerConfigHash: controllerConfigHash(ccDigest),
+					}),
+				),
+				svc: service(providerWithImage, revisionWithCC),
+			},
+		},
+		"ReplicasCC": {
+			reason: "If a ControllerConfig requests more than one replica leader election should be enabled.",
+			fields: args{
+				provider: providerWithImage,
+				revision: revisionWithCC,
+				cc:       ccReplicas,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCC),
+				d: deployment(providerWithImage, revisionWithCC.GetName(), img,
+					withReplicas(3),
+					withArgs("--leader-election"),
+					withPodTemplateAnnotations(map[string]string{
+						AnnotationKeyControllerConfigHash: controllerConfigHash(ccReplicas),
+					}),
+				),
+				svc: service(providerWithImage, revisionWithCC),
+			},
+		},
+		"ReplicasLeaderElectionCC": {
+			reason: "If a ControllerConfig requests more than one replica and configures leader election itself its arguments should be used as is.",
+			fields: args{
+				provider: providerWithImage,
+				revision: revisionWithCC,
+				cc:       ccReplicasLeaderElection,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCC),
+				d: deployment(providerWithImage, revisionWithCC.GetName(), img,
+					withReplicas(3),
+					withArgs("--debug", "--leader-election=true"),
+					withPodTemplateAnnotations(map[string]string{
+						AnnotationKeyControllerConfigHash: controllerConfigHash(ccReplicasLeaderElection),
+					}),
+				),
+				svc: service(providerWithImage, revisionWithCC),
+			},
+		},
+		"DaemonSetCC": {
+			reason: "If a ControllerConfig requests a DaemonSet leader election should not be enabled, regardless of replicas.",
+			fields: args{
+				provider: providerWithImage,
+				revision: revisionWithCC,
+				cc:       ccDaemonSet,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCC),
+				d: deployment(providerWithImage, revisionWithCC.GetName(), img,
+					withReplicas(3),
+					withPodTemplateAnnotations(map[string]string{
+						AnnotationKeyControllerConfigHash: controllerConfigHash(ccDaemonSet),
+					}),
+				),
 				svc: service(providerWithImage, revisionWithCC),
 			},
 		},
@@ -349,3 +486,118 @@ func TestBuildProviderDeployment(t *testing.T) {
 	}
 
 }
+
+func TestControllerConfigHash(t *testing.T) {
+	cc := func(annotations map[string]string) *v1alpha1.ControllerConfig {
+		return &v1alpha1.ControllerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "cc", Annotations: annotations},
+			Spec:       v1alpha1.ControllerConfigSpec{Args: []string{"--debug"}},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		a      *v1alpha1.ControllerConfig
+		b      *v1alpha1.ControllerConfig
+		want   bool
+	}{
+		"Unchanged": {
+			reason: "The hash of identical ControllerConfigs should be equal.",
+			a:      cc(map[string]string{"eks.amazonaws.com/role-arn": "a"}),
+			b:      cc(map[string]string{"eks.amazonaws.com/role-arn": "a"}),
+			want:   true,
+		},
+		"AnnotationsChanged": {
+			reason: "The hash should change when the ServiceAccount annotations change, so that provider pods are restarted.",
+			a:      cc(map[string]string{"eks.amazonaws.com/role-arn": "a"}),
+			b:      cc(map[string]string{"eks.amazonaws.com/role-arn": "b"}),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := controllerConfigHash(tc.a) == controllerConfigHash(tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncontrollerConfigHash(a) == controllerConfigHash(b): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithDeploymentDefaults(t *testing.T) {
+	rc := "gvisor"
+	defaults := controller.DeploymentDefaults{
+		NodeSelector:     map[string]string{"dedicated": "control-plane"},
+		Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		RuntimeClassName: &rc,
+	}
+
+	cases := map[string]struct {
+		reason   string
+		d        *appsv1.Deployment
+		defaults controller.DeploymentDefaults
+		want     *appsv1.Deployment
+	}{
+		"NoDefaults": {
+			reason:   "A Deployment should be unchanged if there are no defaults.",
+			d:        &appsv1.Deployment{},
+			defaults: controller.DeploymentDefaults{},
+			want:     &appsv1.Deployment{},
+		},
+		"Defaults": {
+			reason:   "Defaults should be applied to a Deployment that does not override them.",
+			d:        &appsv1.Deployment{},
+			defaults: defaults,
+			want: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector:     defaults.NodeSelector,
+				Tolerations:      defaults.Tolerations,
+				RuntimeClassName: &rc,
+			}}}},
+		},
+		"ControllerConfigOverrides": {
+			reason: "Settings from a ControllerConfig should take precedence over defaults.",
+			d: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"k": "v"},
+			}}}},
+			defaults: defaults,
+			want: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector:     map[string]string{"k": "v"},
+				Tolerations:      defaults.Tolerations,
+				RuntimeClassName: &rc,
+			}}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			withDeploymentDefaults(tc.d, tc.defaults)
+			if diff := cmp.Diff(tc.want, tc.d); diff != "" {
+				t.Errorf("\n%s\nwithDeploymentDefaults(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildProviderDaemonSet(t *testing.T) {
+	provider := &pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "pkg"}}
+	d := deployment(provider, "rev-123", "img:tag", withReplicas(3))
+
+	want := &appsv1.DaemonSet{
+		ObjectMeta: d.ObjectMeta,
+		Spec: appsv1.DaemonSetSpec{
+			Selector: d.Spec.Selector,
+			Template: d.Spec.Template,
+		},
+	}
+	got := buildProviderDaemonSet(d)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("buildProviderDaemonSet(...): -want, +got:\n%s", diff)
+	}
+
+	// The DaemonSet must not share state with the Deployment it was built from.
+	got.Spec.Template.Labels["k"] = "v"
+	if _, ok := d.Spec.Template.Labels["k"]; ok {
+		t.Errorf("buildProviderDaemonSet(...): DaemonSet shares its pod template with the Deployment")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	admv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,7 +38,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	statusv1alpha1 "github.com/crossplane/crossplane/apis/status/v1alpha1"
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 const (
@@ -52,8 +57,20 @@ const (
 	errConversionWithNoWebhookCA    = "cannot deploy a CRD with webhook conversion strategy without having a TLS bundle"
 	errGetWebhookTLSSecret          = "cannot get webhook tls secret"
 	errWebhookSecretWithoutCABundle = "the value for the key tls.crt cannot be empty"
+
+	errFmtProtectedGroup = "%s %q cannot define types in API group %q, which is reserved for Crossplane"
 )
 
+// coreGroups are the API groups of Crossplane's own types. Packages may not
+// establish control or ownership of their CustomResourceDefinitions; doing so
+// could render the control plane unusable.
+var coreGroups = []string{
+	xpextv1.Group,
+	v1.Group,
+	secretsv1alpha1.Group,
+	statusv1alpha1.Group,
+}
+
 // An Establisher establishes control or ownership of a set of resources in the
 // API server by checking that control or ownership can be established for all
 // resources and then establishing it.
@@ -125,6 +142,12 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 			return nil, errors.New(errWebhookSecretWithoutCABundle)
 		}
 		webhookTLSCert = s.Data["tls.crt"]
+
+		// A certificate issued by cert-manager is not its own CA. Its
+		// issuing CA is written to ca.crt.
+		if len(s.Data["ca.crt"]) != 0 {
+			webhookTLSCert = s.Data["ca.crt"]
+		}
 	}
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(maxConcurrentEstablishers)
@@ -139,6 +162,10 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 				return errors.New(errAssertResourceObj)
 			}
 
+			if err := protect(res); err != nil {
+				return err
+			}
+
 			// The generated webhook configurations have a static hard-coded name
 			// that the developers of the providers can't affect. Here, we make sure
 			// to distinguish one from the other by setting the name to the parent
@@ -314,6 +341,7 @@ func (e *APIEstablisher) create(ctx context.Context, obj resource.Object, parent
 	}
 	// Overwrite any owner references on the desired object.
 	obj.SetOwnerReferences(refs)
+	labelWithParentPackage(obj, parent)
 	return e.client.Create(ctx, obj, opts...)
 }
 
@@ -340,9 +368,45 @@ func (e *APIEstablisher) update(ctx context.Context, current, desired resource.O
 		return err
 	}
 	desired.SetResourceVersion(current.GetResourceVersion())
+	labelWithParentPackage(desired, parent)
 	return e.client.Update(ctx, desired, opts...)
 }
 
+// protect returns an error if the supplied object is a CustomResourceDefinition
+// or CompositeResourceDefinition that defines types in one of Crossplane's
+// own API groups.
+func protect(o runtime.Object) error {
+	var kind, name, group string
+	switch d := o.(type) {
+	case *extv1.CustomResourceDefinition:
+		kind, name, group = "CustomResourceDefinition", d.GetName(), d.Spec.Group
+	case *extv1beta1.CustomResourceDefinition:
+		kind, name, group = "CustomResourceDefinition", d.GetName(), d.Spec.Group
+	case *xpextv1.CompositeResourceDefinition:
+		kind, name, group = xpextv1.CompositeResourceDefinitionKind, d.GetName(), d.Spec.Group
+	default:
+		return nil
+	}
+
+	for _, g := range coreGroups {
+		// A CRD's name must be <plural>.<group>, but we check both in case
+		// the API server hasn't validated the object yet.
+		if group == g || strings.HasSuffix(name, "."+g) {
+			return reason.Wrap(errors.Errorf(errFmtProtectedGroup, kind, name, g), reason.CoreResourceProtected)
+		}
+	}
+	return nil
+}
+
+// labelWithParentPackage labels the supplied object with the name of the
+// package that owns the supplied parent revision, if any. This allows the
+// objects a package installed to be listed by label.
+func labelWithParentPackage(o, parent metav1.Object) {
+	if name, ok := parent.GetLabels()[v1.LabelParentPackage]; ok {
+		meta.AddLabels(o, map[string]string{v1.LabelParentPackage: name})
+	}
+}
+
 // GetPackageOwnerReference returns the owner reference that points to the owner
 // package of given revision, if it can find one.
 func GetPackageOwnerReference(rev resource.Object) (metav1.OwnerReference, bool) {
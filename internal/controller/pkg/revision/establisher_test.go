@@ -36,7 +36,10 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/reason"
 )
 
 var _ Establisher = &APIEstablisher{}
@@ -103,8 +106,13 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 			args: args{
 				est: &APIEstablisher{
 					client: &test.MockClient{
-						MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
-						MockCreate: test.NewMockCreateFn(nil),
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+							if diff := cmp.Diff("provider-name", obj.GetLabels()[v1.LabelParentPackage]); diff != "" {
+								t.Errorf("Create(...): -want parent package label, +got parent package label:\n%s", diff)
+							}
+							return nil
+						}),
 					},
 				},
 				objs: []runtime.Object{
@@ -394,6 +402,66 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"FailedProtectedCRD": {
+			reason: "We should refuse to establish control of a CRD that defines one of Crossplane's own types.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "providers.pkg.crossplane.io",
+						},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Group: v1.Group,
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: reason.Wrap(errors.Errorf(errFmtProtectedGroup, "CustomResourceDefinition", "providers.pkg.crossplane.io", v1.Group), reason.CoreResourceProtected),
+			},
+		},
+		"FailedProtectedXRD": {
+			reason: "We should refuse to establish control of an XRD that defines types in one of Crossplane's own API groups.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				objs: []runtime.Object{
+					&xpextv1.CompositeResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "compositions.apiextensions.crossplane.io",
+						},
+						Spec: xpextv1.CompositeResourceDefinitionSpec{
+							Group: xpextv1.Group,
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: reason.Wrap(errors.Errorf(errFmtProtectedGroup, xpextv1.CompositeResourceDefinitionKind, "compositions.apiextensions.crossplane.io", xpextv1.Group), reason.CoreResourceProtected),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -30,6 +30,7 @@ import (
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -38,12 +39,16 @@ const (
 	errNotProviderRevision           = "not a provider revision"
 	errControllerConfig              = "cannot get referenced controller config"
 	errDeleteProviderDeployment      = "cannot delete provider package deployment"
+	errGetProviderDaemonSet          = "cannot get provider package daemonset"
+	errDeleteProviderDaemonSet       = "cannot delete provider package daemonset"
 	errDeleteProviderSA              = "cannot delete provider package service account"
 	errDeleteProviderService         = "cannot delete provider package service"
 	errApplyProviderDeployment       = "cannot apply provider package deployment"
+	errApplyProviderDaemonSet        = "cannot apply provider package daemonset"
 	errApplyProviderSA               = "cannot apply provider package service account"
 	errApplyProviderService          = "cannot apply provider package service"
 	errUnavailableProviderDeployment = "provider package deployment is unavailable"
+	errUnavailableProviderDaemonSet  = "provider package daemonset is unavailable"
 )
 
 // A Hooks performs operations before and after a revision establishes objects.
@@ -60,14 +65,30 @@ type Hooks interface {
 type ProviderHooks struct {
 	client    resource.ClientApplicator
 	namespace string
+	defaults  controller.DeploymentDefaults
+}
+
+// A ProviderHooksOption configures ProviderHooks.
+type ProviderHooksOption func(*ProviderHooks)
+
+// WithDeploymentDefaults specifies defaults that ProviderHooks should apply to
+// provider Deployments, unless they are overridden by a ControllerConfig.
+func WithDeploymentDefaults(d controller.DeploymentDefaults) ProviderHooksOption {
+	return func(h *ProviderHooks) {
+		h.defaults = d
+	}
 }
 
 // NewProviderHooks creates a new ProviderHooks.
-func NewProviderHooks(client resource.ClientApplicator, namespace string) *ProviderHooks {
-	return &ProviderHooks{
+func NewProviderHooks(client resource.ClientApplicator, namespace string, opts ...ProviderHooksOption) *ProviderHooks {
+	h := &ProviderHooks{
 		client:    client,
 		namespace: namespace,
 	}
+	for _, f := range opts {
+		f(h)
+	}
+	return h
 }
 
 // Pre cleans up a packaged controller and service account if the revision is
@@ -100,6 +121,11 @@ func (h *ProviderHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.Packa
 	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteProviderDeployment)
 	}
+	// The provider may have been run as a DaemonSet, or may have been run as
+	// one before its ControllerConfig changed.
+	if err := h.client.Delete(ctx, buildProviderDaemonSet(d)); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteProviderDaemonSet)
+	}
 	if err := h.client.Delete(ctx, s); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteProviderSA)
 	}
@@ -125,11 +151,19 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		return errors.Wrap(err, errControllerConfig)
 	}
 	s, d, svc := buildProviderDeployment(pkgProvider, pr, cc, h.namespace)
+	withDeploymentDefaults(d, h.defaults)
 	if err := h.client.Apply(ctx, s); err != nil {
 		return errors.Wrap(err, errApplyProviderSA)
 	}
-	if err := h.client.Apply(ctx, d); err != nil {
-		return errors.Wrap(err, errApplyProviderDeployment)
+	ds := buildProviderDaemonSet(d)
+	wt := workloadType(cc)
+	if wt == v1alpha1.WorkloadTypeDaemonSet {
+		err = h.runAsDaemonSet(ctx, d, ds)
+	} else {
+		err = h.runAsDeployment(ctx, d, ds)
+	}
+	if err != nil {
+		return err
 	}
 	if pr.GetWebhookTLSSecretName() != nil {
 		if err := h.client.Apply(ctx, svc); err != nil {
@@ -137,7 +171,41 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		}
 	}
 	pr.SetControllerReference(v1.ControllerReference{Name: d.GetName()})
+	pr.SetControllerImage(declaredImage(pkgProvider, pr), d.Spec.Template.Spec.Containers[0].Image)
+
+	if wt == v1alpha1.WorkloadTypeDaemonSet {
+		return daemonSetAvailable(ds)
+	}
+	return deploymentAvailable(d)
+}
 
+// runAsDeployment applies the supplied Deployment, and deletes the supplied
+// DaemonSet in case the provider previously ran as one.
+func (h *ProviderHooks) runAsDeployment(ctx context.Context, d *appsv1.Deployment, ds *appsv1.DaemonSet) error {
+	// Providers rarely run as a DaemonSet, so we check whether the DaemonSet
+	// exists rather than asking the API server to delete it every time.
+	err := h.client.Get(ctx, types.NamespacedName{Namespace: ds.GetNamespace(), Name: ds.GetName()}, &appsv1.DaemonSet{})
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetProviderDaemonSet)
+	}
+	if err == nil {
+		if err := h.client.Delete(ctx, ds); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteProviderDaemonSet)
+		}
+	}
+	return errors.Wrap(h.client.Apply(ctx, d), errApplyProviderDeployment)
+}
+
+// runAsDaemonSet applies the supplied DaemonSet, and deletes the supplied
+// Deployment in case the provider previously ran as one.
+func (h *ProviderHooks) runAsDaemonSet(ctx context.Context, d *appsv1.Deployment, ds *appsv1.DaemonSet) error {
+	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteProviderDeployment)
+	}
+	return errors.Wrap(h.client.Apply(ctx, ds), errApplyProviderDaemonSet)
+}
+
+func deploymentAvailable(d *appsv1.Deployment) error {
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
 			if c.Status == corev1.ConditionTrue {
@@ -149,6 +217,15 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	return nil
 }
 
+func daemonSetAvailable(ds *appsv1.DaemonSet) error {
+	// A DaemonSet that hasn't scheduled any pods yet, or that can't schedule
+	// any, isn't running the provider.
+	if ds.Status.DesiredNumberScheduled == 0 || ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled {
+		return errors.Errorf("%s: %d of %d pods are available", errUnavailableProviderDaemonSet, ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled)
+	}
+	return nil
+}
+
 func (h *ProviderHooks) getControllerConfig(ctx context.Context, pr v1.PackageRevision) (*v1alpha1.ControllerConfig, error) {
 	var cc *v1alpha1.ControllerConfig
 	if pr.GetControllerConfigRef() != nil {
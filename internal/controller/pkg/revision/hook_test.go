@@ -23,7 +23,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -32,6 +35,7 @@ import (
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 )
 
 var (
@@ -186,6 +190,37 @@ func TestHookPre(t *testing.T) {
 				err: errors.Wrap(errBoom, errDeleteProviderDeployment),
 			},
 		},
+		"ErrProviderDeleteDaemonSet": {
+			reason: "Should return error if we fail to delete daemonset for inactive provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*appsv1.DaemonSet); ok {
+									return errBoom
+								}
+								return nil
+							}),
+						},
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+				err: errors.Wrap(errBoom, errDeleteProviderDaemonSet),
+			},
+		},
 		"ErrProviderDeleteSA": {
 			reason: "Should return error if we fail to delete service account for inactive provider revision.",
 			args: args{
@@ -290,6 +325,15 @@ func TestHookPre(t *testing.T) {
 func TestHookPost(t *testing.T) {
 	errBoom := errors.New("boom")
 
+	withWorkloadType := func(wt v1alpha1.WorkloadType) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			if cc, ok := o.(*v1alpha1.ControllerConfig); ok {
+				cc.Spec.WorkloadType = &wt
+			}
+			return nil
+		})
+	}
+
 	type args struct {
 		hook Hooks
 		pkg  runtime.Object
@@ -339,6 +383,9 @@ func TestHookPost(t *testing.T) {
 			args: args{
 				hook: &ProviderHooks{
 					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
 							switch o.(type) {
 							case *appsv1.Deployment:
@@ -406,6 +453,10 @@ func TestHookPost(t *testing.T) {
 			args: args{
 				hook: &ProviderHooks{
 					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
 							switch o.(type) {
 							case *appsv1.Deployment:
@@ -438,6 +489,10 @@ func TestHookPost(t *testing.T) {
 			args: args{
 				hook: &ProviderHooks{
 					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
 							d, ok := o.(*appsv1.Deployment)
 							if !ok {
@@ -468,11 +523,225 @@ func TestHookPost(t *testing.T) {
 				err: errors.Errorf("%s: %s", errUnavailableProviderDeployment, errBoom.Error()),
 			},
 		},
+		"ErrProviderGetDaemonSet": {
+			reason: "Should return error if we fail to get the daemonset of a provider revision that runs as a deployment.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(errBoom),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Wrap(errBoom, errGetProviderDaemonSet),
+			},
+		},
+		"ErrProviderDeleteDaemonSet": {
+			reason: "Should return error if we fail to delete the daemonset of a provider revision that runs as a deployment.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Wrap(errBoom, errDeleteProviderDaemonSet),
+			},
+		},
+		"ErrProviderApplyDaemonSet": {
+			reason: "Should return error if we fail to apply daemonset for active provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    withWorkloadType(v1alpha1.WorkloadTypeDaemonSet),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*appsv1.DaemonSet); ok {
+								return errBoom
+							}
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Wrap(errBoom, errApplyProviderDaemonSet),
+			},
+		},
+		"ErrProviderUnavailableDaemonSet": {
+			reason: "Should return error if daemonset is unavailable for provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    withWorkloadType(v1alpha1.WorkloadTypeDaemonSet),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if ds, ok := o.(*appsv1.DaemonSet); ok {
+								ds.Status.DesiredNumberScheduled = 3
+								ds.Status.NumberAvailable = 2
+							}
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Errorf("%s: %d of %d pods are available", errUnavailableProviderDaemonSet, 2, 3),
+			},
+		},
+		"ErrProviderDaemonSetNotScheduled": {
+			reason: "Should return error if daemonset has not scheduled any pods for provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    withWorkloadType(v1alpha1.WorkloadTypeDaemonSet),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Errorf("%s: %d of %d pods are available", errUnavailableProviderDaemonSet, 0, 0),
+			},
+		},
+		"SuccessfulProviderApplyDaemonSet": {
+			reason: "Should delete any deployment and apply a daemonset for an active provider revision that requests one.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: withWorkloadType(v1alpha1.WorkloadTypeDaemonSet),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*appsv1.Deployment); !ok {
+									t.Errorf("Delete(...): want *appsv1.Deployment, got %T", o)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*appsv1.Deployment); ok {
+								t.Errorf("Apply(...): unexpected *appsv1.Deployment")
+							}
+							if ds, ok := o.(*appsv1.DaemonSet); ok {
+								ds.Status.DesiredNumberScheduled = 3
+								ds.Status.NumberAvailable = 3
+							}
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						ControllerConfigReference: &v1.ControllerConfigReference{Name: "daemonset"},
+						DesiredState:              v1.PackageRevisionActive,
+					},
+				},
+			},
+		},
 		"SuccessfulProviderApply": {
 			reason: "Should not return error if successfully applied service account and deployment for active provider revision.",
 			args: args{
 				hook: &ProviderHooks{
 					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*appsv1.DaemonSet); ok {
+									t.Errorf("Delete(...): unexpected *appsv1.DaemonSet")
+								}
+								return nil
+							}),
+						},
 						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
 							return nil
 						}),
@@ -493,6 +762,47 @@ func TestHookPost(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulProviderApplyRecordsImages": {
+			reason: "Should record the declared and effective controller image of an active provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+				},
+				pkg: &pkgmetav1.Provider{
+					Spec: pkgmetav1.ProviderSpec{
+						Controller: pkgmetav1.ControllerSpec{
+							Image: pointer.String("crossplane/provider-cool:v1"),
+						},
+					},
+				},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package:      "crossplane/provider-cool-pkg:v1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package:      "crossplane/provider-cool-pkg:v1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+					Status: v1.PackageRevisionStatus{
+						DeclaredImage:  "crossplane/provider-cool:v1",
+						EffectiveImage: "crossplane/provider-cool:v1",
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -20,8 +20,10 @@ import (
 	"archive/tar"
 	"context"
 	"io"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -35,17 +37,39 @@ const (
 	errBadReference            = "package tag is not a valid reference"
 	errFetchPackage            = "failed to fetch package from remote"
 	errGetManifest             = "failed to get package image manifest from remote"
-	errFetchLayer              = "failed to fetch annotated base layer from remote"
+	errFetchLayer              = "failed to fetch annotated package layer from remote"
 	errGetUncompressed         = "failed to get uncompressed contents from layer"
 	errMultipleAnnotatedLayers = "package is invalid due to multiple annotated base layers"
+	errMultipleSBOMLayers      = "package is invalid due to multiple annotated SBOM layers"
+	errObjectsWithoutBase      = "package is invalid due to annotated objects layers without an annotated base layer"
 	errOpenPackageStream       = "failed to open package stream file"
 )
 
 const (
-	layerAnnotation     = "io.crossplane.xpkg"
-	baseAnnotationValue = "base"
+	layerAnnotation         = "io.crossplane.xpkg"
+	baseAnnotationValue     = "base"
+	objectsAnnotationValue  = "objects"
+	examplesAnnotationValue = "examples"
+	sbomAnnotationValue     = "sbom"
 )
 
+// An SBOMDigester reports the digest of a package's SBOM layer, if any.
+type SBOMDigester interface {
+	SBOMDigest() string
+}
+
+// A packageReadCloser reads a package's YAML stream, and reports the digest of
+// its SBOM layer.
+type packageReadCloser struct {
+	io.ReadCloser
+	sbom string
+}
+
+// SBOMDigest returns the digest of the package's SBOM layer, if any.
+func (p *packageReadCloser) SBOMDigest() string {
+	return p.sbom
+}
+
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
 	registry string
@@ -102,40 +126,100 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	if err != nil {
 		return nil, errors.Wrap(err, errGetManifest)
 	}
-	// Determine if the image is using annotated layers.
-	var tarc io.ReadCloser
-	foundAnnotated := false
+	// Determine if the image is using annotated layers. Layers of package
+	// artifacts that contain a raw YAML stream are base layers unless
+	// annotated otherwise.
+	var base *ociv1.Descriptor
+	var objects []ociv1.Descriptor
+	sbom := ""
 	for _, l := range manifest.Layers {
-		if a, ok := l.Annotations[layerAnnotation]; !ok || a != baseAnnotationValue {
-			continue
-		}
-		// NOTE(hasheddan): the xpkg specification dictates that only one layer
-		// descriptor may be annotated as xpkg base. Since iterating through all
-		// descriptors is relatively inexpensive, we opt to do so in order to
-		// verify that we aren't just using the first layer annotated as xpkg
-		// base.
-		if foundAnnotated {
-			return nil, errors.New(errMultipleAnnotatedLayers)
+		switch xpkg.LayerType(l) {
+		case baseAnnotationValue:
+			// NOTE(hasheddan): the xpkg specification dictates that only one
+			// layer descriptor may be annotated as xpkg base. Since iterating
+			// through all descriptors is relatively inexpensive, we opt to do
+			// so in order to verify that we aren't just using the first layer
+			// annotated as xpkg base.
+			if base != nil {
+				return nil, errors.New(errMultipleAnnotatedLayers)
+			}
+			d := l
+			base = &d
+		case objectsAnnotationValue:
+			objects = append(objects, l)
+		case sbomAnnotationValue:
+			if sbom != "" {
+				return nil, errors.New(errMultipleSBOMLayers)
+			}
+			sbom = l.Digest.String()
+		case examplesAnnotationValue:
+			// Examples are for documentation and tooling. We don't install
+			// them, so there's no need to fetch them.
 		}
-		foundAnnotated = true
+	}
+
+	if base == nil && len(objects) > 0 {
+		return nil, errors.New(errObjectsWithoutBase)
+	}
+
+	// If we don't have annotated layers then we need to flatten the image
+	// filesystem. Otherwise we read the annotated base layer, followed by any
+	// annotated objects layers.
+	var rc io.ReadCloser
+	if base == nil {
+		rc, err = streamFromTar(mutate.Extract(img))
+	} else {
+		rc, err = streamFromLayers(img, append([]ociv1.Descriptor{*base}, objects...))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &packageReadCloser{ReadCloser: rc, sbom: sbom}, nil
+}
+
+// streamFromLayers returns the package YAML streams of the supplied layers,
+// in order, joined into a single YAML stream.
+func streamFromLayers(img ociv1.Image, layers []ociv1.Descriptor) (io.ReadCloser, error) {
+	readers := make([]io.Reader, 0, 2*len(layers))
+	closers := make(multiCloser, 0, len(layers))
+	for i, l := range layers {
 		layer, err := img.LayerByDigest(l.Digest)
 		if err != nil {
+			_ = closers.Close()
 			return nil, errors.Wrap(err, errFetchLayer)
 		}
-		tarc, err = layer.Uncompressed()
+		rc, err := layer.Uncompressed()
 		if err != nil {
+			_ = closers.Close()
 			return nil, errors.Wrap(err, errGetUncompressed)
 		}
-	}
 
-	// If we still don't have content then we need to flatten image filesystem.
-	if !foundAnnotated {
-		tarc = mutate.Extract(img)
+		// Layers of package artifacts may contain a raw YAML stream,
+		// rather than a tarball containing one.
+		stream := rc
+		if !xpkg.IsStreamLayer(l) {
+			stream, err = streamFromTar(rc)
+		}
+		if err != nil {
+			_ = rc.Close()
+			_ = closers.Close()
+			return nil, err
+		}
+		closers = append(closers, stream)
+		if i > 0 {
+			// Make sure the last document of one stream is not merged with
+			// the first document of the next.
+			readers = append(readers, strings.NewReader("\n---\n"))
+		}
+		readers = append(readers, stream)
 	}
+	return xpkg.JoinedReadCloser(io.MultiReader(readers...), closers), nil
+}
 
-	// The ReadCloser is an uncompressed tarball, either consisting of annotated
-	// layer contents or flattened filesystem content. Either way, we only want
-	// the package YAML stream.
+// streamFromTar returns the package YAML stream from the supplied uncompressed
+// tarball, which consists of either annotated layer contents or flattened
+// filesystem content.
+func streamFromTar(tarc io.ReadCloser) (io.ReadCloser, error) {
 	t := tar.NewReader(tarc)
 	for {
 		h, err := t.Next()
@@ -154,6 +238,20 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	return xpkg.JoinedReadCloser(t, tarc), nil
 }
 
+// A multiCloser closes several closers.
+type multiCloser []io.Closer
+
+// Close closes all closers, returning the first error encountered.
+func (m multiCloser) Close() error {
+	var err error
+	for _, c := range m {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
 // nestedBackend is a nop parser backend that conforms to the parser backend
 // interface to allow holding intermediate data passed via parser backend
 // options.
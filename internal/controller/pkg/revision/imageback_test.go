@@ -25,9 +25,11 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 
@@ -56,6 +58,18 @@ func TestImageBackend(t *testing.T) {
 		},
 	})
 
+	randImgSBOMDup, _ := mutate.Append(empty.Image,
+		mutate.Addendum{Layer: randLayer, Annotations: map[string]string{layerAnnotation: sbomAnnotationValue}},
+		mutate.Addendum{Layer: randLayer, Annotations: map[string]string{layerAnnotation: sbomAnnotationValue}},
+	)
+
+	randImgObjects, _ := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: randLayer,
+		Annotations: map[string]string{
+			layerAnnotation: objectsAnnotationValue,
+		},
+	})
+
 	streamCont := "somestreamofyaml"
 	tarBuf := new(bytes.Buffer)
 	tw := tar.NewWriter(tarBuf)
@@ -109,6 +123,34 @@ func TestImageBackend(t *testing.T) {
 			},
 			want: errors.New(errMultipleAnnotatedLayers),
 		},
+		"ErrMultipleSBOMLayers": {
+			reason: "Should return error if image has multiple layers annotated as SBOM.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(randImgSBOMDup, nil),
+				},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package: "test/test:latest",
+					},
+				})},
+			},
+			want: errors.New(errMultipleSBOMLayers),
+		},
+		"ErrObjectsWithoutBase": {
+			reason: "Should return error if image has layers annotated as objects but none annotated as base.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(randImgObjects, nil),
+				},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package: "test/test:latest",
+					},
+				})},
+			},
+			want: errors.New(errObjectsWithoutBase),
+		},
 		"ErrFetchedBadPackage": {
 			reason: "Should return error if image with contents does not have package.yaml.",
 			args: args{
@@ -179,3 +221,101 @@ func TestImageBackend(t *testing.T) {
 		})
 	}
 }
+
+func TestImageBackendLayers(t *testing.T) {
+	streamLayer := func(stream string) ociv1.Layer {
+		tarBuf := new(bytes.Buffer)
+		tw := tar.NewWriter(tarBuf)
+		_ = tw.WriteHeader(&tar.Header{
+			Name: xpkg.StreamFile,
+			Mode: int64(xpkg.StreamFileMode),
+			Size: int64(len(stream)),
+		})
+		_, _ = io.Copy(tw, strings.NewReader(stream))
+		_ = tw.Close()
+		l, _ := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(tarBuf.Bytes())), nil
+		})
+		return l
+	}
+	sbomLayer, _ := random.Layer(int64(100), types.DockerLayer)
+	sbomDigest, _ := sbomLayer.Digest()
+
+	type want struct {
+		stream string
+		sbom   string
+	}
+
+	cases := map[string]struct {
+		reason string
+		layers []mutate.Addendum
+		want   want
+	}{
+		"BaseLayer": {
+			reason: "We should read the package stream from the base layer.",
+			layers: []mutate.Addendum{
+				{Layer: streamLayer("meta"), Annotations: map[string]string{layerAnnotation: baseAnnotationValue}},
+			},
+			want: want{
+				stream: "meta",
+			},
+		},
+		"MultipleLayers": {
+			reason: "We should join the package streams of the base and objects layers, ignore examples, and report the SBOM digest.",
+			layers: []mutate.Addendum{
+				{Layer: streamLayer("crds"), Annotations: map[string]string{layerAnnotation: objectsAnnotationValue}},
+				{Layer: streamLayer("examples"), Annotations: map[string]string{layerAnnotation: examplesAnnotationValue}},
+				{Layer: sbomLayer, Annotations: map[string]string{layerAnnotation: sbomAnnotationValue}},
+				{Layer: streamLayer("meta"), Annotations: map[string]string{layerAnnotation: baseAnnotationValue}},
+				{Layer: streamLayer("compositions"), Annotations: map[string]string{layerAnnotation: objectsAnnotationValue}},
+			},
+			want: want{
+				stream: "meta\n---\ncrds\n---\ncompositions",
+				sbom:   sbomDigest.String(),
+			},
+		},
+		"ArtifactLayers": {
+			reason: "We should read raw YAML stream layers of a package artifact, treating an unannotated stream layer as the base layer.",
+			layers: []mutate.Addendum{
+				{Layer: static.NewLayer([]byte("crds"), types.OCILayer), Annotations: map[string]string{layerAnnotation: objectsAnnotationValue, xpkg.TitleAnnotationKey: xpkg.StreamFile}},
+				{Layer: static.NewLayer([]byte("meta"), xpkg.MediaTypeStream)},
+			},
+			want: want{
+				stream: "meta\n---\ncrds",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			img, err := mutate.Append(empty.Image, tc.layers...)
+			if err != nil {
+				t.Fatalf("mutate.Append(...): %s", err)
+			}
+			b := NewImageBackend(&fake.MockFetcher{MockFetch: fake.NewMockFetchFn(img, nil)})
+			rc, err := b.Init(context.TODO(), PackageRevision(&v1.ProviderRevision{
+				Spec: v1.PackageRevisionSpec{
+					Package: "test/test:latest",
+				},
+			}))
+			if err != nil {
+				t.Fatalf("b.Init(...): %s", err)
+			}
+			got, err := io.ReadAll(rc)
+			if err != nil {
+				t.Fatalf("io.ReadAll(...): %s", err)
+			}
+			_ = rc.Close()
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want stream, +got stream:\n%s", tc.reason, diff)
+			}
+			d, ok := rc.(SBOMDigester)
+			if !ok {
+				t.Fatalf("b.Init(...): package ReadCloser is not an SBOMDigester")
+			}
+			if diff := cmp.Diff(tc.want.sbom, d.SBOMDigest()); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want SBOM digest, +got SBOM digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
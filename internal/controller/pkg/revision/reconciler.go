@@ -46,12 +46,18 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/dag"
+	"github.com/crossplane/crossplane/internal/reason"
+	"github.com/crossplane/crossplane/internal/throttle"
 	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const (
 	reconcileTimeout = 3 * time.Minute
+
+	// dependencyWait is how long we wait before checking again whether the
+	// dependencies a package revision waits for have become healthy.
+	dependencyWait = 30 * time.Second
 )
 
 const (
@@ -237,20 +243,21 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithHooks(NewProviderHooks(resource.ClientApplicator{
 			Client:     mgr.GetClient(),
 			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
-		}, o.Namespace)),
+		}, o.Namespace, WithDeploymentDefaults(o.DeploymentDefaults))),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
 		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.ProviderRevision{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.DaemonSet{}).
 		Watches(&source.Kind{Type: &v1alpha1.ControllerConfig{}}, &EnqueueRequestForReferencingProviderRevisions{
 			client: mgr.GetClient(),
 		}).
@@ -291,7 +298,7 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -403,6 +410,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	var rc io.ReadCloser
+	sbom := ""
 	cacheWrite := make(chan error)
 
 	if r.cache.Has(id) {
@@ -438,17 +446,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// Initialize parser backend to obtain package contents.
 		imgrc, err := r.backend.Init(ctx, PackageRevision(pr))
 		if err != nil {
-			pr.SetConditions(v1.Unhealthy())
+			err = errors.Wrap(reason.Wrap(err, reason.ImagePullFailed), errInitParserBackend)
+			pr.SetConditions(reason.Condition(v1.Unhealthy(), err))
 			_ = r.client.Status().Update(ctx, pr)
 
 			// Requeue because we may be waiting for parent package
 			// controller to recreate Pod.
 			log.Debug(errInitParserBackend, "error", err)
-			err = errors.Wrap(err, errInitParserBackend)
-			r.record.Event(pr, event.Warning(reasonParse, err))
+			r.record.Event(pr, reason.Warning(reasonParse, err))
 			return reconcile.Result{}, err
 		}
 
+		// The SBOM digest is only known when we fetch the package image. It
+		// is preserved in our status when we later read from the cache.
+		if d, ok := imgrc.(SBOMDigester); ok {
+			sbom = d.SBOMDigest()
+		}
+
 		// Package is not in cache, so we write it to the cache while parsing.
 		pipeR, pipeW := io.Pipe()
 		rc = xpkg.TeeReadCloser(imgrc, pipeW)
@@ -475,27 +489,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 	if err != nil {
-		pr.SetConditions(v1.Unhealthy())
+		err = errors.Wrap(reason.Wrap(err, reason.PackageParseFailed), errParsePackage)
+		pr.SetConditions(reason.Condition(v1.Unhealthy(), err))
 		_ = r.client.Status().Update(ctx, pr)
 		log.Debug(errParsePackage, "error", err)
 
-		err = errors.Wrap(err, errParsePackage)
-		r.record.Event(pr, event.Warning(reasonParse, err))
+		r.record.Event(pr, reason.Warning(reasonParse, err))
 		return reconcile.Result{}, err
 	}
 
 	// Lint package using package-specific linter.
 	if err := r.linter.Lint(pkg); err != nil {
-		pr.SetConditions(v1.Unhealthy())
+		err = errors.Wrap(reason.Wrap(err, reason.PackageLintFailed), errLintPackage)
+		pr.SetConditions(reason.Condition(v1.Unhealthy(), err))
 		_ = r.client.Status().Update(ctx, pr)
 
 		// NOTE(hasheddan): a failed lint typically will require manual
 		// intervention, but on the off chance that we read pod logs
 		// early, which caused a linting failure, we will requeue by
 		// returning an error.
-		err = errors.Wrap(err, errLintPackage)
 		log.Debug(errLintPackage, "error", err)
-		r.record.Event(pr, event.Warning(reasonLint, err))
+		r.record.Event(pr, reason.Warning(reasonLint, err))
 		return reconcile.Result{}, err
 	}
 
@@ -525,6 +539,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	if sbom != "" {
+		pr.SetSBOMDigest(sbom)
+	}
+
 	// Check Crossplane constraints if they exist.
 	if pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints() {
 		if err := xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta); err != nil {
@@ -546,13 +564,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if pr.GetSkipDependencyResolution() != nil && !*pr.GetSkipDependencyResolution() {
 		found, installed, invalid, err := r.lock.Resolve(ctx, pkgMeta, pr)
 		pr.SetDependencyStatus(int64(found), int64(installed), int64(invalid))
+		if IsWaitingForDependencies(err) {
+			// We don't watch the revisions we depend on, so we check
+			// again after a short wait.
+			log.Debug("Waiting for dependencies to become healthy", "error", err)
+			pr.SetConditions(v1.WaitingForDependencies().WithMessage(err.Error()))
+			r.record.Event(pr, event.Normal(reasonDependencies, err.Error()))
+			return reconcile.Result{RequeueAfter: dependencyWait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+		}
 		if err != nil {
-			pr.SetConditions(v1.UnknownHealth())
+			err = errors.Wrap(reason.Wrap(err, reason.DependenciesMissing), errResolveDeps)
+			pr.SetConditions(reason.Condition(v1.UnknownHealth(), err))
 			_ = r.client.Status().Update(ctx, pr)
 
 			log.Debug(errResolveDeps, "error", err)
-			err = errors.Wrap(err, errResolveDeps)
-			r.record.Event(pr, event.Warning(reasonDependencies, err))
+			r.record.Event(pr, reason.Warning(reasonDependencies, err))
 			return reconcile.Result{}, err
 		}
 	}
@@ -570,12 +596,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// Establish control or ownership of objects.
 	refs, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
 	if err != nil {
-		pr.SetConditions(v1.Unhealthy())
-		_ = r.client.Status().Update(ctx, pr)
-
 		log.Debug(errEstablishControl, "error", err)
 		err = errors.Wrap(err, errEstablishControl)
-		r.record.Event(pr, event.Warning(reasonSync, err))
+		pr.SetConditions(reason.Condition(v1.Unhealthy(), err))
+		_ = r.client.Status().Update(ctx, pr)
+
+		r.record.Event(pr, reason.Warning(reasonSync, err))
 		return reconcile.Result{}, err
 	}
 
@@ -44,6 +44,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/reason"
 	verfake "github.com/crossplane/crossplane/internal/version/fake"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	xpkgfake "github.com/crossplane/crossplane/internal/xpkg/fake"
@@ -501,7 +502,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.ImagePullFailed), errInitParserBackend)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -542,7 +543,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.PackageParseFailed), errParsePackage)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -584,7 +585,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.PackageParseFailed), errParsePackage)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -627,7 +628,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.PackageParseFailed), errParsePackage)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -671,7 +672,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.PackageParseFailed), errParsePackage)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -715,7 +716,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(reason.Wrap(errBoom, reason.PackageLintFailed), errLintPackage)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -923,7 +924,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetSkipDependencyResolution(pointer.BoolPtr(false))
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(reason.Condition(v1.UnknownHealth(), errors.Wrap(reason.Wrap(errBoom, reason.DependenciesMissing), errResolveDeps)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -963,6 +964,72 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errResolveDeps),
 			},
 		},
+		"WaitingForDependencies": {
+			reason: "We should requeue without activating the revision if it is waiting for dependencies to become healthy.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithDependencyManager(&MockDependencyManager{
+						MockResolve: NewMockResolveFn(1, 1, 0, &waitingError{errBoom}),
+					}),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(pointer.BoolPtr(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(pointer.BoolPtr(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetDependencyStatus(1, 1, 0)
+								want.SetConditions(v1.WaitingForDependencies().WithMessage(errBoom.Error()))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetSkipDependencyResolution(pointer.BoolPtr(false))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: dependencyWait},
+			},
+		},
 		"ErrPreHook": {
 			reason: "We should return an error if pre establishment hook returns an error.",
 			args: args{
@@ -1239,7 +1306,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(errBoom, errEstablishControl)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1366,7 +1433,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Unhealthy())
+								want.SetConditions(reason.Condition(v1.Unhealthy(), errors.Wrap(errBoom, errEstablishControl)))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
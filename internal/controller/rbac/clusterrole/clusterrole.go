@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterrole contains helpers shared by the RBAC manager controllers
+// that render ClusterRoles.
+package clusterrole
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Differ returns true if the supplied objects are different ClusterRoles. We
+// consider ClusterRoles to be different if their labels and rules do not match.
+// Rules that differ only in order match, so that we don't make no-op updates.
+func Differ(current, desired runtime.Object) bool {
+	c := current.(*rbacv1.ClusterRole)
+	d := desired.(*rbacv1.ClusterRole)
+	return !cmp.Equal(c.GetLabels(), d.GetLabels(), cmpopts.EquateEmpty()) || !RulesEqual(c.Rules, d.Rules)
+}
+
+// RulesEqual returns true if the supplied rules are canonically equal.
+func RulesEqual(a, b []rbacv1.PolicyRule) bool {
+	return cmp.Equal(Canonical(a), Canonical(b), cmpopts.EquateEmpty())
+}
+
+// WithVerbs returns a copy of the supplied rules with their verbs set to the
+// supplied verbs.
+func WithVerbs(r []rbacv1.PolicyRule, verbs []string) []rbacv1.PolicyRule {
+	verbal := make([]rbacv1.PolicyRule, len(r))
+	for i := range r {
+		verbal[i] = r[i]
+		verbal[i].Verbs = verbs
+	}
+	return verbal
+}
+
+// GroupedResources accumulates resources by API group in order to produce the
+// fewest rules possible. Groups are rendered in the order they were first
+// added, so callers that add them in a stable order get stable rules.
+type GroupedResources struct {
+	groups    []string
+	resources map[string][]string
+}
+
+// Add the supplied resources to the supplied API group.
+func (g *GroupedResources) Add(group string, resources ...string) {
+	if g.resources == nil {
+		g.resources = make(map[string][]string)
+	}
+	if _, ok := g.resources[group]; !ok {
+		g.resources[group] = make([]string, 0, len(resources))
+		g.groups = append(g.groups, group)
+	}
+	g.resources[group] = append(g.resources[group], resources...)
+}
+
+// Rules returns one verbless rule per API group.
+func (g *GroupedResources) Rules() []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{}
+	for _, group := range g.groups {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: g.resources[group],
+		})
+	}
+	return rules
+}
+
+// Canonical returns a copy of the supplied rules in canonical order. The API
+// groups, resources, resource names, non-resource URLs, and verbs of each rule
+// are sorted, and the rules are sorted by them in turn. Two sets of rules that
+// grant the same permissions using the same rules are canonically equal
+// regardless of the order in which they were rendered.
+func Canonical(r []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	canonical := make([]rbacv1.PolicyRule, len(r))
+	for i := range r {
+		canonical[i] = rbacv1.PolicyRule{
+			APIGroups:       sorted(r[i].APIGroups),
+			Resources:       sorted(r[i].Resources),
+			ResourceNames:   sorted(r[i].ResourceNames),
+			NonResourceURLs: sorted(r[i].NonResourceURLs),
+			Verbs:           sorted(r[i].Verbs),
+		}
+	}
+	key := func(p rbacv1.PolicyRule) string {
+		return strings.Join([]string{
+			strings.Join(p.APIGroups, ","),
+			strings.Join(p.Resources, ","),
+			strings.Join(p.ResourceNames, ","),
+			strings.Join(p.NonResourceURLs, ","),
+			strings.Join(p.Verbs, ","),
+		}, ";")
+	}
+	sort.SliceStable(canonical, func(i, j int) bool { return key(canonical[i]) < key(canonical[j]) })
+	return canonical
+}
+
+// sorted returns a sorted copy of the supplied strings, or nil if there are
+// none.
+func sorted(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	c := make([]string, len(s))
+	copy(c, s)
+	sort.Strings(c)
+	return c
+}
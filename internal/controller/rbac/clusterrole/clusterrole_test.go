@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrole
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDiffer(t *testing.T) {
+	cases := map[string]struct {
+		current runtime.Object
+		desired runtime.Object
+		want    bool
+	}{
+		"Equal": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			desired: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			want: false,
+		},
+		"LabelsDiffer": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			desired: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"b": "b"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			want: true,
+		},
+		"RulesDiffer": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			desired: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+			},
+			want: true,
+		},
+		"RulesReordered": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"b"}, Resources: []string{"y", "x"}, Verbs: []string{"list", "get"}},
+					{APIGroups: []string{"a"}, Resources: []string{"z"}, Verbs: []string{"*"}},
+				},
+			},
+			desired: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"a"}, Resources: []string{"z"}, Verbs: []string{"*"}},
+					{APIGroups: []string{"b"}, Resources: []string{"x", "y"}, Verbs: []string{"get", "list"}},
+				},
+			},
+			want: false,
+		},
+		"EmptyLabels": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{},
+				},
+				Rules: []rbacv1.PolicyRule{},
+			},
+			desired: &rbacv1.ClusterRole{},
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Differ(tc.current, tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Differ(...): -want, +got\n:%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupedResources(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		add    [][]string
+		want   []rbacv1.PolicyRule
+	}{
+		"Empty": {
+			reason: "No resources should produce no rules.",
+			want:   []rbacv1.PolicyRule{},
+		},
+		"MergeGroups": {
+			reason: "Resources should be merged by group, in the order each group was first added.",
+			add: [][]string{
+				{"example.org", "a", "a/status"},
+				{"example.net", "b"},
+				{"example.org", "c"},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{"example.org"}, Resources: []string{"a", "a/status", "c"}},
+				{APIGroups: []string{"example.net"}, Resources: []string{"b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := &GroupedResources{}
+			for _, a := range tc.add {
+				g.Add(a[0], a[1:]...)
+			}
+			if diff := cmp.Diff(tc.want, g.Rules()); diff != "" {
+				t.Errorf("\n%s\ng.Rules(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rules  []rbacv1.PolicyRule
+		want   []rbacv1.PolicyRule
+	}{
+		"Empty": {
+			reason: "No rules should produce no rules.",
+			want:   []rbacv1.PolicyRule{},
+		},
+		"Sort": {
+			reason: "The fields of each rule, and the rules themselves, should be sorted.",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"example.org"}, Resources: []string{"b", "a"}, Verbs: []string{"list", "get"}},
+				{APIGroups: []string{"", "coordination.k8s.io"}, Resources: []string{"secrets", "leases"}, Verbs: []string{"*"}},
+				{APIGroups: []string{"example.net"}, Resources: []string{"c"}, Verbs: []string{"get"}},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{"", "coordination.k8s.io"}, Resources: []string{"leases", "secrets"}, Verbs: []string{"*"}},
+				{APIGroups: []string{"example.net"}, Resources: []string{"c"}, Verbs: []string{"get"}},
+				{APIGroups: []string{"example.org"}, Resources: []string{"a", "b"}, Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Canonical(tc.rules)); diff != "" {
+				t.Errorf("\n%s\nCanonical(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	xv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
+	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/throttle"
+)
+
+const (
+	timeout = 2 * time.Minute
+
+	errGetCR      = "cannot get ConfigurationRevision"
+	errListXRDs   = "cannot list CompositeResourceDefinitions"
+	errApplyRole  = "cannot apply ClusterRole"
+	errDeleteRole = "cannot delete ClusterRole"
+)
+
+// Event reasons.
+const (
+	reasonApplyRoles event.Reason = "ApplyClusterRoles"
+)
+
+// A ClusterRoleRenderer renders ClusterRoles for the given XRDs.
+type ClusterRoleRenderer interface {
+	// RenderClusterRoles for the supplied XRDs.
+	RenderClusterRoles(cr *v1.ConfigurationRevision, xrds []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole
+}
+
+// A ClusterRoleRenderFn renders ClusterRoles for the supplied XRDs.
+type ClusterRoleRenderFn func(cr *v1.ConfigurationRevision, xrds []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole
+
+// RenderClusterRoles renders ClusterRoles for the supplied XRDs.
+func (fn ClusterRoleRenderFn) RenderClusterRoles(cr *v1.ConfigurationRevision, xrds []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole {
+	return fn(cr, xrds)
+}
+
+// Setup adds a controller that reconciles a ConfigurationRevision by creating a
+// series of opinionated ClusterRoles that may be bound to allow access to the
+// resources it defines.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "rbac/" + strings.ToLower(v1.ConfigurationRevisionGroupKind)
+
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1.ConfigurationRevision{}).
+		Owns(&rbacv1.ClusterRole{}).
+		Watches(&source.Kind{Type: &xv1.CompositeResourceDefinition{}}, &handler.EnqueueRequestForOwner{OwnerType: &v1.ConfigurationRevision{}}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithClientApplicator specifies how the Reconciler should interact with the
+// Kubernetes API.
+func WithClientApplicator(ca resource.ClientApplicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = ca
+	}
+}
+
+// WithClusterRoleRenderer specifies how the Reconciler should render RBAC
+// ClusterRoles.
+func WithClusterRoleRenderer(rr ClusterRoleRenderer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.rbac = rr
+	}
+}
+
+// NewReconciler returns a Reconciler of ConfigurationRevisions.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: resource.ClientApplicator{
+			Client:     mgr.GetClient(),
+			Applicator: resource.NewAPIUpdatingApplicator(mgr.GetClient()),
+		},
+
+		rbac: ClusterRoleRenderFn(RenderClusterRoles),
+
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler reconciles ConfigurationRevisions.
+type Reconciler struct {
+	client resource.ClientApplicator
+	rbac   ClusterRoleRenderer
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a ConfigurationRevision by creating a series of opinionated
+// ClusterRoles that may be bound to allow access to the resources it defines.
+// This allows access to be granted as soon as a configuration is installed,
+// rather than waiting for the definition controller to reconcile each XRD.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cr := &v1.ConfigurationRevision{}
+	if err := r.client.Get(ctx, req.NamespacedName, cr); err != nil {
+		// In case object is not found, most likely the object was deleted and
+		// then disappeared while the event was in the processing queue. We
+		// don't need to take any action in that case.
+		log.Debug(errGetCR, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetCR)
+	}
+
+	log = log.WithValues(
+		"uid", cr.GetUID(),
+		"version", cr.GetResourceVersion(),
+		"name", cr.GetName(),
+	)
+
+	if meta.WasDeleted(cr) {
+		// There's nothing to do if our revision is being deleted. Any
+		// ClusterRoles we created will be garbage collected by Kubernetes.
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	if cr.GetDesiredState() != v1.PackageRevisionActive {
+		// Only the active revision's XRDs are established, so only it needs
+		// ClusterRoles. Delete any we created while this revision was active.
+		log.Debug("ConfigurationRevision is not active")
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.deleteRoles(ctx, cr), errDeleteRole)
+	}
+
+	l := &xv1.CompositeResourceDefinitionList{}
+	if err := r.client.List(ctx, l); err != nil {
+		log.Debug(errListXRDs, "error", err)
+		err = errors.Wrap(err, errListXRDs)
+		r.record.Event(cr, event.Warning(reasonApplyRoles, err))
+		return reconcile.Result{}, err
+	}
+
+	// Filter down to the XRDs that are owned by this ConfigurationRevision -
+	// i.e. those that it may become the active revision for.
+	xrds := make([]xv1.CompositeResourceDefinition, 0)
+	for _, xrd := range l.Items {
+		for _, ref := range xrd.GetOwnerReferences() {
+			if ref.UID == cr.GetUID() {
+				xrds = append(xrds, xrd)
+				break
+			}
+		}
+	}
+
+	if len(xrds) == 0 {
+		// This revision doesn't (yet) establish any XRDs, or the XRDs it
+		// established were deleted. We'll be queued again when it does,
+		// because we're watching the XRDs it owns. Delete any ClusterRoles we
+		// rendered for XRDs that no longer exist.
+		log.Debug("ConfigurationRevision establishes no CompositeResourceDefinitions")
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.deleteRoles(ctx, cr), errDeleteRole)
+	}
+
+	for _, role := range r.rbac.RenderClusterRoles(cr, xrds) {
+		role := role // Pin range variable so we can take its address.
+		log = log.WithValues("role-name", role.GetName())
+		err := r.client.Apply(ctx, &role, resource.MustBeControllableBy(cr.GetUID()), resource.AllowUpdateIf(clusterrole.Differ))
+		if resource.IsNotAllowed(err) {
+			log.Debug("Skipped no-op RBAC ClusterRole apply")
+			continue
+		}
+		if err != nil {
+			log.Debug(errApplyRole, "error", err)
+			err = errors.Wrap(err, errApplyRole)
+			r.record.Event(cr, event.Warning(reasonApplyRoles, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Applied RBAC ClusterRole")
+	}
+
+	r.record.Event(cr, event.Normal(reasonApplyRoles, "Applied RBAC ClusterRoles"))
+
+	// There's no need to requeue explicitly - we're watching all
+	// ConfigurationRevisions and the XRDs they own.
+	return reconcile.Result{Requeue: false}, nil
+}
+
+// deleteRoles deletes any ClusterRoles rendered for the supplied revision.
+func (r *Reconciler) deleteRoles(ctx context.Context, cr *v1.ConfigurationRevision) error {
+	for _, role := range r.rbac.RenderClusterRoles(cr, nil) {
+		role := role // Pin range variable so we can take its address.
+		if err := r.client.Delete(ctx, &role); resource.IgnoreNotFound(err) != nil {
+			r.record.Event(cr, event.Warning(reasonApplyRoles, errors.Wrap(err, errDeleteRole)))
+			return err
+		}
+	}
+	return nil
+}
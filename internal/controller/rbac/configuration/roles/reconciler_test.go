@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	xv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	now := metav1.Now()
+
+	uid := types.UID("cool-revision")
+	withUID := test.NewMockGetFn(nil, func(o client.Object) error {
+		o.SetUID(uid)
+		o.(*v1.ConfigurationRevision).SetDesiredState(v1.PackageRevisionActive)
+		return nil
+	})
+
+	owned := xv1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "owned",
+			OwnerReferences: []metav1.OwnerReference{{UID: "some-other-revision"}, {UID: uid}},
+		},
+	}
+	unowned := xv1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "unowned",
+			OwnerReferences: []metav1.OwnerReference{{UID: "some-other-revision"}},
+		},
+	}
+	withXRDs := test.NewMockListFn(nil, func(o client.ObjectList) error {
+		l := o.(*xv1.CompositeResourceDefinitionList)
+		l.Items = []xv1.CompositeResourceDefinition{owned, unowned}
+		return nil
+	})
+
+	type args struct {
+		mgr  manager.Manager
+		opts []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ConfigurationRevisionNotFound": {
+			reason: "We should not return an error if the ConfigurationRevision was not found.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetConfigurationRevisionError": {
+			reason: "We should return any other error encountered while getting a ConfigurationRevision.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(errBoom),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCR),
+			},
+		},
+		"ConfigurationRevisionDeleted": {
+			reason: "We should return early if the ConfigurationRevision was deleted.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								d := o.(*v1.ConfigurationRevision)
+								d.SetDeletionTimestamp(&now)
+								return nil
+							}),
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"InactiveRevisionDeleteError": {
+			reason: "We should return an error encountered deleting the ClusterRoles of an inactive ConfigurationRevision.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteRole),
+			},
+		},
+		"InactiveRevision": {
+			reason: "We should delete the ClusterRoles of an inactive ConfigurationRevision rather than applying them.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    test.NewMockGetFn(nil),
+							MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ListXRDsError": {
+			reason: "We should return an error encountered listing XRDs.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  withUID,
+							MockList: test.NewMockListFn(errBoom),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRDs),
+			},
+		},
+		"NoXRDs": {
+			reason: "We should delete rather than apply our ClusterRoles if the ConfigurationRevision owns none of the XRDs.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: withUID,
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*xv1.CompositeResourceDefinitionList)
+								l.Items = []xv1.CompositeResourceDefinition{unowned}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ApplyClusterRoleError": {
+			reason: "We should return an error encountered applying a ClusterRole.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  withUID,
+							MockList: withXRDs,
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ConfigurationRevision, []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyRole),
+			},
+		},
+		"SuccessfulNoOp": {
+			reason: "We should not requeue when no ClusterRoles need applying.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  withUID,
+							MockList: withXRDs,
+						},
+						Applicator: resource.ApplyFn(func(ctx context.Context, o client.Object, ao ...resource.ApplyOption) error {
+							// Simulate a no-op change by not allowing the update.
+							return resource.AllowUpdateIf(func(_, _ runtime.Object) bool { return false })(ctx, o, o)
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ConfigurationRevision, []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulApply": {
+			reason: "We should not requeue when we successfully apply our ClusterRoles.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  withUID,
+							MockList: withXRDs,
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(_ *v1.ConfigurationRevision, xrds []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole {
+						// Only the XRD owned by this revision should be
+						// rendered, and only once.
+						if diff := cmp.Diff([]xv1.CompositeResourceDefinition{owned}, xrds); diff != "" {
+							t.Errorf("RenderClusterRoles(...): -want XRDs, +got XRDs:\n%s", diff)
+						}
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, append(tc.args.opts, WithLogger(testLog))...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
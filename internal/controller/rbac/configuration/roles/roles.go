@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	xv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
+)
+
+const (
+	namePrefix     = "crossplane:configuration:"
+	nameSuffixEdit = ":aggregate-to-edit"
+	nameSuffixView = ":aggregate-to-view"
+
+	keyAggregateToAdmin   = "rbac.crossplane.io/aggregate-to-admin"
+	keyAggregateToNSAdmin = "rbac.crossplane.io/aggregate-to-ns-admin"
+
+	keyAggregateToEdit   = "rbac.crossplane.io/aggregate-to-edit"
+	keyAggregateToNSEdit = "rbac.crossplane.io/aggregate-to-ns-edit"
+
+	keyAggregateToView   = "rbac.crossplane.io/aggregate-to-view"
+	keyAggregateToNSView = "rbac.crossplane.io/aggregate-to-ns-view"
+
+	keyConfigurationRevision = "rbac.crossplane.io/configurationrevision"
+
+	valTrue = "true"
+)
+
+var (
+	verbsEdit = []string{rbacv1.VerbAll}
+	verbsView = []string{"get", "list", "watch"}
+)
+
+// RenderClusterRoles returns ClusterRoles for the supplied
+// ConfigurationRevision. The roles grant access to the composite resources and
+// claims defined by the supplied XRDs, which are assumed to be those the
+// revision establishes.
+//
+// The definition controller renders similar roles for each XRD, with the same
+// aggregation labels. Those are only rendered once the definition controller
+// has reconciled each XRD, and can only be bound one XRD at a time. The roles
+// rendered here exist as soon as the revision establishes its XRDs, and may be
+// bound to grant access to everything a configuration defines. The rules they
+// aggregate duplicate the per-XRD rules, so aggregating both grants nothing
+// extra.
+func RenderClusterRoles(cr *v1.ConfigurationRevision, xrds []xv1.CompositeResourceDefinition) []rbacv1.ClusterRole {
+	// Our list of XRDs has no guaranteed order, so we sort a copy of them in
+	// order to ensure we don't reorder our RBAC rules on each update.
+	sorted := make([]xv1.CompositeResourceDefinition, len(xrds))
+	copy(sorted, xrds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GetName() < sorted[j].GetName() })
+
+	g := &clusterrole.GroupedResources{}
+	for _, xrd := range sorted {
+		g.Add(xrd.Spec.Group, xrd.Spec.Names.Plural)
+		if xrd.Spec.ClaimNames != nil {
+			g.Add(xrd.Spec.Group, xrd.Spec.ClaimNames.Plural)
+		}
+	}
+	rules := g.Rules()
+
+	edit := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namePrefix + cr.GetName() + nameSuffixEdit,
+			Labels: map[string]string{
+				// Edit rules aggregate to admin too. Currently edit and admin
+				// differ only in their base roles.
+				keyAggregateToAdmin:   valTrue,
+				keyAggregateToNSAdmin: valTrue,
+
+				keyAggregateToEdit:   valTrue,
+				keyAggregateToNSEdit: valTrue,
+
+				keyConfigurationRevision: cr.GetName(),
+			},
+		},
+		Rules: clusterrole.WithVerbs(rules, verbsEdit),
+	}
+
+	view := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namePrefix + cr.GetName() + nameSuffixView,
+			Labels: map[string]string{
+				keyAggregateToView:   valTrue,
+				keyAggregateToNSView: valTrue,
+
+				keyConfigurationRevision: cr.GetName(),
+			},
+		},
+		Rules: clusterrole.WithVerbs(rules, verbsView),
+	}
+
+	roles := []rbacv1.ClusterRole{*edit, *view}
+	for i := range roles {
+		ref := meta.AsController(meta.TypedReferenceTo(cr, v1.ConfigurationRevisionGroupVersionKind))
+		roles[i].SetOwnerReferences([]metav1.OwnerReference{ref})
+		roles[i].Rules = clusterrole.Canonical(roles[i].Rules)
+	}
+	return roles
+}
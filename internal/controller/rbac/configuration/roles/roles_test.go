@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRenderClusterRoles(t *testing.T) {
+	crName := "revised"
+	crUID := types.UID("no-you-id")
+
+	ctrl := true
+	crCtrlr := metav1.OwnerReference{
+		APIVersion: v1.ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       v1.ConfigurationRevisionKind,
+		Name:       crName,
+		UID:        crUID,
+		Controller: &ctrl,
+	}
+
+	nameEdit := namePrefix + crName + nameSuffixEdit
+	nameView := namePrefix + crName + nameSuffixView
+
+	groupXRDA := "example.org"
+	groupXRDB := "example.org"
+	groupXRDC := "example.net"
+
+	pluralXRDA := "xexamples"
+	pluralClaimA := "examples"
+	pluralXRDB := "xdemonstrations"
+	pluralXRDC := "xexamples"
+
+	type args struct {
+		cr   *v1.ConfigurationRevision
+		xrds []xv1.CompositeResourceDefinition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []rbacv1.ClusterRole
+	}{
+		"MergeGroups": {
+			reason: "A ConfigurationRevision should merge XRDs by group to produce the fewest rules possible.",
+			args: args{
+				cr: &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: crName, UID: crUID}},
+				xrds: []xv1.CompositeResourceDefinition{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "c"},
+						Spec: xv1.CompositeResourceDefinitionSpec{
+							Group: groupXRDC,
+							Names: extv1.CustomResourceDefinitionNames{Plural: pluralXRDC},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "a"},
+						Spec: xv1.CompositeResourceDefinitionSpec{
+							Group:      groupXRDA,
+							Names:      extv1.CustomResourceDefinitionNames{Plural: pluralXRDA},
+							ClaimNames: &extv1.CustomResourceDefinitionNames{Plural: pluralClaimA},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "b"},
+						Spec: xv1.CompositeResourceDefinitionSpec{
+							Group: groupXRDB,
+							Names: extv1.CustomResourceDefinitionNames{Plural: pluralXRDB},
+						},
+					},
+				},
+			},
+			want: []rbacv1.ClusterRole{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            nameEdit,
+						OwnerReferences: []metav1.OwnerReference{crCtrlr},
+						Labels: map[string]string{
+							keyAggregateToAdmin:      valTrue,
+							keyAggregateToNSAdmin:    valTrue,
+							keyAggregateToEdit:       valTrue,
+							keyAggregateToNSEdit:     valTrue,
+							keyConfigurationRevision: crName,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{groupXRDC},
+							Resources: []string{pluralXRDC},
+							Verbs:     verbsEdit,
+						},
+						{
+							APIGroups: []string{groupXRDA},
+							Resources: []string{pluralClaimA, pluralXRDB, pluralXRDA},
+							Verbs:     verbsEdit,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            nameView,
+						OwnerReferences: []metav1.OwnerReference{crCtrlr},
+						Labels: map[string]string{
+							keyAggregateToView:       valTrue,
+							keyAggregateToNSView:     valTrue,
+							keyConfigurationRevision: crName,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{groupXRDC},
+							Resources: []string{pluralXRDC},
+							Verbs:     verbsView,
+						},
+						{
+							APIGroups: []string{groupXRDA},
+							Resources: []string{pluralClaimA, pluralXRDB, pluralXRDA},
+							Verbs:     verbsView,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make([]xv1.CompositeResourceDefinition, len(tc.args.xrds))
+			copy(in, tc.args.xrds)
+
+			got := RenderClusterRoles(tc.args.cr, tc.args.xrds)
+			if diff := cmp.Diff(in, tc.args.xrds); diff != "" {
+				t.Errorf("\n%s\nRenderClusterRoles(...): must not modify supplied XRDs: -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRenderClusterRoles(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	rbacv1 "k8s.io/api/rbac/v1"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 )
 
@@ -48,4 +50,14 @@ type Options struct {
 	// permissions may be granted to Providers that request them. The
 	// provider may request any permission that appears in the named role.
 	AllowClusterRole string
+
+	// BindSubjects are additional subjects that should be bound to the
+	// Crossplane ClusterRoles, keyed by ClusterRole name.
+	BindSubjects map[string][]rbacv1.Subject
+
+	// CRDPageSize is how many CustomResourceDefinitions the provider RBAC
+	// controller requests per page when listing them. CRDs are listed from
+	// the API server in pages if it is greater than zero, and from an
+	// informer cache otherwise.
+	CRDPageSize int64
 }
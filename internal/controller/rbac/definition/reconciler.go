@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-cmp/cmp"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,7 +35,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/throttle"
 )
 
 const (
@@ -73,7 +74,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -206,7 +207,5 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 // ClusterRoles. We consider ClusterRoles to be different if their labels and
 // rules do not match.
 func ClusterRolesDiffer(current, desired runtime.Object) bool {
-	c := current.(*rbacv1.ClusterRole)
-	d := desired.(*rbacv1.ClusterRole)
-	return !cmp.Equal(c.GetLabels(), d.GetLabels()) || !cmp.Equal(c.Rules, d.Rules)
+	return clusterrole.Differ(current, desired)
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 )
 
 const (
@@ -168,5 +169,11 @@ func RenderClusterRoles(d *v1.CompositeResourceDefinition) []rbacv1.ClusterRole
 		meta.AddOwnerReference(o, meta.AsController(meta.TypedReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)))
 	}
 
+	// Render rules in canonical order, so that equivalent roles are rendered
+	// identically.
+	for _, cr := range []*rbacv1.ClusterRole{system, edit, view, browse} {
+		cr.Rules = clusterrole.Canonical(cr.Rules)
+	}
+
 	return []rbacv1.ClusterRole{*system, *edit, *view, *browse}
 }
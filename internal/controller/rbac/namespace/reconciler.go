@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,7 +37,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/throttle"
 )
 
 const (
@@ -74,7 +77,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -217,8 +220,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 // RolesDiffer returns true if the supplied objects are different Roles. We
 // consider Roles to be different if their annotations and rules do not match.
+// Rules that differ only in order match, so that we don't make no-op updates.
 func RolesDiffer(current, desired runtime.Object) bool {
 	c := current.(*rbacv1.Role)
 	d := desired.(*rbacv1.Role)
-	return !cmp.Equal(c.GetAnnotations(), d.GetAnnotations()) || !cmp.Equal(c.Rules, d.Rules)
+	return !cmp.Equal(c.GetAnnotations(), d.GetAnnotations(), cmpopts.EquateEmpty()) || !clusterrole.RulesEqual(c.Rules, d.Rules)
 }
@@ -265,6 +265,37 @@ func TestRolesDiffer(t *testing.T) {
 			},
 			want: true,
 		},
+		"RulesReordered": {
+			current: &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"b"}, Resources: []string{"y", "x"}, Verbs: []string{"list", "get"}},
+					{APIGroups: []string{"a"}, Resources: []string{"z"}, Verbs: []string{"*"}},
+				},
+			},
+			desired: &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"a"}, Resources: []string{"z"}, Verbs: []string{"*"}},
+					{APIGroups: []string{"b"}, Resources: []string{"x", "y"}, Verbs: []string{"get", "list"}},
+				},
+			},
+			want: false,
+		},
+		"EmptyAnnotations": {
+			current: &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{},
+				},
+				Rules: []rbacv1.PolicyRule{},
+			},
+			desired: &rbacv1.Role{},
+			want:    false,
+		},
 	}
 
 	for name, tc := range cases {
@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 )
 
 const (
@@ -45,6 +47,11 @@ const (
 
 	keyXRD = keyPrefix + "xrd"
 
+	// Namespace annotations that specify additional aggregation labels.
+	keyAdminLabels = keyPrefix + "ns-admin-aggregation-labels"
+	keyEditLabels  = keyPrefix + "ns-edit-aggregation-labels"
+	keyViewLabels  = keyPrefix + "ns-view-aggregation-labels"
+
 	keyAggregated = "aggregated-by-crossplane"
 
 	valTrue   = "true"
@@ -92,9 +99,10 @@ func RenderRoles(ns *corev1.Namespace, crs []rbacv1.ClusterRole) []rbacv1.Role {
 		}
 	}
 
-	acrs := crSelector{keyAggToAdmin, keyBaseOfAdmin, accepts}
-	ecrs := crSelector{keyAggToEdit, keyBaseOfEdit, accepts}
-	vcrs := crSelector{keyAggToView, keyBaseOfView, accepts}
+	a := ns.GetAnnotations()
+	acrs := crSelector{keyAggToAdmin, keyBaseOfAdmin, accepts, aggregationLabels(a[keyAdminLabels])}
+	ecrs := crSelector{keyAggToEdit, keyBaseOfEdit, accepts, aggregationLabels(a[keyEditLabels])}
+	vcrs := crSelector{keyAggToView, keyBaseOfView, accepts, aggregationLabels(a[keyViewLabels])}
 
 	// TODO(negz): Annotate rendered Roles to indicate which ClusterRoles they
 	// are aggregating rules from? This aggregation is likely to be surprising
@@ -113,18 +121,62 @@ func RenderRoles(ns *corev1.Namespace, crs []rbacv1.ClusterRole) []rbacv1.Role {
 		}
 	}
 
+	// Aggregated rules are rendered in canonical order, so that the order
+	// of the ClusterRoles they're aggregated from doesn't matter.
+	for _, rl := range []*rbacv1.Role{admin, edit, view} {
+		if len(rl.Rules) > 0 {
+			rl.Rules = clusterrole.Canonical(rl.Rules)
+		}
+	}
+
 	return []rbacv1.Role{*admin, *edit, *view}
 }
 
+// aggregationLabels parses a comma separated list of label keys.
+func aggregationLabels(v string) []string {
+	keys := make([]string, 0)
+	for _, k := range strings.Split(v, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// AggregatesFrom returns true if the supplied namespace's annotations specify
+// that any of the supplied labels should be used to aggregate ClusterRoles.
+func AggregatesFrom(ns metav1.Object, labels map[string]string) bool {
+	a := ns.GetAnnotations()
+	for _, k := range []string{keyAdminLabels, keyEditLabels, keyViewLabels} {
+		for _, l := range aggregationLabels(a[k]) {
+			if _, ok := labels[l]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type crSelector struct {
 	keyAgg  string
 	keyBase string
 	accepts map[string]bool
+
+	// Additional aggregation labels. Cluster roles with any of these labels
+	// are selected regardless of the base and XRD labels, allowing a namespace
+	// to opt in to tenant specific permissions.
+	keysAgg []string
 }
 
 func (s crSelector) Select(cr rbacv1.ClusterRole) bool {
 	l := cr.GetLabels()
 
+	for _, k := range s.keysAgg {
+		if l[k] == valTrue {
+			return true
+		}
+	}
+
 	// All cluster roles must have an aggregation key to be selected.
 	if l[s.keyAgg] != valTrue {
 		return false
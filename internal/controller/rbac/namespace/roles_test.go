@@ -33,6 +33,7 @@ func TestCRSelector(t *testing.T) {
 		keyAgg  string
 		keyBase string
 		accepts map[string]bool
+		keysAgg []string
 	}
 
 	cases := map[string]struct {
@@ -104,11 +105,24 @@ func TestCRSelector(t *testing.T) {
 			}}},
 			want: false,
 		},
+		"IsAdditionalAggregationRole": {
+			reason: "ClusterRoles with one of the namespace's additional aggregation labels should be selected",
+			fields: fields{
+				keyAgg:  keyAggToAdmin,
+				keyBase: keyBaseOfAdmin,
+				accepts: map[string]bool{xrdName: true},
+				keysAgg: []string{"example.org/tenant"},
+			},
+			cr: rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				"example.org/tenant": valTrue,
+			}}},
+			want: true,
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			crs := crSelector{tc.fields.keyAgg, tc.fields.keyBase, tc.fields.accepts}
+			crs := crSelector{tc.fields.keyAgg, tc.fields.keyBase, tc.fields.accepts, tc.fields.keysAgg}
 			got := crs.Select(tc.cr)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("crs.Select(...): -want, +got:\n%s\n", diff)
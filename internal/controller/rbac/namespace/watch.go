@@ -36,7 +36,8 @@ type adder interface {
 }
 
 // EnqueueRequestForNamespaces enqueues a reconcile for all namespaces whenever
-// a ClusterRole with the aggregation labels we're concerned with changes. This
+// a ClusterRole with the aggregation labels we're concerned with changes, and
+// for namespaces that opted in to aggregating a ClusterRole's other labels. This
 // is unusual, but we expect there to be relatively few ClusterRoles, and we
 // have no way of relating a specific ClusterRoles back to the Roles that
 // aggregate it. This is the approach the upstream aggregation controller uses.
@@ -76,7 +77,8 @@ func (e *EnqueueRequestForNamespaces) add(obj runtime.Object, queue adder) {
 		return
 	}
 
-	if !aggregates(cr) {
+	// A ClusterRole without labels can't be aggregated.
+	if len(cr.GetLabels()) == 0 {
 		return
 	}
 
@@ -85,8 +87,13 @@ func (e *EnqueueRequestForNamespaces) add(obj runtime.Object, queue adder) {
 		return
 	}
 
-	for _, ns := range l.Items {
-		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: ns.GetName()}})
+	all := aggregates(cr)
+	for i := range l.Items {
+		// Namespaces may opt in to aggregating ClusterRoles with arbitrary
+		// labels, so we also enqueue any that aggregate this ClusterRole.
+		if all || AggregatesFrom(&l.Items[i], cr.GetLabels()) {
+			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: l.Items[i].GetName()}})
+		}
 	}
 
 }
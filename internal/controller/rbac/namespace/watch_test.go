@@ -81,6 +81,25 @@ func TestAdd(t *testing.T) {
 				}
 			}),
 		},
+		"ClusterRoleIsAggregatedByAnnotation": {
+			client: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					nsl := o.(*corev1.NamespaceList)
+					*nsl = corev1.NamespaceList{Items: []corev1.Namespace{
+						{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{keyEditLabels: "example.org/tenant"}}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+					}}
+					return nil
+				}),
+			},
+			obj: &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.org/tenant": valTrue}}},
+			queue: addFn(func(got any) {
+				want := reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("-want, +got:\n%s\n", diff)
+				}
+			}),
+		},
 	}
 
 	for _, tc := range cases {
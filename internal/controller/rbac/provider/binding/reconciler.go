@@ -40,6 +40,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
 	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
+	"github.com/crossplane/crossplane/internal/throttle"
 )
 
 const (
@@ -65,7 +66,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	kindCRD = "CustomResourceDefinition"
+)
+
+// listCRDs returns the CRDs that are owned by the supplied ProviderRevision -
+// i.e. those that it may become the active revision for. Only CRDs labelled
+// with the revision's parent package are listed, unless the revision has no
+// parent package or fewer labelled CRDs are found than the revision
+// established. The latter may happen when the CRDs were established before
+// they were labelled. CRDs are listed in pages of the supplied size, if it is
+// greater than zero. Note that pagination is only supported when reading from
+// the API server, not from an informer cache.
+func listCRDs(ctx context.Context, c client.Reader, pr *v1.ProviderRevision, pageSize int64) ([]extv1.CustomResourceDefinition, error) {
+	pkg, ok := pr.GetLabels()[v1.LabelParentPackage]
+	if !ok {
+		return listOwnedCRDs(ctx, c, pr, pageSize)
+	}
+
+	crds, err := listOwnedCRDs(ctx, c, pr, pageSize, client.MatchingLabels{v1.LabelParentPackage: pkg})
+	if err != nil || len(crds) >= establishedCRDs(pr) {
+		return crds, err
+	}
+	return listOwnedCRDs(ctx, c, pr, pageSize)
+}
+
+func listOwnedCRDs(ctx context.Context, c client.Reader, pr *v1.ProviderRevision, pageSize int64, opts ...client.ListOption) ([]extv1.CustomResourceDefinition, error) {
+	if pageSize > 0 {
+		opts = append(opts, client.Limit(pageSize))
+	}
+
+	crds := make([]extv1.CustomResourceDefinition, 0)
+	cont := ""
+	for {
+		l := &extv1.CustomResourceDefinitionList{}
+		if err := c.List(ctx, l, append(opts, client.Continue(cont))...); err != nil {
+			return nil, err
+		}
+		for _, crd := range l.Items {
+			for _, ref := range crd.GetOwnerReferences() {
+				if ref.UID == pr.GetUID() {
+					crds = append(crds, crd)
+				}
+			}
+		}
+		if cont = l.GetContinue(); cont == "" {
+			return crds, nil
+		}
+	}
+}
+
+// establishedCRDs returns the number of CRDs the supplied ProviderRevision
+// established.
+func establishedCRDs(pr *v1.ProviderRevision) int {
+	n := 0
+	for _, ref := range pr.Status.ObjectRefs {
+		if schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() == extv1.Kind(kindCRD) {
+			n++
+		}
+	}
+	return n
+}
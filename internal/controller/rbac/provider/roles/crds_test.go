@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestListCRDs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	owned := func(name string) extv1.CustomResourceDefinition {
+		return extv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{{UID: "pr-uid"}},
+		}}
+	}
+	other := extv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{
+		Name:            "other",
+		OwnerReferences: []metav1.OwnerReference{{UID: "other-uid"}},
+	}}
+
+	pr := func(lbls map[string]string, refs ...xpv1.TypedReference) *v1.ProviderRevision {
+		return &v1.ProviderRevision{
+			ObjectMeta: metav1.ObjectMeta{UID: "pr-uid", Labels: lbls},
+			Status:     v1.PackageRevisionStatus{ObjectRefs: refs},
+		}
+	}
+	crdRef := xpv1.TypedReference{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "a"}
+	pkgLabel := map[string]string{v1.LabelParentPackage: "provider-cool"}
+
+	// list returns a MockListFn that returns the supplied CRDs. Each CRD is
+	// returned as a page if pages is true. Otherwise all CRDs are returned at
+	// once, filtered by any label selector.
+	list := func(pages bool, crds ...extv1.CustomResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			l := obj.(*extv1.CustomResourceDefinitionList)
+			if !pages {
+				for _, crd := range crds {
+					if lo.LabelSelector == nil || lo.LabelSelector.Matches(labels.Set(crd.GetLabels())) {
+						l.Items = append(l.Items, crd)
+					}
+				}
+				return nil
+			}
+			if lo.Limit != 1 {
+				t.Errorf("List(...): want limit 1, got %d", lo.Limit)
+			}
+			i := 0
+			if lo.Continue != "" {
+				i = int(lo.Continue[0] - '0')
+			}
+			l.Items = []extv1.CustomResourceDefinition{crds[i]}
+			if i+1 < len(crds) {
+				l.Continue = string(rune('0' + i + 1))
+			}
+			return nil
+		}
+	}
+
+	labelled := owned("labelled")
+	labelled.SetLabels(pkgLabel)
+
+	type args struct {
+		c        client.Reader
+		pr       *v1.ProviderRevision
+		pageSize int64
+	}
+	type want struct {
+		crds []string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoParentPackage": {
+			reason: "We should list all CRDs owned by a revision without a parent package.",
+			args: args{
+				c:  &test.MockClient{MockList: list(false, owned("a"), other, labelled)},
+				pr: pr(nil),
+			},
+			want: want{
+				crds: []string{"a", "labelled"},
+			},
+		},
+		"ParentPackage": {
+			reason: "We should only list the CRDs labelled with the parent package of a revision.",
+			args: args{
+				c:  &test.MockClient{MockList: list(false, owned("a"), other, labelled)},
+				pr: pr(pkgLabel),
+			},
+			want: want{
+				crds: []string{"labelled"},
+			},
+		},
+		"ParentPackageUnlabelledCRDs": {
+			reason: "We should list all CRDs if fewer labelled CRDs are found than the revision established.",
+			args: args{
+				c:  &test.MockClient{MockList: list(false, owned("a"), other, labelled)},
+				pr: pr(pkgLabel, crdRef, crdRef),
+			},
+			want: want{
+				crds: []string{"a", "labelled"},
+			},
+		},
+		"Paginated": {
+			reason: "We should list CRDs in pages of the supplied size.",
+			args: args{
+				c:        &test.MockClient{MockList: list(true, owned("a"), other, owned("b"))},
+				pr:       pr(nil),
+				pageSize: 1,
+			},
+			want: want{
+				crds: []string{"a", "b"},
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing CRDs.",
+			args: args{
+				c:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				pr: pr(pkgLabel),
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crds, err := listCRDs(context.Background(), tc.args.c, tc.args.pr, tc.args.pageSize)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nlistCRDs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			var got []string
+			for _, crd := range crds {
+				got = append(got, crd.GetName())
+			}
+			if diff := cmp.Diff(tc.want.crds, got); diff != "" {
+				t.Errorf("\n%s\nlistCRDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"bytes"
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
+)
+
+const errFmtMarshalClusterRole = "cannot marshal ClusterRole %q"
+
+// RenderCanonicalYAML renders the ClusterRoles RenderClusterRoles would
+// produce for the supplied ProviderRevision and CRDs as a stream of YAML
+// documents. The output is canonical: ClusterRoles are sorted by name, their
+// rules are sorted (see clusterrole.Canonical), and the owner references and
+// creation timestamps that vary between control planes are omitted. This makes
+// it suitable for golden file tests that track the RBAC a provider will be
+// granted as it is upgraded.
+func RenderCanonicalYAML(pr *v1.ProviderRevision, crds []extv1.CustomResourceDefinition) ([]byte, error) {
+	roles := RenderClusterRoles(pr, crds)
+	sort.Slice(roles, func(i, j int) bool { return roles[i].GetName() < roles[j].GetName() })
+
+	out := &bytes.Buffer{}
+	for i := range roles {
+		cr := rbacv1.ClusterRole{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRole",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   roles[i].GetName(),
+				Labels: roles[i].GetLabels(),
+			},
+			Rules: clusterrole.Canonical(roles[i].Rules),
+		}
+		b, err := yaml.Marshal(cr)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMarshalClusterRole, cr.GetName())
+		}
+		// ObjectMeta always marshals its creation timestamp, even when it
+		// is unset.
+		b = bytes.Replace(b, []byte("  creationTimestamp: null\n"), nil, 1)
+		out.WriteString("---\n")
+		out.Write(b)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRenderCanonicalYAML(t *testing.T) {
+	pr := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider-example-1234", UID: "some-uid"},
+		Status: v1.PackageRevisionStatus{
+			PermissionRequests: []rbacv1.PolicyRule{{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+				Verbs:     []string{"list", "get"},
+			}},
+		},
+	}
+	crds := []extv1.CustomResourceDefinition{
+		{Spec: extv1.CustomResourceDefinitionSpec{Group: "example.org", Names: extv1.CustomResourceDefinitionNames{Plural: "buckets"}}},
+	}
+
+	want := `---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  labels:
+    rbac.crossplane.io/aggregate-to-admin: "true"
+    rbac.crossplane.io/aggregate-to-crossplane: "true"
+    rbac.crossplane.io/aggregate-to-edit: "true"
+  name: crossplane:provider:provider-example-1234:aggregate-to-edit
+rules:
+- apiGroups:
+  - example.org
+  resources:
+  - buckets
+  - buckets/status
+  verbs:
+  - '*'
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  labels:
+    rbac.crossplane.io/aggregate-to-view: "true"
+  name: crossplane:provider:provider-example-1234:aggregate-to-view
+rules:
+- apiGroups:
+  - example.org
+  resources:
+  - buckets
+  - buckets/status
+  verbs:
+  - get
+  - list
+  - watch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: crossplane:provider:provider-example-1234:system
+rules:
+- apiGroups:
+  - ""
+  - coordination.k8s.io
+  resources:
+  - configmaps
+  - events
+  - leases
+  - secrets
+  verbs:
+  - '*'
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - example.org
+  resources:
+  - buckets
+  - buckets/status
+  verbs:
+  - create
+  - get
+  - list
+  - patch
+  - update
+  - watch
+`
+
+	got, err := RenderCanonicalYAML(pr, crds)
+	if err != nil {
+		t.Fatalf("RenderCanonicalYAML(...): %s", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("RenderCanonicalYAML(...): -want, +got:\n%s", diff)
+	}
+}
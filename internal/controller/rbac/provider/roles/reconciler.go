@@ -21,11 +21,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-cmp/cmp"
 	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -38,7 +38,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/throttle"
 )
 
 const (
@@ -47,6 +49,7 @@ const (
 	errGetPR               = "cannot get ProviderRevision"
 	errListCRDs            = "cannot list CustomResourceDefinitions"
 	errApplyRole           = "cannot apply ClusterRole"
+	errDeleteRole          = "cannot delete ClusterRole"
 	errValidatePermissions = "cannot validate permission requests"
 	errRejectedPermission  = "refusing to apply any RBAC roles due to request for disallowed permission"
 )
@@ -94,10 +97,18 @@ func (fn ClusterRoleRenderFn) RenderClusterRoles(pr *v1.ProviderRevision, crds [
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := "rbac/" + strings.ToLower(v1.ProviderRevisionGroupKind)
 
+	ro := []ReconcilerOption{
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+	}
+	if o.CRDPageSize > 0 {
+		// Informer caches don't support pagination, so we must list CRDs
+		// from the API server in order to list them in pages.
+		ro = append(ro, WithCRDPageSize(mgr.GetAPIReader(), o.CRDPageSize))
+	}
+
 	if o.AllowClusterRole == "" {
-		r := NewReconciler(mgr,
-			WithLogger(o.Logger.WithValues("controller", name)),
-			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		r := NewReconciler(mgr, ro...)
 
 		return ctrl.NewControllerManagedBy(mgr).
 			Named(name).
@@ -111,10 +122,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		client:          mgr.GetClient(),
 		clusterRoleName: o.AllowClusterRole}
 
-	r := NewReconciler(mgr,
-		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		WithPermissionRequestsValidator(NewClusterRoleBackedValidator(mgr.GetClient(), o.AllowClusterRole)))
+	r := NewReconciler(mgr, append(ro, WithPermissionRequestsValidator(NewClusterRoleBackedValidator(mgr.GetClient(), o.AllowClusterRole)))...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -166,6 +174,17 @@ func WithPermissionRequestsValidator(rv PermissionRequestsValidator) ReconcilerO
 	}
 }
 
+// WithCRDPageSize specifies that the Reconciler should list
+// CustomResourceDefinitions using the supplied reader, requesting the supplied
+// number of CustomResourceDefinitions per page. Pagination is only supported
+// when reading from the API server, not from an informer cache.
+func WithCRDPageSize(c client.Reader, n int64) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.crds = c
+		r.crdPageSize = n
+	}
+}
+
 // NewReconciler returns a Reconciler of ProviderRevisions.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
@@ -200,6 +219,9 @@ type Reconciler struct {
 	client resource.ClientApplicator
 	rbac   rbac
 
+	crds        client.Reader
+	crdPageSize int64
+
 	log    logging.Logger
 	record event.Recorder
 }
@@ -237,25 +259,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	l := &extv1.CustomResourceDefinitionList{}
-	if err := r.client.List(ctx, l); err != nil {
+	if pr.GetDesiredState() == v1.PackageRevisionInactive {
+		// Inactive revisions don't run a provider, so they need no RBAC
+		// ClusterRoles. Rendering them anyway would aggregate duplicates of
+		// the active revision's rules, and may conflict with it during an
+		// upgrade. Delete any we created while the revision was active.
+		for _, cr := range r.rbac.RenderClusterRoles(pr, nil) {
+			cr := cr // Pin range variable so we can take its address.
+			if err := r.client.Delete(ctx, &cr); resource.IgnoreNotFound(err) != nil {
+				log.Debug(errDeleteRole, "error", err, "role-name", cr.GetName())
+				err = errors.Wrap(err, errDeleteRole)
+				r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+				return reconcile.Result{}, err
+			}
+		}
+		log.Debug("Deleted RBAC ClusterRoles of inactive revision")
+
+		// There's no need to requeue explicitly - we're watching all PRs.
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	// CRDs are read using our client unless we're configured to list them
+	// in pages using a different reader.
+	var reader client.Reader = r.client
+	if r.crds != nil {
+		reader = r.crds
+	}
+
+	crds, err := listCRDs(ctx, reader, pr, r.crdPageSize)
+	if err != nil {
 		log.Debug(errListCRDs, "error", err)
 		err = errors.Wrap(err, errListCRDs)
 		r.record.Event(pr, event.Warning(reasonApplyRoles, err))
 		return reconcile.Result{}, err
 	}
 
-	// Filter down to the CRDs that are owned by this ProviderRevision - i.e.
-	// those that it may become the active revision for.
-	crds := make([]extv1.CustomResourceDefinition, 0)
-	for _, crd := range l.Items {
-		for _, ref := range crd.GetOwnerReferences() {
-			if ref.UID == pr.GetUID() {
-				crds = append(crds, crd)
-			}
-		}
-	}
-
 	rejected, err := r.rbac.ValidatePermissionRequests(ctx, pr.Status.PermissionRequests...)
 	if err != nil {
 		log.Debug(errValidatePermissions, "error", err)
@@ -308,7 +346,5 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 // ClusterRoles. We consider ClusterRoles to be different if their labels and
 // rules do not match.
 func ClusterRolesDiffer(current, desired runtime.Object) bool {
-	c := current.(*rbacv1.ClusterRole)
-	d := desired.(*rbacv1.ClusterRole)
-	return !cmp.Equal(c.GetLabels(), d.GetLabels()) || !cmp.Equal(c.Rules, d.Rules)
+	return clusterrole.Differ(current, desired)
 }
@@ -112,6 +112,60 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"DeleteInactiveRolesError": {
+			reason: "We should return an error encountered deleting the ClusterRoles of an inactive revision.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.ProviderRevision).SetDesiredState(v1.PackageRevisionInactive)
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ProviderRevision, []extv1.CustomResourceDefinition) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteRole),
+			},
+		},
+		"InactiveRevision": {
+			reason: "We should delete the ClusterRoles of an inactive revision rather than applying them.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.ProviderRevision).SetDesiredState(v1.PackageRevisionInactive)
+								return nil
+							}),
+							MockList: func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+								t.Errorf("List(...): we should not list CRDs for an inactive revision")
+								return nil
+							},
+							MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we should not apply ClusterRoles for an inactive revision")
+							return nil
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ProviderRevision, []extv1.CustomResourceDefinition) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ListCRDsError": {
 			reason: "We should return an error encountered listing CRDs.",
 			args: args{
@@ -129,6 +183,24 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errListCRDs),
 			},
 		},
+		"ListCRDsInPagesError": {
+			reason: "We should return an error encountered listing CRDs in pages using the supplied reader.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(nil),
+						},
+					}),
+					WithCRDPageSize(&test.MockClient{MockList: test.NewMockListFn(errBoom)}, 10),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCRDs),
+			},
+		},
 		"ValidatePermissionRequestsError": {
 			reason: "We should return an error encountered validating permission requests.",
 			args: args{
@@ -26,6 +26,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/clusterrole"
 )
 
 const (
@@ -83,26 +84,11 @@ func RenderClusterRoles(pr *v1.ProviderRevision, crds []extv1.CustomResourceDefi
 	// ensure we don't reorder our RBAC rules on each update.
 	sort.Slice(crds, func(i, j int) bool { return crds[i].GetName() < crds[j].GetName() })
 
-	groups := make([]string, 0)            // Allows deterministic iteration over groups.
-	resources := make(map[string][]string) // Resources by group.
+	g := &clusterrole.GroupedResources{}
 	for _, crd := range crds {
-		if _, ok := resources[crd.Spec.Group]; !ok {
-			resources[crd.Spec.Group] = make([]string, 0)
-			groups = append(groups, crd.Spec.Group)
-		}
-		resources[crd.Spec.Group] = append(resources[crd.Spec.Group],
-			crd.Spec.Names.Plural,
-			crd.Spec.Names.Plural+suffixStatus,
-		)
-	}
-
-	rules := []rbacv1.PolicyRule{}
-	for _, g := range groups {
-		rules = append(rules, rbacv1.PolicyRule{
-			APIGroups: []string{g},
-			Resources: resources[g],
-		})
+		g.Add(crd.Spec.Group, crd.Spec.Names.Plural, crd.Spec.Names.Plural+suffixStatus)
 	}
+	rules := g.Rules()
 
 	edit := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
@@ -120,7 +106,7 @@ func RenderClusterRoles(pr *v1.ProviderRevision, crds []extv1.CustomResourceDefi
 				keyAggregateToEdit: valTrue,
 			},
 		},
-		Rules: withVerbs(rules, verbsEdit),
+		Rules: clusterrole.WithVerbs(rules, verbsEdit),
 	}
 
 	view := &rbacv1.ClusterRole{
@@ -130,29 +116,24 @@ func RenderClusterRoles(pr *v1.ProviderRevision, crds []extv1.CustomResourceDefi
 				keyAggregateToView: valTrue,
 			},
 		},
-		Rules: withVerbs(rules, verbsView),
+		Rules: clusterrole.WithVerbs(rules, verbsView),
 	}
 
 	// The 'system' RBAC role does not aggregate; it is intended to be bound
 	// directly to the service account tha provider runs as.
 	system := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{Name: SystemClusterRoleName(pr.GetName())},
-		Rules:      append(append(withVerbs(rules, verbsSystem), rulesSystemExtra...), pr.Status.PermissionRequests...),
+		Rules:      append(append(clusterrole.WithVerbs(rules, verbsSystem), rulesSystemExtra...), pr.Status.PermissionRequests...),
 	}
 
 	roles := []rbacv1.ClusterRole{*edit, *view, *system}
 	for i := range roles {
 		ref := meta.AsController(meta.TypedReferenceTo(pr, v1.ProviderRevisionGroupVersionKind))
 		roles[i].SetOwnerReferences([]metav1.OwnerReference{ref})
-	}
-	return roles
-}
 
-func withVerbs(r []rbacv1.PolicyRule, verbs []string) []rbacv1.PolicyRule {
-	verbal := make([]rbacv1.PolicyRule, len(r))
-	for i := range r {
-		verbal[i] = r[i]
-		verbal[i].Verbs = verbs
+		// Permission requests may be listed in any order, so we render
+		// rules in canonical order to avoid reordering them on each update.
+		roles[i].Rules = clusterrole.Canonical(roles[i].Rules)
 	}
-	return verbal
+	return roles
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -101,13 +102,13 @@ func TestRenderClusterRoles(t *testing.T) {
 					},
 					Rules: []rbacv1.PolicyRule{
 						{
-							APIGroups: []string{groupCRDA},
-							Resources: []string{pluralCRDA, pluralCRDA + suffixStatus, pluralCRDB, pluralCRDB + suffixStatus},
+							APIGroups: []string{groupCRDC},
+							Resources: []string{pluralCRDC, pluralCRDC + suffixStatus},
 							Verbs:     verbsEdit,
 						},
 						{
-							APIGroups: []string{groupCRDC},
-							Resources: []string{pluralCRDC, pluralCRDC + suffixStatus},
+							APIGroups: []string{groupCRDA},
+							Resources: []string{pluralCRDB, pluralCRDB + suffixStatus, pluralCRDA, pluralCRDA + suffixStatus},
 							Verbs:     verbsEdit,
 						},
 					},
@@ -122,13 +123,13 @@ func TestRenderClusterRoles(t *testing.T) {
 					},
 					Rules: []rbacv1.PolicyRule{
 						{
-							APIGroups: []string{groupCRDA},
-							Resources: []string{pluralCRDA, pluralCRDA + suffixStatus, pluralCRDB, pluralCRDB + suffixStatus},
+							APIGroups: []string{groupCRDC},
+							Resources: []string{pluralCRDC, pluralCRDC + suffixStatus},
 							Verbs:     verbsView,
 						},
 						{
-							APIGroups: []string{groupCRDC},
-							Resources: []string{pluralCRDC, pluralCRDC + suffixStatus},
+							APIGroups: []string{groupCRDA},
+							Resources: []string{pluralCRDB, pluralCRDB + suffixStatus, pluralCRDA, pluralCRDA + suffixStatus},
 							Verbs:     verbsView,
 						},
 					},
@@ -138,18 +139,24 @@ func TestRenderClusterRoles(t *testing.T) {
 						Name:            nameSystem,
 						OwnerReferences: []metav1.OwnerReference{crCtrlr},
 					},
-					Rules: append([]rbacv1.PolicyRule{
+					// Rules are rendered in canonical order.
+					Rules: []rbacv1.PolicyRule{
 						{
-							APIGroups: []string{groupCRDA},
-							Resources: []string{pluralCRDA, pluralCRDA + suffixStatus, pluralCRDB, pluralCRDB + suffixStatus},
-							Verbs:     verbsSystem,
+							APIGroups: []string{"", coordinationv1.GroupName},
+							Resources: []string{pluralConfigmaps, pluralEvents, pluralLeases, pluralSecrets},
+							Verbs:     verbsEdit,
 						},
 						{
 							APIGroups: []string{groupCRDC},
 							Resources: []string{pluralCRDC, pluralCRDC + suffixStatus},
-							Verbs:     verbsSystem,
+							Verbs:     []string{"create", "get", "list", "patch", "update", "watch"},
 						},
-					}, rulesSystemExtra...),
+						{
+							APIGroups: []string{groupCRDA},
+							Resources: []string{pluralCRDB, pluralCRDB + suffixStatus, pluralCRDA, pluralCRDA + suffixStatus},
+							Verbs:     []string{"create", "get", "list", "patch", "update", "watch"},
+						},
+					},
 				},
 			},
 		},
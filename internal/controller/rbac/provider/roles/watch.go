@@ -89,6 +89,10 @@ func (e *EnqueueRequestForAllRevisionsWithRequests) add(obj runtime.Object, queu
 			// requests that were denied may now be approved.
 			continue
 		}
+		if pr.GetDesiredState() == v1.PackageRevisionInactive {
+			// Inactive revisions have no ClusterRoles to approve.
+			continue
+		}
 		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: pr.GetName()}})
 	}
 }
@@ -84,6 +84,13 @@ func TestAdd(t *testing.T) {
 							},
 						},
 						{}, // A ProviderRevision with no permission requests.
+						{
+							// An inactive ProviderRevision.
+							Spec: v1.PackageRevisionSpec{DesiredState: v1.PackageRevisionInactive},
+							Status: v1.PackageRevisionStatus{
+								PermissionRequests: []rbacv1.PolicyRule{{}},
+							},
+						},
 					}
 					return nil
 				}),
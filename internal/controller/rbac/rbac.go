@@ -19,10 +19,12 @@ package rbac
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	configurationroles "github.com/crossplane/crossplane/internal/controller/rbac/configuration/roles"
 	"github.com/crossplane/crossplane/internal/controller/rbac/definition"
 	"github.com/crossplane/crossplane/internal/controller/rbac/namespace"
 	"github.com/crossplane/crossplane/internal/controller/rbac/provider/binding"
 	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
+	"github.com/crossplane/crossplane/internal/controller/rbac/subject"
 
 	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
 )
@@ -33,6 +35,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		definition.Setup,
 		binding.Setup,
 		roles.Setup,
+		configurationroles.Setup,
+		subject.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package subject implements the controller that binds additional subjects to
+// the Crossplane ClusterRoles.
+package subject
+
+import (
+	"context"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/internal/controller/rbac/controller"
+	"github.com/crossplane/crossplane/internal/throttle"
+)
+
+const (
+	timeout = 2 * time.Minute
+
+	errGetRole       = "cannot get ClusterRole"
+	errApplyBinding  = "cannot apply ClusterRoleBinding"
+	errDeleteBinding = "cannot delete ClusterRoleBinding"
+
+	kindClusterRole = "ClusterRole"
+	namePrefix      = "crossplane:subjects:"
+)
+
+// Event reasons.
+const (
+	reasonBind event.Reason = "BindClusterRole"
+)
+
+// The Crossplane ClusterRoles to which additional subjects may be bound.
+const (
+	ClusterRoleAdmin  = "crossplane-admin"
+	ClusterRoleEdit   = "crossplane-edit"
+	ClusterRoleView   = "crossplane-view"
+	ClusterRoleBrowse = "crossplane-browse"
+)
+
+// ClusterRoles to which additional subjects may be bound.
+var ClusterRoles = []string{ClusterRoleAdmin, ClusterRoleEdit, ClusterRoleView, ClusterRoleBrowse}
+
+// BindingName returns the name of the ClusterRoleBinding that binds additional
+// subjects to the supplied ClusterRole.
+func BindingName(role string) string {
+	return namePrefix + role
+}
+
+// Setup adds a controller that reconciles the Crossplane ClusterRoles by
+// binding any additional subjects to them.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "rbac/subjects"
+
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(throttle.NewRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		WithSubjects(o.BindSubjects))
+
+	fns := make([]resource.PredicateFn, len(ClusterRoles))
+	for i, n := range ClusterRoles {
+		fns[i] = resource.IsNamed(n)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&rbacv1.ClusterRole{}, builder.WithPredicates(resource.NewPredicates(resource.AnyOf(fns...)))).
+		Owns(&rbacv1.ClusterRoleBinding{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithClientApplicator specifies how the Reconciler should interact with the
+// Kubernetes API.
+func WithClientApplicator(ca resource.ClientApplicator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = ca
+	}
+}
+
+// WithSubjects specifies the additional subjects that should be bound to each
+// ClusterRole, keyed by ClusterRole name.
+func WithSubjects(s map[string][]rbacv1.Subject) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.subjects = s
+	}
+}
+
+// NewReconciler returns a Reconciler of ClusterRoles.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: resource.ClientApplicator{
+			Client:     mgr.GetClient(),
+			Applicator: resource.NewAPIUpdatingApplicator(mgr.GetClient()),
+		},
+
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler reconciles ClusterRoles.
+type Reconciler struct {
+	client   resource.ClientApplicator
+	subjects map[string][]rbacv1.Subject
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a ClusterRole by creating a ClusterRoleBinding that binds any
+// additional subjects to it, or deleting the ClusterRoleBinding if there are
+// none.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cr := &rbacv1.ClusterRole{}
+	if err := r.client.Get(ctx, req.NamespacedName, cr); err != nil {
+		// In case object is not found, most likely the object was deleted and
+		// then disappeared while the event was in the processing queue. We
+		// don't need to take any action in that case.
+		log.Debug(errGetRole, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetRole)
+	}
+
+	log = log.WithValues(
+		"uid", cr.GetUID(),
+		"version", cr.GetResourceVersion(),
+		"name", cr.GetName(),
+	)
+
+	if meta.WasDeleted(cr) {
+		// There's nothing to do if our ClusterRole is being deleted. Any
+		// ClusterRoleBinding we created will be garbage collected by
+		// Kubernetes.
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	n := BindingName(cr.GetName())
+	subjects := r.subjects[cr.GetName()]
+	log = log.WithValues(
+		"binding-name", n,
+		"subjects", subjects,
+	)
+
+	if len(subjects) == 0 {
+		// Subjects may have been bound before the RBAC manager was reconfigured.
+		rb := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: n}}
+		if err := r.client.Delete(ctx, rb); resource.IgnoreNotFound(err) != nil {
+			log.Debug(errDeleteBinding, "error", err)
+			err = errors.Wrap(err, errDeleteBinding)
+			r.record.Event(cr, event.Warning(reasonBind, err))
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	rb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            n,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, rbacv1.SchemeGroupVersion.WithKind(kindClusterRole)))},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     kindClusterRole,
+			Name:     cr.GetName(),
+		},
+		Subjects: subjects,
+	}
+
+	if err := r.client.Apply(ctx, rb, resource.MustBeControllableBy(cr.GetUID())); err != nil {
+		log.Debug(errApplyBinding, "error", err)
+		err = errors.Wrap(err, errApplyBinding)
+		r.record.Event(cr, event.Warning(reasonBind, err))
+		return reconcile.Result{}, err
+	}
+	log.Debug("Applied ClusterRoleBinding")
+
+	// There's no need to requeue explicitly - we're watching the ClusterRoles
+	// and the ClusterRoleBindings they own.
+	return reconcile.Result{Requeue: false}, nil
+}
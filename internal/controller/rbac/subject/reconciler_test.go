@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subject
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	now := metav1.Now()
+
+	admins := map[string][]rbacv1.Subject{
+		ClusterRoleAdmin: {{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "platform-team"}},
+	}
+
+	// named returns a MockGetFn that gets a ClusterRole with the supplied
+	// name.
+	named := func(name string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o client.Object) error {
+			o.SetName(name)
+			return nil
+		})
+	}
+
+	type args struct {
+		mgr  manager.Manager
+		opts []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ClusterRoleNotFound": {
+			reason: "We should not return an error if the ClusterRole was not found.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetClusterRoleError": {
+			reason: "We should return any other error encountered while getting a ClusterRole.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(errBoom),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRole),
+			},
+		},
+		"ClusterRoleDeleted": {
+			reason: "We should return early if the ClusterRole was deleted.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.SetDeletionTimestamp(&now)
+								return nil
+							}),
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DeleteClusterRoleBindingError": {
+			reason: "We should return an error encountered deleting a ClusterRoleBinding that binds no subjects.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    named(ClusterRoleEdit),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+					}),
+					WithSubjects(admins),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteBinding),
+			},
+		},
+		"NoSubjects": {
+			reason: "We should delete any ClusterRoleBinding if there are no subjects to bind.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:    named(ClusterRoleEdit),
+							MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("Apply(...): we should not apply a ClusterRoleBinding with no subjects")
+							return nil
+						}),
+					}),
+					WithSubjects(admins),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ApplyClusterRoleBindingError": {
+			reason: "We should return an error encountered applying a ClusterRoleBinding.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: named(ClusterRoleAdmin),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					}),
+					WithSubjects(admins),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyBinding),
+			},
+		},
+		"Success": {
+			reason: "We should bind the configured subjects to the ClusterRole.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: named(ClusterRoleAdmin),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							rb := o.(*rbacv1.ClusterRoleBinding)
+							if diff := cmp.Diff(BindingName(ClusterRoleAdmin), rb.GetName()); diff != "" {
+								t.Errorf("Apply(...): -want name, +got name:\n%s", diff)
+							}
+							if diff := cmp.Diff(ClusterRoleAdmin, rb.RoleRef.Name); diff != "" {
+								t.Errorf("Apply(...): -want role, +got role:\n%s", diff)
+							}
+							if diff := cmp.Diff(admins[ClusterRoleAdmin], rb.Subjects); diff != "" {
+								t.Errorf("Apply(...): -want subjects, +got subjects:\n%s", diff)
+							}
+							return nil
+						}),
+					}),
+					WithSubjects(admins),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, append(tc.args.opts, WithLogger(testLog))...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status implements the ControlPlaneStatus controller.
+package status
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/status/v1alpha1"
+)
+
+const (
+	timeout = 1 * time.Minute
+
+	// Some of what we summarize, like the expiry of the webhook certificate,
+	// isn't watched so we periodically resync.
+	resync = 5 * time.Minute
+)
+
+// Error strings.
+const (
+	errGetStatus                  = "cannot get ControlPlaneStatus"
+	errCreateStatus               = "cannot create ControlPlaneStatus"
+	errUpdateStatus               = "cannot update ControlPlaneStatus status"
+	errListProviders              = "cannot list Providers"
+	errListConfigurations         = "cannot list Configurations"
+	errListProviderRevisions      = "cannot list ProviderRevisions"
+	errListConfigurationRevisions = "cannot list ConfigurationRevisions"
+	errListXRDs                   = "cannot list CompositeResourceDefinitions"
+	errGetWebhookSecret           = "cannot get webhook TLS Secret"
+	errDecodeCertificate          = "cannot decode PEM encoded webhook TLS certificate"
+	errParseCertificate           = "cannot parse webhook TLS certificate"
+)
+
+// Messages explaining why the control plane is unavailable.
+const (
+	msgFmtUnhealthyProviders      = "%d of %d providers are unhealthy"
+	msgFmtUnhealthyConfigurations = "%d of %d configurations are unhealthy"
+	msgFmtPendingRevisions        = "%d package revisions are pending"
+	msgFmtUnestablishedXRDs       = "%d of %d composite resource definitions are not established"
+	msgCertificateExpired         = "the webhook TLS certificate has expired"
+)
+
+// Options specific to the ControlPlaneStatus controller.
+type Options struct {
+	controller.Options
+
+	// WebhookTLSSecretRef refers to the Secret containing the TLS certificate
+	// that Crossplane's webhooks are served with, if any.
+	WebhookTLSSecretRef *types.NamespacedName
+}
+
+// Setup adds a controller that maintains the ControlPlaneStatus.
+func Setup(mgr ctrl.Manager, o Options) error {
+	name := "status/" + strings.ToLower(v1alpha1.ControlPlaneStatusGroupKind)
+
+	ro := []ReconcilerOption{WithLogger(o.Logger.WithValues("controller", name))}
+	if o.WebhookTLSSecretRef != nil {
+		ro = append(ro, WithWebhookTLSSecretRef(*o.WebhookTLSSecretRef))
+	}
+	r := NewReconciler(mgr, ro...)
+
+	// Any change to the things we summarize is a change to the singleton.
+	h := handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: v1alpha1.ControlPlaneStatusName}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.ControlPlaneStatus{}).
+		Watches(&source.Kind{Type: &v1.Provider{}}, h).
+		Watches(&source.Kind{Type: &v1.Configuration{}}, h).
+		Watches(&source.Kind{Type: &v1.ProviderRevision{}}, h).
+		Watches(&source.Kind{Type: &v1.ConfigurationRevision{}}, h).
+		Watches(&source.Kind{Type: &extv1.CompositeResourceDefinition{}}, h).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithWebhookTLSSecretRef specifies the Secret containing the TLS certificate
+// that Crossplane's webhooks are served with. The expiry of the certificate is
+// not reported if no Secret is specified.
+func WithWebhookTLSSecretRef(nn types.NamespacedName) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.webhookTLSSecretRef = &nn
+	}
+}
+
+// WithClock specifies how the Reconciler should determine the current time.
+func WithClock(now func() time.Time) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.now = now
+	}
+}
+
+// NewReconciler returns a Reconciler of the ControlPlaneStatus.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		log:    logging.NewNopLogger(),
+		now:    time.Now,
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+
+	return r
+}
+
+// A Reconciler reconciles the ControlPlaneStatus.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+	now    func() time.Time
+
+	webhookTLSSecretRef *types.NamespacedName
+}
+
+// Reconcile the ControlPlaneStatus. The ControlPlaneStatus is a singleton;
+// it's created if it doesn't exist, and any other ControlPlaneStatus is
+// ignored.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	if req.Name != v1alpha1.ControlPlaneStatusName {
+		return reconcile.Result{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s := &v1alpha1.ControlPlaneStatus{}
+	if err := r.client.Get(ctx, req.NamespacedName, s); err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Debug(errGetStatus, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errGetStatus)
+		}
+		s.SetName(v1alpha1.ControlPlaneStatusName)
+		if err := r.client.Create(ctx, s); err != nil {
+			log.Debug(errCreateStatus, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errCreateStatus)
+		}
+	}
+
+	sum, err := r.summarize(ctx)
+	if err != nil {
+		log.Debug("Cannot summarize control plane", "error", err)
+		return reconcile.Result{}, err
+	}
+
+	// Start from our existing conditions so that transition times are only
+	// updated when a condition actually changes.
+	sum.ConditionedStatus = s.Status.ConditionedStatus
+	sum.SetConditions(available(sum, r.now()))
+	s.Status = sum
+
+	return reconcile.Result{RequeueAfter: resync}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+}
+
+func (r *Reconciler) summarize(ctx context.Context) (v1alpha1.ControlPlaneSummary, error) { // nolint:gocyclo
+	sum := v1alpha1.ControlPlaneSummary{}
+
+	pl := &v1.ProviderList{}
+	if err := r.client.List(ctx, pl); err != nil {
+		return sum, errors.Wrap(err, errListProviders)
+	}
+	for i := range pl.Items {
+		count(&sum.Providers, &pl.Items[i])
+	}
+
+	cl := &v1.ConfigurationList{}
+	if err := r.client.List(ctx, cl); err != nil {
+		return sum, errors.Wrap(err, errListConfigurations)
+	}
+	for i := range cl.Items {
+		count(&sum.Configurations, &cl.Items[i])
+	}
+
+	prl := &v1.ProviderRevisionList{}
+	if err := r.client.List(ctx, prl); err != nil {
+		return sum, errors.Wrap(err, errListProviderRevisions)
+	}
+	crl := &v1.ConfigurationRevisionList{}
+	if err := r.client.List(ctx, crl); err != nil {
+		return sum, errors.Wrap(err, errListConfigurationRevisions)
+	}
+	for _, rl := range []v1.PackageRevisionList{prl, crl} {
+		for _, rev := range rl.GetRevisions() {
+			if rev.GetDesiredState() == v1.PackageRevisionActive && !healthy(rev) {
+				sum.PendingRevisions++
+			}
+		}
+	}
+
+	xl := &extv1.CompositeResourceDefinitionList{}
+	if err := r.client.List(ctx, xl); err != nil {
+		return sum, errors.Wrap(err, errListXRDs)
+	}
+	for _, xrd := range xl.Items {
+		sum.CompositeResourceDefinitions.Total++
+		if xrd.Status.GetCondition(extv1.TypeEstablished).Status == corev1.ConditionTrue {
+			sum.CompositeResourceDefinitions.Established++
+		}
+	}
+
+	if r.webhookTLSSecretRef == nil {
+		return sum, nil
+	}
+	exp, err := r.certificateExpiry(ctx, *r.webhookTLSSecretRef)
+	sum.WebhookCertificateExpiry = exp
+	return sum, err
+}
+
+// certificateExpiry returns the expiry of the TLS certificate in the supplied
+// Secret, or nil if the Secret doesn't (yet) contain a certificate.
+func (r *Reconciler) certificateExpiry(ctx context.Context, nn types.NamespacedName) (*metav1.Time, error) {
+	s := &corev1.Secret{}
+	if err := r.client.Get(ctx, nn, s); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetWebhookSecret)
+	}
+	if len(s.Data[corev1.TLSCertKey]) == 0 {
+		return nil, nil
+	}
+	b, _ := pem.Decode(s.Data[corev1.TLSCertKey])
+	if b == nil {
+		return nil, errors.New(errDecodeCertificate)
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseCertificate)
+	}
+	t := metav1.NewTime(c.NotAfter)
+	return &t, nil
+}
+
+// available returns the Ready condition of a control plane with the supplied
+// summary at the supplied time.
+func available(sum v1alpha1.ControlPlaneSummary, now time.Time) xpv1.Condition {
+	var msgs []string
+	if p := sum.Providers; p.Unhealthy > 0 {
+		msgs = append(msgs, fmt.Sprintf(msgFmtUnhealthyProviders, p.Unhealthy, p.Total))
+	}
+	if c := sum.Configurations; c.Unhealthy > 0 {
+		msgs = append(msgs, fmt.Sprintf(msgFmtUnhealthyConfigurations, c.Unhealthy, c.Total))
+	}
+	if sum.PendingRevisions > 0 {
+		msgs = append(msgs, fmt.Sprintf(msgFmtPendingRevisions, sum.PendingRevisions))
+	}
+	if x := sum.CompositeResourceDefinitions; x.Established < x.Total {
+		msgs = append(msgs, fmt.Sprintf(msgFmtUnestablishedXRDs, x.Total-x.Established, x.Total))
+	}
+	if exp := sum.WebhookCertificateExpiry; exp != nil && !now.Before(exp.Time) {
+		msgs = append(msgs, msgCertificateExpired)
+	}
+	if len(msgs) == 0 {
+		return xpv1.Available()
+	}
+	return xpv1.Unavailable().WithMessage(strings.Join(msgs, "; "))
+}
+
+// count the supplied package in the supplied summary.
+func count(s *v1alpha1.PackageSummary, p v1.Package) {
+	s.Total++
+	if healthy(p) {
+		s.Healthy++
+		return
+	}
+	s.Unhealthy++
+}
+
+type conditioned interface {
+	GetCondition(xpv1.ConditionType) xpv1.Condition
+}
+
+func healthy(o conditioned) bool {
+	return o.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/status/v1alpha1"
+)
+
+// certificate returns a PEM encoded self-signed certificate that expires at
+// the supplied time.
+func certificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notAfter.Add(-time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	expiry := now.Add(24 * time.Hour).Truncate(time.Second)
+	crt := certificate(t, expiry)
+	secret := types.NamespacedName{Namespace: "crossplane-system", Name: "webhook-tls-secret"}
+	singleton := reconcile.Request{NamespacedName: types.NamespacedName{Name: v1alpha1.ControlPlaneStatusName}}
+
+	healthy := func() xpv1.Condition { return v1.Healthy() }
+	unhealthy := func() xpv1.Condition { return v1.Unhealthy() }
+
+	// withPackages returns a MockListFn that lists two healthy providers, an
+	// unhealthy configuration, a healthy and a pending revision, and an
+	// established and a pending XRD.
+	withPackages := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1.ProviderList:
+			l.Items = []v1.Provider{{}, {}}
+			l.Items[0].Status.SetConditions(healthy())
+			l.Items[1].SetConditions(healthy())
+		case *v1.ConfigurationList:
+			l.Items = []v1.Configuration{{}}
+			l.Items[0].Status.SetConditions(unhealthy())
+		case *v1.ProviderRevisionList:
+			l.Items = []v1.ProviderRevision{{}, {}}
+			l.Items[0].SetDesiredState(v1.PackageRevisionActive)
+			l.Items[0].Status.SetConditions(healthy())
+			l.Items[1].SetDesiredState(v1.PackageRevisionActive)
+		case *v1.ConfigurationRevisionList:
+			l.Items = []v1.ConfigurationRevision{{}}
+			l.Items[0].SetDesiredState(v1.PackageRevisionInactive)
+		case *extv1.CompositeResourceDefinitionList:
+			l.Items = []extv1.CompositeResourceDefinition{{}, {}}
+			l.Items[0].Status.SetConditions(extv1.WatchingComposite())
+		}
+		return nil
+	}
+
+	type args struct {
+		client client.Client
+		req    reconcile.Request
+		opts   []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotSingleton": {
+			reason: "We should ignore any ControlPlaneStatus other than the singleton.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				req:    reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool-status"}},
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the ControlPlaneStatus.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				req:    singleton,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetStatus),
+			},
+		},
+		"CreateError": {
+			reason: "We should return any error encountered creating the ControlPlaneStatus.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				req: singleton,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateStatus),
+			},
+		},
+		"ListProvidersError": {
+			reason: "We should return any error encountered listing Providers.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(errBoom),
+				},
+				req: singleton,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListProviders),
+			},
+		},
+		"GetWebhookSecretError": {
+			reason: "We should return any error encountered getting the webhook TLS Secret.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if _, ok := obj.(*corev1.Secret); ok {
+							return errBoom
+						}
+						return nil
+					},
+					MockList: withPackages,
+				},
+				req:  singleton,
+				opts: []ReconcilerOption{WithWebhookTLSSecretRef(secret)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetWebhookSecret),
+			},
+		},
+		"UpdateStatusError": {
+			reason: "We should return any error encountered updating the status of the ControlPlaneStatus.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockList:         test.NewMockListFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				req: singleton,
+			},
+			want: want{
+				r:   reconcile.Result{RequeueAfter: resync},
+				err: errors.Wrap(errBoom, errUpdateStatus),
+			},
+		},
+		"Available": {
+			reason: "We should create the ControlPlaneStatus if it doesn't exist, and report that an empty control plane is available.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockList:   test.NewMockListFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						want := &v1alpha1.ControlPlaneStatus{}
+						want.SetName(v1alpha1.ControlPlaneStatusName)
+						want.SetConditions(xpv1.Available())
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("StatusUpdate(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				req: singleton,
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: resync},
+			},
+		},
+		"Unavailable": {
+			reason: "We should summarize the health of the control plane, and report why it is unavailable.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok {
+							s.Data = map[string][]byte{corev1.TLSCertKey: crt}
+						}
+						return nil
+					},
+					MockList: withPackages,
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						exp := metav1.NewTime(expiry)
+						want := &v1alpha1.ControlPlaneStatus{
+							Status: v1alpha1.ControlPlaneSummary{
+								Providers:                    v1alpha1.PackageSummary{Total: 2, Healthy: 2},
+								Configurations:               v1alpha1.PackageSummary{Total: 1, Unhealthy: 1},
+								CompositeResourceDefinitions: v1alpha1.DefinitionSummary{Total: 2, Established: 1},
+								PendingRevisions:             1,
+								WebhookCertificateExpiry:     &exp,
+							},
+						}
+						want.SetConditions(xpv1.Unavailable().WithMessage("1 of 1 configurations are unhealthy; 1 package revisions are pending; 1 of 2 composite resource definitions are not established"))
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("StatusUpdate(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				req: singleton,
+				opts: []ReconcilerOption{
+					WithWebhookTLSSecretRef(secret),
+					WithClock(func() time.Time { return now }),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: resync},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(&fake.Manager{Client: tc.args.client}, tc.args.opts...)
+			got, err := r.Reconcile(context.Background(), tc.args.req)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	now := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	expired := metav1.NewTime(now.Add(-time.Minute))
+
+	cases := map[string]struct {
+		reason string
+		sum    v1alpha1.ControlPlaneSummary
+		want   xpv1.Condition
+	}{
+		"Healthy": {
+			reason: "A control plane with only healthy packages and established XRDs should be available.",
+			sum: v1alpha1.ControlPlaneSummary{
+				Providers:                    v1alpha1.PackageSummary{Total: 1, Healthy: 1},
+				CompositeResourceDefinitions: v1alpha1.DefinitionSummary{Total: 1, Established: 1},
+			},
+			want: xpv1.Available(),
+		},
+		"UnhealthyProviders": {
+			reason: "A control plane with unhealthy providers should be unavailable.",
+			sum: v1alpha1.ControlPlaneSummary{
+				Providers: v1alpha1.PackageSummary{Total: 2, Healthy: 1, Unhealthy: 1},
+			},
+			want: xpv1.Unavailable().WithMessage("1 of 2 providers are unhealthy"),
+		},
+		"CertificateExpired": {
+			reason: "A control plane whose webhook certificate has expired should be unavailable.",
+			sum: v1alpha1.ControlPlaneSummary{
+				WebhookCertificateExpiry: &expired,
+			},
+			want: xpv1.Unavailable().WithMessage(msgCertificateExpired),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := available(tc.sum, now)
+			if diff := cmp.Diff(tc.want, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\navailable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
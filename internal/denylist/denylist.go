@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package denylist determines which kinds of resource Compositions may not
+// compose.
+package denylist
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// AnyKind matches any kind of resource in a group.
+const AnyKind = "*"
+
+const errFmtNoKind = "%q does not specify a kind"
+
+// A Rule denies composing a kind of resource.
+type Rule struct {
+	// Namespace in which the kind of resource may not be composed. The kind
+	// may not be composed in any namespace if empty.
+	Namespace string
+
+	// GroupKind that may not be composed. A Kind of AnyKind matches any kind
+	// of resource in the group.
+	schema.GroupKind
+}
+
+// Denies returns true if the Rule denies composing the supplied kind of
+// resource in the supplied namespace.
+func (r Rule) Denies(gk schema.GroupKind, namespace string) bool {
+	if r.Group != gk.Group {
+		return false
+	}
+	if r.Kind != AnyKind && r.Kind != gk.Kind {
+		return false
+	}
+	return r.Namespace == "" || r.Namespace == namespace
+}
+
+// String returns the Rule in the form it was parsed from.
+func (r Rule) String() string {
+	if r.Namespace == "" {
+		return r.GroupKind.String()
+	}
+	return r.Namespace + "/" + r.GroupKind.String()
+}
+
+// A List of Rules. A kind of resource may not be composed if any Rule denies
+// it. An empty List denies nothing.
+type List []Rule
+
+// Parse a List from the supplied rules, each of the form
+// [NAMESPACE/]KIND[.GROUP]. For example "Secret", "kube-system/Secret",
+// "ClusterRole.rbac.authorization.k8s.io", or "*.rbac.authorization.k8s.io".
+func Parse(rules []string) (List, error) {
+	l := make(List, 0, len(rules))
+	for _, s := range rules {
+		r := Rule{}
+		gk := s
+		if ns, rest, ok := strings.Cut(s, "/"); ok {
+			r.Namespace, gk = ns, rest
+		}
+		r.GroupKind = schema.ParseGroupKind(gk)
+		if r.Kind == "" {
+			return nil, errors.Errorf(errFmtNoKind, s)
+		}
+		l = append(l, r)
+	}
+	return l, nil
+}
+
+// Denies returns the first Rule that denies composing the supplied kind of
+// resource in the supplied namespace, if any.
+func (l List) Denies(gk schema.GroupKind, namespace string) (Rule, bool) {
+	for _, r := range l {
+		if r.Denies(gk, namespace) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
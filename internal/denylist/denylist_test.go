@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package denylist
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParse(t *testing.T) {
+	type want struct {
+		l   List
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		rules  []string
+		want   want
+	}{
+		"Empty": {
+			reason: "No rules should parse to an empty list.",
+			want:   want{l: List{}},
+		},
+		"Rules": {
+			reason: "Rules with and without namespaces and groups should be parsed.",
+			rules:  []string{"Secret", "kube-system/Secret", "ClusterRole.rbac.authorization.k8s.io", "*.rbac.authorization.k8s.io"},
+			want: want{l: List{
+				{GroupKind: schema.GroupKind{Kind: "Secret"}},
+				{Namespace: "kube-system", GroupKind: schema.GroupKind{Kind: "Secret"}},
+				{GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}},
+				{GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: AnyKind}},
+			}},
+		},
+		"NoKind": {
+			reason: "A rule without a kind should return an error.",
+			rules:  []string{"kube-system/.apps"},
+			want:   want{err: errors.Errorf(errFmtNoKind, "kube-system/.apps")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.rules)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.l, got); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDenies(t *testing.T) {
+	l, _ := Parse([]string{"kube-system/Secret", "*.rbac.authorization.k8s.io", "Deployment.apps"})
+
+	type args struct {
+		gk        schema.GroupKind
+		namespace string
+	}
+	type want struct {
+		r      Rule
+		denied bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NamespacedRuleMatch": {
+			reason: "A namespaced rule should deny its kind in its namespace.",
+			args:   args{gk: schema.GroupKind{Kind: "Secret"}, namespace: "kube-system"},
+			want:   want{r: l[0], denied: true},
+		},
+		"NamespacedRuleOtherNamespace": {
+			reason: "A namespaced rule should not deny its kind in other namespaces.",
+			args:   args{gk: schema.GroupKind{Kind: "Secret"}, namespace: "default"},
+			want:   want{},
+		},
+		"AnyKind": {
+			reason: "A rule for any kind should deny every kind in its group.",
+			args:   args{gk: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}, namespace: "default"},
+			want:   want{r: l[1], denied: true},
+		},
+		"OtherGroup": {
+			reason: "A rule should not deny a kind of the same name in another group.",
+			args:   args{gk: schema.GroupKind{Group: "example.org", Kind: "Deployment"}},
+			want:   want{},
+		},
+		"ClusterRule": {
+			reason: "A rule without a namespace should deny its kind in any namespace.",
+			args:   args{gk: schema.GroupKind{Group: "apps", Kind: "Deployment"}, namespace: "default"},
+			want:   want{r: l[2], denied: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, denied := l.Denies(tc.args.gk, tc.args.namespace)
+			if diff := cmp.Diff(tc.want.denied, denied); diff != "" {
+				t.Errorf("\n%s\nDenies(...): -want denied, +got denied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, r); diff != "" {
+				t.Errorf("\n%s\nDenies(...): -want rule, +got rule:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package describe describes how Crossplane composes a composite resource.
+package describe
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	xr "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+const (
+	errGetClaim               = "cannot get claim"
+	errGetComposite           = "cannot get composite resource"
+	errGetComposition         = "cannot get composition"
+	errGetCompositionRevision = "cannot get composition revision"
+	errExpandPatchSets        = "cannot expand patch sets"
+	errClaimNotBound          = "claim is not yet bound to a composite resource"
+	errNoComposition          = "composite resource has not yet selected a composition"
+)
+
+// Composite returns the composite resource of the supplied kind and name. If a
+// namespace is supplied the kind and name are assumed to be those of a claim,
+// and the composite resource the claim is bound to is returned.
+func Composite(ctx context.Context, c client.Reader, gvk schema.GroupVersionKind, name, namespace string) (*composite.Unstructured, error) {
+	if namespace == "" {
+		cr := composite.New(composite.WithGroupVersionKind(gvk))
+		err := c.Get(ctx, types.NamespacedName{Name: name}, cr)
+		return cr, errors.Wrap(err, errGetComposite)
+	}
+
+	cm := claim.New(claim.WithGroupVersionKind(gvk))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cm); err != nil {
+		return nil, errors.Wrap(err, errGetClaim)
+	}
+	ref := cm.GetResourceReference()
+	if ref == nil {
+		return nil, errors.New(errClaimNotBound)
+	}
+	cr := composite.New(composite.WithGroupVersionKind(ref.GroupVersionKind()))
+	err := c.Get(ctx, meta.NamespacedNameOf(ref), cr)
+	return cr, errors.Wrap(err, errGetComposite)
+}
+
+// EffectiveComposition returns the Composition the supplied composite resource
+// is composed by, with any patch sets expanded into the patches of each
+// composed resource template. The CompositionRevision the composite resource
+// uses is returned (as a Composition) if it references one, so that the
+// returned Composition is exactly what Crossplane composes resources with.
+func EffectiveComposition(ctx context.Context, c client.Reader, cr resource.Composite) (*v1.Composition, error) {
+	var comp *v1.Composition
+	switch {
+	case cr.GetCompositionRevisionReference() != nil:
+		rev := &v1alpha1.CompositionRevision{}
+		if err := c.Get(ctx, meta.NamespacedNameOf(cr.GetCompositionRevisionReference()), rev); err != nil {
+			return nil, errors.Wrap(err, errGetCompositionRevision)
+		}
+		// Keep the revision's metadata, which records the Composition and
+		// revision number it was created from.
+		comp = xr.AsComposition(rev)
+		comp.ObjectMeta = *rev.ObjectMeta.DeepCopy()
+	case cr.GetCompositionReference() != nil:
+		comp = &v1.Composition{}
+		if err := c.Get(ctx, meta.NamespacedNameOf(cr.GetCompositionReference()), comp); err != nil {
+			return nil, errors.Wrap(err, errGetComposition)
+		}
+	default:
+		return nil, errors.New(errNoComposition)
+	}
+
+	ct, err := comp.Spec.ComposedTemplates()
+	if err != nil {
+		return nil, errors.Wrap(err, errExpandPatchSets)
+	}
+	comp.Spec.Resources = ct
+	comp.Spec.PatchSets = nil
+
+	// Managed fields are noise to someone trying to understand what a
+	// Composition does.
+	comp.SetManagedFields(nil)
+	comp.SetGroupVersionKind(v1.CompositionGroupVersionKind)
+	return comp, nil
+}
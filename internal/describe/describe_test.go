@@ -0,0 +1,273 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+func TestComposite(t *testing.T) {
+	errBoom := errors.New("boom")
+	xrGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}
+	claimGVK := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"}
+
+	type args struct {
+		c         client.Reader
+		gvk       schema.GroupVersionKind
+		name      string
+		namespace string
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Composite": {
+			reason: "We should get the named composite resource when no namespace is supplied.",
+			args: args{
+				c: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Namespace != "" || obj.GetObjectKind().GroupVersionKind() != xrGVK {
+						t.Errorf("Get(...): unexpected %s %s", obj.GetObjectKind().GroupVersionKind(), key)
+					}
+					obj.SetName(key.Name)
+					return nil
+				}},
+				gvk:  xrGVK,
+				name: "cool-xr",
+			},
+			want: want{
+				name: "cool-xr",
+			},
+		},
+		"GetCompositeError": {
+			reason: "We should return any error encountered getting a composite resource.",
+			args: args{
+				c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				gvk:  xrGVK,
+				name: "cool-xr",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposite),
+			},
+		},
+		"Claim": {
+			reason: "We should get the composite resource a claim is bound to when a namespace is supplied.",
+			args: args{
+				c: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *claim.Unstructured:
+						o.SetResourceReference(&corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XDatabase", Name: "cool-xr"})
+					case *composite.Unstructured:
+						if o.GroupVersionKind() != xrGVK {
+							t.Errorf("Get(...): unexpected %s", o.GroupVersionKind())
+						}
+						o.SetName(key.Name)
+					}
+					return nil
+				}},
+				gvk:       claimGVK,
+				name:      "cool-claim",
+				namespace: "default",
+			},
+			want: want{
+				name: "cool-xr",
+			},
+		},
+		"ClaimNotBound": {
+			reason: "We should return an error if a claim is not yet bound to a composite resource.",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				gvk:       claimGVK,
+				name:      "cool-claim",
+				namespace: "default",
+			},
+			want: want{
+				err: errors.New(errClaimNotBound),
+			},
+		},
+		"GetClaimError": {
+			reason: "We should return any error encountered getting a claim.",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				gvk:       claimGVK,
+				name:      "cool-claim",
+				namespace: "default",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetClaim),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr, err := Composite(context.Background(), tc.args.c, tc.args.gvk, tc.args.name, tc.args.namespace)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nComposite(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.name, cr.GetName()); diff != "" {
+				t.Errorf("\n%s\nComposite(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEffectiveComposition(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func(comp, rev string) resource.Composite {
+		cr := composite.New()
+		if comp != "" {
+			cr.SetCompositionReference(&corev1.ObjectReference{Name: comp})
+		}
+		if rev != "" {
+			cr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: rev})
+		}
+		return cr
+	}
+
+	setA := v1.Patch{FromFieldPath: pointer.String("spec.a")}
+	setB := v1.Patch{FromFieldPath: pointer.String("spec.b")}
+	own := v1.Patch{FromFieldPath: pointer.String("spec.own")}
+	ref := v1.Patch{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("set")}
+
+	type want struct {
+		comp *v1.Composition
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		cr     resource.Composite
+		want   want
+	}{
+		"NoComposition": {
+			reason: "We should return an error if the composite resource has not selected a composition.",
+			cr:     xr("", ""),
+			want: want{
+				err: errors.New(errNoComposition),
+			},
+		},
+		"GetCompositionError": {
+			reason: "We should return any error encountered getting a composition.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     xr("cool-comp", ""),
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposition),
+			},
+		},
+		"GetCompositionRevisionError": {
+			reason: "We should return any error encountered getting a composition revision.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     xr("cool-comp", "cool-comp-1"),
+			want: want{
+				err: errors.Wrap(errBoom, errGetCompositionRevision),
+			},
+		},
+		"ExpandPatchSetsError": {
+			reason: "We should return any error encountered expanding patch sets.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*v1.Composition).Spec.Resources = []v1.ComposedTemplate{{Patches: []v1.Patch{ref}}}
+				return nil
+			})},
+			cr: xr("cool-comp", ""),
+			want: want{
+				err: errors.Wrap(errors.Errorf("cannot find PatchSet by name %s", "set"), errExpandPatchSets),
+			},
+		},
+		"Composition": {
+			reason: "We should return the composition with its patch sets expanded.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				c := obj.(*v1.Composition)
+				c.SetName("cool-comp")
+				c.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+				c.Spec.PatchSets = []v1.PatchSet{{Name: "set", Patches: []v1.Patch{setA, setB}}}
+				c.Spec.Resources = []v1.ComposedTemplate{{Patches: []v1.Patch{own, ref}}}
+				return nil
+			})},
+			cr: xr("cool-comp", ""),
+			want: want{
+				comp: &v1.Composition{
+					TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositionKind},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-comp"},
+					Spec: v1.CompositionSpec{
+						Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{own, setA, setB}}},
+					},
+				},
+			},
+		},
+		"CompositionRevision": {
+			reason: "We should return the composition revision the composite resource uses, with its patch sets expanded.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				r := obj.(*v1alpha1.CompositionRevision)
+				r.SetName("cool-comp-1")
+				r.Spec.PatchSets = []v1alpha1.PatchSet{{Name: "set", Patches: []v1alpha1.Patch{{FromFieldPath: pointer.String("spec.a")}}}}
+				r.Spec.Resources = []v1alpha1.ComposedTemplate{{Patches: []v1alpha1.Patch{{Type: v1alpha1.PatchTypePatchSet, PatchSetName: pointer.String("set")}}}}
+				return nil
+			})},
+			cr: xr("cool-comp", "cool-comp-1"),
+			want: want{
+				comp: &v1.Composition{
+					TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositionKind},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-comp-1"},
+					Spec: v1.CompositionSpec{
+						Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{setA}}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comp, err := EffectiveComposition(context.Background(), tc.c, tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEffectiveComposition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.comp, comp, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nEffectiveComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export exports the packages and APIs installed in a Crossplane
+// control plane as manifests that may be applied to another control plane.
+package export
+
+import (
+	"context"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errFmtList    = "cannot list %s"
+	errFmtMarshal = "cannot marshal %s %q"
+)
+
+// The annotation kubectl uses to record the configuration it last applied.
+const annotationLastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Kinds of resource that are exported, in the order in which they should be
+// applied. ControllerConfigs come first because Providers may reference them,
+// and XRDs come before the Compositions that compose their composite
+// resources.
+var Kinds = []schema.GroupVersionKind{
+	v1alpha1.ControllerConfigGroupVersionKind,
+	pkgv1.ProviderGroupVersionKind,
+	pkgv1.ConfigurationGroupVersionKind,
+	extv1.CompositeResourceDefinitionGroupVersionKind,
+	extv1.CompositionGroupVersionKind,
+}
+
+// SnapshotKinds of resource that make up the package manager's state, in the
+// order in which they should be restored. Package revisions are restored
+// before their packages so that each package adopts its existing revisions,
+// rather than creating new ones.
+var SnapshotKinds = []schema.GroupVersionKind{
+	v1beta1.LockGroupVersionKind,
+	pkgv1.ProviderRevisionGroupVersionKind,
+	pkgv1.ConfigurationRevisionGroupVersionKind,
+	pkgv1.ProviderGroupVersionKind,
+	pkgv1.ConfigurationGroupVersionKind,
+}
+
+// Objects returns all exportable resources in the control plane, in the order
+// in which they should be applied. Resources that were installed by a package
+// are omitted, because installing the package will install them again. Each
+// returned resource is cleaned; see Clean.
+func Objects(ctx context.Context, c client.Reader) ([]*unstructured.Unstructured, error) {
+	out := make([]*unstructured.Unstructured, 0)
+	for _, gvk := range Kinds {
+		l, err := list(ctx, c, gvk)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range l {
+			if InstalledByPackage(u) {
+				continue
+			}
+			Clean(u)
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// Snapshot returns the package manager's state - its Lock, package revisions,
+// and packages - in the order in which it should be restored. Each package is
+// annotated with its current source, revision, and digest, so that Crossplane
+// can restore it to the same revision in restore mode. Each returned resource
+// is cleaned; see Clean.
+func Snapshot(ctx context.Context, c client.Reader) ([]*unstructured.Unstructured, error) {
+	out := make([]*unstructured.Unstructured, 0)
+	for _, gvk := range SnapshotKinds {
+		l, err := list(ctx, c, gvk)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range l {
+			if gvk == pkgv1.ProviderGroupVersionKind || gvk == pkgv1.ConfigurationGroupVersionKind {
+				annotateSnapshot(u)
+			}
+			Clean(u)
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func list(ctx context.Context, c client.Reader, gvk schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, l); err != nil {
+		return nil, errors.Wrapf(err, errFmtList, gvk.Kind)
+	}
+	out := make([]*unstructured.Unstructured, len(l.Items))
+	for i := range l.Items {
+		out[i] = &l.Items[i]
+		out[i].SetGroupVersionKind(gvk)
+	}
+	return out, nil
+}
+
+// annotateSnapshot records the supplied package's current source, revision,
+// and digest, which are otherwise lost when its status is cleaned.
+func annotateSnapshot(u *unstructured.Unstructured) {
+	rev, _, _ := unstructured.NestedString(u.Object, "status", "currentRevision")
+	if rev == "" {
+		return
+	}
+	src, _, _ := unstructured.NestedString(u.Object, "status", "currentIdentifier")
+	digest, _, _ := unstructured.NestedString(u.Object, "status", "currentDigest")
+	a := u.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[pkgv1.AnnotationSnapshotSource] = src
+	a[pkgv1.AnnotationSnapshotRevision] = rev
+	a[pkgv1.AnnotationSnapshotDigest] = digest
+	u.SetAnnotations(a)
+}
+
+// InstalledByPackage returns true if the supplied resource is controlled by a
+// package revision, for example because it is a Composition that was
+// installed by a Configuration.
+func InstalledByPackage(u *unstructured.Unstructured) bool {
+	for _, ref := range u.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if gv.Group == pkgv1.Group && ref.Controller != nil && *ref.Controller {
+			return true
+		}
+	}
+	return false
+}
+
+// Clean strips the supplied resource of its status, and of the metadata that
+// is set by the API server or that is specific to the control plane it was read
+// from, so that it may be applied to another control plane.
+func Clean(u *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(u.Object, "metadata", "uid")
+	unstructured.RemoveNestedField(u.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(u.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "metadata", "selfLink")
+	unstructured.RemoveNestedField(u.Object, "metadata", "ownerReferences")
+	unstructured.RemoveNestedField(u.Object, "metadata", "annotations", annotationLastApplied)
+	if len(u.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(u.Object, "metadata", "annotations")
+	}
+}
+
+// Write writes the supplied resources to the supplied writer as a stream of
+// YAML documents.
+func Write(w io.Writer, objs []*unstructured.Unstructured) error {
+	for _, u := range objs {
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return errors.Wrapf(err, errFmtMarshal, u.GetKind(), u.GetName())
+		}
+		if _, err := w.Write(append([]byte("---\n"), b...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
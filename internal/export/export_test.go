@@ -0,0 +1,249 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestObjects(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	provider := func() unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":            "provider-aws",
+				"uid":             "some-uid",
+				"resourceVersion": "42",
+				"managedFields":   []interface{}{map[string]interface{}{"manager": "kubectl"}},
+				"annotations": map[string]interface{}{
+					annotationLastApplied: "{}",
+				},
+			},
+			"spec":   map[string]interface{}{"package": "xpkg.upbound.io/crossplane/provider-aws:v0.30.0"},
+			"status": map[string]interface{}{"currentRevision": "provider-aws-123"},
+		}}
+	}
+	installed := func() unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "installed",
+				"ownerReferences": []interface{}{map[string]interface{}{
+					"apiVersion": pkgv1.ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
+					"kind":       pkgv1.ConfigurationRevisionKind,
+					"name":       "getting-started-123",
+					"uid":        "some-uid",
+					"controller": true,
+				}},
+			},
+		}}
+	}
+	authored := func() unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "authored"},
+			"spec":     map[string]interface{}{"compositeTypeRef": map[string]interface{}{"kind": "XDatabase"}},
+		}}
+	}
+
+	type want struct {
+		objs []*unstructured.Unstructured
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing resources.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, "ControllerConfig"),
+			},
+		},
+		"Success": {
+			reason: "We should return cleaned resources of each kind, omitting those installed by packages.",
+			c: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+				l := obj.(*unstructured.UnstructuredList)
+				switch l.GetKind() {
+				case pkgv1.ProviderKind + "List":
+					l.Items = []unstructured.Unstructured{provider()}
+				case extv1.CompositionKind + "List":
+					l.Items = []unstructured.Unstructured{installed(), authored()}
+				}
+				return nil
+			})},
+			want: want{
+				objs: []*unstructured.Unstructured{
+					{Object: map[string]interface{}{
+						"apiVersion": pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+						"kind":       pkgv1.ProviderKind,
+						"metadata":   map[string]interface{}{"name": "provider-aws"},
+						"spec":       map[string]interface{}{"package": "xpkg.upbound.io/crossplane/provider-aws:v0.30.0"},
+					}},
+					{Object: map[string]interface{}{
+						"apiVersion": extv1.CompositionGroupVersionKind.GroupVersion().String(),
+						"kind":       extv1.CompositionKind,
+						"metadata":   map[string]interface{}{"name": "authored"},
+						"spec":       map[string]interface{}{"compositeTypeRef": map[string]interface{}{"kind": "XDatabase"}},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Objects(context.Background(), tc.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObjects(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\n%s\nObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		objs []*unstructured.Unstructured
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing resources.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, "Lock"),
+			},
+		},
+		"Success": {
+			reason: "We should return cleaned revisions, and packages annotated with their current revision and digest.",
+			c: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+				l := obj.(*unstructured.UnstructuredList)
+				switch l.GetKind() {
+				case pkgv1.ProviderRevisionKind + "List":
+					l.Items = []unstructured.Unstructured{{Object: map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"name": "provider-aws-1234",
+							"ownerReferences": []interface{}{map[string]interface{}{
+								"apiVersion": pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+								"kind":       pkgv1.ProviderKind,
+								"name":       "provider-aws",
+								"uid":        "some-uid",
+								"controller": true,
+							}},
+						},
+						"spec":   map[string]interface{}{"revision": int64(2)},
+						"status": map[string]interface{}{"foundDependencies": int64(0)},
+					}}}
+				case pkgv1.ProviderKind + "List":
+					l.Items = []unstructured.Unstructured{{Object: map[string]interface{}{
+						"metadata": map[string]interface{}{"name": "provider-aws"},
+						"spec":     map[string]interface{}{"package": "crossplane/provider-aws:latest"},
+						"status": map[string]interface{}{
+							"currentIdentifier": "crossplane/provider-aws:latest",
+							"currentRevision":   "provider-aws-1234",
+							"currentDigest":     "sha256:1234",
+						},
+					}}}
+				}
+				return nil
+			})},
+			want: want{
+				objs: []*unstructured.Unstructured{
+					{Object: map[string]interface{}{
+						"apiVersion": pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+						"kind":       pkgv1.ProviderRevisionKind,
+						"metadata":   map[string]interface{}{"name": "provider-aws-1234"},
+						"spec":       map[string]interface{}{"revision": int64(2)},
+					}},
+					{Object: map[string]interface{}{
+						"apiVersion": pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+						"kind":       pkgv1.ProviderKind,
+						"metadata": map[string]interface{}{
+							"name": "provider-aws",
+							"annotations": map[string]interface{}{
+								pkgv1.AnnotationSnapshotSource:   "crossplane/provider-aws:latest",
+								pkgv1.AnnotationSnapshotRevision: "provider-aws-1234",
+								pkgv1.AnnotationSnapshotDigest:   "sha256:1234",
+							},
+						},
+						"spec": map[string]interface{}{"package": "crossplane/provider-aws:latest"},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Snapshot(context.Background(), tc.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSnapshot(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\n%s\nSnapshot(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		{Object: map[string]interface{}{"kind": "Provider", "metadata": map[string]interface{}{"name": "a"}}},
+		{Object: map[string]interface{}{"kind": "Configuration", "metadata": map[string]interface{}{"name": "b"}}},
+	}
+	want := `---
+kind: Provider
+metadata:
+  name: a
+---
+kind: Configuration
+metadata:
+  name: b
+`
+
+	b := &bytes.Buffer{}
+	if err := Write(b, objs); err != nil {
+		t.Fatalf("Write(...): %s", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("\nWrite(...): -want, +got:\n%s", diff)
+	}
+}
@@ -28,4 +28,21 @@ const (
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
+	// RestoreMode makes the composite resource and claim controllers adopt
+	// composite and composed resources that were restored from a backup,
+	// rather than creating duplicates. It also makes the package managers
+	// use the revisions recorded when a package was snapshotted.
+	RestoreMode feature.Flag = "RestoreMode"
+	// RecordComposedResourceDiffs makes the composite resource controllers
+	// record an event describing how each composed resource will change
+	// whenever they update it.
+	RecordComposedResourceDiffs feature.Flag = "RecordComposedResourceDiffs"
+	// RecordReconcileSummaries makes the composite resource controllers log
+	// a unique ID for each reconcile, and record why they requeued a
+	// composite resource in its status.
+	RecordReconcileSummaries feature.Flag = "RecordReconcileSummaries"
+	// CompositionServiceAccounts makes the composite resource controllers
+	// apply composed resources as the ServiceAccount named by their
+	// Composition, if any.
+	CompositionServiceAccounts feature.Flag = "CompositionServiceAccounts"
 )
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errApplyCertificate           = "cannot apply cert-manager certificate"
+	errFmtCertificateIssueTimeout = "%f seconds timeout for waiting cert-manager to issue webhook tls certificate is exceeded"
+)
+
+const (
+	defaultCertificateTimeout = 2 * time.Minute
+	defaultCertificatePeriod  = 5 * time.Second
+)
+
+// IssuerKinds of cert-manager.
+const (
+	IssuerKindIssuer        = "Issuer"
+	IssuerKindClusterIssuer = "ClusterIssuer"
+)
+
+// certificateGVK is the GroupVersionKind of a cert-manager Certificate. We use
+// unstructured Certificates in order to avoid depending on cert-manager.
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// An IssuerRef references the cert-manager Issuer or ClusterIssuer that should
+// issue a certificate.
+type IssuerRef struct {
+	Name string
+	Kind string
+}
+
+// CertManagerCertificateOption is used to configure CertManagerCertificate
+// behavior.
+type CertManagerCertificateOption func(*CertManagerCertificate)
+
+// WithCertificateTimeout sets how long CertManagerCertificate waits for
+// cert-manager to issue the certificate.
+func WithCertificateTimeout(t time.Duration) CertManagerCertificateOption {
+	return func(c *CertManagerCertificate) {
+		c.Timeout = t
+	}
+}
+
+// WithCertificatePeriod sets how often CertManagerCertificate checks whether
+// cert-manager has issued the certificate.
+func WithCertificatePeriod(p time.Duration) CertManagerCertificateOption {
+	return func(c *CertManagerCertificate) {
+		c.Period = p
+	}
+}
+
+// NewCertManagerCertificate returns a new *CertManagerCertificate.
+func NewCertManagerCertificate(nn types.NamespacedName, svcNamespace string, issuer IssuerRef, log logging.Logger, opts ...CertManagerCertificateOption) *CertManagerCertificate {
+	c := &CertManagerCertificate{
+		SecretRef:        nn,
+		ServiceNamespace: svcNamespace,
+		Issuer:           issuer,
+		Timeout:          defaultCertificateTimeout,
+		Period:           defaultCertificatePeriod,
+		log:              log,
+	}
+	for _, f := range opts {
+		f(c)
+	}
+	return c
+}
+
+// CertManagerCertificate is an initializer step that requests a TLS
+// certificate for *.<namespace>.svc domains from cert-manager, and waits for
+// cert-manager to write it to the given secret. It is an alternative to
+// WebhookCertificateGenerator. cert-manager renews the certificate before it
+// expires. The webhook servers of core Crossplane and providers reload the
+// renewed certificate, while the CA bundle injected into webhook
+// configurations and CRDs remains valid as long as the issuing CA does.
+type CertManagerCertificate struct {
+	SecretRef        types.NamespacedName
+	ServiceNamespace string
+	Issuer           IssuerRef
+	Timeout          time.Duration
+	Period           time.Duration
+
+	log logging.Logger
+}
+
+// Run creates or updates the cert-manager Certificate, then blocks until the
+// given secret contains the issued certificate.
+func (c *CertManagerCertificate) Run(ctx context.Context, kube client.Client) error {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	cert.SetName(c.SecretRef.Name)
+	cert.SetNamespace(c.SecretRef.Namespace)
+	cert.SetLabels(map[string]string{"app.kubernetes.io/managed-by": "crossplane"})
+	_ = unstructured.SetNestedField(cert.Object, map[string]any{
+		"secretName": c.SecretRef.Name,
+		"dnsNames":   []any{fmt.Sprintf("*.%s.svc", c.ServiceNamespace)},
+		"issuerRef": map[string]any{
+			"name":  c.Issuer.Name,
+			"kind":  c.Issuer.Kind,
+			"group": certificateGVK.Group,
+		},
+	}, "spec")
+
+	if err := resource.NewAPIPatchingApplicator(kube).Apply(ctx, cert); err != nil {
+		return errors.Wrap(err, errApplyCertificate)
+	}
+
+	timeout := time.After(c.Timeout)
+	ticker := time.NewTicker(c.Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.log.Info("Waiting for cert-manager to issue webhook tls certificate", "secret", c.SecretRef.String(), "poll-interval", c.Period)
+			s := &corev1.Secret{}
+			err := kube.Get(ctx, c.SecretRef, s)
+			if resource.Ignore(kerrors.IsNotFound, err) != nil {
+				return errors.Wrap(err, errGetWebhookSecret)
+			}
+			if len(s.Data[corev1.TLSCertKey]) != 0 && len(s.Data[corev1.TLSPrivateKeyKey]) != 0 {
+				return nil
+			}
+		case <-timeout:
+			return errors.Errorf(errFmtCertificateIssueTimeout, c.Timeout.Seconds())
+		}
+	}
+}
+
+// tlsCABundle returns the CA bundle that clients should use to verify the
+// certificate in the supplied TLS secret. cert-manager writes the issuing CA
+// to ca.crt, while WebhookCertificateGenerator writes a self-signed
+// certificate that is its own CA to tls.crt.
+func tlsCABundle(s *corev1.Secret) []byte {
+	if ca := s.Data["ca.crt"]; len(ca) != 0 {
+		return ca
+	}
+	return s.Data[corev1.TLSCertKey]
+}
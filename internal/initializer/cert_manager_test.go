@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCertManagerCertificate(t *testing.T) {
+	errBoom := errors.New("boom")
+	nn := types.NamespacedName{Name: "webhook-tls-secret", Namespace: "crossplane-system"}
+	issuer := IssuerRef{Name: "cool-issuer", Kind: IssuerKindClusterIssuer}
+
+	type args struct {
+		kube client.Client
+		opts []CertManagerCertificateOption
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Success": {
+			reason: "It should be successful if the Certificate is created and cert-manager fills the Secret.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok {
+							s.Data = map[string][]byte{"tls.crt": []byte("CRT"), "tls.key": []byte("KEY")}
+							return nil
+						}
+						return kerrors.NewNotFound(schema.GroupResource{}, nn.Name)
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						u := obj.(*unstructured.Unstructured)
+						want := map[string]any{
+							"secretName": nn.Name,
+							"dnsNames":   []any{"*.crossplane-system.svc"},
+							"issuerRef": map[string]any{
+								"name":  issuer.Name,
+								"kind":  issuer.Kind,
+								"group": "cert-manager.io",
+							},
+						}
+						if diff := cmp.Diff(want, u.Object["spec"]); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				opts: []CertManagerCertificateOption{WithCertificatePeriod(time.Millisecond)},
+			},
+		},
+		"ApplyFailed": {
+			reason: "It should fail if the Certificate cannot be applied.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get object"), errApplyCertificate),
+			},
+		},
+		"GetSecretFailed": {
+			reason: "It should fail if the Secret cannot be fetched.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if _, ok := obj.(*corev1.Secret); ok {
+							return errBoom
+						}
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				opts: []CertManagerCertificateOption{WithCertificatePeriod(time.Millisecond)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetWebhookSecret),
+			},
+		},
+		"Timeout": {
+			reason: "It should fail if cert-manager does not fill the Secret before the timeout.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				opts: []CertManagerCertificateOption{
+					WithCertificatePeriod(time.Millisecond),
+					WithCertificateTimeout(10 * time.Millisecond),
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCertificateIssueTimeout, (10 * time.Millisecond).Seconds()),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewCertManagerCertificate(nn, "crossplane-system", issuer, logging.NewNopLogger(), tc.args.opts...).Run(context.TODO(), tc.args.kube)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTLSCABundle(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		data   map[string][]byte
+		want   []byte
+	}{
+		"SelfSigned": {
+			reason: "The certificate should be used as the CA bundle if the Secret has no CA.",
+			data:   map[string][]byte{"tls.crt": []byte("CRT")},
+			want:   []byte("CRT"),
+		},
+		"CertManager": {
+			reason: "The CA should be used as the CA bundle if the Secret has one.",
+			data:   map[string][]byte{"tls.crt": []byte("CRT"), "ca.crt": []byte("CA")},
+			want:   []byte("CA"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tlsCABundle(&corev1.Secret{Data: tc.data})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntlsCABundle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -90,7 +90,7 @@ func (c *CoreCRDs) Run(ctx context.Context, kube client.Client) error { // nolin
 		if len(s.Data["tls.crt"]) == 0 {
 			return errors.Errorf(errFmtNoTLSCrtInSecret, c.WebhookTLSSecretRef.String())
 		}
-		caBundle = s.Data["tls.crt"]
+		caBundle = tlsCABundle(s)
 	}
 
 	r, err := parser.NewFsBackend(c.fs,
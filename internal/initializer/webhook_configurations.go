@@ -83,7 +83,7 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 	if len(s.Data["tls.crt"]) == 0 {
 		return errors.Errorf(errFmtNoTLSCrtInSecret, c.TLSSecretRef.String())
 	}
-	caBundle := s.Data["tls.crt"]
+	caBundle := tlsCABundle(s)
 
 	r, err := parser.NewFsBackend(c.fs,
 		parser.FsDir(c.Path),
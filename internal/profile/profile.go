@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package profile serves Go runtime profiles, and captures bundles of
+// profiles on demand, to help diagnose Crossplane's CPU and memory usage.
+package profile
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"path/filepath"
+	rpprof "runtime/pprof"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errServe        = "cannot serve profiles"
+	errShutdown     = "cannot shut down profile server"
+	errMkdir        = "cannot create profile bundle directory"
+	errCreate       = "cannot create profile file"
+	errClose        = "cannot close profile file"
+	errStartCPU     = "cannot start CPU profile"
+	errCapturing    = "a profile bundle is already being captured"
+	errFmtWrite     = "cannot write %s profile"
+	errFmtNoProfile = "no %s profile exists"
+)
+
+const (
+	// PathProfiles is the path at which Go runtime profiles are served.
+	PathProfiles = "/debug/pprof/"
+
+	// PathCapture is the path to which a POST request captures a bundle of
+	// profiles.
+	PathCapture = "/debug/capture"
+
+	// Bundles are written to a directory named for the time at which they
+	// were captured.
+	bundleTimeFormat = "20060102-150405"
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// Profiles captured in each bundle, in addition to a CPU profile.
+var Profiles = []string{"heap", "allocs", "goroutine"}
+
+// A Capturer captures bundles of profiles to a directory.
+type Capturer struct {
+	dir      string
+	duration time.Duration
+	log      logging.Logger
+
+	mx        sync.Mutex
+	capturing bool
+}
+
+// NewCapturer returns a Capturer that captures bundles of profiles to the
+// supplied directory, for example a mounted volume. Each bundle includes a CPU
+// profile captured for the supplied duration.
+func NewCapturer(dir string, duration time.Duration, log logging.Logger) *Capturer {
+	return &Capturer{dir: dir, duration: duration, log: log}
+}
+
+// Capture a bundle of profiles. A CPU profile is captured for the Capturer's
+// duration, or until the supplied context is cancelled, after which all other
+// profiles are captured. Capture returns the directory the bundle was written
+// to. Only one bundle may be captured at a time.
+func (c *Capturer) Capture(ctx context.Context) (string, error) {
+	c.mx.Lock()
+	if c.capturing {
+		c.mx.Unlock()
+		return "", errors.New(errCapturing)
+	}
+	c.capturing = true
+	c.mx.Unlock()
+
+	defer func() {
+		c.mx.Lock()
+		c.capturing = false
+		c.mx.Unlock()
+	}()
+
+	dir := filepath.Join(c.dir, time.Now().UTC().Format(bundleTimeFormat))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", errors.Wrap(err, errMkdir)
+	}
+
+	if err := c.captureCPU(ctx, filepath.Join(dir, "cpu.pprof")); err != nil {
+		return dir, err
+	}
+	for _, name := range Profiles {
+		if err := write(name, filepath.Join(dir, name+".pprof")); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+func (c *Capturer) captureCPU(ctx context.Context, path string) error {
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrap(err, errCreate)
+	}
+	if err := rpprof.StartCPUProfile(f); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, errStartCPU)
+	}
+
+	t := time.NewTimer(c.duration)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+
+	rpprof.StopCPUProfile()
+	return errors.Wrap(f.Close(), errClose)
+}
+
+func write(name, path string) error {
+	p := rpprof.Lookup(name)
+	if p == nil {
+		return errors.Errorf(errFmtNoProfile, name)
+	}
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrap(err, errCreate)
+	}
+	if err := p.WriteTo(f, 0); err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, errFmtWrite, name)
+	}
+	return errors.Wrap(f.Close(), errClose)
+}
+
+// ServeHTTP starts capturing a bundle of profiles in response to a POST
+// request. The bundle is captured in the background.
+func (c *Capturer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	c.mx.Lock()
+	capturing := c.capturing
+	c.mx.Unlock()
+	if capturing {
+		http.Error(w, errCapturing, http.StatusConflict)
+		return
+	}
+	go c.capture(context.Background())
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "Capturing a bundle of profiles to %s for %s\n", c.dir, c.duration)
+}
+
+func (c *Capturer) capture(ctx context.Context) {
+	c.log.Info("Capturing profile bundle", "directory", c.dir, "duration", c.duration.String())
+	dir, err := c.Capture(ctx)
+	if err != nil {
+		c.log.Info("Cannot capture profile bundle", "error", err)
+		return
+	}
+	c.log.Info("Captured profile bundle", "directory", dir)
+}
+
+// Start capturing a bundle of profiles each time the process receives
+// SIGUSR1. Start blocks until the supplied context is cancelled.
+func (c *Capturer) Start(ctx context.Context) error {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR1)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-sig:
+			c.capture(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection returns false; every replica may capture profiles.
+func (c *Capturer) NeedLeaderElection() bool {
+	return false
+}
+
+// A Server serves Go runtime profiles over HTTP.
+type Server struct {
+	address  string
+	capturer *Capturer
+	log      logging.Logger
+}
+
+// NewServer returns a Server that serves Go runtime profiles at the supplied
+// address. If the supplied Capturer is not nil the Server also captures a
+// bundle of profiles when it receives a POST request at PathCapture.
+func NewServer(address string, c *Capturer, log logging.Logger) *Server {
+	return &Server{address: address, capturer: c, log: log}
+}
+
+// Start serving profiles. Start blocks until the supplied context is
+// cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathProfiles, pprof.Index)
+	mux.HandleFunc(PathProfiles+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(PathProfiles+"profile", pprof.Profile)
+	mux.HandleFunc(PathProfiles+"symbol", pprof.Symbol)
+	mux.HandleFunc(PathProfiles+"trace", pprof.Trace)
+	if s.capturer != nil {
+		mux.Handle(PathCapture, s.capturer)
+	}
+	srv := &http.Server{Addr: s.address, Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.log.Info("Serving profiles", "address", s.address)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(err, errServe)
+	case <-ctx.Done():
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return errors.Wrap(srv.Shutdown(sctx), errShutdown)
+	}
+}
+
+// NeedLeaderElection returns false; every replica serves profiles.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
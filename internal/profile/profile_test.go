@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCapture(t *testing.T) {
+	type want struct {
+		files []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		capturing bool
+		want      want
+	}{
+		"Capture": {
+			reason: "We should write a CPU profile and every other profile to a new bundle directory.",
+			want: want{
+				files: []string{"allocs.pprof", "cpu.pprof", "goroutine.pprof", "heap.pprof"},
+			},
+		},
+		"AlreadyCapturing": {
+			reason:    "We should refuse to capture a bundle while another is being captured.",
+			capturing: true,
+			want: want{
+				err: errors.New(errCapturing),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCapturer(t.TempDir(), 10*time.Millisecond, logging.NewNopLogger())
+			c.capturing = tc.capturing
+
+			dir, err := c.Capture(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Capture(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("os.ReadDir(%q): %s", dir, err)
+			}
+			files := make([]string, 0, len(entries))
+			for _, e := range entries {
+				files = append(files, e.Name())
+			}
+			sort.Strings(files)
+			if diff := cmp.Diff(tc.want.files, files); diff != "" {
+				t.Errorf("\n%s\nc.Capture(...): -want files, +got files:\n%s", tc.reason, diff)
+			}
+			if filepath.Dir(dir) != c.dir {
+				t.Errorf("\n%s\nc.Capture(...): bundle %q is not in %q", tc.reason, dir, c.dir)
+			}
+		})
+	}
+}
+
+func TestCapturerServeHTTP(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		method    string
+		capturing bool
+		want      int
+	}{
+		"NotPost": {
+			reason: "We should only capture a bundle in response to a POST request.",
+			method: http.MethodGet,
+			want:   http.StatusMethodNotAllowed,
+		},
+		"AlreadyCapturing": {
+			reason:    "We should return a conflict if a bundle is already being captured.",
+			method:    http.MethodPost,
+			capturing: true,
+			want:      http.StatusConflict,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCapturer(t.TempDir(), 10*time.Millisecond, logging.NewNopLogger())
+			c.capturing = tc.capturing
+
+			w := httptest.NewRecorder()
+			c.ServeHTTP(w, httptest.NewRequest(tc.method, PathCapture, nil))
+			if diff := cmp.Diff(tc.want, w.Code); diff != "" {
+				t.Errorf("\n%s\nc.ServeHTTP(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
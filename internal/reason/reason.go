@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reason contains machine-readable reasons for reconcile failures.
+// Controllers attach a reason to an error where the error originates, and
+// surface it in the events and status conditions they emit so that alerting
+// and user interfaces can key off the reason rather than the error message.
+package reason
+
+import (
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// Reasons a composite resource or claim could not be reconciled.
+const (
+	CompositionNotFound     xpv1.ConditionReason = "CompositionNotFound"
+	CompositionInvalid      xpv1.ConditionReason = "CompositionInvalid"
+	RenderFailed            xpv1.ConditionReason = "RenderFailed"
+	ApplyConflict           xpv1.ConditionReason = "ApplyConflict"
+	ApplyFailed             xpv1.ConditionReason = "ApplyFailed"
+	ConnectionDetailsFailed xpv1.ConditionReason = "ConnectionDetailsFailed"
+	ReadinessCheckFailed    xpv1.ConditionReason = "ReadinessCheckFailed"
+	BindConflict            xpv1.ConditionReason = "BindConflict"
+)
+
+// Reasons a package or package revision could not be reconciled.
+const (
+	ImagePullFailed     xpv1.ConditionReason = "ImagePullFailed"
+	PackageParseFailed  xpv1.ConditionReason = "PackageParseFailed"
+	PackageLintFailed   xpv1.ConditionReason = "PackageLintFailed"
+	DependenciesMissing xpv1.ConditionReason = "DependenciesMissing"
+
+	// CoreResourceProtected indicates a package tried to take control of
+	// one of Crossplane's own types.
+	CoreResourceProtected xpv1.ConditionReason = "CoreResourceProtected"
+)
+
+type reasoned struct {
+	error
+	reason xpv1.ConditionReason
+}
+
+func (e *reasoned) Unwrap() error {
+	return e.error
+}
+
+// Wrap the supplied error with the supplied machine-readable reason. The
+// reason is preserved by subsequent calls to errors.Wrap. Wrap returns nil if
+// the supplied error is nil.
+func Wrap(err error, r xpv1.ConditionReason) error {
+	if err == nil {
+		return nil
+	}
+	return &reasoned{error: err, reason: r}
+}
+
+// WrapAPIError wraps the supplied error with the ApplyConflict reason if it is
+// an API server conflict, and with the supplied reason otherwise.
+func WrapAPIError(err error, r xpv1.ConditionReason) error {
+	if kerrors.IsConflict(err) {
+		return Wrap(err, ApplyConflict)
+	}
+	return Wrap(err, r)
+}
+
+// Of returns the outermost machine-readable reason of the supplied error, if
+// any.
+func Of(err error) (xpv1.ConditionReason, bool) {
+	r := &reasoned{}
+	if !errors.As(err, &r) {
+		return "", false
+	}
+	return r.reason, true
+}
+
+// Warning returns a warning event for the supplied error. The event's reason
+// is the error's machine-readable reason if it has one, and the supplied
+// fallback reason otherwise.
+func Warning(fallback event.Reason, err error, annotations ...string) event.Event {
+	if r, ok := Of(err); ok {
+		return event.Warning(event.Reason(r), err, annotations...)
+	}
+	return event.Warning(fallback, err, annotations...)
+}
+
+// Condition returns the supplied condition with its reason replaced by the
+// supplied error's machine-readable reason, if it has one, and its message set
+// to the error.
+func Condition(c xpv1.Condition, err error) xpv1.Condition {
+	if err == nil {
+		return c
+	}
+	if r, ok := Of(err); ok {
+		c.Reason = r
+	}
+	c.Message = err.Error()
+	return c
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reason
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+func TestOf(t *testing.T) {
+	errBoom := errors.New("boom")
+	conflict := kerrors.NewConflict(schema.GroupResource{}, "cool", errBoom)
+
+	type want struct {
+		r  xpv1.ConditionReason
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"NoReason": {
+			reason: "An error without a reason should not return one.",
+			err:    errBoom,
+			want:   want{},
+		},
+		"Wrapped": {
+			reason: "The reason of an error should survive subsequent wrapping.",
+			err:    errors.Wrap(Wrap(errBoom, RenderFailed), "cannot render"),
+			want:   want{r: RenderFailed, ok: true},
+		},
+		"Outermost": {
+			reason: "The outermost reason of an error should be returned.",
+			err:    Wrap(Wrap(errBoom, RenderFailed), ApplyFailed),
+			want:   want{r: ApplyFailed, ok: true},
+		},
+		"APIConflict": {
+			reason: "An API server conflict should have the ApplyConflict reason.",
+			err:    errors.Wrap(WrapAPIError(errors.Wrap(conflict, "cannot apply"), ApplyFailed), "cannot compose"),
+			want:   want{r: ApplyConflict, ok: true},
+		},
+		"APIError": {
+			reason: "Other API server errors should have the supplied reason.",
+			err:    WrapAPIError(errBoom, ApplyFailed),
+			want:   want{r: ApplyFailed, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, ok := Of(tc.err)
+			if diff := cmp.Diff(tc.want, want{r: r, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWarning(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason   string
+		fallback event.Reason
+		err      error
+		want     event.Event
+	}{
+		"Fallback": {
+			reason:   "An error without a reason should use the fallback reason.",
+			fallback: "ComposeResources",
+			err:      errBoom,
+			want:     event.Warning("ComposeResources", errBoom),
+		},
+		"Reason": {
+			reason:   "An error with a reason should use its reason.",
+			fallback: "ComposeResources",
+			err:      Wrap(errBoom, CompositionNotFound),
+			want:     event.Warning(event.Reason(CompositionNotFound), errBoom),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Warning(tc.fallback, tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWarning(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCondition(t *testing.T) {
+	errBoom := errors.New("boom")
+	c := xpv1.Condition{Type: "Healthy", Status: "False", Reason: "Unhealthy"}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   xpv1.Condition
+	}{
+		"NoError": {
+			reason: "The condition should be unchanged if there is no error.",
+			want:   c,
+		},
+		"NoReason": {
+			reason: "An error without a reason should only set the condition's message.",
+			err:    errBoom,
+			want:   xpv1.Condition{Type: "Healthy", Status: "False", Reason: "Unhealthy", Message: "boom"},
+		},
+		"Reason": {
+			reason: "An error with a reason should set the condition's reason and message.",
+			err:    errors.Wrap(Wrap(errBoom, ImagePullFailed), "cannot pull"),
+			want:   xpv1.Condition{Type: "Healthy", Status: "False", Reason: ImagePullFailed, Message: "cannot pull: boom"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Condition(c, tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle limits how often Kubernetes events are recorded.
+package throttle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// DefaultInterval is the default interval at which a Recorder records events
+// with the same type and reason about the same object.
+const DefaultInterval = 5 * time.Minute
+
+// A key identifies events that are deduplicated together.
+type key struct {
+	object string
+	kind   event.Type
+	reason event.Reason
+}
+
+// An entry tracks when an event was last recorded, and how many equivalent
+// events have been suppressed since.
+type entry struct {
+	recorded   time.Time
+	suppressed int
+}
+
+// state is shared by a Recorder and any Recorders derived from it using
+// WithAnnotations, so that events are deduplicated regardless of which
+// Recorder records them.
+type state struct {
+	mx      sync.Mutex
+	entries map[key]*entry
+	swept   time.Time
+}
+
+// A Recorder records at most one event with a particular type and reason
+// about a particular object per interval. It counts the events it suppresses,
+// and includes the count in the message of the next event it records.
+type Recorder struct {
+	wrapped  event.Recorder
+	interval time.Duration
+	now      func() time.Time
+	state    *state
+}
+
+// An Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithInterval configures the interval at which the Recorder records events
+// with the same type and reason about the same object.
+func WithInterval(d time.Duration) Option {
+	return func(r *Recorder) {
+		r.interval = d
+	}
+}
+
+// WithClock configures the function the Recorder uses to tell the time.
+func WithClock(now func() time.Time) Option {
+	return func(r *Recorder) {
+		r.now = now
+	}
+}
+
+// NewRecorder returns a Recorder that throttles the events recorded by the
+// supplied Recorder.
+func NewRecorder(wrapped event.Recorder, o ...Option) *Recorder {
+	r := &Recorder{
+		wrapped:  wrapped,
+		interval: DefaultInterval,
+		now:      time.Now,
+		state:    &state{entries: make(map[key]*entry)},
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Event records the supplied event, unless an event with the same type and
+// reason was recorded about the same object within the interval.
+func (r *Recorder) Event(obj runtime.Object, e event.Event) {
+	k := key{object: identify(obj), kind: e.Type, reason: e.Reason}
+	now := r.now()
+
+	r.state.mx.Lock()
+	r.sweep(now)
+	en, ok := r.state.entries[k]
+	if ok && now.Sub(en.recorded) < r.interval {
+		en.suppressed++
+		r.state.mx.Unlock()
+		return
+	}
+	suppressed := 0
+	if ok {
+		suppressed = en.suppressed
+	}
+	r.state.entries[k] = &entry{recorded: now}
+	r.state.mx.Unlock()
+
+	if suppressed > 0 {
+		e.Message = fmt.Sprintf("%s (%d similar events suppressed in the last %s)", e.Message, suppressed, r.interval)
+	}
+	r.wrapped.Event(obj, e)
+}
+
+// WithAnnotations returns a new Recorder that includes the supplied
+// annotations with all recorded events. The new Recorder shares its throttling
+// state with this one.
+func (r *Recorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return &Recorder{
+		wrapped:  r.wrapped.WithAnnotations(keysAndValues...),
+		interval: r.interval,
+		now:      r.now,
+		state:    r.state,
+	}
+}
+
+// sweep forgets events that were recorded more than two intervals ago, so
+// that we don't track objects that no longer exist forever. Any events that
+// were suppressed since then go unreported. The caller must hold the state
+// lock.
+func (r *Recorder) sweep(now time.Time) {
+	if now.Sub(r.state.swept) < r.interval {
+		return
+	}
+	for k, en := range r.state.entries {
+		if now.Sub(en.recorded) > 2*r.interval {
+			delete(r.state.entries, k)
+		}
+	}
+	r.state.swept = now
+}
+
+// identify returns a string that uniquely identifies the supplied object.
+func identify(obj runtime.Object) string {
+	m, err := kmeta.Accessor(obj)
+	if err != nil {
+		return fmt.Sprintf("%p", obj)
+	}
+	if uid := m.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind(), m.GetNamespace(), m.GetName())
+}
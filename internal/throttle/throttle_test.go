@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+var _ event.Recorder = &Recorder{}
+
+// A capture Recorder captures the messages of the events it records.
+type capture struct {
+	messages *[]string
+}
+
+func (c capture) Event(_ runtime.Object, e event.Event) {
+	*c.messages = append(*c.messages, e.Message)
+}
+
+func (c capture) WithAnnotations(_ ...string) event.Recorder {
+	return c
+}
+
+func TestEvent(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Now()
+
+	a := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "a"}}
+	b := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "b"}}
+
+	// An occurrence of an event at a particular time.
+	type occurrence struct {
+		after time.Duration
+		obj   runtime.Object
+		e     event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		events []occurrence
+		want   []string
+	}{
+		"Distinct": {
+			reason: "Events about different objects, or with different reasons, should not be throttled.",
+			events: []occurrence{
+				{obj: a, e: event.Warning("ApplyFailed", errBoom)},
+				{obj: b, e: event.Warning("ApplyFailed", errBoom)},
+				{obj: a, e: event.Warning("RenderFailed", errBoom)},
+			},
+			want: []string{"boom", "boom", "boom"},
+		},
+		"Suppressed": {
+			reason: "Events with the same type and reason about the same object should be recorded once per interval.",
+			events: []occurrence{
+				{obj: a, e: event.Warning("ApplyFailed", errBoom)},
+				{after: time.Minute, obj: a, e: event.Warning("ApplyFailed", errors.New("different"))},
+				{after: 2 * time.Minute, obj: a, e: event.Warning("ApplyFailed", errBoom)},
+			},
+			want: []string{"boom"},
+		},
+		"Aggregated": {
+			reason: "The next event recorded after an interval should include the number of suppressed events.",
+			events: []occurrence{
+				{obj: a, e: event.Warning("ApplyFailed", errBoom)},
+				{after: time.Minute, obj: a, e: event.Warning("ApplyFailed", errBoom)},
+				{after: 2 * time.Minute, obj: a, e: event.Warning("ApplyFailed", errBoom)},
+				{after: 6 * time.Minute, obj: a, e: event.Warning("ApplyFailed", errBoom)},
+			},
+			want: []string{"boom", "boom (2 similar events suppressed in the last 5m0s)"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			now := start
+			r := NewRecorder(capture{messages: &got}, WithInterval(5*time.Minute), WithClock(func() time.Time { return now }))
+			for _, o := range tc.events {
+				now = start.Add(o.after)
+				r.WithAnnotations("controller", "cool").Event(o.obj, o.e)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEvent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
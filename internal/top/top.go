@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package top summarizes the load on a Crossplane control plane.
+package top
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errParseMetrics    = "cannot parse metrics"
+	errParsePodMetrics = "cannot parse pod metrics"
+)
+
+// The controller-runtime metric and labels from which we derive reconcile
+// rates.
+const (
+	metricReconcileTotal = "controller_runtime_reconcile_total"
+	labelController      = "controller"
+	labelResult          = "result"
+	resultError          = "error"
+)
+
+// Reconciles counts the reconciles of a controller.
+type Reconciles struct {
+	// Total number of reconciles.
+	Total float64
+
+	// Errors is the number of reconciles that returned an error.
+	Errors float64
+}
+
+// ParseReconciles parses the number of reconciles of each controller from the
+// supplied Prometheus text exposition format metrics.
+func ParseReconciles(r io.Reader) (map[string]Reconciles, error) {
+	p := &expfmt.TextParser{}
+	mfs, err := p.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseMetrics)
+	}
+
+	out := map[string]Reconciles{}
+	mf, ok := mfs[metricReconcileTotal]
+	if !ok {
+		return out, nil
+	}
+	for _, m := range mf.GetMetric() {
+		ctrl, result := "", ""
+		for _, l := range m.GetLabel() {
+			switch l.GetName() {
+			case labelController:
+				ctrl = l.GetValue()
+			case labelResult:
+				result = l.GetValue()
+			}
+		}
+		rc := out[ctrl]
+		rc.Total += m.GetCounter().GetValue()
+		if result == resultError {
+			rc.Errors += m.GetCounter().GetValue()
+		}
+		out[ctrl] = rc
+	}
+	return out, nil
+}
+
+// A ControllerLoad is the rate at which a controller reconciles.
+type ControllerLoad struct {
+	Controller string
+
+	// Reconciles and errors per second.
+	Reconciles float64
+	Errors     float64
+}
+
+// Rates returns the per second reconcile and error rates of each controller
+// between two samples taken the supplied duration apart. Controllers are
+// sorted by descending reconcile rate.
+func Rates(before, after map[string]Reconciles, d time.Duration) []ControllerLoad {
+	out := make([]ControllerLoad, 0, len(after))
+	if d <= 0 {
+		return out
+	}
+	for ctrl, a := range after {
+		b := before[ctrl]
+		out = append(out, ControllerLoad{
+			Controller: ctrl,
+			Reconciles: (a.Total - b.Total) / d.Seconds(),
+			Errors:     (a.Errors - b.Errors) / d.Seconds(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Reconciles != out[j].Reconciles {
+			return out[i].Reconciles > out[j].Reconciles
+		}
+		return out[i].Controller < out[j].Controller
+	})
+	return out
+}
+
+// A DefinitionLoad is the number of composite resources and claims of a
+// CompositeResourceDefinition.
+type DefinitionLoad struct {
+	Name       string
+	Composites int
+	Claims     int
+}
+
+// A PodLoad is the resource usage of a pod.
+type PodLoad struct {
+	Namespace string
+	Name      string
+	CPU       resource.Quantity
+	Memory    resource.Quantity
+}
+
+// podMetricsList mirrors the parts of a metrics.k8s.io PodMetricsList that we
+// use, in order to avoid depending on k8s.io/metrics.
+type podMetricsList struct {
+	Items []struct {
+		metav1.ObjectMeta `json:"metadata"`
+		Containers        []struct {
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// ParsePodMetrics parses the resource usage of each pod from the supplied
+// metrics.k8s.io PodMetricsList. Pods are sorted by name.
+func ParsePodMetrics(b []byte) ([]PodLoad, error) {
+	l := &podMetricsList{}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, errors.Wrap(err, errParsePodMetrics)
+	}
+	out := make([]PodLoad, 0, len(l.Items))
+	for _, pm := range l.Items {
+		pl := PodLoad{Namespace: pm.GetNamespace(), Name: pm.GetName()}
+		for _, c := range pm.Containers {
+			pl.CPU.Add(c.Usage[corev1.ResourceCPU])
+			pl.Memory.Add(c.Usage[corev1.ResourceMemory])
+		}
+		out = append(out, pl)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// A Snapshot of the load on a Crossplane control plane.
+type Snapshot struct {
+	Definitions []DefinitionLoad
+	Controllers []ControllerLoad
+	Pods        []PodLoad
+}
+
+// Write a human readable table of the snapshot to the supplied writer. Empty
+// sections are omitted.
+func (s Snapshot) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	if len(s.Definitions) > 0 {
+		fmt.Fprintln(tw, "DEFINITION\tCOMPOSITES\tCLAIMS")
+		for _, d := range s.Definitions {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", d.Name, d.Composites, d.Claims)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	if len(s.Controllers) > 0 {
+		fmt.Fprintln(tw, "CONTROLLER\tRECONCILES/S\tERRORS/S")
+		for _, c := range s.Controllers {
+			fmt.Fprintf(tw, "%s\t%.2f\t%.2f\n", c.Controller, c.Reconciles, c.Errors)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	if len(s.Pods) > 0 {
+		fmt.Fprintln(tw, "POD\tCPU\tMEMORY")
+		for _, p := range s.Pods {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.CPU.String(), p.Memory.String())
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
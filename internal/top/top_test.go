@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package top
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const metrics = `# HELP controller_runtime_reconcile_total Total number of reconciliations per controller
+# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="composite/xcools.example.org",result="error"} 2
+controller_runtime_reconcile_total{controller="composite/xcools.example.org",result="requeue"} 3
+controller_runtime_reconcile_total{controller="composite/xcools.example.org",result="success"} 5
+controller_runtime_reconcile_total{controller="packages/provider.pkg.crossplane.io",result="success"} 1
+# HELP workqueue_depth Current depth of workqueue
+# TYPE workqueue_depth gauge
+workqueue_depth{name="composite/xcools.example.org"} 0
+`
+
+func TestParseReconciles(t *testing.T) {
+	type want struct {
+		r   map[string]Reconciles
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		metrics string
+		want    want
+	}{
+		"Reconciles": {
+			reason:  "We should sum the reconciles and errors of each controller.",
+			metrics: metrics,
+			want: want{
+				r: map[string]Reconciles{
+					"composite/xcools.example.org":        {Total: 10, Errors: 2},
+					"packages/provider.pkg.crossplane.io": {Total: 1},
+				},
+			},
+		},
+		"NoReconciles": {
+			reason:  "We should return no reconciles if the reconcile metric is not exposed.",
+			metrics: "# TYPE workqueue_depth gauge\nworkqueue_depth{name=\"cool\"} 0\n",
+			want: want{
+				r: map[string]Reconciles{},
+			},
+		},
+		"InvalidMetrics": {
+			reason:  "We should return an error if the metrics cannot be parsed.",
+			metrics: "{",
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseReconciles(strings.NewReader(tc.metrics))
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nParseReconciles(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nParseReconciles(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRates(t *testing.T) {
+	before := map[string]Reconciles{
+		"a": {Total: 10, Errors: 1},
+	}
+	after := map[string]Reconciles{
+		"a": {Total: 30, Errors: 11},
+		"b": {Total: 40},
+	}
+	want := []ControllerLoad{
+		{Controller: "b", Reconciles: 4},
+		{Controller: "a", Reconciles: 2, Errors: 1},
+	}
+	got := Rates(before, after, 10*time.Second)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nRates(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestParsePodMetrics(t *testing.T) {
+	b := []byte(`{"kind":"PodMetricsList","items":[
+		{"metadata":{"name":"provider-cool","namespace":"crossplane-system"},"containers":[
+			{"name":"provider","usage":{"cpu":"10m","memory":"20Mi"}},
+			{"name":"sidecar","usage":{"cpu":"5m","memory":"12Mi"}}]},
+		{"metadata":{"name":"crossplane","namespace":"crossplane-system"},"containers":[
+			{"name":"crossplane","usage":{"cpu":"100m","memory":"64Mi"}}]}]}`)
+
+	got, err := ParsePodMetrics(b)
+	if err != nil {
+		t.Fatalf("ParsePodMetrics(...): %s", err)
+	}
+
+	want := []PodLoad{
+		{Namespace: "crossplane-system", Name: "crossplane", CPU: resource.MustParse("100m"), Memory: resource.MustParse("64Mi")},
+		{Namespace: "crossplane-system", Name: "provider-cool", CPU: resource.MustParse("15m"), Memory: resource.MustParse("32Mi")},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); diff != "" {
+		t.Errorf("\nParsePodMetrics(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s := Snapshot{
+		Definitions: []DefinitionLoad{{Name: "xcools.example.org", Composites: 3, Claims: 2}},
+		Controllers: []ControllerLoad{{Controller: "composite/xcools.example.org", Reconciles: 0.5, Errors: 0.1}},
+	}
+	want := `DEFINITION          COMPOSITES  CLAIMS
+xcools.example.org  3           2
+
+CONTROLLER                    RECONCILES/S  ERRORS/S
+composite/xcools.example.org  0.50          0.10
+
+`
+	b := &strings.Builder{}
+	if err := s.Write(b); err != nil {
+		t.Fatalf("Write(...): %s", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("\nWrite(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	warnFmtFromPath           = "resource template %s: patch %d: fromFieldPath %q is not in the composite resource's schema, so the patch will never be applied: %s"
+	warnFmtVarPath            = "resource template %s: patch %d: combine variable %d fromFieldPath %q is not in the composite resource's schema, so the patch will never be applied: %s"
+	warnFmtOverwritten        = "resource template %s: patches %d and %d both patch toFieldPath %q, so patch %d overwrites patch %d"
+	warnFmtXROverwritten      = "patch %d of resource template %s and patch %d of resource template %s both patch the composite resource's %q, so one overwrites the other"
+	warnFmtNoConnectionSecret = "resource template %s: connection detail %d reads connection secret key %q, but the resource template never writes a connection secret"
+)
+
+// The field of a composed resource that specifies where to write its
+// connection secret.
+const fieldWriteConnectionSecretToRef = "spec.writeConnectionSecretToRef"
+
+// A patchTarget identifies a patch that patches a field path.
+type patchTarget struct {
+	template string
+	patch    int
+}
+
+// Lint returns a description of each likely authoring mistake in the supplied
+// Composition. Unlike validation problems, these mistakes don't stop a
+// Composition from working, but usually mean it doesn't work as its author
+// intended. The fromFieldPaths of patches are checked against the supplied
+// composite resource schema, unless it is nil.
+func Lint(comp *v1.Composition, xr *extv1.JSONSchemaProps) []string {
+	warnings := make([]string, 0)
+
+	templates, err := comp.Spec.ComposedTemplates()
+	if err != nil {
+		// Validation reports unresolvable patch sets.
+		return warnings
+	}
+
+	xrTargets := map[string]patchTarget{}
+	for i, t := range templates {
+		id := fmt.Sprintf("%d", i)
+		if t.Name != nil {
+			id = fmt.Sprintf("%q", *t.Name)
+		}
+
+		cdTargets := map[string]int{}
+		for j, p := range t.Patches {
+			if xr != nil {
+				warnings = append(warnings, lintFromPaths(id, j, p, xr)...)
+			}
+
+			if to, ok := toComposedPath(p); ok {
+				if k, dup := cdTargets[to]; dup && !merges(p) && !merges(t.Patches[k]) {
+					warnings = append(warnings, fmt.Sprintf(warnFmtOverwritten, id, k, j, to, j, k))
+				}
+				cdTargets[to] = j
+			}
+
+			if to, ok := toCompositePath(p); ok {
+				if pt, dup := xrTargets[to]; dup {
+					warnings = append(warnings, fmt.Sprintf(warnFmtXROverwritten, pt.patch, pt.template, j, id, to))
+				}
+				xrTargets[to] = patchTarget{template: id, patch: j}
+			}
+		}
+
+		if writesConnectionSecret(t) {
+			continue
+		}
+		for j, cd := range t.ConnectionDetails {
+			if key, ok := fromConnectionSecretKey(cd); ok {
+				warnings = append(warnings, fmt.Sprintf(warnFmtNoConnectionSecret, id, j, key))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// lintFromPaths returns a warning for each fromFieldPath of the supplied patch
+// from the composite resource that isn't in the composite resource's schema.
+// Such patches are skipped (unless they are required) because the field they
+// patch from can never be set.
+func lintFromPaths(id string, j int, p v1.Patch, xr *extv1.JSONSchemaProps) []string {
+	warnings := make([]string, 0)
+	switch p.Type {
+	case "", v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeExternalNameFromComposite:
+		if p.FromFieldPath == nil {
+			return warnings
+		}
+		if err := ValidateFieldPath(xr, *p.FromFieldPath); err != nil {
+			warnings = append(warnings, fmt.Sprintf(warnFmtFromPath, id, j, *p.FromFieldPath, err))
+		}
+	case v1.PatchTypeCombineFromComposite:
+		if p.Combine == nil {
+			return warnings
+		}
+		for k, cv := range p.Combine.Variables {
+			if err := ValidateFieldPath(xr, cv.FromFieldPath); err != nil {
+				warnings = append(warnings, fmt.Sprintf(warnFmtVarPath, id, j, k, cv.FromFieldPath, err))
+			}
+		}
+	case v1.PatchTypePatchSet, v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite, v1.PatchTypeTemplateFromComposite:
+		// These patches don't patch from a field path of the composite
+		// resource, or (in the case of templates) don't specify one.
+	}
+	return warnings
+}
+
+// toComposedPath returns the field path of the composed resource that the
+// supplied patch patches, if any.
+func toComposedPath(p v1.Patch) (string, bool) {
+	switch p.Type {
+	case "", v1.PatchTypeFromCompositeFieldPath:
+		// Patches default to patching the same path they patch from.
+		if p.ToFieldPath != nil {
+			return *p.ToFieldPath, true
+		}
+		if p.FromFieldPath != nil {
+			return *p.FromFieldPath, true
+		}
+	case v1.PatchTypeCombineFromComposite, v1.PatchTypeTemplateFromComposite:
+		if p.ToFieldPath != nil {
+			return *p.ToFieldPath, true
+		}
+	case v1.PatchTypeExternalNameFromComposite:
+		return fmt.Sprintf("metadata.annotations[%s]", meta.AnnotationKeyExternalName), true
+	case v1.PatchTypePatchSet, v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+	}
+	return "", false
+}
+
+// toCompositePath returns the field path of the composite resource that the
+// supplied patch patches, if any.
+func toCompositePath(p v1.Patch) (string, bool) {
+	switch p.Type {
+	case v1.PatchTypeToCompositeFieldPath:
+		if p.ToFieldPath != nil {
+			return *p.ToFieldPath, true
+		}
+		if p.FromFieldPath != nil {
+			return *p.FromFieldPath, true
+		}
+	case v1.PatchTypeCombineToComposite:
+		if p.ToFieldPath != nil {
+			return *p.ToFieldPath, true
+		}
+	case "", v1.PatchTypeFromCompositeFieldPath, v1.PatchTypePatchSet, v1.PatchTypeCombineFromComposite, v1.PatchTypeTemplateFromComposite, v1.PatchTypeExternalNameFromComposite:
+	}
+	return "", false
+}
+
+// merges returns true if the supplied patch merges its value into the field
+// it patches, rather than overwriting it.
+func merges(p v1.Patch) bool {
+	return p.Policy != nil && p.Policy.MergeOptions != nil
+}
+
+// writesConnectionSecret returns true if the composed resource rendered from
+// the supplied template may write a connection secret, either because its
+// base specifies where to write it or because a patch does.
+func writesConnectionSecret(t v1.ComposedTemplate) bool {
+	base := map[string]any{}
+	if err := json.Unmarshal(t.Base.Raw, &base); err != nil {
+		// Validation reports invalid base templates.
+		return true
+	}
+	if _, err := fieldpath.Pave(base).GetValue(fieldWriteConnectionSecretToRef); err == nil {
+		return true
+	}
+	for _, p := range t.Patches {
+		if to, ok := toComposedPath(p); ok && strings.HasPrefix(to, fieldWriteConnectionSecretToRef) {
+			return true
+		}
+	}
+	return false
+}
+
+// fromConnectionSecretKey returns the connection secret key the supplied
+// connection detail reads, if any.
+func fromConnectionSecretKey(cd v1.ConnectionDetail) (string, bool) {
+	if cd.FromConnectionSecretKey == nil {
+		return "", false
+	}
+	if cd.Type != nil && *cd.Type != v1.ConnectionDetailTypeFromConnectionSecretKey {
+		return "", false
+	}
+	return *cd.FromConnectionSecretKey, true
+}
+
+// Lint returns a description of each likely authoring mistake in the
+// Validator's Compositions. The fromFieldPaths of patches aren't checked,
+// because Validate reports unknown fromFieldPaths as errors.
+func (v *Validator) Lint() []string {
+	warnings := make([]string, 0)
+	for _, comp := range v.comps {
+		for _, w := range Lint(comp, nil) {
+			warnings = append(warnings, fmt.Sprintf(errFmtObject+": %s", comp.Kind, comp.GetName(), w))
+		}
+	}
+	return warnings
+}
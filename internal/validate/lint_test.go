@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestLint(t *testing.T) {
+	xr := &extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"size":   {Type: "string"},
+					"region": {Type: "string"},
+				},
+			},
+		},
+	}
+
+	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"db.example.org/v1","kind":"Instance"}`)}
+	baseWithSecret := runtime.RawExtension{Raw: []byte(`{"apiVersion":"db.example.org/v1","kind":"Instance","spec":{"writeConnectionSecretToRef":{"namespace":"default"}}}`)}
+
+	from := func(from, to string) v1.Patch {
+		return v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: pointer.String(from), ToFieldPath: pointer.String(to)}
+	}
+	toXR := func(from, to string) v1.Patch {
+		return v1.Patch{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String(from), ToFieldPath: pointer.String(to)}
+	}
+	comp := func(ts ...v1.ComposedTemplate) *v1.Composition {
+		return &v1.Composition{Spec: v1.CompositionSpec{Resources: ts}}
+	}
+
+	type args struct {
+		comp *v1.Composition
+		xr   *extv1.JSONSchemaProps
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoProblems": {
+			reason: "A Composition without authoring mistakes should not return any warnings.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Name:    pointer.String("db"),
+					Base:    baseWithSecret,
+					Patches: []v1.Patch{from("spec.size", "spec.forProvider.instanceClass"), toXR("status.atProvider.id", "status.id")},
+					ConnectionDetails: []v1.ConnectionDetail{
+						{FromConnectionSecretKey: pointer.String("password")},
+					},
+				}),
+				xr: xr,
+			},
+			want: []string{},
+		},
+		"UnknownFromFieldPath": {
+			reason: "We should warn about patches from fields that aren't in the composite resource's schema.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Name: pointer.String("db"),
+					Base: base,
+					Patches: []v1.Patch{
+						from("spec.sizee", "spec.forProvider.instanceClass"),
+						{
+							Type: v1.PatchTypeCombineFromComposite,
+							Combine: &v1.Combine{Variables: []v1.CombineVariable{
+								{FromFieldPath: "spec.region"},
+								{FromFieldPath: "spec.zone"},
+							}},
+							ToFieldPath: pointer.String("spec.forProvider.location"),
+						},
+					},
+				}),
+				xr: xr,
+			},
+			want: []string{
+				fmt.Sprintf(warnFmtFromPath, `"db"`, 0, "spec.sizee", ValidateFieldPath(xr, "spec.sizee")),
+				fmt.Sprintf(warnFmtVarPath, `"db"`, 1, 1, "spec.zone", ValidateFieldPath(xr, "spec.zone")),
+			},
+		},
+		"UnknownSchema": {
+			reason: "We should not check fromFieldPaths if we don't know the composite resource's schema.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Base:    base,
+					Patches: []v1.Patch{from("spec.sizee", "spec.forProvider.instanceClass")},
+				}),
+			},
+			want: []string{},
+		},
+		"Overwritten": {
+			reason: "We should warn about patches that overwrite another patch's toFieldPath.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Base: base,
+					Patches: []v1.Patch{
+						from("spec.size", "spec.forProvider.instanceClass"),
+						from("spec.region", "spec.forProvider.instanceClass"),
+					},
+				}),
+			},
+			want: []string{
+				fmt.Sprintf(warnFmtOverwritten, "0", 0, 1, "spec.forProvider.instanceClass", 1, 0),
+			},
+		},
+		"Merged": {
+			reason: "We should not warn about patches that merge their values into the same toFieldPath.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Base: base,
+					Patches: []v1.Patch{
+						from("metadata.labels", "spec.forProvider.tags"),
+						func() v1.Patch {
+							p := from("metadata.annotations", "spec.forProvider.tags")
+							p.Policy = &v1.PatchPolicy{MergeOptions: &xpv1.MergeOptions{KeepMapValues: pointer.Bool(true)}}
+							return p
+						}(),
+					},
+				}),
+			},
+			want: []string{},
+		},
+		"CompositeOverwritten": {
+			reason: "We should warn about patches from different templates that patch the same composite resource field.",
+			args: args{
+				comp: comp(
+					v1.ComposedTemplate{Name: pointer.String("a"), Base: base, Patches: []v1.Patch{toXR("status.atProvider.id", "status.id")}},
+					v1.ComposedTemplate{Name: pointer.String("b"), Base: base, Patches: []v1.Patch{toXR("status.atProvider.id", "status.id")}},
+				),
+			},
+			want: []string{
+				fmt.Sprintf(warnFmtXROverwritten, 0, `"a"`, 0, `"b"`, "status.id"),
+			},
+		},
+		"NoConnectionSecret": {
+			reason: "We should warn about connection details read from a connection secret the template never writes.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Base: base,
+					ConnectionDetails: []v1.ConnectionDetail{
+						{FromConnectionSecretKey: pointer.String("password")},
+						{Name: pointer.String("static"), Value: pointer.String("cool")},
+					},
+				}),
+			},
+			want: []string{
+				fmt.Sprintf(warnFmtNoConnectionSecret, "0", 0, "password"),
+			},
+		},
+		"PatchedConnectionSecret": {
+			reason: "We should not warn about connection details read from a connection secret a patch specifies.",
+			args: args{
+				comp: comp(v1.ComposedTemplate{
+					Base:    base,
+					Patches: []v1.Patch{from("spec.writeConnectionSecretToRef.namespace", "spec.writeConnectionSecretToRef.namespace")},
+					ConnectionDetails: []v1.ConnectionDetail{
+						{FromConnectionSecretKey: pointer.String("password")},
+					},
+				}),
+			},
+			want: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Lint(tc.args.comp, tc.args.xr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLint(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+const (
+	errFmtUnknownField = "%s: unknown field"
+	errFmtNotObject    = "%s: is not an object"
+	errFmtNotArray     = "%s: is not an array"
+	errFmtRequired     = "%s: required field is missing"
+	errFmtType         = "%s: must be of type %s"
+	errFmtEnum         = "%s: must be one of the enumerated values"
+	errParseFieldPath  = "cannot parse field path"
+	wildcard           = "*"
+)
+
+// ValidateFieldPath returns an error if the supplied field path could not
+// exist in an object with the supplied schema. A nil schema is unknown; any
+// field path is considered valid.
+func ValidateFieldPath(s *extv1.JSONSchemaProps, path string) error {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return errors.Wrap(err, errParseFieldPath)
+	}
+
+	for i, seg := range segments {
+		if s == nil || preservesUnknown(s) {
+			return nil
+		}
+
+		// The API server validates metadata, so composite resource and
+		// claim schemas don't specify it.
+		if i == 0 && seg.Type == fieldpath.SegmentField && seg.Field == "metadata" {
+			return nil
+		}
+
+		at := segments[:i+1].String()
+		switch {
+		case seg.Type == fieldpath.SegmentIndex || seg.Field == wildcard && s.Type == "array":
+			if s.Type != "array" {
+				return errors.Errorf(errFmtNotArray, segments[:i].String())
+			}
+			if s.Items == nil {
+				return nil
+			}
+			s = s.Items.Schema
+		default:
+			if s.Type != "object" {
+				return errors.Errorf(errFmtNotObject, segments[:i].String())
+			}
+			p, ok := s.Properties[seg.Field]
+			switch {
+			case seg.Field == wildcard:
+				// We can't tell which fields a wildcard will match.
+				return nil
+			case ok:
+				s = &p
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+				s = s.AdditionalProperties.Schema
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Allows:
+				return nil
+			default:
+				return errors.Errorf(errFmtUnknownField, at)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateObject returns every way in which the supplied object does not
+// conform to the supplied schema. It checks for unknown and missing required
+// fields, types, and enumerations, but not other validation rules such as
+// patterns or minimums.
+func ValidateObject(s *extv1.JSONSchemaProps, obj map[string]any) []error {
+	errs := make([]error, 0)
+	validateObject(s, obj, nil, true, &errs)
+	return errs
+}
+
+func validateObject(s *extv1.JSONSchemaProps, obj map[string]any, path fieldpath.Segments, root bool, errs *[]error) {
+	for _, r := range s.Required {
+		if _, ok := obj[r]; !ok {
+			*errs = append(*errs, errors.Errorf(errFmtRequired, child(path, fieldpath.Field(r)).String()))
+		}
+	}
+
+	// Sort our keys so that we return errors in a deterministic order.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// The API server validates these fields.
+		if root && (k == "apiVersion" || k == "kind" || k == "metadata") {
+			continue
+		}
+		p := child(path, fieldpath.Field(k))
+		prop, ok := s.Properties[k]
+		switch {
+		case ok:
+			validateValue(&prop, obj[k], p, errs)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+			validateValue(s.AdditionalProperties.Schema, obj[k], p, errs)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Allows:
+		case preservesUnknown(s):
+		default:
+			*errs = append(*errs, errors.Errorf(errFmtUnknownField, p.String()))
+		}
+	}
+}
+
+func validateValue(s *extv1.JSONSchemaProps, v any, path fieldpath.Segments, errs *[]error) {
+	if v == nil && s.Nullable {
+		return
+	}
+	if len(s.Enum) > 0 && !enumerated(s.Enum, v) {
+		*errs = append(*errs, errors.Errorf(errFmtEnum, path.String()))
+	}
+
+	switch s.Type {
+	case "object":
+		o, ok := v.(map[string]any)
+		if !ok {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+			return
+		}
+		validateObject(s, o, path, false, errs)
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+			return
+		}
+		if s.Items == nil || s.Items.Schema == nil {
+			return
+		}
+		for i := range a {
+			validateValue(s.Items.Schema, a[i], child(path, fieldpath.Segment{Type: fieldpath.SegmentIndex, Index: uint(i)}), errs)
+		}
+	case "string":
+		if _, ok := v.(string); !ok && !s.XIntOrString {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+		}
+	case "integer":
+		if !isInteger(v) && !(s.XIntOrString && isString(v)) {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+		}
+	case "number":
+		if !isInteger(v) && !isFloat(v) {
+			*errs = append(*errs, errors.Errorf(errFmtType, path.String(), s.Type))
+		}
+	}
+}
+
+// child returns a copy of the supplied path with the supplied segment appended.
+func child(path fieldpath.Segments, s fieldpath.Segment) fieldpath.Segments {
+	return append(append(make(fieldpath.Segments, 0, len(path)+1), path...), s)
+}
+
+func preservesUnknown(s *extv1.JSONSchemaProps) bool {
+	return s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields
+}
+
+func enumerated(enum []extv1.JSON, v any) bool {
+	for _, e := range enum {
+		var ev any
+		if err := json.Unmarshal(e.Raw, &ev); err != nil {
+			continue
+		}
+		if reflect.DeepEqual(normalize(ev), normalize(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isInteger(v any) bool {
+	switch n := v.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return n == float64(int64(n))
+	}
+	return false
+}
+
+func isFloat(v any) bool {
+	_, ok := v.(float64)
+	return ok
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// normalize numeric values so that values parsed from YAML and JSON compare
+// equal.
+func normalize(v any) any {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return v
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate validates CompositeResourceDefinitions, Compositions, and
+// example composite resources and claims without access to an API server.
+package validate
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	errReadYAML      = "cannot read YAML document"
+	errDecodeYAML    = "cannot decode YAML document"
+	errConvert       = "cannot convert object"
+	errUnmarshalBase = "cannot unmarshal base template"
+	errComposite     = "cannot derive composite resource CRD"
+	errClaim         = "cannot derive claim CRD"
+	errPatchSets     = "cannot resolve patch sets"
+	errTemplate      = "template"
+
+	errFmtObject        = "%s %q"
+	errFmtNoSchema      = "no CRD or XRD defines the schema for %s"
+	errFmtTemplate      = "resource template %s"
+	errFmtPatch         = "patch %d"
+	errFmtReadinessPath = "readiness check %d"
+	errFmtFromPath      = "fromFieldPath %q"
+	errFmtToPath        = "toFieldPath %q"
+	errFmtVarPath       = "combine variable %d fromFieldPath %q"
+)
+
+// Parse the YAML documents in the supplied reader as unstructured objects.
+func Parse(r io.Reader) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, 0)
+	yr := yaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		b, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadYAML)
+		}
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(b, &u.Object); err != nil {
+			return nil, errors.Wrap(err, errDecodeYAML)
+		}
+		// Skip empty documents, e.g. those containing only comments.
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
+
+// A Validator validates CompositeResourceDefinitions, Compositions, and
+// example composite resources and claims without access to an API server.
+type Validator struct {
+	xrds    []*v1.CompositeResourceDefinition
+	comps   []*v1.Composition
+	crds    []*extv1.CustomResourceDefinition
+	objects []*unstructured.Unstructured
+
+	schemas map[schema.GroupVersionKind]*extv1.JSONSchemaProps
+}
+
+// New returns a Validator for the supplied objects. XRDs, Compositions, and
+// CRDs are validated and used to validate the remaining objects. CRDs may be
+// supplied to validate the patches of Compositions against the schemas of
+// the resources they compose.
+func New(objs []*unstructured.Unstructured) (*Validator, error) {
+	v := &Validator{schemas: map[schema.GroupVersionKind]*extv1.JSONSchemaProps{}}
+	for _, u := range objs {
+		switch u.GroupVersionKind() {
+		case v1.CompositeResourceDefinitionGroupVersionKind:
+			xrd := &v1.CompositeResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, xrd); err != nil {
+				return nil, errors.Wrapf(errors.Wrap(err, errConvert), errFmtObject, u.GetKind(), u.GetName())
+			}
+			v.xrds = append(v.xrds, xrd)
+		case v1.CompositionGroupVersionKind:
+			comp := &v1.Composition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, comp); err != nil {
+				return nil, errors.Wrapf(errors.Wrap(err, errConvert), errFmtObject, u.GetKind(), u.GetName())
+			}
+			v.comps = append(v.comps, comp)
+		case extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"):
+			crd := &extv1.CustomResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+				return nil, errors.Wrapf(errors.Wrap(err, errConvert), errFmtObject, u.GetKind(), u.GetName())
+			}
+			v.crds = append(v.crds, crd)
+		default:
+			v.objects = append(v.objects, u)
+		}
+	}
+	return v, nil
+}
+
+// Validate returns every problem found with the Validator's objects.
+func (v *Validator) Validate() []error {
+	errs := make([]error, 0)
+
+	for _, crd := range v.crds {
+		v.addSchemas(crd)
+	}
+
+	for _, xrd := range v.xrds {
+		crd, err := xcrd.ForCompositeResource(xrd)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(errors.Wrap(err, errComposite), errFmtObject, xrd.Kind, xrd.GetName()))
+			continue
+		}
+		v.addSchemas(crd)
+
+		if xrd.Spec.ClaimNames == nil {
+			continue
+		}
+		crd, err = xcrd.ForCompositeResourceClaim(xrd)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(errors.Wrap(err, errClaim), errFmtObject, xrd.Kind, xrd.GetName()))
+			continue
+		}
+		v.addSchemas(crd)
+	}
+
+	for _, comp := range v.comps {
+		for _, err := range v.validateComposition(comp) {
+			errs = append(errs, errors.Wrapf(err, errFmtObject, comp.Kind, comp.GetName()))
+		}
+	}
+
+	for _, u := range v.objects {
+		s, ok := v.schemas[u.GroupVersionKind()]
+		if !ok {
+			errs = append(errs, errors.Wrapf(errors.Errorf(errFmtNoSchema, u.GroupVersionKind()), errFmtObject, u.GetKind(), u.GetName()))
+			continue
+		}
+		for _, err := range ValidateObject(s, u.Object) {
+			errs = append(errs, errors.Wrapf(err, errFmtObject, u.GetKind(), u.GetName()))
+		}
+	}
+
+	return errs
+}
+
+func (v *Validator) addSchemas(crd *extv1.CustomResourceDefinition) {
+	for _, vr := range crd.Spec.Versions {
+		if vr.Schema == nil || vr.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: vr.Name, Kind: crd.Spec.Names.Kind}
+		v.schemas[gvk] = vr.Schema.OpenAPIV3Schema
+	}
+}
+
+func (v *Validator) validateComposition(comp *v1.Composition) []error {
+	errs := make([]error, 0)
+
+	for _, fn := range []func(*v1.Composition) error{composite.RejectMixedTemplates, composite.RejectDuplicateNames} {
+		if err := fn(comp); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	gv, err := schema.ParseGroupVersion(comp.Spec.CompositeTypeRef.APIVersion)
+	if err != nil {
+		return append(errs, err)
+	}
+	xrGVK := gv.WithKind(comp.Spec.CompositeTypeRef.Kind)
+	xr, ok := v.schemas[xrGVK]
+	if !ok {
+		errs = append(errs, errors.Errorf(errFmtNoSchema, xrGVK))
+	}
+
+	templates, err := comp.Spec.ComposedTemplates()
+	if err != nil {
+		return append(errs, errors.Wrap(err, errPatchSets))
+	}
+
+	for i, t := range templates {
+		id := fmt.Sprintf("%d", i)
+		if t.Name != nil {
+			id = fmt.Sprintf("%q", *t.Name)
+		}
+
+		tm := &metav1.TypeMeta{}
+		if err := json.Unmarshal(t.Base.Raw, tm); err != nil {
+			errs = append(errs, errors.Wrapf(errors.Wrap(err, errUnmarshalBase), errFmtTemplate, id))
+			continue
+		}
+		cd := v.schemas[tm.GroupVersionKind()]
+
+		for j, p := range t.Patches {
+			for _, err := range validatePatch(p, xr, cd) {
+				errs = append(errs, errors.Wrapf(errors.Wrapf(err, errFmtPatch, j), errFmtTemplate, id))
+			}
+		}
+
+		for j, rc := range t.ReadinessChecks {
+			if rc.FieldPath == "" {
+				continue
+			}
+			if err := ValidateFieldPath(cd, rc.FieldPath); err != nil {
+				errs = append(errs, errors.Wrapf(errors.Wrapf(err, errFmtReadinessPath, j), errFmtTemplate, id))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validatePatch validates the field paths of the supplied patch against the
+// supplied composite and composed resource schemas. A nil schema is unknown,
+// and any field path is considered valid.
+func validatePatch(p v1.Patch, xr, cd *extv1.JSONSchemaProps) []error {
+	from, to := xr, cd
+	switch p.Type {
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+		from, to = cd, xr
+	case v1.PatchTypePatchSet:
+		// Patch sets are resolved before patches are validated.
+		return nil
+	case v1.PatchTypeExternalNameFromComposite:
+		// The external name annotation is always patched, and any
+		// annotation is valid.
+		to = nil
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite, v1.PatchTypeTemplateFromComposite:
+	}
+
+	errs := make([]error, 0)
+	if p.FromFieldPath != nil {
+		if err := ValidateFieldPath(from, *p.FromFieldPath); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtFromPath, *p.FromFieldPath))
+		}
+	}
+	if p.Template != nil {
+		// Template field paths aren't validated, but the template must parse.
+		if _, err := v1.ParseTemplate(*p.Template); err != nil {
+			errs = append(errs, errors.Wrap(err, errTemplate))
+		}
+	}
+	if p.Combine != nil {
+		for i, cv := range p.Combine.Variables {
+			if err := ValidateFieldPath(from, cv.FromFieldPath); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtVarPath, i, cv.FromFieldPath))
+			}
+		}
+	}
+
+	// Patches default to patching the same path they patch from.
+	toPath := p.ToFieldPath
+	if toPath == nil {
+		toPath = p.FromFieldPath
+	}
+	if toPath != nil {
+		if err := ValidateFieldPath(to, *toPath); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtToPath, *toPath))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const xrd = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xdatabases.example.org
+spec:
+  group: example.org
+  names:
+    kind: XDatabase
+    plural: xdatabases
+  claimNames:
+    kind: Database
+    plural: databases
+  versions:
+  - name: v1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [size]
+            properties:
+              size:
+                type: string
+                enum: [small, large]
+              region:
+                type: string
+`
+
+const crd = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: instances.db.example.org
+spec:
+  group: db.example.org
+  names:
+    kind: Instance
+    plural: instances
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              forProvider:
+                type: object
+                properties:
+                  instanceClass:
+                    type: string
+                  tags:
+                    type: object
+                    additionalProperties:
+                      type: string
+`
+
+func composition(patches string) string {
+	return `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xdatabases.example.org
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XDatabase
+  resources:
+  - name: instance
+    base:
+      apiVersion: db.example.org/v1
+      kind: Instance
+    patches:
+` + patches
+}
+
+const claim = `
+apiVersion: example.org/v1
+kind: Database
+metadata:
+  name: cool-db
+  namespace: default
+spec:
+  size: %s
+`
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		docs   []string
+		want   []error
+	}{
+		"Valid": {
+			reason: "Valid XRDs, Compositions, and claims should not return any errors.",
+			docs: []string{xrd, crd, composition(`
+    - fromFieldPath: spec.size
+      toFieldPath: spec.forProvider.instanceClass
+    - fromFieldPath: metadata.labels[example.org/team]
+      toFieldPath: spec.forProvider.tags[team]
+    - type: ToCompositeFieldPath
+      fromFieldPath: spec.forProvider.instanceClass
+      toFieldPath: spec.region
+`), strings.Replace(claim, "%s", "small", 1)},
+			want: []error{},
+		},
+		"InvalidPatchPaths": {
+			reason: "Patch field paths that don't exist in the composite or composed resource schema should return errors.",
+			docs: []string{xrd, crd, composition(`
+    - fromFieldPath: spec.sizee
+      toFieldPath: spec.forProvider.instanceClass
+    - type: CombineFromComposite
+      combine:
+        variables:
+        - fromFieldPath: spec.region
+        - fromFieldPath: spec.zone
+        strategy: string
+        string:
+          fmt: "%s-%s"
+      toFieldPath: spec.forProvider.klass
+`)},
+			want: []error{
+				errors.New(`Composition "xdatabases.example.org": resource template "instance": patch 0: fromFieldPath "spec.sizee": spec.sizee: unknown field`),
+				errors.New(`Composition "xdatabases.example.org": resource template "instance": patch 1: combine variable 1 fromFieldPath "spec.zone": spec.zone: unknown field`),
+				errors.New(`Composition "xdatabases.example.org": resource template "instance": patch 1: toFieldPath "spec.forProvider.klass": spec.forProvider.klass: unknown field`),
+			},
+		},
+		"InvalidTemplate": {
+			reason: "A template patch whose template can't be parsed should return an error.",
+			docs: []string{xrd, crd, composition(`
+    - type: TemplateFromComposite
+      template: "{{ .spec.size "
+      toFieldPath: spec.forProvider.instanceClass
+`)},
+			want: []error{
+				errors.New(`Composition "xdatabases.example.org": resource template "instance": patch 0: template: cannot parse template: template: patch:1: unclosed action`),
+			},
+		},
+		"ExternalNamePatch": {
+			reason: "An external name patch's fromFieldPath should only be validated against the composite resource schema.",
+			docs: []string{xrd, crd, composition(`
+    - type: ExternalNameFromComposite
+      fromFieldPath: spec.size
+`)},
+			want: []error{},
+		},
+		"UnknownComposedSchema": {
+			reason: "Patches to a composed resource whose schema is unknown should only be validated against the composite resource schema.",
+			docs: []string{xrd, composition(`
+    - fromFieldPath: spec.size
+      toFieldPath: spec.anything.goes
+`)},
+			want: []error{},
+		},
+		"InvalidClaim": {
+			reason: "A claim that does not conform to its XRD's schema should return an error.",
+			docs:   []string{xrd, strings.Replace(claim, "%s", "medium", 1)},
+			want: []error{
+				errors.New(`Database "cool-db": spec.size: must be one of the enumerated values`),
+			},
+		},
+		"NoSchema": {
+			reason: "An object whose schema is unknown should return an error.",
+			docs:   []string{strings.Replace(claim, "%s", "small", 1)},
+			want: []error{
+				errors.New(`Database "cool-db": no CRD or XRD defines the schema for example.org/v1, Kind=Database`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := Parse(strings.NewReader(strings.Join(tc.docs, "\n---\n")))
+			if err != nil {
+				t.Fatalf("Parse(...): %s", err)
+			}
+			v, err := New(objs)
+			if err != nil {
+				t.Fatalf("New(...): %s", err)
+			}
+			// Validation errors are reported to users as messages, and
+			// wrap several layers deep, so we only compare messages.
+			got := v.Validate()
+			if diff := cmp.Diff(tc.want, got, equateMessages); diff != "" {
+				t.Errorf("\n%s\nv.Validate(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// equateMessages considers errors equal if their messages are equal.
+var equateMessages = cmp.Comparer(func(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Error() == b.Error()
+})
+
+func TestValidateObject(t *testing.T) {
+	s := &extv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"spec"},
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"count": {Type: "integer"},
+					"names": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    map[string]any
+		want   []error
+	}{
+		"Valid": {
+			reason: "An object that conforms to the schema should not return any errors.",
+			obj:    map[string]any{"apiVersion": "v1", "kind": "K", "metadata": map[string]any{}, "spec": map[string]any{"count": int64(1), "names": []any{"a"}}},
+			want:   []error{},
+		},
+		"MissingRequired": {
+			reason: "A missing required field should return an error.",
+			obj:    map[string]any{},
+			want:   []error{errors.New("spec: required field is missing")},
+		},
+		"WrongTypes": {
+			reason: "Fields of the wrong type should return errors.",
+			obj:    map[string]any{"spec": map[string]any{"count": "one", "names": []any{"a", int64(2)}}},
+			want: []error{
+				errors.New("spec.count: must be of type integer"),
+				errors.New("spec.names[1]: must be of type string"),
+			},
+		},
+		"UnknownField": {
+			reason: "An unknown field should return an error.",
+			obj:    map[string]any{"spec": map[string]any{"colour": "blue"}},
+			want:   []error{errors.New("spec.colour: unknown field")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateObject(s, tc.obj)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateObject(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// MutatingWebhookPath is the path at which the claim mutating webhook is
+// served.
+const MutatingWebhookPath = "/mutate-claims"
+
+// Error strings.
+const (
+	errEncode = "cannot encode claim"
+)
+
+// A RequesterAnnotatorOption configures a RequesterAnnotator.
+type RequesterAnnotatorOption func(*RequesterAnnotator)
+
+// WithTrustedUsers specifies users that may create claims on behalf of other
+// users. The requester annotation of a claim created by a trusted user is
+// preserved, rather than replaced by the trusted user. Crossplane should trust
+// its own service account, so that claims it migrates to a new kind keep their
+// original requester.
+func WithTrustedUsers(users ...string) RequesterAnnotatorOption {
+	return func(a *RequesterAnnotator) {
+		for _, u := range users {
+			a.trusted[u] = true
+		}
+	}
+}
+
+// NewRequesterAnnotator returns a RequesterAnnotator of claims.
+func NewRequesterAnnotator(o ...RequesterAnnotatorOption) *RequesterAnnotator {
+	a := &RequesterAnnotator{trusted: map[string]bool{}}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
+}
+
+// A RequesterAnnotator annotates claims with the user that requested them, so
+// that the user can be propagated to the claim's composite and composed
+// resources. The annotation is set when a claim is created, and can't be
+// changed afterward.
+type RequesterAnnotator struct {
+	trusted map[string]bool
+}
+
+// Handle an admission request for a claim.
+func (a *RequesterAnnotator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	cm := claim.New()
+	if err := json.Unmarshal(req.Object.Raw, &cm.Unstructured); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	// The requester of a claim is whoever created it. We don't trust any
+	// requester annotation supplied by the user unless they're a trusted
+	// user, and preserve the original requester (if any) when a claim is
+	// updated.
+	requester := req.UserInfo.Username
+	if u := cm.GetAnnotations()[xcrd.AnnotationKeyClaimRequester]; u != "" && a.trusted[requester] {
+		requester = u
+	}
+	if req.Operation == admissionv1.Update {
+		old := claim.New()
+		if err := json.Unmarshal(req.OldObject.Raw, &old.Unstructured); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+		}
+		requester = old.GetAnnotations()[xcrd.AnnotationKeyClaimRequester]
+	}
+
+	current, ok := cm.GetAnnotations()[xcrd.AnnotationKeyClaimRequester]
+	if current == requester && ok == (requester != "") {
+		return admission.Allowed("")
+	}
+
+	if requester == "" {
+		meta.RemoveAnnotations(cm, xcrd.AnnotationKeyClaimRequester)
+	} else {
+		meta.AddAnnotations(cm, map[string]string{xcrd.AnnotationKeyClaimRequester: requester})
+	}
+
+	raw, err := json.Marshal(&cm.Unstructured)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errEncode))
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+var _ admission.Handler = &RequesterAnnotator{}
+
+func TestRequesterAnnotatorHandle(t *testing.T) {
+	raw := func(c *claim.Unstructured) runtime.RawExtension {
+		b, _ := json.Marshal(c)
+		return runtime.RawExtension{Raw: b}
+	}
+	requestedBy := func(c *claim.Unstructured, u string) *claim.Unstructured {
+		meta.AddAnnotations(c, map[string]string{xcrd.AnnotationKeyClaimRequester: u})
+		return c
+	}
+	withoutRequester := func(c *claim.Unstructured) *claim.Unstructured {
+		meta.RemoveAnnotations(c, xcrd.AnnotationKeyClaimRequester)
+		return c
+	}
+	// patch returns the response that patches the first supplied claim to
+	// become the second.
+	patch := func(from, to *claim.Unstructured) admission.Response {
+		return admission.PatchResponseFromRaw(raw(from).Raw, raw(to).Raw)
+	}
+	alice := authv1.UserInfo{Username: "alice"}
+	crossplane := authv1.UserInfo{Username: "system:serviceaccount:crossplane-system:crossplane"}
+
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   admission.Response
+	}{
+		"Delete": {
+			reason: "We should allow operations other than creates and updates.",
+			req:    admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			want:   admission.Allowed(""),
+		},
+		"Create": {
+			reason: "We should annotate a new claim with the user that created it.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  alice,
+				Object:    raw(cm("Database", "a", "")),
+			}},
+			want: patch(cm("Database", "a", ""), requestedBy(cm("Database", "a", ""), "alice")),
+		},
+		"CreateForged": {
+			reason: "We should overwrite any requester annotation supplied when a claim is created.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  alice,
+				Object:    raw(requestedBy(cm("Database", "a", ""), "bob")),
+			}},
+			want: patch(requestedBy(cm("Database", "a", ""), "bob"), requestedBy(cm("Database", "a", ""), "alice")),
+		},
+		"CreateTrusted": {
+			reason: "We should preserve the requester annotation supplied by a trusted user when a claim is created.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  crossplane,
+				Object:    raw(requestedBy(cm("Database", "a", ""), "alice")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"CreateTrustedWithoutRequester": {
+			reason: "We should annotate a new claim with the trusted user that created it if it has no requester.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  crossplane,
+				Object:    raw(cm("Database", "a", "")),
+			}},
+			want: patch(cm("Database", "a", ""), requestedBy(cm("Database", "a", ""), crossplane.Username)),
+		},
+		"UpdateUnchanged": {
+			reason: "We should allow an update that preserves the requester annotation.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authv1.UserInfo{Username: "bob"},
+				Object:    raw(requestedBy(cm("Database", "a", "conn"), "alice")),
+				OldObject: raw(requestedBy(cm("Database", "a", ""), "alice")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"UpdateChanged": {
+			reason: "We should restore the original requester if an update changes it.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authv1.UserInfo{Username: "bob"},
+				Object:    raw(requestedBy(cm("Database", "a", ""), "bob")),
+				OldObject: raw(requestedBy(cm("Database", "a", ""), "alice")),
+			}},
+			want: patch(requestedBy(cm("Database", "a", ""), "bob"), requestedBy(cm("Database", "a", ""), "alice")),
+		},
+		"UpdateRemoved": {
+			reason: "We should restore the original requester if an update removes it.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authv1.UserInfo{Username: "bob"},
+				Object:    raw(cm("Database", "a", "")),
+				OldObject: raw(requestedBy(cm("Database", "a", ""), "alice")),
+			}},
+			want: patch(cm("Database", "a", ""), requestedBy(cm("Database", "a", ""), "alice")),
+		},
+		"UpdateAdded": {
+			reason: "We should remove a requester added to a claim that was created without one.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authv1.UserInfo{Username: "bob"},
+				Object:    raw(requestedBy(cm("Database", "a", ""), "bob")),
+				OldObject: raw(cm("Database", "a", "")),
+			}},
+			want: patch(requestedBy(cm("Database", "a", ""), "bob"), withoutRequester(requestedBy(cm("Database", "a", ""), "bob"))),
+		},
+		"UpdateWithoutRequester": {
+			reason: "We should allow updating a claim that was created without a requester.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authv1.UserInfo{Username: "bob"},
+				Object:    raw(cm("Database", "a", "conn")),
+				OldObject: raw(cm("Database", "a", "")),
+			}},
+			want: admission.Allowed(""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewRequesterAnnotator(WithTrustedUsers(crossplane.Username))
+			got := a.Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\na.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	// WebhookConfigurationName is the name of the ValidatingWebhookConfiguration
+	// that Crossplane's initializer installs, and of the
+	// MutatingWebhookConfiguration that hosts the claim mutating webhook.
+	WebhookConfigurationName = "crossplane"
+
+	// WebhookName is the name of the claim webhook within the
+	// ValidatingWebhookConfiguration and the MutatingWebhookConfiguration.
+	WebhookName = "claims.apiextensions.crossplane.io"
+
+	timeout = 2 * time.Minute
+)
+
+// Error strings.
+const (
+	errGetConfig    = "cannot get ValidatingWebhookConfiguration"
+	errUpdateConfig = "cannot update ValidatingWebhookConfiguration"
+	errNoTemplate   = "cannot find a webhook to copy client configuration from"
+
+	errGetMutatingConfig    = "cannot get MutatingWebhookConfiguration"
+	errCreateMutatingConfig = "cannot create MutatingWebhookConfiguration"
+	errUpdateMutatingConfig = "cannot update MutatingWebhookConfiguration"
+)
+
+// SetupRules adds controllers that keep the rules of the claim webhooks in
+// sync with the kinds of claim that CompositeResourceDefinitions offer. Claim
+// kinds are defined at runtime, so the rules can't be installed along with the
+// rest of Crossplane's webhooks.
+func SetupRules(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("webhook/claims").
+		For(&v1.CompositeResourceDefinition{}).
+		Complete(NewRulesReconciler(mgr.GetClient())); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("webhook/claims-mutating").
+		For(&v1.CompositeResourceDefinition{}).
+		Complete(NewMutatingRulesReconciler(mgr.GetClient()))
+}
+
+// NewRulesReconciler returns a reconciler that keeps the rules of the claim
+// webhook in sync with the kinds of claim that are offered.
+func NewRulesReconciler(c client.Client) *RulesReconciler {
+	return &RulesReconciler{client: c}
+}
+
+// A RulesReconciler keeps the rules of the claim webhook in sync with the kinds
+// of claim that are offered.
+type RulesReconciler struct {
+	client client.Client
+}
+
+// Reconcile the claim webhook. The webhook is added to the
+// ValidatingWebhookConfiguration when the first kind of claim is offered, and
+// removed when the last is withdrawn. It's served by the same service as the
+// configuration's other webhooks.
+func (r *RulesReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	xrds := &v1.CompositeResourceDefinitionList{}
+	if err := r.client.List(ctx, xrds); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListXRDs)
+	}
+
+	vwc := &admv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, vwc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetConfig)
+	}
+
+	rules := Rules(xrds.Items)
+	hooks := make([]admv1.ValidatingWebhook, 0, len(vwc.Webhooks)+1)
+	var current, tmpl *admv1.ValidatingWebhook
+	for i := range vwc.Webhooks {
+		if vwc.Webhooks[i].Name == WebhookName {
+			current = &vwc.Webhooks[i]
+			continue
+		}
+		if tmpl == nil {
+			tmpl = &vwc.Webhooks[i]
+		}
+		hooks = append(hooks, vwc.Webhooks[i])
+	}
+
+	if len(rules) == 0 {
+		if current == nil {
+			return reconcile.Result{}, nil
+		}
+		vwc.Webhooks = hooks
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, vwc), errUpdateConfig)
+	}
+
+	if tmpl == nil {
+		return reconcile.Result{}, errors.New(errNoTemplate)
+	}
+	want := claimWebhook(tmpl.ClientConfig, rules)
+
+	// The API server defaults some fields of the webhook, so we only compare
+	// those we set to determine whether it needs to be updated.
+	if current != nil && equality.Semantic.DeepEqual(current.Rules, want.Rules) && equality.Semantic.DeepEqual(current.ClientConfig, want.ClientConfig) {
+		return reconcile.Result{}, nil
+	}
+	hooks = append(hooks, want)
+	vwc.Webhooks = hooks
+	return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, vwc), errUpdateConfig)
+}
+
+// NewMutatingRulesReconciler returns a reconciler that keeps the rules of the
+// claim mutating webhook in sync with the kinds of claim that are offered.
+func NewMutatingRulesReconciler(c client.Client) *MutatingRulesReconciler {
+	return &MutatingRulesReconciler{client: c}
+}
+
+// A MutatingRulesReconciler keeps the rules of the claim mutating webhook in
+// sync with the kinds of claim that are offered.
+type MutatingRulesReconciler struct {
+	client client.Client
+}
+
+// Reconcile the claim mutating webhook. Crossplane's initializer doesn't
+// install a MutatingWebhookConfiguration, so it's created when the first kind
+// of claim is offered. The webhook is served by the same service as the
+// ValidatingWebhookConfiguration's webhooks.
+func (r *MutatingRulesReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) { // nolint:gocyclo
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	xrds := &v1.CompositeResourceDefinitionList{}
+	if err := r.client.List(ctx, xrds); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListXRDs)
+	}
+
+	vwc := &admv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, vwc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetConfig)
+	}
+
+	var tmpl *admv1.ValidatingWebhook
+	for i := range vwc.Webhooks {
+		if vwc.Webhooks[i].Name != WebhookName {
+			tmpl = &vwc.Webhooks[i]
+			break
+		}
+	}
+
+	rules := Rules(xrds.Items)
+
+	mwc := &admv1.MutatingWebhookConfiguration{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, mwc)
+	if resource.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.Wrap(err, errGetMutatingConfig)
+	}
+	if kerrors.IsNotFound(err) {
+		if len(rules) == 0 {
+			return reconcile.Result{}, nil
+		}
+		if tmpl == nil {
+			return reconcile.Result{}, errors.New(errNoTemplate)
+		}
+		mwc.SetName(WebhookConfigurationName)
+		mwc.Webhooks = []admv1.MutatingWebhook{claimMutatingWebhook(tmpl.ClientConfig, rules)}
+		return reconcile.Result{}, errors.Wrap(r.client.Create(ctx, mwc), errCreateMutatingConfig)
+	}
+
+	hooks := make([]admv1.MutatingWebhook, 0, len(mwc.Webhooks)+1)
+	var current *admv1.MutatingWebhook
+	for i := range mwc.Webhooks {
+		if mwc.Webhooks[i].Name == WebhookName {
+			current = &mwc.Webhooks[i]
+			continue
+		}
+		hooks = append(hooks, mwc.Webhooks[i])
+	}
+
+	if len(rules) == 0 {
+		if current == nil {
+			return reconcile.Result{}, nil
+		}
+		mwc.Webhooks = hooks
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, mwc), errUpdateMutatingConfig)
+	}
+
+	if tmpl == nil {
+		return reconcile.Result{}, errors.New(errNoTemplate)
+	}
+
+	want := claimMutatingWebhook(tmpl.ClientConfig, rules)
+	if current != nil && equality.Semantic.DeepEqual(current.Rules, want.Rules) && equality.Semantic.DeepEqual(current.ClientConfig, want.ClientConfig) {
+		return reconcile.Result{}, nil
+	}
+
+	hooks = append(hooks, want)
+	mwc.Webhooks = hooks
+	return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, mwc), errUpdateMutatingConfig)
+}
+
+// Rules returns the webhook rules that match the kinds of claim the supplied
+// CompositeResourceDefinitions offer, or previously offered.
+func Rules(xrds []v1.CompositeResourceDefinition) []admv1.RuleWithOperations {
+	rules := make([]admv1.RuleWithOperations, 0, len(xrds))
+	for _, xrd := range xrds {
+		if !xrd.OffersClaim() || xrd.GetDeletionTimestamp() != nil {
+			continue
+		}
+		rules = append(rules, rule(xrd.Spec.Group, xrd.Spec.ClaimNames.Plural))
+		for _, n := range xrd.Spec.PreviousClaimNames {
+			rules = append(rules, rule(xrd.Spec.Group, n.Plural))
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].APIGroups[0]+"/"+rules[i].Resources[0] < rules[j].APIGroups[0]+"/"+rules[j].Resources[0]
+	})
+	return rules
+}
+
+// rule returns a webhook rule that matches creates and updates of the supplied
+// group and resource.
+func rule(group, resource string) admv1.RuleWithOperations {
+	scope := admv1.NamespacedScope
+	return admv1.RuleWithOperations{
+		Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+		Rule: admv1.Rule{
+			APIGroups:   []string{group},
+			APIVersions: []string{"*"},
+			Resources:   []string{resource},
+			Scope:       &scope,
+		},
+	}
+}
+
+// claimWebhook returns the claim webhook, served by the supplied client
+// configuration's service at the claim validating webhook path.
+func claimWebhook(cc admv1.WebhookClientConfig, rules []admv1.RuleWithOperations) admv1.ValidatingWebhook {
+	cc = *cc.DeepCopy()
+	if cc.Service != nil {
+		path := ValidatingWebhookPath
+		cc.Service.Path = &path
+	}
+	fail := admv1.Fail
+	none := admv1.SideEffectClassNone
+	return admv1.ValidatingWebhook{
+		Name:                    WebhookName,
+		ClientConfig:            cc,
+		Rules:                   rules,
+		FailurePolicy:           &fail,
+		SideEffects:             &none,
+		AdmissionReviewVersions: []string{"v1"},
+	}
+}
+
+// claimMutatingWebhook returns the claim mutating webhook, served by the
+// supplied client configuration's service at the claim mutating webhook path.
+func claimMutatingWebhook(cc admv1.WebhookClientConfig, rules []admv1.RuleWithOperations) admv1.MutatingWebhook {
+	cc = *cc.DeepCopy()
+	if cc.Service != nil {
+		path := MutatingWebhookPath
+		cc.Service.Path = &path
+	}
+	fail := admv1.Fail
+	none := admv1.SideEffectClassNone
+	return admv1.MutatingWebhook{
+		Name:                    WebhookName,
+		ClientConfig:            cc,
+		Rules:                   rules,
+		FailurePolicy:           &fail,
+		SideEffects:             &none,
+		AdmissionReviewVersions: []string{"v1"},
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestRules(t *testing.T) {
+	scope := admv1.NamespacedScope
+	rule := func(resource string) admv1.RuleWithOperations {
+		return admv1.RuleWithOperations{
+			Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+			Rule: admv1.Rule{
+				APIGroups:   []string{"example.org"},
+				APIVersions: []string{"*"},
+				Resources:   []string{resource},
+				Scope:       &scope,
+			},
+		}
+	}
+
+	renamed := xrd("Database", "databases")
+	renamed.Spec.PreviousClaimNames = []extv1.CustomResourceDefinitionNames{{Kind: "DB", Plural: "dbs"}}
+	noClaim := xrd("Bucket", "buckets")
+	noClaim.Spec.ClaimNames = nil
+
+	cases := map[string]struct {
+		reason string
+		xrds   []v1.CompositeResourceDefinition
+		want   []admv1.RuleWithOperations
+	}{
+		"OfferedClaims": {
+			reason: "We should return a rule for each kind of claim that is offered.",
+			xrds:   []v1.CompositeResourceDefinition{xrd("Database", "databases"), noClaim},
+			want:   []admv1.RuleWithOperations{rule("databases")},
+		},
+		"PreviousClaims": {
+			reason: "We should return a rule for each kind of claim that was previously offered.",
+			xrds:   []v1.CompositeResourceDefinition{renamed},
+			want:   []admv1.RuleWithOperations{rule("databases"), rule("dbs")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Rules(tc.xrds)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRules(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRulesReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	other := admv1.ValidatingWebhook{
+		Name: "compositions.apiextensions.crossplane.io",
+		ClientConfig: admv1.WebhookClientConfig{
+			Service:  &admv1.ServiceReference{Name: "crossplane-webhooks", Namespace: "crossplane-system", Path: pointer.String("/validate-compositions")},
+			CABundle: []byte("ca"),
+		},
+	}
+	db := xrd("Database", "databases")
+	rules := Rules([]v1.CompositeResourceDefinition{db})
+	hook := claimWebhook(other.ClientConfig, rules)
+
+	// withConfig returns a MockGetFn that gets a ValidatingWebhookConfiguration
+	// with the supplied webhooks.
+	withConfig := func(hooks ...admv1.ValidatingWebhook) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*admv1.ValidatingWebhookConfiguration).Webhooks = hooks
+			return nil
+		}
+	}
+	withXRDs := func(xrds ...v1.CompositeResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*v1.CompositeResourceDefinitionList).Items = xrds
+			return nil
+		}
+	}
+	// wantUpdate returns a MockUpdateFn that expects the supplied webhooks.
+	wantUpdate := func(hooks ...admv1.ValidatingWebhook) test.MockUpdateFn {
+		return func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			if diff := cmp.Diff(hooks, obj.(*admv1.ValidatingWebhookConfiguration).Webhooks); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   want
+	}{
+		"ListXRDsError": {
+			reason: "We should return any error encountered while listing XRDs.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRDs),
+			},
+		},
+		"ConfigNotFound": {
+			reason: "We should do nothing if the ValidatingWebhookConfiguration does not exist.",
+			client: &test.MockClient{
+				MockList: withXRDs(db),
+				MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, WebhookConfigurationName)),
+			},
+		},
+		"AddWebhook": {
+			reason: "We should add the claim webhook when a kind of claim is offered.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfig(other),
+				MockUpdate: wantUpdate(other, hook),
+			},
+		},
+		"UpToDate": {
+			reason: "We should not update an up-to-date claim webhook.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfig(other, hook),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+		},
+		"RemoveWebhook": {
+			reason: "We should remove the claim webhook when no kinds of claim are offered.",
+			client: &test.MockClient{
+				MockList:   withXRDs(),
+				MockGet:    withConfig(other, hook),
+				MockUpdate: wantUpdate(other),
+			},
+		},
+		"NoTemplate": {
+			reason: "We should return an error if there is no webhook to copy client configuration from.",
+			client: &test.MockClient{
+				MockList: withXRDs(db),
+				MockGet:  withConfig(),
+			},
+			want: want{
+				err: errors.New(errNoTemplate),
+			},
+		},
+		"UpdateError": {
+			reason: "We should return any error encountered while updating the ValidatingWebhookConfiguration.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfig(other),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRulesReconciler(tc.client)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMutatingRulesReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	other := admv1.ValidatingWebhook{
+		Name: "compositions.apiextensions.crossplane.io",
+		ClientConfig: admv1.WebhookClientConfig{
+			Service:  &admv1.ServiceReference{Name: "crossplane-webhooks", Namespace: "crossplane-system", Path: pointer.String("/validate-compositions")},
+			CABundle: []byte("ca"),
+		},
+	}
+	db := xrd("Database", "databases")
+	hook := claimMutatingWebhook(other.ClientConfig, Rules([]v1.CompositeResourceDefinition{db}))
+
+	// withConfigs returns a MockGetFn that gets a ValidatingWebhookConfiguration
+	// with the supplied webhook, and a MutatingWebhookConfiguration with the
+	// supplied webhooks. The MutatingWebhookConfiguration is not found if
+	// mhooks is nil.
+	withConfigs := func(vhooks []admv1.ValidatingWebhook, mhooks []admv1.MutatingWebhook) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch c := obj.(type) {
+			case *admv1.ValidatingWebhookConfiguration:
+				c.Webhooks = vhooks
+			case *admv1.MutatingWebhookConfiguration:
+				if mhooks == nil {
+					return kerrors.NewNotFound(schema.GroupResource{}, WebhookConfigurationName)
+				}
+				c.Webhooks = mhooks
+			}
+			return nil
+		}
+	}
+	withXRDs := func(xrds ...v1.CompositeResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*v1.CompositeResourceDefinitionList).Items = xrds
+			return nil
+		}
+	}
+	// wantWebhooks returns a function that expects a MutatingWebhookConfiguration
+	// with the supplied webhooks.
+	wantWebhooks := func(hooks ...admv1.MutatingWebhook) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			mwc := obj.(*admv1.MutatingWebhookConfiguration)
+			if diff := cmp.Diff(hooks, mwc.Webhooks, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("MutatingWebhookConfiguration webhooks: -want, +got:\n%s", diff)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   want
+	}{
+		"ValidatingConfigNotFound": {
+			reason: "We should do nothing if the ValidatingWebhookConfiguration does not exist.",
+			client: &test.MockClient{
+				MockList: withXRDs(db),
+				MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, WebhookConfigurationName)),
+			},
+		},
+		"CreateConfig": {
+			reason: "We should create the MutatingWebhookConfiguration when the first kind of claim is offered.",
+			client: &test.MockClient{
+				MockList: withXRDs(db),
+				MockGet:  withConfigs([]admv1.ValidatingWebhook{other}, nil),
+				MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+					if obj.GetName() != WebhookConfigurationName {
+						t.Errorf("Create(...): want name %q, got %q", WebhookConfigurationName, obj.GetName())
+					}
+					return wantWebhooks(hook)(obj)
+				}),
+			},
+		},
+		"NoClaimsOffered": {
+			reason: "We should not create the MutatingWebhookConfiguration if no kinds of claim are offered.",
+			client: &test.MockClient{
+				MockList:   withXRDs(),
+				MockGet:    withConfigs([]admv1.ValidatingWebhook{other}, nil),
+				MockCreate: test.NewMockCreateFn(errBoom),
+			},
+		},
+		"CreateError": {
+			reason: "We should return any error encountered while creating the MutatingWebhookConfiguration.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfigs([]admv1.ValidatingWebhook{other}, nil),
+				MockCreate: test.NewMockCreateFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateMutatingConfig),
+			},
+		},
+		"AddWebhook": {
+			reason: "We should add the claim webhook to an existing MutatingWebhookConfiguration.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfigs([]admv1.ValidatingWebhook{other}, []admv1.MutatingWebhook{}),
+				MockUpdate: test.NewMockUpdateFn(nil, wantWebhooks(hook)),
+			},
+		},
+		"UpToDate": {
+			reason: "We should not update an up-to-date claim webhook.",
+			client: &test.MockClient{
+				MockList:   withXRDs(db),
+				MockGet:    withConfigs([]admv1.ValidatingWebhook{other}, []admv1.MutatingWebhook{hook}),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+		},
+		"RemoveWebhook": {
+			reason: "We should remove the claim webhook when no kinds of claim are offered.",
+			client: &test.MockClient{
+				MockList:   withXRDs(),
+				MockGet:    withConfigs([]admv1.ValidatingWebhook{other}, []admv1.MutatingWebhook{hook}),
+				MockUpdate: test.NewMockUpdateFn(nil, wantWebhooks()),
+			},
+		},
+		"NoTemplate": {
+			reason: "We should return an error if there is no webhook to copy client configuration from.",
+			client: &test.MockClient{
+				MockList: withXRDs(db),
+				MockGet:  withConfigs(nil, nil),
+			},
+			want: want{
+				err: errors.New(errNoTemplate),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewMutatingRulesReconciler(tc.client)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
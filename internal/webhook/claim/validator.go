@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claim implements validating and mutating webhooks for composite
+// resource claims.
+package claim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// ValidatingWebhookPath is the path at which the claim validating webhook is
+// served.
+const ValidatingWebhookPath = "/validate-claims"
+
+// Error strings.
+const (
+	errDecode       = "cannot decode claim"
+	errDecodeOld    = "cannot decode previous claim"
+	errListXRDs     = "cannot list CompositeResourceDefinitions"
+	errFmtList      = "cannot list %s"
+	errFmtCollision = "connection secret %q is already written by %s %q"
+	errFmtRenamed   = "%s claims can no longer be created - create a %s instead"
+)
+
+// SetupWebhookWithManager registers validating and mutating webhooks for claims
+// with the supplied manager's webhook server, and adds controllers that keep
+// the webhooks' rules in sync with the kinds of claim that are offered.
+func SetupWebhookWithManager(mgr ctrl.Manager, o ...RequesterAnnotatorOption) error {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: NewValidator(mgr.GetClient())})
+	mgr.GetWebhookServer().Register(MutatingWebhookPath, &webhook.Admission{Handler: NewRequesterAnnotator(o...)})
+	return SetupRules(mgr)
+}
+
+// NewValidator returns a Validator of claims.
+func NewValidator(c client.Reader) *Validator {
+	return &Validator{client: c}
+}
+
+// A Validator validates claims, rejecting those that would write their
+// connection secret to a Secret another claim in the same namespace writes to.
+// Such claims would otherwise silently overwrite each other's connection
+// details. It also rejects new claims of a kind that was renamed, because
+// existing claims of that kind are being migrated to the new kind.
+type Validator struct {
+	client client.Reader
+}
+
+// Handle an admission request for a claim.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	cm := claim.New()
+	if err := json.Unmarshal(req.Object.Raw, &cm.Unstructured); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	if req.Operation == admissionv1.Create {
+		msg, err := v.Renamed(ctx, cm)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if msg != "" {
+			return admission.Denied(msg)
+		}
+	}
+
+	ref := cm.GetWriteConnectionSecretToReference()
+	if ref == nil || ref.Name == "" {
+		return admission.Allowed("")
+	}
+
+	// Don't block updates to a claim whose connection secret didn't change,
+	// for example removing the finalizer of a claim that collided before
+	// this webhook was enabled.
+	if req.Operation == admissionv1.Update {
+		old := claim.New()
+		if err := json.Unmarshal(req.OldObject.Raw, &old.Unstructured); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+		}
+		if o := old.GetWriteConnectionSecretToReference(); o != nil && o.Name == ref.Name {
+			return admission.Allowed("")
+		}
+	}
+
+	msg, err := v.Collision(ctx, cm, req.Namespace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if msg != "" {
+		return admission.Denied(msg)
+	}
+	return admission.Allowed("")
+}
+
+// Renamed returns a description of the kind of claim that replaced the supplied
+// claim's kind, if the supplied claim is of a kind that was renamed.
+func (v *Validator) Renamed(ctx context.Context, cm *claim.Unstructured) (string, error) {
+	gk := cm.GroupVersionKind().GroupKind()
+
+	xrds := &v1.CompositeResourceDefinitionList{}
+	if err := v.client.List(ctx, xrds); err != nil {
+		return "", errors.Wrap(err, errListXRDs)
+	}
+
+	for _, xrd := range xrds.Items {
+		if !xrd.OffersClaim() || xrd.Spec.Group != gk.Group {
+			continue
+		}
+		for _, n := range xrd.Spec.PreviousClaimNames {
+			if n.Kind == gk.Kind {
+				return fmt.Sprintf(errFmtRenamed, gk.Kind, xrd.Spec.ClaimNames.Kind), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// Collision returns a description of the claim in the supplied namespace that
+// already writes its connection secret to the Secret the supplied claim would
+// write to, if any. Claims of every kind are considered, because they all
+// write connection secrets to the same namespace.
+func (v *Validator) Collision(ctx context.Context, cm *claim.Unstructured, namespace string) (string, error) {
+	name := cm.GetWriteConnectionSecretToReference().Name
+	gk := cm.GroupVersionKind().GroupKind()
+
+	xrds := &v1.CompositeResourceDefinitionList{}
+	if err := v.client.List(ctx, xrds); err != nil {
+		return "", errors.Wrap(err, errListXRDs)
+	}
+
+	for _, xrd := range xrds.Items {
+		if !xrd.OffersClaim() {
+			continue
+		}
+		gvk := xrd.GetClaimGroupVersionKind()
+
+		l := &kunstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := v.client.List(ctx, l, client.InNamespace(namespace))
+		if kmeta.IsNoMatchError(err) {
+			// The kind isn't served (yet), so there can't be any claims.
+			continue
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtList, gvk.Kind)
+		}
+
+		for i := range l.Items {
+			other := &claim.Unstructured{Unstructured: l.Items[i]}
+			if other.GroupVersionKind().GroupKind() == gk && other.GetName() == cm.GetName() {
+				continue
+			}
+			if ref := other.GetWriteConnectionSecretToReference(); ref != nil && ref.Name == name {
+				return fmt.Sprintf(errFmtCollision, name, gvk.Kind, other.GetName()), nil
+			}
+		}
+	}
+
+	return "", nil
+}
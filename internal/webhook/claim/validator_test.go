@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ admission.Handler = &Validator{}
+
+// xrd returns a CompositeResourceDefinition that offers the supplied kind of
+// claim.
+func xrd(kind, plural string) v1.CompositeResourceDefinition {
+	d := v1.CompositeResourceDefinition{}
+	d.SetName("x" + plural + ".example.org")
+	d.Spec.Group = "example.org"
+	d.Spec.Names = extv1.CustomResourceDefinitionNames{Kind: "X" + kind, Plural: "x" + plural}
+	d.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural}
+	d.Spec.Versions = []v1.CompositeResourceDefinitionVersion{{Name: "v1", Referenceable: true, Served: true}}
+	return d
+}
+
+// cm returns a claim of the supplied kind that writes its connection secret
+// to the supplied secret.
+func cm(kind, name, secret string) *claim.Unstructured {
+	c := claim.New(claim.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: kind}))
+	c.SetName(name)
+	c.SetNamespace("default")
+	if secret != "" {
+		c.SetWriteConnectionSecretToReference(&xpv1.LocalSecretReference{Name: secret})
+	}
+	return c
+}
+
+// withClaims returns a MockListFn that lists the supplied XRDs, and the
+// supplied claims of each kind.
+func withClaims(xrds []v1.CompositeResourceDefinition, claims ...*claim.Unstructured) test.MockListFn {
+	return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1.CompositeResourceDefinitionList:
+			l.Items = xrds
+		case *kunstructured.UnstructuredList:
+			for _, c := range claims {
+				if c.GetKind()+"List" == l.GetKind() {
+					l.Items = append(l.Items, c.Unstructured)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func TestCollision(t *testing.T) {
+	errBoom := errors.New("boom")
+	xrds := []v1.CompositeResourceDefinition{xrd("Database", "databases"), xrd("Bucket", "buckets")}
+
+	type args struct {
+		client client.Reader
+		cm     *claim.Unstructured
+	}
+	type want struct {
+		msg string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoCollision": {
+			reason: "A claim that writes to a Secret no other claim writes to should not collide.",
+			args: args{
+				client: &test.MockClient{MockList: withClaims(xrds, cm("Database", "a", "a-conn"), cm("Bucket", "b", "b-conn"))},
+				cm:     cm("Database", "c", "c-conn"),
+			},
+		},
+		"Itself": {
+			reason: "A claim should not collide with itself.",
+			args: args{
+				client: &test.MockClient{MockList: withClaims(xrds, cm("Database", "a", "a-conn"))},
+				cm:     cm("Database", "a", "a-conn"),
+			},
+		},
+		"SameKind": {
+			reason: "A claim that writes to the same Secret as another claim of the same kind should collide.",
+			args: args{
+				client: &test.MockClient{MockList: withClaims(xrds, cm("Database", "a", "conn"))},
+				cm:     cm("Database", "c", "conn"),
+			},
+			want: want{
+				msg: fmt.Sprintf(errFmtCollision, "conn", "Database", "a"),
+			},
+		},
+		"OtherKind": {
+			reason: "A claim that writes to the same Secret as a claim of another kind should collide.",
+			args: args{
+				client: &test.MockClient{MockList: withClaims(xrds, cm("Bucket", "a", "conn"))},
+				cm:     cm("Database", "a", "conn"),
+			},
+			want: want{
+				msg: fmt.Sprintf(errFmtCollision, "conn", "Bucket", "a"),
+			},
+		},
+		"KindNotServed": {
+			reason: "We should ignore kinds of claim that are not served.",
+			args: args{
+				client: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					if l, ok := obj.(*v1.CompositeResourceDefinitionList); ok {
+						l.Items = xrds
+						return nil
+					}
+					return &kmeta.NoKindMatchError{}
+				}},
+				cm: cm("Database", "c", "conn"),
+			},
+		},
+		"ListXRDsError": {
+			reason: "We should return any error encountered while listing XRDs.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cm:     cm("Database", "c", "conn"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRDs),
+			},
+		},
+		"ListClaimsError": {
+			reason: "We should return any error encountered while listing claims.",
+			args: args{
+				client: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					if l, ok := obj.(*v1.CompositeResourceDefinitionList); ok {
+						l.Items = xrds
+						return nil
+					}
+					return errBoom
+				}},
+				cm: cm("Database", "c", "conn"),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, "Database"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.args.client)
+			msg, err := v.Collision(context.Background(), tc.args.cm, "default")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Collision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msg, msg); diff != "" {
+				t.Errorf("\n%s\nv.Collision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	raw := func(c *claim.Unstructured) runtime.RawExtension {
+		b, _ := json.Marshal(c)
+		return runtime.RawExtension{Raw: b}
+	}
+	errBoom := errors.New("boom")
+	renamed := xrd("Database", "databases")
+	renamed.Spec.PreviousClaimNames = []extv1.CustomResourceDefinitionNames{{Kind: "DB", Plural: "dbs"}}
+	xrds := []v1.CompositeResourceDefinition{renamed}
+	list := withClaims(xrds, cm("Database", "a", "conn"))
+	collision := fmt.Sprintf(errFmtCollision, "conn", "Database", "a")
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		req    admission.Request
+		want   admission.Response
+	}{
+		"Delete": {
+			reason: "We should allow operations other than creates and updates.",
+			req:    admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			want:   admission.Allowed(""),
+		},
+		"NoConnectionSecret": {
+			reason: "We should allow claims that don't write a connection secret.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"CreateRenamed": {
+			reason: "We should deny creating a claim of a kind that was renamed.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    raw(cm("DB", "b", "")),
+			}},
+			want: admission.Denied(fmt.Sprintf(errFmtRenamed, "DB", "Database")),
+		},
+		"UpdateRenamed": {
+			reason: "We should allow updating an existing claim of a kind that was renamed, so that it can be migrated.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Namespace: "default",
+				Object:    raw(cm("DB", "b", "")),
+				OldObject: raw(cm("DB", "b", "")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"RenamedListError": {
+			reason: "We should return an error if we can't determine whether a kind of claim was renamed.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "")),
+			}},
+			want: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errListXRDs)),
+		},
+		"CreateCollision": {
+			reason: "We should deny creating a claim that would write to another claim's connection secret.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "conn")),
+			}},
+			want: admission.Denied(collision),
+		},
+		"UpdateCollision": {
+			reason: "We should deny updating a claim to write to another claim's connection secret.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "conn")),
+				OldObject: raw(cm("Database", "b", "other")),
+			}},
+			want: admission.Denied(collision),
+		},
+		"UpdateUnchanged": {
+			reason: "We should allow updating a claim without changing its connection secret, even if it collides.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "conn")),
+				OldObject: raw(cm("Database", "b", "conn")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"NoCollision": {
+			reason: "We should allow a claim that writes to a Secret no other claim writes to.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "default",
+				Object:    raw(cm("Database", "b", "b-conn")),
+			}},
+			want: admission.Allowed(""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.client)
+			got := v.Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nv.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
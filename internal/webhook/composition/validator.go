@@ -0,0 +1,395 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package composition implements a validating webhook for Compositions.
+package composition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/denylist"
+	"github.com/crossplane/crossplane/internal/validate"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// ValidatingWebhookPath is the path at which the Composition validating
+// webhook is served.
+const ValidatingWebhookPath = "/validate-apiextensions-crossplane-io-v1-composition"
+
+// Error strings.
+const (
+	errDecode       = "cannot decode Composition"
+	errDecodeOld    = "cannot decode previous Composition"
+	errListXRs      = "cannot list composite resources"
+	errListRevs     = "cannot list CompositionRevisions"
+	errParseTypeRef = "cannot parse spec.compositeTypeRef"
+
+	errTypeRefImmutable     = "spec.compositeTypeRef is immutable"
+	errFmtDeniedKind        = "spec.resources[%d]: Compositions may not compose %s resources (denied by %q)"
+	errFmtDropInUseResource = "spec.resources: cannot remove resource templates %s from a Composition that is used by existing composite resources"
+	errChangeAnonymous      = "spec.resources: cannot add, remove, or reorder resource templates of a Composition that has anonymous resource templates and is used by existing composite resources"
+)
+
+// An UpdatePolicy specifies how incompatible Composition updates are handled.
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyEnforce rejects incompatible Composition updates.
+	UpdatePolicyEnforce UpdatePolicy = "Enforce"
+
+	// UpdatePolicyWarn allows incompatible Composition updates, but returns
+	// a warning to the client that made them.
+	UpdatePolicyWarn UpdatePolicy = "Warn"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-apiextensions-crossplane-io-v1-composition,mutating=false,failurePolicy=fail,groups=apiextensions.crossplane.io,resources=compositions,versions=v1,name=compositions.apiextensions.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers a validating webhook for Compositions with
+// the supplied manager's webhook server.
+func SetupWebhookWithManager(mgr ctrl.Manager, opts ...ValidatorOption) {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: NewValidator(mgr.GetClient(), opts...)})
+}
+
+// A ValidatorOption configures a Validator.
+type ValidatorOption func(*Validator)
+
+// WithUpdatePolicy specifies how the Validator should handle incompatible
+// Composition updates.
+func WithUpdatePolicy(p UpdatePolicy) ValidatorOption {
+	return func(v *Validator) {
+		if p == "" {
+			return
+		}
+		v.policy = p
+	}
+}
+
+// WithDeniedKinds specifies kinds of resource that Compositions may not
+// compose.
+func WithDeniedKinds(l denylist.List) ValidatorOption {
+	return func(v *Validator) {
+		v.denied = l
+	}
+}
+
+// WithLinting specifies that the Validator should warn about likely authoring
+// mistakes, for example patches that overwrite each other.
+func WithLinting() ValidatorOption {
+	return func(v *Validator) {
+		v.lint = true
+	}
+}
+
+// NewValidator returns a Validator of Compositions.
+func NewValidator(c client.Reader, opts ...ValidatorOption) *Validator {
+	v := &Validator{client: c, policy: UpdatePolicyEnforce}
+	for _, f := range opts {
+		f(v)
+	}
+	return v
+}
+
+// A Validator validates Compositions. It rejects Compositions that compose
+// denied kinds of resource, and rejects (or warns about) updates that could
+// break the composite resources that use them. It may also warn about likely
+// authoring mistakes.
+type Validator struct {
+	client client.Reader
+	policy UpdatePolicy
+	denied denylist.List
+	lint   bool
+}
+
+// Handle an admission request for a Composition.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	comp := &v1.Composition{}
+	if err := json.Unmarshal(req.Object.Raw, comp); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	// Denied kinds are a security control, so they're always enforced
+	// regardless of our update policy.
+	if denied := v.DeniedKinds(comp); len(denied) > 0 {
+		return admission.Denied(strings.Join(denied, "; "))
+	}
+
+	warnings := make([]string, 0)
+	if v.lint {
+		warnings = validate.Lint(comp, v.compositeSchema(ctx, comp))
+	}
+	if req.Operation != admissionv1.Update {
+		return withWarnings(admission.Allowed(""), warnings)
+	}
+
+	old := &v1.Composition{}
+	if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+	}
+
+	problems, err := v.ValidateUpdate(ctx, old, comp)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(problems) == 0 {
+		return withWarnings(admission.Allowed(""), warnings)
+	}
+	if v.policy == UpdatePolicyWarn {
+		return admission.Allowed("").WithWarnings(append(problems, warnings...)...)
+	}
+	return withWarnings(admission.Denied(strings.Join(problems, "; ")), warnings)
+}
+
+// withWarnings adds the supplied warnings, if any, to the supplied response.
+func withWarnings(r admission.Response, warnings []string) admission.Response {
+	if len(warnings) == 0 {
+		return r
+	}
+	return r.WithWarnings(warnings...)
+}
+
+// compositeSchema returns the schema of the composite resource the supplied
+// Composition composes, or nil if it isn't known. Linting is best effort, so
+// the schema is unknown if it can't be derived for any reason.
+func (v *Validator) compositeSchema(ctx context.Context, comp *v1.Composition) *extv1.JSONSchemaProps {
+	gv, err := schema.ParseGroupVersion(comp.Spec.CompositeTypeRef.APIVersion)
+	if err != nil {
+		return nil
+	}
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := v.client.List(ctx, l); err != nil {
+		return nil
+	}
+	for i := range l.Items {
+		xrd := &l.Items[i]
+		if xrd.Spec.Group != gv.Group || xrd.Spec.Names.Kind != comp.Spec.CompositeTypeRef.Kind {
+			continue
+		}
+		crd, err := xcrd.ForCompositeResource(xrd)
+		if err != nil {
+			return nil
+		}
+		for _, vr := range crd.Spec.Versions {
+			if vr.Name == gv.Version && vr.Schema != nil {
+				return vr.Schema.OpenAPIV3Schema
+			}
+		}
+	}
+	return nil
+}
+
+// DeniedKinds returns a description of each resource template of the supplied
+// Composition that composes a denied kind of resource. Templates are checked
+// using the namespace of their base resource, so a template that is patched
+// into a denied namespace is only caught when composite resources are
+// reconciled.
+func (v *Validator) DeniedKinds(comp *v1.Composition) []string {
+	denied := make([]string, 0)
+	if len(v.denied) == 0 {
+		return denied
+	}
+	for i, t := range comp.Spec.Resources {
+		u := &kunstructured.Unstructured{}
+		if err := json.Unmarshal(t.Base.Raw, u); err != nil {
+			// Let the composite resource reconciler report invalid templates.
+			continue
+		}
+		gk := u.GroupVersionKind().GroupKind()
+		if r, ok := v.denied.Denies(gk, u.GetNamespace()); ok {
+			denied = append(denied, fmt.Sprintf(errFmtDeniedKind, i, gk, r))
+		}
+	}
+	return denied
+}
+
+// ValidateUpdate returns a description of each way in which the supplied
+// Composition update is incompatible with the previous Composition.
+func (v *Validator) ValidateUpdate(ctx context.Context, old, comp *v1.Composition) ([]string, error) {
+	problems := make([]string, 0)
+
+	if comp.Spec.CompositeTypeRef != old.Spec.CompositeTypeRef {
+		problems = append(problems, errTypeRefImmutable)
+	}
+
+	incompatible := ""
+	switch {
+	case hasAnonymous(old) || hasAnonymous(comp):
+		// Composite resources associate their composed resources with
+		// anonymous resource templates by index, so any change to the
+		// number or order of templates could break them.
+		if ReorderedResources(old, comp) {
+			incompatible = errChangeAnonymous
+		}
+	default:
+		if dropped := DroppedResources(old, comp); len(dropped) > 0 {
+			incompatible = fmt.Sprintf(errFmtDropInUseResource, strings.Join(dropped, ", "))
+		}
+	}
+	if incompatible == "" {
+		return problems, nil
+	}
+
+	used, err := v.inUse(ctx, old)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		problems = append(problems, incompatible)
+	}
+
+	return problems, nil
+}
+
+// inUse returns true if any composite resource references the supplied
+// Composition, or any of its CompositionRevisions.
+func (v *Validator) inUse(ctx context.Context, comp *v1.Composition) (bool, error) {
+	gv, err := schema.ParseGroupVersion(comp.Spec.CompositeTypeRef.APIVersion)
+	if err != nil {
+		return false, errors.Wrap(err, errParseTypeRef)
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gv.WithKind(comp.Spec.CompositeTypeRef.Kind + "List"))
+	err = v.client.List(ctx, l)
+	if kmeta.IsNoMatchError(err) {
+		// The type of composite resource isn't served, so there can't be
+		// any composite resources using this Composition.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errListXRs)
+	}
+	if len(l.Items) == 0 {
+		return false, nil
+	}
+
+	revs, err := v.revisions(ctx, comp)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range l.Items {
+		xr := &composite.Unstructured{Unstructured: l.Items[i]}
+		if ref := xr.GetCompositionReference(); ref != nil && ref.Name == comp.GetName() {
+			return true, nil
+		}
+		if ref := xr.GetCompositionRevisionReference(); ref != nil && revs[ref.Name] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// revisions returns the names of the supplied Composition's revisions.
+func (v *Validator) revisions(ctx context.Context, comp *v1.Composition) (map[string]bool, error) {
+	l := &v1alpha1.CompositionRevisionList{}
+	err := v.client.List(ctx, l, client.MatchingLabels{v1alpha1.LabelCompositionName: comp.GetName()})
+	if kmeta.IsNoMatchError(err) {
+		// CompositionRevisions aren't served, so composite resources can't
+		// reference them.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errListRevs)
+	}
+
+	names := make(map[string]bool, len(l.Items))
+	for _, rev := range l.Items {
+		names[rev.GetName()] = true
+	}
+	return names, nil
+}
+
+// DroppedResources returns the names of the resource templates of the supplied
+// old Composition that are not present in the new Composition. Anonymous
+// templates are ignored; see ReorderedResources.
+func DroppedResources(old, comp *v1.Composition) []string {
+	keep := map[string]bool{}
+	for _, t := range comp.Spec.Resources {
+		if t.Name != nil {
+			keep[*t.Name] = true
+		}
+	}
+
+	dropped := make([]string, 0)
+	for _, t := range old.Spec.Resources {
+		if t.Name != nil && !keep[*t.Name] {
+			dropped = append(dropped, fmt.Sprintf("%q", *t.Name))
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// ReorderedResources returns true if the supplied new Composition has a
+// different number of resource templates than the supplied old Composition,
+// or if any of its templates composes a different kind of resource than the
+// old template at the same index. Changing the API version of a template is
+// compatible. Anonymous templates are associated with
+// composed resources by index, so either change is incompatible. Naming a
+// previously anonymous template without moving it is compatible.
+func ReorderedResources(old, comp *v1.Composition) bool {
+	if len(old.Spec.Resources) != len(comp.Spec.Resources) {
+		return true
+	}
+	for i := range old.Spec.Resources {
+		if templateKind(old.Spec.Resources[i]) != templateKind(comp.Spec.Resources[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnonymous(comp *v1.Composition) bool {
+	for _, t := range comp.Spec.Resources {
+		if t.Name == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func templateKind(t v1.ComposedTemplate) schema.GroupKind {
+	tm := &metav1.TypeMeta{}
+	// A template we can't decode has no kind, which is only equal to the
+	// kind of another template we can't decode.
+	_ = json.Unmarshal(t.Base.Raw, tm)
+	return tm.GroupVersionKind().GroupKind()
+}
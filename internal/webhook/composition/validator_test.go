@@ -0,0 +1,504 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/denylist"
+	"github.com/crossplane/crossplane/internal/validate"
+)
+
+var _ admission.Handler = &Validator{}
+
+const (
+	compName = "cool-composition"
+	revName  = "cool-composition-abcdef"
+)
+
+type compositionModifier func(c *v1.Composition)
+
+func withTypeRef(kind string) compositionModifier {
+	return func(c *v1.Composition) {
+		c.Spec.CompositeTypeRef = v1.TypeReference{APIVersion: "example.org/v1", Kind: kind}
+	}
+}
+
+func withTemplate(name, kind string) compositionModifier {
+	return func(c *v1.Composition) {
+		t := v1.ComposedTemplate{
+			Base: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"example.org/v1","kind":%q}`, kind))},
+		}
+		if name != "" {
+			t.Name = &name
+		}
+		c.Spec.Resources = append(c.Spec.Resources, t)
+	}
+}
+
+func comp(m ...compositionModifier) *v1.Composition {
+	c := &v1.Composition{}
+	c.SetName(compName)
+	withTypeRef("XR")(c)
+	for _, fn := range m {
+		fn(c)
+	}
+	return c
+}
+
+// withXR returns a MockListFn that lists a composite resource that references
+// the supplied Composition and CompositionRevision, and a CompositionRevision
+// of our Composition.
+func withXR(compRef, revRef string) test.MockListFn {
+	return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *kunstructured.UnstructuredList:
+			xr := composite.New()
+			if compRef != "" {
+				xr.SetCompositionReference(&corev1.ObjectReference{Name: compRef})
+			}
+			if revRef != "" {
+				xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: revRef})
+			}
+			l.Items = []kunstructured.Unstructured{xr.Unstructured}
+		case *v1alpha1.CompositionRevisionList:
+			rev := v1alpha1.CompositionRevision{}
+			rev.SetName(revName)
+			l.Items = []v1alpha1.CompositionRevision{rev}
+		}
+		return nil
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		old    *v1.Composition
+		comp   *v1.Composition
+	}
+	type want struct {
+		problems []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CompositeTypeRefChanged": {
+			reason: "We should report that spec.compositeTypeRef is immutable.",
+			args: args{
+				old:  comp(withTypeRef("XR")),
+				comp: comp(withTypeRef("OtherXR")),
+			},
+			want: want{
+				problems: []string{errTypeRefImmutable},
+			},
+		},
+		"DropNamedTemplateNotInUse": {
+			reason: "We should allow dropping a named template from a Composition no composite resource uses.",
+			args: args{
+				client: &test.MockClient{MockList: withXR("some-other-composition", "")},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B")),
+				comp:   comp(withTemplate("a", "A")),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"DropNamedTemplateInUse": {
+			reason: "We should report dropping a named template from a Composition a composite resource uses.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(compName, "")},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B"), withTemplate("c", "C")),
+				comp:   comp(withTemplate("b", "B")),
+			},
+			want: want{
+				problems: []string{fmt.Sprintf(errFmtDropInUseResource, `"a", "c"`)},
+			},
+		},
+		"DropNamedTemplateInUseByRevision": {
+			reason: "We should report dropping a named template from a Composition whose revision a composite resource uses.",
+			args: args{
+				client: &test.MockClient{MockList: withXR("", revName)},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B")),
+				comp:   comp(withTemplate("a", "A")),
+			},
+			want: want{
+				problems: []string{fmt.Sprintf(errFmtDropInUseResource, `"b"`)},
+			},
+		},
+		"ReorderNamedTemplates": {
+			reason: "We should allow reordering named templates, which are associated with composed resources by name.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(compName, "")},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B")),
+				comp:   comp(withTemplate("b", "B"), withTemplate("a", "A")),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"DropAnonymousTemplateInUse": {
+			reason: "We should report dropping an anonymous template from a Composition a composite resource uses.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(compName, "")},
+				old:    comp(withTemplate("", "A"), withTemplate("", "B"), withTemplate("", "C")),
+				comp:   comp(withTemplate("", "A"), withTemplate("", "C")),
+			},
+			want: want{
+				problems: []string{errChangeAnonymous},
+			},
+		},
+		"ReorderAnonymousTemplatesInUse": {
+			reason: "We should report reordering anonymous templates of a Composition a composite resource uses.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(compName, "")},
+				old:    comp(withTemplate("", "A"), withTemplate("", "B")),
+				comp:   comp(withTemplate("", "B"), withTemplate("", "A")),
+			},
+			want: want{
+				problems: []string{errChangeAnonymous},
+			},
+		},
+		"NameAnonymousTemplatesInUse": {
+			reason: "We should allow naming the anonymous templates of a Composition without moving them.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(compName, "")},
+				old:    comp(withTemplate("", "A"), withTemplate("", "B")),
+				comp:   comp(withTemplate("a", "A"), withTemplate("b", "B")),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"CompositeKindNotServed": {
+			reason: "We should allow dropping templates if the kind of composite resource is not served.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(&kmeta.NoKindMatchError{})},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B")),
+				comp:   comp(withTemplate("a", "A")),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"ListXRsError": {
+			reason: "We should return any error encountered while listing composite resources.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				old:    comp(withTemplate("a", "A"), withTemplate("b", "B")),
+				comp:   comp(withTemplate("a", "A")),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRs),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.args.client)
+			got, err := v.ValidateUpdate(context.Background(), tc.args.old, tc.args.comp)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.problems, got); diff != "" {
+				t.Errorf("\n%s\nv.ValidateUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	raw := func(c *v1.Composition) runtime.RawExtension {
+		b, _ := json.Marshal(c)
+		return runtime.RawExtension{Raw: b}
+	}
+	old := comp(withTemplate("a", "A"), withTemplate("b", "B"))
+	dropped := comp(withTemplate("a", "A"))
+	problem := fmt.Sprintf(errFmtDropInUseResource, `"b"`)
+	deny, _ := denylist.Parse([]string{"B.example.org"})
+	denied := fmt.Sprintf(errFmtDeniedKind, 1, "B.example.org", "B.example.org")
+	overwrites := comp(withTemplate("a", "A"))
+	overwrites.Spec.Resources[0].Patches = []v1.Patch{
+		{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: pointer.String("spec.size"), ToFieldPath: pointer.String("spec.class")},
+		{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: pointer.String("spec.region"), ToFieldPath: pointer.String("spec.class")},
+	}
+	overwritten := validate.Lint(overwrites, nil)
+
+	type args struct {
+		opts []ValidatorOption
+		req  admission.Request
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   admission.Response
+	}{
+		"Delete": {
+			reason: "We should allow operations other than creates and updates.",
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			},
+			want: admission.Allowed(""),
+		},
+		"Create": {
+			reason: "We should allow creating a Composition that composes no denied kinds.",
+			args: args{
+				opts: []ValidatorOption{WithDeniedKinds(deny)},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    raw(dropped),
+				}},
+			},
+			want: admission.Allowed(""),
+		},
+		"CreateDeniedKind": {
+			reason: "We should deny creating a Composition that composes a denied kind.",
+			args: args{
+				opts: []ValidatorOption{WithDeniedKinds(deny)},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    raw(old),
+				}},
+			},
+			want: admission.Denied(denied),
+		},
+		"WarnUpdateDeniedKind": {
+			reason: "We should deny updating a Composition to compose a denied kind, even when our update policy is to warn.",
+			args: args{
+				opts: []ValidatorOption{WithUpdatePolicy(UpdatePolicyWarn), WithDeniedKinds(deny)},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    raw(old),
+					OldObject: raw(dropped),
+				}},
+			},
+			want: admission.Denied(denied),
+		},
+		"CompatibleUpdate": {
+			reason: "We should allow compatible updates.",
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    raw(old),
+					OldObject: raw(old),
+				}},
+			},
+			want: admission.Allowed(""),
+		},
+		"EnforceIncompatibleUpdate": {
+			reason: "We should deny incompatible updates when our policy is to enforce compatibility.",
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    raw(dropped),
+					OldObject: raw(old),
+				}},
+			},
+			want: admission.Denied(problem),
+		},
+		"WarnIncompatibleUpdate": {
+			reason: "We should allow incompatible updates with a warning when our policy is to warn.",
+			args: args{
+				opts: []ValidatorOption{WithUpdatePolicy(UpdatePolicyWarn)},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    raw(dropped),
+					OldObject: raw(old),
+				}},
+			},
+			want: admission.Allowed("").WithWarnings(problem),
+		},
+		"LintCreate": {
+			reason: "We should warn about likely authoring mistakes when linting is enabled.",
+			args: args{
+				opts: []ValidatorOption{WithLinting()},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    raw(overwrites),
+				}},
+			},
+			want: admission.Allowed("").WithWarnings(overwritten...),
+		},
+		"NoLintCreate": {
+			reason: "We should not warn about likely authoring mistakes unless linting is enabled.",
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    raw(overwrites),
+				}},
+			},
+			want: admission.Allowed(""),
+		},
+		"LintWarnIncompatibleUpdate": {
+			reason: "We should return both incompatibilities and likely authoring mistakes as warnings when our policy is to warn.",
+			args: args{
+				opts: []ValidatorOption{WithUpdatePolicy(UpdatePolicyWarn), WithLinting()},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    raw(overwrites),
+					OldObject: raw(old),
+				}},
+			},
+			want: admission.Allowed("").WithWarnings(append([]string{problem}, overwritten...)...),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(&test.MockClient{MockList: withXR(compName, "")}, tc.args.opts...)
+			got := v.Handle(context.Background(), tc.args.req)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nv.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeniedKinds(t *testing.T) {
+	deny, _ := denylist.Parse([]string{"kube-system/Secret", "B.example.org"})
+
+	cases := map[string]struct {
+		reason string
+		denied denylist.List
+		comp   *v1.Composition
+		want   []string
+	}{
+		"NothingDenied": {
+			reason: "A Composition should compose any kind if nothing is denied.",
+			comp:   comp(withTemplate("b", "B")),
+			want:   []string{},
+		},
+		"DeniedKind": {
+			reason: "We should return each template that composes a denied kind.",
+			denied: deny,
+			comp:   comp(withTemplate("a", "A"), withTemplate("b", "B"), withTemplate("", "B")),
+			want: []string{
+				fmt.Sprintf(errFmtDeniedKind, 1, "B.example.org", "B.example.org"),
+				fmt.Sprintf(errFmtDeniedKind, 2, "B.example.org", "B.example.org"),
+			},
+		},
+		"DeniedNamespace": {
+			reason: "We should return a template that composes a kind that is denied in the namespace of its base resource.",
+			denied: deny,
+			comp: comp(func(c *v1.Composition) {
+				c.Spec.Resources = []v1.ComposedTemplate{
+					{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"namespace":"default"}}`)}},
+					{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"namespace":"kube-system"}}`)}},
+				}
+			}),
+			want: []string{fmt.Sprintf(errFmtDeniedKind, 1, "Secret", "kube-system/Secret")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(&test.MockClient{}, WithDeniedKinds(tc.denied))
+			got := v.DeniedKinds(tc.comp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nv.DeniedKinds(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositeSchema(t *testing.T) {
+	xrd := v1.CompositeResourceDefinition{}
+	xrd.SetName("xrs.example.org")
+	xrd.Spec.Group = "example.org"
+	xrd.Spec.Names = extv1.CustomResourceDefinitionNames{Kind: "XR", Plural: "xrs"}
+	xrd.Spec.Versions = []v1.CompositeResourceDefinitionVersion{{
+		Name:          "v1",
+		Referenceable: true,
+		Served:        true,
+		Schema: &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{
+			"type": "object",
+			"properties": {"spec": {"type": "object", "properties": {"size": {"type": "string"}}}}
+		}`)}},
+	}}
+	withXRD := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		if l, ok := obj.(*v1.CompositeResourceDefinitionList); ok {
+			l.Items = []v1.CompositeResourceDefinition{xrd}
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		comp   *v1.Composition
+		want   bool
+	}{
+		"Known": {
+			reason: "We should return the schema of the composite resource defined by an XRD.",
+			list:   withXRD,
+			comp:   comp(),
+			want:   true,
+		},
+		"UnknownKind": {
+			reason: "We should return a nil schema if no XRD defines the composite resource.",
+			list:   withXRD,
+			comp:   comp(withTypeRef("OtherXR")),
+		},
+		"ListError": {
+			reason: "We should return a nil schema if we can't list XRDs.",
+			list:   test.NewMockListFn(errors.New("boom")),
+			comp:   comp(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(&test.MockClient{MockList: tc.list})
+			got := v.compositeSchema(context.Background(), tc.comp)
+			if diff := cmp.Diff(tc.want, got != nil); diff != "" {
+				t.Errorf("\n%s\nv.compositeSchema(...): -want schema, +got schema:\n%s", tc.reason, diff)
+			}
+			if got != nil && got.Properties["spec"].Properties["size"].Type != "string" {
+				t.Errorf("\n%s\nv.compositeSchema(...): got schema without spec.size: %+v", tc.reason, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definition implements a validating webhook for
+// CompositeResourceDefinitions.
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// ValidatingWebhookPath is the path at which the CompositeResourceDefinition
+// validating webhook is served.
+const ValidatingWebhookPath = "/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition"
+
+// AnnotationKeyForceSchemaUpdate may be set to "true" on a
+// CompositeResourceDefinition to allow a schema update that would invalidate
+// existing composite resources or claims. Such updates are allowed with a
+// warning.
+const AnnotationKeyForceSchemaUpdate = "apiextensions.crossplane.io/force-schema-update"
+
+// Error strings.
+const (
+	errDecode     = "cannot decode CompositeResourceDefinition"
+	errDecodeOld  = "cannot decode previous CompositeResourceDefinition"
+	errFmtSchema  = "cannot parse schema of version %q"
+	errFmtList    = "cannot list %s"
+	errFmtProblem = "%s: %s in existing %s %s"
+
+	errTypeChanged  = "type changed"
+	errFieldRemoved = "field removed while data is present"
+	errNowRequired  = "field made required while data is missing"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition,mutating=false,failurePolicy=fail,groups=apiextensions.crossplane.io,resources=compositeresourcedefinitions,versions=v1,name=compositeresourcedefinitions.apiextensions.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers a validating webhook for
+// CompositeResourceDefinitions with the supplied manager's webhook server.
+func SetupWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: NewValidator(mgr.GetClient())})
+}
+
+// NewValidator returns a Validator of CompositeResourceDefinitions.
+func NewValidator(c client.Reader) *Validator {
+	return &Validator{client: c}
+}
+
+// A Validator validates CompositeResourceDefinitions. It rejects invalid
+// CompositeResourceDefinitions, and updates that change immutable fields or
+// that change a schema in a way that would invalidate existing composite
+// resources or claims.
+type Validator struct {
+	client client.Reader
+}
+
+// Handle an admission request for a CompositeResourceDefinition.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	xrd := &v1.CompositeResourceDefinition{}
+	if err := json.Unmarshal(req.Object.Raw, xrd); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	if req.Operation == admissionv1.Create {
+		if err := xrd.ValidateCreate(); err != nil {
+			return admission.Denied(err.Error())
+		}
+		return admission.Allowed("")
+	}
+
+	old := &v1.CompositeResourceDefinition{}
+	if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+	}
+
+	// Immutable fields can't be forced; the CRDs we render from them can't
+	// be changed either.
+	if err := xrd.ValidateUpdate(old); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	problems, err := v.ValidateSchemaUpdate(ctx, old, xrd)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(problems) == 0 {
+		return admission.Allowed("")
+	}
+	if xrd.GetAnnotations()[AnnotationKeyForceSchemaUpdate] == "true" {
+		return admission.Allowed("").WithWarnings(problems...)
+	}
+	return admission.Denied(strings.Join(problems, "; "))
+}
+
+// ValidateSchemaUpdate returns a description of each way in which the supplied
+// CompositeResourceDefinition update would invalidate existing composite
+// resources or claims.
+func (v *Validator) ValidateSchemaUpdate(ctx context.Context, old, xrd *v1.CompositeResourceDefinition) ([]string, error) {
+	problems := make([]string, 0)
+
+	for _, ov := range old.Spec.Versions {
+		nv, ok := version(xrd, ov.Name)
+		if !ok {
+			// Removing a version is governed by the API server, which
+			// won't let us remove a version that objects are stored at.
+			continue
+		}
+
+		oldSchema, err := openAPIV3Schema(ov)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtSchema, ov.Name)
+		}
+		newSchema, err := openAPIV3Schema(nv)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtSchema, nv.Name)
+		}
+
+		c := SchemaChanges(oldSchema, newSchema)
+		if c.Empty() {
+			continue
+		}
+
+		kinds := []string{xrd.Spec.Names.Kind}
+		if xrd.OffersClaim() {
+			kinds = append(kinds, xrd.Spec.ClaimNames.Kind)
+		}
+		for _, k := range kinds {
+			p, err := v.invalidated(ctx, schema.GroupVersionKind{Group: xrd.Spec.Group, Version: ov.Name, Kind: k}, c)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p...)
+		}
+	}
+
+	return problems, nil
+}
+
+// invalidated returns a description of each way in which the supplied schema
+// changes invalidate existing resources of the supplied kind.
+func (v *Validator) invalidated(ctx context.Context, gvk schema.GroupVersionKind, c Changes) ([]string, error) {
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	err := v.client.List(ctx, l)
+	if kmeta.IsNoMatchError(err) {
+		// The kind isn't served (yet), so there can't be any resources.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtList, gvk.Kind)
+	}
+
+	problems := make([]string, 0)
+	for i := range l.Items {
+		o := &l.Items[i]
+		p := fieldpath.Pave(o.Object)
+		report := func(path, problem string) {
+			problems = append(problems, fmt.Sprintf(errFmtProblem, path, problem, gvk.Kind, name(o)))
+		}
+
+		for _, path := range c.TypeChanged {
+			if present(p, path) {
+				report(path, errTypeChanged)
+			}
+		}
+		for _, path := range c.Removed {
+			if present(p, path) {
+				report(path, errFieldRemoved)
+			}
+		}
+		for _, r := range c.Required {
+			parents := []string{""}
+			if r.Parent != "" {
+				// A parent we can't expand doesn't match its schema,
+				// so there's nothing for us to invalidate.
+				parents, _ = p.ExpandWildcards(r.Parent)
+			}
+			for _, pp := range parents {
+				path := child(pp, r.Field)
+				if _, err := p.GetValue(path); fieldpath.IsNotFound(err) {
+					report(child(r.Parent, r.Field), errNowRequired)
+					break
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+// A RequiredField is a field that must be set if its parent object is set.
+type RequiredField struct {
+	// Parent is the field path of the object the field belongs to. It is
+	// empty for top level fields.
+	Parent string
+
+	// Field is the name of the required field.
+	Field string
+}
+
+// Changes between two versions of a schema that could invalidate resources
+// that were valid under the older version.
+type Changes struct {
+	// TypeChanged contains the paths of fields whose type changed.
+	TypeChanged []string
+
+	// Removed contains the paths of fields that were removed.
+	Removed []string
+
+	// Required contains fields that were made required.
+	Required []RequiredField
+}
+
+// Empty returns true if there are no changes.
+func (c Changes) Empty() bool {
+	return len(c.TypeChanged) == 0 && len(c.Removed) == 0 && len(c.Required) == 0
+}
+
+// SchemaChanges returns the changes between the supplied old and new schemas
+// that could invalidate resources that were valid under the old schema. Array
+// items are represented by a [*] wildcard in the returned field paths.
+func SchemaChanges(old, s *extv1.JSONSchemaProps) Changes {
+	c := Changes{TypeChanged: []string{}, Removed: []string{}, Required: []RequiredField{}}
+	schemaChanges(old, s, fieldpath.Segments{}, &c)
+	sort.Strings(c.TypeChanged)
+	sort.Strings(c.Removed)
+	sort.Slice(c.Required, func(i, j int) bool {
+		return child(c.Required[i].Parent, c.Required[i].Field) < child(c.Required[j].Parent, c.Required[j].Field)
+	})
+	return c
+}
+
+func schemaChanges(old, s *extv1.JSONSchemaProps, path fieldpath.Segments, c *Changes) {
+	if old.Type != "" && s.Type != "" && old.Type != s.Type {
+		c.TypeChanged = append(c.TypeChanged, path.String())
+		return
+	}
+
+	was := map[string]bool{}
+	for _, r := range old.Required {
+		was[r] = true
+	}
+	for _, r := range s.Required {
+		if !was[r] {
+			c.Required = append(c.Required, RequiredField{Parent: path.String(), Field: r})
+		}
+	}
+
+	for k := range old.Properties {
+		op := old.Properties[k]
+		np, ok := s.Properties[k]
+		if !ok {
+			// Unknown fields are not pruned from objects that preserve
+			// them, so the field's data isn't lost.
+			if s.XPreserveUnknownFields == nil || !*s.XPreserveUnknownFields {
+				c.Removed = append(c.Removed, join(path, fieldpath.Field(k)).String())
+			}
+			continue
+		}
+		schemaChanges(&op, &np, join(path, fieldpath.Field(k)), c)
+	}
+
+	if old.Items != nil && old.Items.Schema != nil && s.Items != nil && s.Items.Schema != nil {
+		schemaChanges(old.Items.Schema, s.Items.Schema, join(path, fieldpath.Field("*")), c)
+	}
+}
+
+func join(path fieldpath.Segments, s fieldpath.Segment) fieldpath.Segments {
+	return append(append(make(fieldpath.Segments, 0, len(path)+1), path...), s)
+}
+
+func child(parent, field string) string {
+	p, err := fieldpath.Parse(parent)
+	if err != nil || parent == "" {
+		p = fieldpath.Segments{}
+	}
+	return join(p, fieldpath.Field(field)).String()
+}
+
+func version(xrd *v1.CompositeResourceDefinition, name string) (v1.CompositeResourceDefinitionVersion, bool) {
+	for _, v := range xrd.Spec.Versions {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return v1.CompositeResourceDefinitionVersion{}, false
+}
+
+func openAPIV3Schema(v v1.CompositeResourceDefinitionVersion) (*extv1.JSONSchemaProps, error) {
+	s := &extv1.JSONSchemaProps{}
+	if v.Schema == nil || len(v.Schema.OpenAPIV3Schema.Raw) == 0 {
+		return s, nil
+	}
+	return s, json.Unmarshal(v.Schema.OpenAPIV3Schema.Raw, s)
+}
+
+func name(o *kunstructured.Unstructured) string {
+	if o.GetNamespace() == "" {
+		return fmt.Sprintf("%q", o.GetName())
+	}
+	return fmt.Sprintf("%q", o.GetNamespace()+"/"+o.GetName())
+}
+
+// present returns true if the supplied object has data at the supplied field
+// path. Data that doesn't match the shape of the path is considered present.
+func present(p *fieldpath.Paved, path string) bool {
+	found, err := p.ExpandWildcards(path)
+	return err != nil || len(found) > 0
+}
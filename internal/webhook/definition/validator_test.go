@@ -0,0 +1,359 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ admission.Handler = &Validator{}
+
+func xrd(schema string, annotations map[string]string) *v1.CompositeResourceDefinition {
+	d := &v1.CompositeResourceDefinition{}
+	d.SetName("xcools.example.org")
+	d.SetAnnotations(annotations)
+	d.Spec.Group = "example.org"
+	d.Spec.Names = extv1.CustomResourceDefinitionNames{Kind: "XCool", Plural: "xcools"}
+	d.Spec.Versions = []v1.CompositeResourceDefinitionVersion{{
+		Name:   "v1",
+		Schema: &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(schema)}},
+	}}
+	return d
+}
+
+// withXR returns a MockListFn that lists a composite resource with the
+// supplied spec.
+func withXR(spec map[string]any) test.MockListFn {
+	return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		xr := kunstructured.Unstructured{Object: map[string]any{"spec": spec}}
+		xr.SetName("cool-xr")
+		obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{xr}
+		return nil
+	}
+}
+
+const (
+	schemaV1 = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+		"size":{"type":"integer"},
+		"region":{"type":"string"},
+		"tags":{"type":"array","items":{"type":"object","properties":{"key":{"type":"string"}}}}}}}}`
+
+	schemaTypeChanged = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+		"size":{"type":"string"},
+		"region":{"type":"string"},
+		"tags":{"type":"array","items":{"type":"object","properties":{"key":{"type":"string"}}}}}}}}`
+
+	schemaRegionRemoved = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+		"size":{"type":"integer"},
+		"tags":{"type":"array","items":{"type":"object","properties":{"key":{"type":"string"}}}}}}}}`
+
+	schemaKeyRequired = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+		"size":{"type":"integer"},
+		"region":{"type":"string"},
+		"tags":{"type":"array","items":{"type":"object","required":["key"],"properties":{"key":{"type":"string"}}}}}}}}`
+)
+
+func TestSchemaChanges(t *testing.T) {
+	parse := func(s string) *extv1.JSONSchemaProps {
+		p := &extv1.JSONSchemaProps{}
+		_ = json.Unmarshal([]byte(s), p)
+		return p
+	}
+	none := Changes{TypeChanged: []string{}, Removed: []string{}, Required: []RequiredField{}}
+
+	cases := map[string]struct {
+		reason string
+		old    string
+		s      string
+		want   Changes
+	}{
+		"NoChanges": {
+			reason: "An unchanged schema should have no changes.",
+			old:    schemaV1,
+			s:      schemaV1,
+			want:   none,
+		},
+		"TypeChanged": {
+			reason: "A field whose type changed should be reported.",
+			old:    schemaV1,
+			s:      schemaTypeChanged,
+			want:   Changes{TypeChanged: []string{"spec.size"}, Removed: []string{}, Required: []RequiredField{}},
+		},
+		"Removed": {
+			reason: "A removed field should be reported.",
+			old:    schemaV1,
+			s:      schemaRegionRemoved,
+			want:   Changes{TypeChanged: []string{}, Removed: []string{"spec.region"}, Required: []RequiredField{}},
+		},
+		"RemovedButPreserved": {
+			reason: "A field removed from an object that preserves unknown fields should not be reported.",
+			old:    `{"type":"object","properties":{"spec":{"type":"object","properties":{"region":{"type":"string"}}}}}`,
+			s:      `{"type":"object","properties":{"spec":{"type":"object","x-kubernetes-preserve-unknown-fields":true}}}`,
+			want:   none,
+		},
+		"Required": {
+			reason: "A field of array items that was made required should be reported.",
+			old:    schemaV1,
+			s:      schemaKeyRequired,
+			want:   Changes{TypeChanged: []string{}, Removed: []string{}, Required: []RequiredField{{Parent: "spec.tags[*]", Field: "key"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SchemaChanges(parse(tc.old), parse(tc.s))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSchemaChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		old    *v1.CompositeResourceDefinition
+		xrd    *v1.CompositeResourceDefinition
+	}
+	type want struct {
+		problems []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "We should not list composite resources if the schema did not change.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaV1, nil),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"TypeChangedWithData": {
+			reason: "We should report a type change to a field existing composite resources set.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(map[string]any{"size": int64(3)})},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaTypeChanged, nil),
+			},
+			want: want{
+				problems: []string{fmt.Sprintf(errFmtProblem, "spec.size", errTypeChanged, "XCool", `"cool-xr"`)},
+			},
+		},
+		"TypeChangedWithoutData": {
+			reason: "We should allow a type change to a field no existing composite resource sets.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(map[string]any{"region": "us-west-2"})},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaTypeChanged, nil),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"RemovedWithData": {
+			reason: "We should report removing a field existing composite resources set.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(map[string]any{"region": "us-west-2"})},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaRegionRemoved, nil),
+			},
+			want: want{
+				problems: []string{fmt.Sprintf(errFmtProblem, "spec.region", errFieldRemoved, "XCool", `"cool-xr"`)},
+			},
+		},
+		"RequiredWithMissingData": {
+			reason: "We should report making a field required that existing composite resources don't set.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(map[string]any{"tags": []any{
+					map[string]any{"key": "a"},
+					map[string]any{},
+				}})},
+				old: xrd(schemaV1, nil),
+				xrd: xrd(schemaKeyRequired, nil),
+			},
+			want: want{
+				problems: []string{fmt.Sprintf(errFmtProblem, "spec.tags[*].key", errNowRequired, "XCool", `"cool-xr"`)},
+			},
+		},
+		"RequiredWithData": {
+			reason: "We should allow making a field required that all existing composite resources set.",
+			args: args{
+				client: &test.MockClient{MockList: withXR(map[string]any{"tags": []any{
+					map[string]any{"key": "a"},
+				}})},
+				old: xrd(schemaV1, nil),
+				xrd: xrd(schemaKeyRequired, nil),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"KindNotServed": {
+			reason: "We should allow any change if the composite resource kind is not served.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(&kmeta.NoKindMatchError{})},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaRegionRemoved, nil),
+			},
+			want: want{
+				problems: []string{},
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered while listing composite resources.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				old:    xrd(schemaV1, nil),
+				xrd:    xrd(schemaRegionRemoved, nil),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtList, "XCool"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.args.client)
+			got, err := v.ValidateSchemaUpdate(context.Background(), tc.args.old, tc.args.xrd)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateSchemaUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.problems, got); diff != "" {
+				t.Errorf("\n%s\nv.ValidateSchemaUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	raw := func(d *v1.CompositeResourceDefinition) runtime.RawExtension {
+		b, _ := json.Marshal(d)
+		return runtime.RawExtension{Raw: b}
+	}
+	old := xrd(schemaV1, nil)
+	removed := xrd(schemaRegionRemoved, nil)
+	forced := xrd(schemaRegionRemoved, map[string]string{AnnotationKeyForceSchemaUpdate: "true"})
+	renamed := xrd(schemaV1, nil)
+	renamed.Spec.Names.Kind = "XOther"
+	invalid := xrd(schemaV1, nil)
+	invalid.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Kind: "XCool", Plural: "cools"}
+	problem := fmt.Sprintf(errFmtProblem, "spec.region", errFieldRemoved, "XCool", `"cool-xr"`)
+
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   admission.Response
+	}{
+		"NotACreateOrUpdate": {
+			reason: "We should allow operations other than creates and updates.",
+			req:    admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			want:   admission.Allowed(""),
+		},
+		"ValidCreate": {
+			reason: "We should allow valid creates.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    raw(old),
+			}},
+			want: admission.Allowed(""),
+		},
+		"InvalidCreate": {
+			reason: "We should deny creates that are invalid.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    raw(invalid),
+			}},
+			want: admission.Denied(invalid.ValidateCreate().Error()),
+		},
+		"CompatibleUpdate": {
+			reason: "We should allow compatible updates.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(old),
+				OldObject: raw(old),
+			}},
+			want: admission.Allowed(""),
+		},
+		"ImmutableFieldChanged": {
+			reason: "We should deny updates to immutable fields.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(renamed),
+				OldObject: raw(old),
+			}},
+			want: admission.Denied(renamed.ValidateUpdate(old).Error()),
+		},
+		"IncompatibleUpdate": {
+			reason: "We should deny schema updates that would invalidate existing composite resources.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(removed),
+				OldObject: raw(old),
+			}},
+			want: admission.Denied(problem),
+		},
+		"ForcedIncompatibleUpdate": {
+			reason: "We should allow forced schema updates that would invalidate existing composite resources with a warning.",
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(forced),
+				OldObject: raw(old),
+			}},
+			want: admission.Allowed("").WithWarnings(problem),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(&test.MockClient{MockList: withXR(map[string]any{"region": "us-west-2"})})
+			got := v.Handle(context.Background(), tc.req)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nv.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
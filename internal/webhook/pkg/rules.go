@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"time"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+const (
+	// WebhookConfigurationName is the name of the ValidatingWebhookConfiguration
+	// that Crossplane's initializer installs.
+	WebhookConfigurationName = "crossplane"
+
+	// WebhookName is the name of the package webhook within the
+	// ValidatingWebhookConfiguration.
+	WebhookName = "packages.pkg.crossplane.io"
+
+	timeout = 2 * time.Minute
+)
+
+// Error strings.
+const (
+	errGetConfig    = "cannot get ValidatingWebhookConfiguration"
+	errUpdateConfig = "cannot update ValidatingWebhookConfiguration"
+	errNoTemplate   = "cannot find a webhook to copy client configuration from"
+)
+
+// SetupRules adds a controller that adds the package webhook to the
+// ValidatingWebhookConfiguration while any PackagePolicy exists. The webhook
+// can't be installed along with the rest of Crossplane's webhooks, because
+// Crossplane's initializer installs packages before the webhook is served.
+func SetupRules(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("webhook/packages").
+		For(&v1alpha1.PackagePolicy{}).
+		Complete(NewRulesReconciler(mgr.GetClient()))
+}
+
+// NewRulesReconciler returns a reconciler that adds the package webhook while
+// any PackagePolicy exists.
+func NewRulesReconciler(c client.Client) *RulesReconciler {
+	return &RulesReconciler{client: c}
+}
+
+// A RulesReconciler adds the package webhook while any PackagePolicy exists.
+type RulesReconciler struct {
+	client client.Client
+}
+
+// Reconcile the package webhook. The webhook is added to the
+// ValidatingWebhookConfiguration when the first PackagePolicy is created, and
+// removed when the last is deleted. It's served by the same service as the
+// configuration's other webhooks.
+func (r *RulesReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	l := &v1alpha1.PackagePolicyList{}
+	if err := r.client.List(ctx, l); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListPolicies)
+	}
+
+	vwc := &admv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, vwc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetConfig)
+	}
+
+	hooks := make([]admv1.ValidatingWebhook, 0, len(vwc.Webhooks)+1)
+	var current, tmpl *admv1.ValidatingWebhook
+	for i := range vwc.Webhooks {
+		if vwc.Webhooks[i].Name == WebhookName {
+			current = &vwc.Webhooks[i]
+			continue
+		}
+		if tmpl == nil {
+			tmpl = &vwc.Webhooks[i]
+		}
+		hooks = append(hooks, vwc.Webhooks[i])
+	}
+
+	if len(l.Items) == 0 {
+		if current == nil {
+			return reconcile.Result{}, nil
+		}
+		vwc.Webhooks = hooks
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, vwc), errUpdateConfig)
+	}
+
+	if tmpl == nil {
+		return reconcile.Result{}, errors.New(errNoTemplate)
+	}
+	want := packageWebhook(tmpl.ClientConfig)
+
+	// The API server defaults some fields of the webhook, so we only compare
+	// those we set to determine whether it needs to be updated.
+	if current != nil && equality.Semantic.DeepEqual(current.Rules, want.Rules) && equality.Semantic.DeepEqual(current.ClientConfig, want.ClientConfig) {
+		return reconcile.Result{}, nil
+	}
+	hooks = append(hooks, want)
+	vwc.Webhooks = hooks
+	return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, vwc), errUpdateConfig)
+}
+
+// packageWebhook returns the package webhook, served by the supplied client
+// configuration's service at the package validating webhook path.
+func packageWebhook(cc admv1.WebhookClientConfig) admv1.ValidatingWebhook {
+	cc = *cc.DeepCopy()
+	if cc.Service != nil {
+		path := ValidatingWebhookPath
+		cc.Service.Path = &path
+	}
+	scope := admv1.ClusterScope
+	fail := admv1.Fail
+	none := admv1.SideEffectClassNone
+	return admv1.ValidatingWebhook{
+		Name:         WebhookName,
+		ClientConfig: cc,
+		Rules: []admv1.RuleWithOperations{{
+			Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+			Rule: admv1.Rule{
+				APIGroups:   []string{v1.Group},
+				APIVersions: []string{v1.Version},
+				Resources:   []string{"providers", "configurations"},
+				Scope:       &scope,
+			},
+		}},
+		FailurePolicy:           &fail,
+		SideEffects:             &none,
+		AdmissionReviewVersions: []string{"v1"},
+	}
+}
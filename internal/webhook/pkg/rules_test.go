@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRulesReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	other := admv1.ValidatingWebhook{
+		Name: "compositions.apiextensions.crossplane.io",
+		ClientConfig: admv1.WebhookClientConfig{
+			Service:  &admv1.ServiceReference{Name: "crossplane-webhooks", Namespace: "crossplane-system", Path: pointer.String("/validate-compositions")},
+			CABundle: []byte("ca"),
+		},
+	}
+	hook := packageWebhook(other.ClientConfig)
+	policy := withPolicies([]string{"xpkg.upbound.io"})
+
+	// withConfig returns a MockGetFn that gets a ValidatingWebhookConfiguration
+	// with the supplied webhooks.
+	withConfig := func(hooks ...admv1.ValidatingWebhook) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*admv1.ValidatingWebhookConfiguration).Webhooks = hooks
+			return nil
+		}
+	}
+	// wantUpdate returns a MockUpdateFn that expects the supplied webhooks.
+	wantUpdate := func(hooks ...admv1.ValidatingWebhook) test.MockUpdateFn {
+		return func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			if diff := cmp.Diff(hooks, obj.(*admv1.ValidatingWebhookConfiguration).Webhooks); diff != "" {
+				t.Errorf("Update(...): -want, +got:\n%s", diff)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		want   want
+	}{
+		"ListPoliciesError": {
+			reason: "We should return any error encountered while listing PackagePolicies.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListPolicies),
+			},
+		},
+		"ConfigNotFound": {
+			reason: "We should do nothing if the ValidatingWebhookConfiguration does not exist.",
+			client: &test.MockClient{
+				MockList: policy,
+				MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, WebhookConfigurationName)),
+			},
+		},
+		"AddWebhook": {
+			reason: "We should add the package webhook when a PackagePolicy exists.",
+			client: &test.MockClient{
+				MockList:   policy,
+				MockGet:    withConfig(other),
+				MockUpdate: wantUpdate(other, hook),
+			},
+		},
+		"UpToDate": {
+			reason: "We should not update an up-to-date package webhook.",
+			client: &test.MockClient{
+				MockList:   policy,
+				MockGet:    withConfig(other, hook),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+		},
+		"RemoveWebhook": {
+			reason: "We should remove the package webhook when no PackagePolicy exists.",
+			client: &test.MockClient{
+				MockList:   withPolicies(),
+				MockGet:    withConfig(other, hook),
+				MockUpdate: wantUpdate(other),
+			},
+		},
+		"NoTemplate": {
+			reason: "We should return an error if there is no webhook to copy client configuration from.",
+			client: &test.MockClient{
+				MockList: policy,
+				MockGet:  withConfig(),
+			},
+			want: want{
+				err: errors.New(errNoTemplate),
+			},
+		},
+		"UpdateError": {
+			reason: "We should return any error encountered while updating the ValidatingWebhookConfiguration.",
+			client: &test.MockClient{
+				MockList:   policy,
+				MockGet:    withConfig(other),
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRulesReconciler(tc.client)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
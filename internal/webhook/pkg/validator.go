@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pkg implements a validating webhook for packages.
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+// ValidatingWebhookPath is the path at which the package validating webhook is
+// served.
+const ValidatingWebhookPath = "/validate-pkg-crossplane-io-v1-package"
+
+// Error strings.
+const (
+	errDecode       = "cannot decode package"
+	errDecodeOld    = "cannot decode previous package"
+	errListPolicies = "cannot list PackagePolicies"
+	errParseSource  = "cannot parse package source"
+
+	errFmtNotAllowed = "package %q is not from a source allowed by any PackagePolicy: %s is not an allowed source"
+)
+
+// SetupWebhookWithManager registers a validating webhook for packages with the
+// supplied manager's webhook server, and adds a controller that installs the
+// webhook only while PackagePolicies exist. Packages that don't specify a
+// registry are assumed to be from the supplied default registry.
+func SetupWebhookWithManager(mgr ctrl.Manager, registry string) error {
+	mgr.GetWebhookServer().Register(ValidatingWebhookPath, &webhook.Admission{Handler: NewValidator(mgr.GetClient(), registry)})
+	return SetupRules(mgr)
+}
+
+// NewValidator returns a Validator of packages.
+func NewValidator(c client.Reader, registry string) *Validator {
+	return &Validator{client: c, registry: registry}
+}
+
+// A Validator validates Providers and Configurations, rejecting those whose
+// source is not allowed by any PackagePolicy.
+type Validator struct {
+	client   client.Reader
+	registry string
+}
+
+// Handle an admission request for a package.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	p := newPackage(req.Kind.Kind)
+	if p == nil {
+		return admission.Allowed("")
+	}
+	if err := json.Unmarshal(req.Object.Raw, p); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	// Don't block updates to a package whose source didn't change, for
+	// example removing the finalizer of a package that was installed before
+	// a PackagePolicy disallowed its source.
+	if req.Operation == admissionv1.Update {
+		old := newPackage(req.Kind.Kind)
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+		}
+		if old.GetSource() == p.GetSource() {
+			return admission.Allowed("")
+		}
+	}
+
+	msg, err := v.Check(ctx, p.GetSource())
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if msg != "" {
+		return admission.Denied(msg)
+	}
+	return admission.Allowed("")
+}
+
+// Check returns a description of why the supplied package source may not be
+// installed, if it may not. Any source may be installed when no PackagePolicy
+// exists. Otherwise the source must be allowed by at least one PackagePolicy.
+func (v *Validator) Check(ctx context.Context, source string) (string, error) {
+	l := &v1alpha1.PackagePolicyList{}
+	if err := v.client.List(ctx, l); err != nil {
+		return "", errors.Wrap(err, errListPolicies)
+	}
+	if len(l.Items) == 0 {
+		return "", nil
+	}
+
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(v.registry))
+	if err != nil {
+		return errors.Wrap(err, errParseSource).Error(), nil
+	}
+	repo := ref.Context().Name()
+
+	for _, pp := range l.Items {
+		for _, s := range pp.Spec.AllowedSources {
+			if Allows(s, repo) {
+				return "", nil
+			}
+		}
+	}
+
+	return fmt.Sprintf(errFmtNotAllowed, source, repo), nil
+}
+
+// Allows returns true if the supplied allowed source (a registry, organization,
+// or repository) includes the supplied fully qualified repository.
+func Allows(source, repo string) bool {
+	source = strings.TrimSuffix(source, "/")
+	return repo == source || strings.HasPrefix(repo, source+"/")
+}
+
+func newPackage(kind string) v1.Package {
+	switch kind {
+	case v1.ProviderKind:
+		return &v1.Provider{}
+	case v1.ConfigurationKind:
+		return &v1.Configuration{}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+var _ admission.Handler = &Validator{}
+
+const registry = "xpkg.upbound.io"
+
+// withPolicies returns a MockListFn that lists PackagePolicies that allow the
+// supplied sources.
+func withPolicies(sources ...[]string) test.MockListFn {
+	return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		l := obj.(*v1alpha1.PackagePolicyList)
+		for _, s := range sources {
+			l.Items = append(l.Items, v1alpha1.PackagePolicy{Spec: v1alpha1.PackagePolicySpec{AllowedSources: s}})
+		}
+		return nil
+	}
+}
+
+func TestCheck(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		source string
+	}
+	type want struct {
+		msg string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListPoliciesError": {
+			reason: "We should return any error encountered while listing PackagePolicies.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				source: "crossplane/provider-aws:v0.1.0",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListPolicies),
+			},
+		},
+		"NoPolicies": {
+			reason: "Any package should be allowed when no PackagePolicy exists.",
+			args: args{
+				client: &test.MockClient{MockList: withPolicies()},
+				source: "evil.example.org/crossplane/provider-aws:v0.1.0",
+			},
+		},
+		"AllowedRegistry": {
+			reason: "A package from an allowed registry should be allowed.",
+			args: args{
+				client: &test.MockClient{MockList: withPolicies([]string{"xpkg.upbound.io"})},
+				source: "xpkg.upbound.io/crossplane/provider-aws:v0.1.0",
+			},
+		},
+		"AllowedOrganizationDefaultRegistry": {
+			reason: "A package that doesn't specify a registry should be assumed to be from the default registry.",
+			args: args{
+				client: &test.MockClient{MockList: withPolicies([]string{"xpkg.upbound.io/crossplane/"})},
+				source: "crossplane/provider-aws:v0.1.0",
+			},
+		},
+		"AllowedByAnyPolicy": {
+			reason: "A package should be allowed if any PackagePolicy allows its source.",
+			args: args{
+				client: &test.MockClient{MockList: withPolicies([]string{"registry.example.org"}, []string{"xpkg.upbound.io/crossplane/provider-aws"})},
+				source: "xpkg.upbound.io/crossplane/provider-aws@sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e",
+			},
+		},
+		"NotAllowed": {
+			reason: "A package should not be allowed if no PackagePolicy allows its source.",
+			args: args{
+				client: &test.MockClient{MockList: withPolicies([]string{"xpkg.upbound.io/crossplane"})},
+				source: "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0",
+			},
+			want: want{
+				msg: fmt.Sprintf(errFmtNotAllowed, "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0", "xpkg.upbound.io/crossplane-contrib/provider-aws"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.args.client, registry)
+			msg, err := v.Check(context.Background(), tc.args.source)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Check(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.msg, msg); diff != "" {
+				t.Errorf("\n%s\nv.Check(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	raw := func(o runtime.Object) runtime.RawExtension {
+		b, _ := json.Marshal(o)
+		return runtime.RawExtension{Raw: b}
+	}
+	provider := func(source string) *v1.Provider {
+		return &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}}}
+	}
+	configuration := func(source string) *v1.Configuration {
+		return &v1.Configuration{Spec: v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source}}}
+	}
+	pkind := metav1.GroupVersionKind{Group: v1.Group, Version: v1.Version, Kind: v1.ProviderKind}
+	ckind := metav1.GroupVersionKind{Group: v1.Group, Version: v1.Version, Kind: v1.ConfigurationKind}
+	list := withPolicies([]string{"xpkg.upbound.io/crossplane"})
+	denied := fmt.Sprintf(errFmtNotAllowed, "evil.example.org/provider-aws:v0.1.0", "evil.example.org/provider-aws")
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		req    admission.Request
+		want   admission.Response
+	}{
+		"Delete": {
+			reason: "We should allow operations other than creates and updates.",
+			req:    admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Delete}},
+			want:   admission.Allowed(""),
+		},
+		"CreateAllowedProvider": {
+			reason: "We should allow creating a Provider from an allowed source.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      pkind,
+				Object:    raw(provider("crossplane/provider-aws:v0.1.0")),
+			}},
+			want: admission.Allowed(""),
+		},
+		"CreateDeniedConfiguration": {
+			reason: "We should deny creating a Configuration from a source that is not allowed.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      ckind,
+				Object:    raw(configuration("evil.example.org/provider-aws:v0.1.0")),
+			}},
+			want: admission.Denied(denied),
+		},
+		"UpdateDenied": {
+			reason: "We should deny updating a package to a source that is not allowed.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      pkind,
+				Object:    raw(provider("evil.example.org/provider-aws:v0.1.0")),
+				OldObject: raw(provider("crossplane/provider-aws:v0.1.0")),
+			}},
+			want: admission.Denied(denied),
+		},
+		"UpdateUnchanged": {
+			reason: "We should allow updating a package without changing its source, even if the source is not allowed.",
+			client: &test.MockClient{MockList: list},
+			req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Kind:      pkind,
+				Object:    raw(provider("evil.example.org/provider-aws:v0.1.0")),
+				OldObject: raw(provider("evil.example.org/provider-aws:v0.1.0")),
+			}},
+			want: admission.Allowed(""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewValidator(tc.client, registry)
+			got := v.Handle(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nv.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
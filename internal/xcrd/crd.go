@@ -46,6 +46,9 @@ const (
 	errInvalidClaimNames       = "invalid resource claim names"
 	errMissingClaimNames       = "missing names"
 	errFmtConflictingClaimName = "%q conflicts with composite resource name"
+
+	errInvalidPreviousClaimNames       = "invalid previous resource claim names"
+	errFmtConflictingPreviousClaimName = "%q conflicts with resource claim name"
 )
 
 // ForCompositeResource derives the CustomResourceDefinition for a composite
@@ -188,6 +191,26 @@ func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.Cust
 	return crd, nil
 }
 
+// ForPreviousCompositeResourceClaim derives the CustomResourceDefinition for a
+// composite resource claim that the supplied CompositeResourceDefinition
+// previously offered using the supplied names. A previous kind of claim has the
+// same schema as the kind of claim that is currently offered.
+func ForPreviousCompositeResourceClaim(xrd *v1.CompositeResourceDefinition, names extv1.CustomResourceDefinitionNames) (*extv1.CustomResourceDefinition, error) {
+	if xrd.Spec.ClaimNames == nil {
+		return nil, errors.Wrap(errors.New(errMissingClaimNames), errInvalidPreviousClaimNames)
+	}
+	if n := names.Kind; n == xrd.Spec.ClaimNames.Kind {
+		return nil, errors.Wrap(errors.Errorf(errFmtConflictingPreviousClaimName, n), errInvalidPreviousClaimNames)
+	}
+	if n := names.Plural; n == xrd.Spec.ClaimNames.Plural {
+		return nil, errors.Wrap(errors.Errorf(errFmtConflictingPreviousClaimName, n), errInvalidPreviousClaimNames)
+	}
+
+	prev := xrd.DeepCopy()
+	prev.Spec.ClaimNames = names.DeepCopy()
+	return ForCompositeResourceClaim(prev)
+}
+
 func validateClaimNames(d *v1.CompositeResourceDefinition) error {
 	if d.Spec.ClaimNames == nil {
 		return errors.New(errMissingClaimNames)
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -169,6 +170,11 @@ func TestForCompositeResource(t *testing.T) {
 					Status: &extv1.CustomResourceSubresourceStatus{},
 				},
 				AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+					{
+						Name:     "SYNCED",
+						Type:     "string",
+						JSONPath: ".status.conditions[?(@.type=='Synced')].status",
+					},
 					{
 						Name:     "READY",
 						Type:     "string",
@@ -253,6 +259,14 @@ func TestForCompositeResource(t *testing.T) {
 										Default:     &extv1.JSON{Raw: []byte(`"Automatic"`)},
 										Description: "Alpha: This field may be deprecated or changed without notice.",
 									},
+									"driftPolicy": {
+										Type: "string",
+										Enum: []extv1.JSON{
+											{Raw: []byte(`"Correct"`)},
+											{Raw: []byte(`"Report"`)},
+										},
+										Description: "DriftPolicy overrides the drift policy of the composition.",
+									},
 									"claimRef": {
 										Type:     "object",
 										Required: []string{"apiVersion", "kind", "namespace", "name"},
@@ -332,18 +346,37 @@ func TestForCompositeResource(t *testing.T) {
 
 									// From CompositeResourceStatusProps()
 									"conditions": {
-										Description: "Conditions of the resource.",
-										Type:        "array",
+										Description:  "Conditions of the resource.",
+										Type:         "array",
+										XListType:    pointer.String("map"),
+										XListMapKeys: []string{"type"},
 										Items: &extv1.JSONSchemaPropsOrArray{
 											Schema: &extv1.JSONSchemaProps{
 												Type:     "object",
 												Required: []string{"lastTransitionTime", "reason", "status", "type"},
 												Properties: map[string]extv1.JSONSchemaProps{
-													"lastTransitionTime": {Type: "string", Format: "date-time"},
-													"message":            {Type: "string"},
-													"reason":             {Type: "string"},
-													"status":             {Type: "string"},
-													"type":               {Type: "string"},
+													"lastTransitionTime": {
+														Description: "LastTransitionTime is the last time this condition transitioned from one status to another.",
+														Type:        "string",
+														Format:      "date-time",
+													},
+													"message": {
+														Description: "A Message containing details about this condition's last transition from one status to another, if any.",
+														Type:        "string",
+													},
+													"reason": {
+														Description: "A Reason for this condition's last transition from one status to another.",
+														Type:        "string",
+													},
+													"status": {
+														Description: "Status of this condition; is it currently True, False, or Unknown?",
+														Type:        "string",
+														Enum:        []extv1.JSON{{Raw: []byte(`"True"`)}, {Raw: []byte(`"False"`)}, {Raw: []byte(`"Unknown"`)}},
+													},
+													"type": {
+														Description: "Type of this condition. At most one of each condition type may apply to a resource at any point in time.",
+														Type:        "string",
+													},
 												},
 											},
 										},
@@ -354,6 +387,16 @@ func TestForCompositeResource(t *testing.T) {
 											"lastPublishedTime": {Type: "string", Format: "date-time"},
 										},
 									},
+									"lastReconcile": {
+										Description: "LastReconcile summarizes why the resource was last requeued. It is only recorded when Crossplane is started with --record-reconcile-summaries.",
+										Type:        "object",
+										Properties: map[string]extv1.JSONSchemaProps{
+											"id":      {Type: "string"},
+											"time":    {Type: "string", Format: "date-time"},
+											"reason":  {Type: "string"},
+											"message": {Type: "string"},
+										},
+									},
 								},
 							},
 						},
@@ -582,6 +625,11 @@ func TestForCompositeResourceClaim(t *testing.T) {
 						Status: &extv1.CustomResourceSubresourceStatus{},
 					},
 					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:     "SYNCED",
+							Type:     "string",
+							JSONPath: ".status.conditions[?(@.type=='Synced')].status",
+						},
 						{
 							Name:     "READY",
 							Type:     "string",
@@ -727,18 +775,37 @@ func TestForCompositeResourceClaim(t *testing.T) {
 
 										// From CompositeResourceStatusProps()
 										"conditions": {
-											Description: "Conditions of the resource.",
-											Type:        "array",
+											Description:  "Conditions of the resource.",
+											Type:         "array",
+											XListType:    pointer.String("map"),
+											XListMapKeys: []string{"type"},
 											Items: &extv1.JSONSchemaPropsOrArray{
 												Schema: &extv1.JSONSchemaProps{
 													Type:     "object",
 													Required: []string{"lastTransitionTime", "reason", "status", "type"},
 													Properties: map[string]extv1.JSONSchemaProps{
-														"lastTransitionTime": {Type: "string", Format: "date-time"},
-														"message":            {Type: "string"},
-														"reason":             {Type: "string"},
-														"status":             {Type: "string"},
-														"type":               {Type: "string"},
+														"lastTransitionTime": {
+															Description: "LastTransitionTime is the last time this condition transitioned from one status to another.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A Message containing details about this condition's last transition from one status to another, if any.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "A Reason for this condition's last transition from one status to another.",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of this condition; is it currently True, False, or Unknown?",
+															Type:        "string",
+															Enum:        []extv1.JSON{{Raw: []byte(`"True"`)}, {Raw: []byte(`"False"`)}, {Raw: []byte(`"Unknown"`)}},
+														},
+														"type": {
+															Description: "Type of this condition. At most one of each condition type may apply to a resource at any point in time.",
+															Type:        "string",
+														},
 													},
 												},
 											},
@@ -749,6 +816,16 @@ func TestForCompositeResourceClaim(t *testing.T) {
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
 											},
 										},
+										"lastReconcile": {
+											Description: "LastReconcile summarizes why the resource was last requeued. It is only recorded when Crossplane is started with --record-reconcile-summaries.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"id":      {Type: "string"},
+												"time":    {Type: "string", Format: "date-time"},
+												"reason":  {Type: "string"},
+												"message": {Type: "string"},
+											},
+										},
 									},
 								},
 							},
@@ -768,3 +845,89 @@ func TestForCompositeResourceClaim(t *testing.T) {
 		t.Errorf("ForCompositeResourceClaim(...): -want, +got:\n%s", diff)
 	}
 }
+
+func TestForPreviousCompositeResourceClaim(t *testing.T) {
+	d := &v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			Names:      extv1.CustomResourceDefinitionNames{Kind: "CoolComposite", Plural: "coolcomposites"},
+			ClaimNames: &extv1.CustomResourceDefinitionNames{Kind: "CoolerClaim", Plural: "coolerclaims"},
+			Versions:   []v1.CompositeResourceDefinitionVersion{{Name: "v1", Served: true, Referenceable: true}},
+		},
+	}
+	previous := extv1.CustomResourceDefinitionNames{Kind: "CoolClaim", Plural: "coolclaims"}
+
+	// A previous kind of claim should be rendered exactly as it was when its
+	// names were the claim names.
+	renamed := d.DeepCopy()
+	renamed.Spec.ClaimNames = previous.DeepCopy()
+	crd, _ := ForCompositeResourceClaim(renamed)
+
+	type args struct {
+		d     *v1.CompositeResourceDefinition
+		names extv1.CustomResourceDefinitionNames
+	}
+	type want struct {
+		crd *extv1.CustomResourceDefinition
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MissingClaimNames": {
+			reason: "We should return an error if the XRD doesn't name a claim.",
+			args: args{
+				d:     &v1.CompositeResourceDefinition{},
+				names: previous,
+			},
+			want: want{
+				err: errors.Wrap(errors.New(errMissingClaimNames), errInvalidPreviousClaimNames),
+			},
+		},
+		"KindConflict": {
+			reason: "We should return an error if the previous kind of claim is the current kind of claim.",
+			args: args{
+				d:     d,
+				names: extv1.CustomResourceDefinitionNames{Kind: "CoolerClaim", Plural: "coolclaims"},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtConflictingPreviousClaimName, "CoolerClaim"), errInvalidPreviousClaimNames),
+			},
+		},
+		"PluralConflict": {
+			reason: "We should return an error if the previous plural of claim is the current plural of claim.",
+			args: args{
+				d:     d,
+				names: extv1.CustomResourceDefinitionNames{Kind: "CoolClaim", Plural: "coolerclaims"},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtConflictingPreviousClaimName, "coolerclaims"), errInvalidPreviousClaimNames),
+			},
+		},
+		"Success": {
+			reason: "We should render a claim CRD using the previous claim names.",
+			args: args{
+				d:     d,
+				names: previous,
+			},
+			want: want{
+				crd: crd,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ForPreviousCompositeResourceClaim(tc.args.d, tc.args.names)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nForPreviousCompositeResourceClaim(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.crd, got); diff != "" {
+				t.Errorf("\n%s\nForPreviousCompositeResourceClaim(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
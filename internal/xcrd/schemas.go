@@ -16,13 +16,39 @@ limitations under the License.
 
 package xcrd
 
-import extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
 
 // Label keys.
 const (
 	LabelKeyNamePrefixForComposed = "crossplane.io/composite"
 	LabelKeyClaimName             = "crossplane.io/claim-name"
 	LabelKeyClaimNamespace        = "crossplane.io/claim-namespace"
+
+	// LabelKeyClaimRequester is the user that requested a claim, rewritten
+	// as a valid label value. It's set on composite and composed resources
+	// alongside AnnotationKeyClaimRequester.
+	LabelKeyClaimRequester = "crossplane.io/claim-requester"
+)
+
+// Annotation keys.
+const (
+	// AnnotationKeyRestored may be set to "true" on a claim or composite
+	// resource that was restored from a backup. Crossplane treats annotated
+	// resources as it treats all resources in restore mode.
+	AnnotationKeyRestored = "crossplane.io/restored"
+
+	// AnnotationKeyClaimRequester is the user that requested a claim. It's
+	// set on claims by Crossplane's claim webhook, and propagated to their
+	// composite and composed resources.
+	AnnotationKeyClaimRequester = "crossplane.io/claim-requester"
+
+	// AnnotationKeyMigratedFrom is the UID of the claim of a previous kind
+	// that a claim was migrated from. It's set on claims by Crossplane when
+	// the kind of claim a CompositeResourceDefinition offers is renamed.
+	AnnotationKeyMigratedFrom = "crossplane.io/migrated-from"
 )
 
 // PropagateSpecProps is the list of XRC spec properties to propagate
@@ -103,6 +129,14 @@ func CompositeResourceSpecProps() map[string]extv1.JSONSchemaProps {
 			Default:     &extv1.JSON{Raw: []byte(`"Automatic"`)},
 			Description: "Alpha: This field may be deprecated or changed without notice.",
 		},
+		"driftPolicy": {
+			Type: "string",
+			Enum: []extv1.JSON{
+				{Raw: []byte(`"Correct"`)},
+				{Raw: []byte(`"Report"`)},
+			},
+			Description: "DriftPolicy overrides the drift policy of the composition.",
+		},
 		"claimRef": {
 			Type:     "object",
 			Required: []string{"apiVersion", "kind", "namespace", "name"},
@@ -278,19 +312,40 @@ func CompositeResourceClaimSpecProps() map[string]extv1.JSONSchemaProps {
 // infrastructure resources.
 func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 	return map[string]extv1.JSONSchemaProps{
+		// Conditions follow the conventions of metav1.Condition, and are keyed
+		// by type, so that tools like kubectl wait understand them.
 		"conditions": {
-			Description: "Conditions of the resource.",
-			Type:        "array",
+			Description:  "Conditions of the resource.",
+			Type:         "array",
+			XListType:    pointer.String("map"),
+			XListMapKeys: []string{"type"},
 			Items: &extv1.JSONSchemaPropsOrArray{
 				Schema: &extv1.JSONSchemaProps{
 					Type:     "object",
 					Required: []string{"lastTransitionTime", "reason", "status", "type"},
 					Properties: map[string]extv1.JSONSchemaProps{
-						"lastTransitionTime": {Type: "string", Format: "date-time"},
-						"message":            {Type: "string"},
-						"reason":             {Type: "string"},
-						"status":             {Type: "string"},
-						"type":               {Type: "string"},
+						"lastTransitionTime": {
+							Description: "LastTransitionTime is the last time this condition transitioned from one status to another.",
+							Type:        "string",
+							Format:      "date-time",
+						},
+						"message": {
+							Description: "A Message containing details about this condition's last transition from one status to another, if any.",
+							Type:        "string",
+						},
+						"reason": {
+							Description: "A Reason for this condition's last transition from one status to another.",
+							Type:        "string",
+						},
+						"status": {
+							Description: "Status of this condition; is it currently True, False, or Unknown?",
+							Type:        "string",
+							Enum:        []extv1.JSON{{Raw: []byte(`"True"`)}, {Raw: []byte(`"False"`)}, {Raw: []byte(`"Unknown"`)}},
+						},
+						"type": {
+							Description: "Type of this condition. At most one of each condition type may apply to a resource at any point in time.",
+							Type:        "string",
+						},
 					},
 				},
 			},
@@ -301,6 +356,16 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 				"lastPublishedTime": {Type: "string", Format: "date-time"},
 			},
 		},
+		"lastReconcile": {
+			Description: "LastReconcile summarizes why the resource was last requeued. It is only recorded when Crossplane is started with --record-reconcile-summaries.",
+			Type:        "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"id":      {Type: "string"},
+				"time":    {Type: "string", Format: "date-time"},
+				"reason":  {Type: "string"},
+				"message": {Type: "string"},
+			},
+		},
 	}
 }
 
@@ -308,6 +373,11 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 // that should exist in all generated composite resource CRDs.
 func CompositeResourcePrinterColumns() []extv1.CustomResourceColumnDefinition {
 	return []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "SYNCED",
+			Type:     "string",
+			JSONPath: ".status.conditions[?(@.type=='Synced')].status",
+		},
 		{
 			Name:     "READY",
 			Type:     "string",
@@ -330,6 +400,11 @@ func CompositeResourcePrinterColumns() []extv1.CustomResourceColumnDefinition {
 // columns that should exist in all generated composite resource claim CRDs.
 func CompositeResourceClaimPrinterColumns() []extv1.CustomResourceColumnDefinition {
 	return []extv1.CustomResourceColumnDefinition{
+		{
+			Name:     "SYNCED",
+			Type:     "string",
+			JSONPath: ".status.conditions[?(@.type=='Synced')].status",
+		},
 		{
 			Name:     "READY",
 			Type:     "string",
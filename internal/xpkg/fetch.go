@@ -54,9 +54,10 @@ type Fetcher interface {
 
 // K8sFetcher uses kubernetes credentials to fetch package images.
 type K8sFetcher struct {
-	client    kubernetes.Interface
-	namespace string
-	transport http.RoundTripper
+	client     kubernetes.Interface
+	namespace  string
+	transport  http.RoundTripper
+	transports TransportSource
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher
@@ -124,7 +125,11 @@ func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...s
 	if err != nil {
 		return nil, err
 	}
-	return remote.Image(ref, remote.WithAuthFromKeychain(auth), remote.WithTransport(i.transport), remote.WithContext(ctx))
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return remote.Image(ref, remote.WithAuthFromKeychain(auth), remote.WithTransport(t), remote.WithContext(ctx))
 }
 
 // Head fetches a package descriptor.
@@ -136,7 +141,11 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
-	return remote.Head(ref, remote.WithAuthFromKeychain(auth), remote.WithTransport(i.transport), remote.WithContext(ctx))
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return remote.Head(ref, remote.WithAuthFromKeychain(auth), remote.WithTransport(t), remote.WithContext(ctx))
 }
 
 // Tags fetches a package's tags.
@@ -148,7 +157,19 @@ func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
-	return remote.List(ref.Context(), remote.WithAuthFromKeychain(auth), remote.WithTransport(i.transport), remote.WithContext(ctx))
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return remote.List(ref.Context(), remote.WithAuthFromKeychain(auth), remote.WithTransport(t), remote.WithContext(ctx))
+}
+
+// transportFor returns the transport used to fetch the supplied reference.
+func (i *K8sFetcher) transportFor(ctx context.Context, ref name.Reference) (http.RoundTripper, error) {
+	if i.transports == nil {
+		return i.transport, nil
+	}
+	return i.transports.Transport(ctx, ref.Context().RegistryStr())
 }
 
 // NopFetcher always returns an empty image and never returns error.
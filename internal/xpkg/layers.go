@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Package image layer annotations.
+const (
+	// AnnotationKey is the key of the annotation that distinguishes the
+	// layers of a package image.
+	AnnotationKey = "io.crossplane.xpkg"
+
+	// BaseAnnotationValue distinguishes the layer containing package
+	// metadata.
+	BaseAnnotationValue = "base"
+
+	// ObjectsAnnotationValue distinguishes layers containing additional
+	// package objects.
+	ObjectsAnnotationValue = "objects"
+
+	// ExamplesAnnotationValue distinguishes layers containing example
+	// objects, which are not installed.
+	ExamplesAnnotationValue = "examples"
+)
+
+// Package artifact layers. Packages may be published as OCI artifacts rather
+// than container images, for example using ORAS, so that they can be stored
+// in registries that reject container image manifests. The layers of a package
+// artifact may contain a raw YAML stream rather than a tarball.
+const (
+	// MediaTypeStream is the media type of layers that contain a raw
+	// package YAML stream.
+	MediaTypeStream = "application/vnd.crossplane.xpkg.stream.v1+yaml"
+
+	// TitleAnnotationKey is the key of the annotation that ORAS uses to
+	// record the name of the file a layer was pushed from.
+	TitleAnnotationKey = "org.opencontainers.image.title"
+)
+
+// IsStreamLayer returns true if the supplied layer contains a raw package YAML
+// stream rather than a tarball, i.e. if it has media type MediaTypeStream or
+// was pushed from a file named package.yaml.
+func IsStreamLayer(l v1.Descriptor) bool {
+	return string(l.MediaType) == MediaTypeStream || l.Annotations[TitleAnnotationKey] == StreamFile
+}
+
+// LayerType returns the value of the supplied layer's io.crossplane.xpkg
+// annotation. A stream layer without the annotation is a base layer.
+func LayerType(l v1.Descriptor) string {
+	if t, ok := l.Annotations[AnnotationKey]; ok {
+		return t
+	}
+	if IsStreamLayer(l) {
+		return BaseAnnotationValue
+	}
+	return ""
+}
+
+const (
+	errGetManifest      = "failed to get package image manifest"
+	errGetConfigFile    = "failed to get package image config file"
+	errGetLayer         = "failed to get package image layer"
+	errReadLayer        = "failed to read package image layer"
+	errNoStreamFile     = "failed to find package stream file"
+	errNoExamples       = "no examples supplied"
+	errBuildImage       = "failed to build package image"
+	errFmtUnsafeExample = "refusing to use example with unsafe path %q"
+)
+
+// Contents of a package image.
+type Contents struct {
+	// Stream is the package's YAML stream.
+	Stream []byte
+
+	// Examples are the files of the package's examples layers, keyed by
+	// their slash separated path relative to the examples root.
+	Examples map[string][]byte
+}
+
+// Extract the contents of the supplied package image. Like the package manager,
+// we read the YAML stream from the annotated base and objects layers if the
+// image has an annotated base layer, and from the flattened image filesystem
+// otherwise. Examples are only read from annotated examples layers.
+func Extract(img v1.Image) (*Contents, error) {
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+
+	c := &Contents{Examples: map[string][]byte{}}
+	var base []byte
+	objects := make([][]byte, 0)
+	for _, l := range m.Layers {
+		switch LayerType(l) {
+		case BaseAnnotationValue, ObjectsAnnotationValue:
+			s, err := layerStream(img, l)
+			if err != nil {
+				return nil, err
+			}
+			if LayerType(l) == BaseAnnotationValue {
+				base = s
+				continue
+			}
+			objects = append(objects, s)
+		case ExamplesAnnotationValue:
+			files, err := layerFiles(img, l.Digest)
+			if err != nil {
+				return nil, err
+			}
+			for p, b := range files {
+				c.Examples[p] = b
+			}
+		}
+	}
+
+	if base == nil {
+		files, err := tarFiles(mutate.Extract(img))
+		if err != nil {
+			return nil, err
+		}
+		s, ok := files[StreamFile]
+		if !ok {
+			return nil, errors.New(errNoStreamFile)
+		}
+		c.Stream = s
+		return c, nil
+	}
+
+	// The base layer's stream comes first, regardless of the order of the
+	// layers. Make sure the last document of one stream is not merged with the
+	// first document of the next.
+	c.Stream = bytes.Join(append([][]byte{base}, objects...), []byte("\n---\n"))
+	return c, nil
+}
+
+// ExamplesLayer returns an image layer containing the supplied example files,
+// keyed by their slash separated path relative to the examples root.
+func ExamplesLayer(examples map[string][]byte) (v1.Layer, error) {
+	if len(examples) == 0 {
+		return nil, errors.New(errNoExamples)
+	}
+
+	paths := make([]string, 0, len(examples))
+	for p := range examples {
+		if !safe(p) {
+			return nil, errors.Errorf(errFmtUnsafeExample, p)
+		}
+		paths = append(paths, p)
+	}
+	// Sort the paths so that the same examples always produce the same layer.
+	sort.Strings(paths)
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, p := range paths {
+		hdr := &tar.Header{
+			Name: p,
+			Mode: int64(StreamFileMode),
+			Size: int64(len(examples[p])),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, errors.Wrap(err, errTarFromStream)
+		}
+		if _, err := tw.Write(examples[p]); err != nil {
+			return nil, errors.Wrap(err, errTarFromStream)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, errTarFromStream)
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	return layer, errors.Wrap(err, errLayerFromTar)
+}
+
+// WithExamples returns the supplied package image with any existing examples
+// layers replaced by the supplied examples layer. All other layers, and their
+// annotations, are preserved.
+func WithExamples(img v1.Image, examples v1.Layer) (v1.Image, error) {
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+	cf, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetConfigFile)
+	}
+
+	adds := make([]mutate.Addendum, 0, len(m.Layers)+1)
+	for _, l := range m.Layers {
+		if l.Annotations[AnnotationKey] == ExamplesAnnotationValue {
+			continue
+		}
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetLayer)
+		}
+		adds = append(adds, mutate.Addendum{Layer: layer, Annotations: l.Annotations})
+	}
+	adds = append(adds, mutate.Addendum{Layer: examples, Annotations: map[string]string{AnnotationKey: ExamplesAnnotationValue}})
+
+	// Start from the original config without its layers, which are appended
+	// back below along with the new examples layer.
+	cf = cf.DeepCopy()
+	cf.RootFS.DiffIDs = nil
+	cf.History = nil
+	out, err := mutate.ConfigFile(empty.Image, cf)
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildImage)
+	}
+	out, err = mutate.Append(out, adds...)
+	return out, errors.Wrap(err, errBuildImage)
+}
+
+// layerStream returns the package YAML stream of the supplied layer of the
+// supplied image.
+func layerStream(img v1.Image, l v1.Descriptor) ([]byte, error) {
+	if !IsStreamLayer(l) {
+		files, err := layerFiles(img, l.Digest)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := files[StreamFile]
+		if !ok {
+			return nil, errors.New(errNoStreamFile)
+		}
+		return s, nil
+	}
+	layer, err := img.LayerByDigest(l.Digest)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetLayer)
+	}
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayer)
+	}
+	defer rc.Close() //nolint:errcheck // Only reads; nothing to flush.
+	s, err := io.ReadAll(rc)
+	return s, errors.Wrap(err, errReadLayer)
+}
+
+// layerFiles returns the regular files of the supplied layer of the supplied
+// image, keyed by their slash separated path.
+func layerFiles(img v1.Image, d v1.Hash) (map[string][]byte, error) {
+	l, err := img.LayerByDigest(d)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetLayer)
+	}
+	rc, err := l.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadLayer)
+	}
+	return tarFiles(rc)
+}
+
+// tarFiles returns the regular files of the supplied tarball, keyed by their
+// slash separated path. Files with unsafe paths are ignored.
+func tarFiles(rc io.ReadCloser) (map[string][]byte, error) {
+	defer rc.Close() //nolint:errcheck // Only reads; nothing to flush.
+
+	files := map[string][]byte{}
+	t := tar.NewReader(rc)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadLayer)
+		}
+		p := path.Clean(strings.TrimPrefix(h.Name, "/"))
+		if h.Typeflag != tar.TypeReg || !safe(p) {
+			continue
+		}
+		b, err := io.ReadAll(t)
+		if err != nil {
+			return nil, errors.Wrap(err, errReadLayer)
+		}
+		files[p] = b
+	}
+}
+
+// safe returns true if the supplied slash separated path is relative and stays
+// within its root directory.
+func safe(p string) bool {
+	c := path.Clean(p)
+	return c == p && c != "." && !path.IsAbs(c) && c != ".." && !strings.HasPrefix(c, "../")
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// tarLayer returns a layer containing the supplied files.
+func tarLayer(t *testing.T, files map[string]string) v1.Layer {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// image returns an image with the supplied layers.
+func image(t *testing.T, adds ...mutate.Addendum) v1.Image {
+	t.Helper()
+	img, err := mutate.Append(empty.Image, adds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func annotated(value string) map[string]string {
+	return map[string]string{AnnotationKey: value}
+}
+
+func TestExtract(t *testing.T) {
+	type want struct {
+		c   *Contents
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		img    v1.Image
+		want   want
+	}{
+		"Flattened": {
+			reason: "We should read the stream from the flattened filesystem of an image without an annotated base layer.",
+			img: image(t,
+				mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "old"})},
+				mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "meta"})},
+			),
+			want: want{
+				c: &Contents{Stream: []byte("meta")},
+			},
+		},
+		"NoStreamFile": {
+			reason: "We should return an error if an image has no stream file.",
+			img:    image(t, mutate.Addendum{Layer: tarLayer(t, map[string]string{"other.yaml": "meta"})}),
+			want: want{
+				err: errors.New(errNoStreamFile),
+			},
+		},
+		"Annotated": {
+			reason: "We should read the base layer's stream followed by any objects layers' streams, and any examples.",
+			img: image(t,
+				mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "crds"}), Annotations: annotated(ObjectsAnnotationValue)},
+				mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "meta"}), Annotations: annotated(BaseAnnotationValue)},
+				mutate.Addendum{Layer: tarLayer(t, map[string]string{"aws/bucket.yaml": "bucket", "../escape.yaml": "nope"}), Annotations: annotated(ExamplesAnnotationValue)},
+			),
+			want: want{
+				c: &Contents{
+					Stream:   []byte("meta\n---\ncrds"),
+					Examples: map[string][]byte{"aws/bucket.yaml": []byte("bucket")},
+				},
+			},
+		},
+		"Artifact": {
+			reason: "We should read raw YAML stream layers of a package artifact, treating an unannotated stream layer as the base layer.",
+			img: image(t,
+				mutate.Addendum{Layer: static.NewLayer([]byte("meta"), MediaTypeStream)},
+				mutate.Addendum{
+					Layer:       static.NewLayer([]byte("crds"), types.OCILayer),
+					Annotations: map[string]string{AnnotationKey: ObjectsAnnotationValue, TitleAnnotationKey: StreamFile},
+				},
+			),
+			want: want{
+				c: &Contents{Stream: []byte("meta\n---\ncrds")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := Extract(tc.img)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtract(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, c, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nExtract(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExamplesLayer(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		examples map[string][]byte
+		want     error
+	}{
+		"NoExamples": {
+			reason: "We should return an error if no examples are supplied.",
+			want:   errors.New(errNoExamples),
+		},
+		"UnsafePath": {
+			reason:   "We should return an error if an example's path is not within the examples root.",
+			examples: map[string][]byte{"../bucket.yaml": []byte("bucket")},
+			want:     errors.Errorf(errFmtUnsafeExample, "../bucket.yaml"),
+		},
+		"Success": {
+			reason:   "We should build a layer from safe example paths.",
+			examples: map[string][]byte{"aws/bucket.yaml": []byte("bucket")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ExamplesLayer(tc.examples)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExamplesLayer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithExamples(t *testing.T) {
+	img := image(t,
+		mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "meta"}), Annotations: annotated(BaseAnnotationValue)},
+		mutate.Addendum{Layer: tarLayer(t, map[string]string{"old.yaml": "old"}), Annotations: annotated(ExamplesAnnotationValue)},
+		mutate.Addendum{Layer: tarLayer(t, map[string]string{StreamFile: "crds"}), Annotations: annotated(ObjectsAnnotationValue)},
+	)
+
+	examples, err := ExamplesLayer(map[string][]byte{"new.yaml": []byte("new")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := WithExamples(img, examples)
+	if err != nil {
+		t.Fatalf("WithExamples(...): %s", err)
+	}
+
+	m, err := got.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := make([]string, 0, len(m.Layers))
+	for _, l := range m.Layers {
+		annotations = append(annotations, l.Annotations[AnnotationKey])
+	}
+	want := []string{BaseAnnotationValue, ObjectsAnnotationValue, ExamplesAnnotationValue}
+	if diff := cmp.Diff(want, annotations); diff != "" {
+		t.Errorf("WithExamples(...): -want layer annotations, +got layer annotations:\n%s", diff)
+	}
+
+	c, err := Extract(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantc := &Contents{Stream: []byte("meta\n---\ncrds"), Examples: map[string][]byte{"new.yaml": []byte("new")}}
+	if diff := cmp.Diff(wantc, c); diff != "" {
+		t.Errorf("WithExamples(...): -want contents, +got contents:\n%s", diff)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+const (
+	errNotHTTPTransport    = "fetcher transport is not an HTTP transport"
+	errListRegistryConfigs = "cannot list RegistryConfigs"
+	errParseProxyURL       = "cannot parse proxy URL"
+	errNoCACerts           = "no certificates could be parsed from the CA bundle"
+	errLoadClientCert      = "cannot load client certificate"
+	errFmtRegistryConfig   = "cannot configure access to registry %q using RegistryConfig %q"
+	errFmtGetSecret        = "cannot get Secret %q"
+	errFmtProxyScheme      = "proxy URL scheme must be http or https, not %q"
+)
+
+// SecretKeyCABundle is the key of a RegistryConfig's CA bundle Secret under
+// which PEM encoded certificates are stored.
+const SecretKeyCABundle = "ca.crt"
+
+// A TransportSource returns the HTTP transport used to access a registry.
+type TransportSource interface {
+	Transport(ctx context.Context, registry string) (http.RoundTripper, error)
+}
+
+// A TransportSourceFn returns the HTTP transport used to access a registry.
+type TransportSourceFn func(ctx context.Context, registry string) (http.RoundTripper, error)
+
+// Transport returns the HTTP transport used to access the supplied registry.
+func (fn TransportSourceFn) Transport(ctx context.Context, registry string) (http.RoundTripper, error) {
+	return fn(ctx, registry)
+}
+
+// WithTransportSource is a FetcherOpt that determines the HTTP transport used
+// to access each registry using the supplied TransportSource.
+func WithTransportSource(s TransportSource) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.transports = s
+		return nil
+	}
+}
+
+// WithRegistryConfigs is a FetcherOpt that configures a K8sFetcher to access
+// registries as specified by the RegistryConfigs read using the supplied
+// client. Registries with no RegistryConfig are accessed using the fetcher's
+// default transport.
+func WithRegistryConfigs(c client.Reader) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		t, ok := k.transport.(*http.Transport)
+		if !ok {
+			return errors.New(errNotHTTPTransport)
+		}
+		k.transports = NewRegistryConfigTransports(c, k.namespace, t)
+		return nil
+	}
+}
+
+// RegistryConfigTransports derives the HTTP transport used to access a
+// registry from the RegistryConfig for that registry, if any.
+type RegistryConfigTransports struct {
+	client    client.Reader
+	namespace string
+	base      *http.Transport
+
+	mx    sync.Mutex
+	cache map[string]versionedTransport
+}
+
+// A versionedTransport is a transport derived from particular versions of a
+// RegistryConfig and the Secrets it references.
+type versionedTransport struct {
+	version   string
+	transport *http.Transport
+}
+
+// NewRegistryConfigTransports returns a TransportSource that derives the
+// transport used to access a registry by applying the RegistryConfig for that
+// registry, if any, to the supplied base transport. Secrets referenced by
+// RegistryConfigs are read from the supplied namespace.
+func NewRegistryConfigTransports(c client.Reader, namespace string, base *http.Transport) *RegistryConfigTransports {
+	return &RegistryConfigTransports{
+		client:    c,
+		namespace: namespace,
+		base:      base,
+		cache:     map[string]versionedTransport{},
+	}
+}
+
+// Transport returns the HTTP transport used to access the supplied registry.
+// If more than one RegistryConfig applies to the registry the first, sorted by
+// name, is used. Transports are reused until the RegistryConfig or any of the
+// Secrets it references change.
+func (r *RegistryConfigTransports) Transport(ctx context.Context, registry string) (http.RoundTripper, error) {
+	l := &v1alpha1.RegistryConfigList{}
+	if err := r.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListRegistryConfigs)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].GetName() < l.Items[j].GetName() })
+
+	var rc *v1alpha1.RegistryConfig
+	for i := range l.Items {
+		if l.Items[i].Spec.Registry == registry {
+			rc = &l.Items[i]
+			break
+		}
+	}
+	if rc == nil {
+		return r.base, nil
+	}
+
+	ca, cert, err := r.secrets(ctx, rc)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRegistryConfig, registry, rc.GetName())
+	}
+
+	version := versionOf(rc, ca, cert)
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if c, ok := r.cache[registry]; ok && c.version == version {
+		return c.transport, nil
+	}
+
+	t, err := configure(r.base, rc.Spec, ca, cert)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRegistryConfig, registry, rc.GetName())
+	}
+	if c, ok := r.cache[registry]; ok {
+		c.transport.CloseIdleConnections()
+	}
+	r.cache[registry] = versionedTransport{version: version, transport: t}
+	return t, nil
+}
+
+// secrets returns the CA bundle and client certificate Secrets referenced by
+// the supplied RegistryConfig. Either may be nil if it is not referenced.
+func (r *RegistryConfigTransports) secrets(ctx context.Context, rc *v1alpha1.RegistryConfig) (ca, cert *corev1.Secret, err error) {
+	if rc.Spec.TLS == nil {
+		return nil, nil, nil
+	}
+	if ref := rc.Spec.TLS.CABundleSecretRef; ref != nil {
+		ca = &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: ref.Name}, ca); err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtGetSecret, ref.Name)
+		}
+	}
+	if ref := rc.Spec.TLS.ClientCertificateSecretRef; ref != nil {
+		cert = &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: ref.Name}, cert); err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtGetSecret, ref.Name)
+		}
+	}
+	return ca, cert, nil
+}
+
+// versionOf returns a string that changes whenever the supplied RegistryConfig
+// or any of the supplied Secrets change.
+func versionOf(rc *v1alpha1.RegistryConfig, secrets ...*corev1.Secret) string {
+	v := []string{string(rc.GetUID()), rc.GetResourceVersion()}
+	for _, s := range secrets {
+		if s == nil {
+			v = append(v, "")
+			continue
+		}
+		v = append(v, string(s.GetUID()), s.GetResourceVersion())
+	}
+	return strings.Join(v, "/")
+}
+
+// configure returns a copy of the supplied base transport configured per the
+// supplied RegistryConfig spec, using the supplied CA bundle and client
+// certificate Secrets.
+func configure(base *http.Transport, spec v1alpha1.RegistryConfigSpec, ca, cert *corev1.Secret) (*http.Transport, error) {
+	t := base.Clone()
+
+	if spec.ProxyURL != nil {
+		u, err := url.Parse(*spec.ProxyURL)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseProxyURL)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errors.Errorf(errFmtProxyScheme, u.Scheme)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if ca == nil && cert == nil {
+		return t, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t.TLSClientConfig != nil {
+		cfg = t.TLSClientConfig.Clone()
+	}
+
+	if ca != nil {
+		// The CA bundle is trusted in addition to the system's certificate
+		// authorities, replacing any custom CA bundle Crossplane was started
+		// with. SystemCertPool returns a copy we may safely append to.
+		pool, _ := x509.SystemCertPool()
+		if pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if ok := pool.AppendCertsFromPEM(ca.Data[SecretKeyCABundle]); !ok {
+			return nil, errors.New(errNoCACerts)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if cert != nil {
+		c, err := tls.X509KeyPair(cert.Data[corev1.TLSCertKey], cert.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientCert)
+		}
+		cfg.Certificates = []tls.Certificate{c}
+	}
+
+	t.TLSClientConfig = cfg
+	return t, nil
+}
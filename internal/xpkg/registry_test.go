@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+var _ TransportSource = &RegistryConfigTransports{}
+
+// keyPair returns a PEM encoded self-signed certificate and its private key.
+func keyPair(t *testing.T) (cert, key []byte) {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "registry.example.org"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &k.PublicKey, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+func TestRegistryConfigTransports(t *testing.T) {
+	errBoom := errors.New("boom")
+	crt, key := keyPair(t)
+
+	configs := func(rcs ...v1alpha1.RegistryConfig) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			obj.(*v1alpha1.RegistryConfigList).Items = rcs
+			return nil
+		})
+	}
+	secret := func(data map[string][]byte) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.Secret).Data = data
+			return nil
+		})
+	}
+	rc := func(name string, spec v1alpha1.RegistryConfigSpec) v1alpha1.RegistryConfig {
+		return v1alpha1.RegistryConfig{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+	withCA := &v1alpha1.RegistryTLS{CABundleSecretRef: &corev1.LocalObjectReference{Name: "ca"}}
+	withCert := &v1alpha1.RegistryTLS{ClientCertificateSecretRef: &corev1.LocalObjectReference{Name: "cert"}}
+
+	type args struct {
+		client   client.Reader
+		registry string
+	}
+	type want struct {
+		base        bool
+		proxy       string
+		rootCAs     bool
+		clientCerts int
+		err         error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing RegistryConfigs.",
+			args: args{
+				client:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				registry: "registry.example.org",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRegistryConfigs),
+			},
+		},
+		"NoRegistryConfig": {
+			reason: "We should return the base transport if no RegistryConfig applies to the registry.",
+			args: args{
+				client: &test.MockClient{MockList: configs(
+					rc("other", v1alpha1.RegistryConfigSpec{Registry: "other.example.org", ProxyURL: pointer.String("http://proxy.example.org")}),
+				)},
+				registry: "registry.example.org",
+			},
+			want: want{
+				base: true,
+			},
+		},
+		"Proxy": {
+			reason: "We should access the registry through its RegistryConfig's proxy.",
+			args: args{
+				client: &test.MockClient{MockList: configs(
+					rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", ProxyURL: pointer.String("http://proxy.example.org:3128")}),
+				)},
+				registry: "registry.example.org",
+			},
+			want: want{
+				proxy: "http://proxy.example.org:3128",
+			},
+		},
+		"FirstByName": {
+			reason: "We should use the first RegistryConfig by name if more than one applies to the registry.",
+			args: args{
+				client: &test.MockClient{MockList: configs(
+					rc("b", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", ProxyURL: pointer.String("http://b.example.org")}),
+					rc("a", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", ProxyURL: pointer.String("http://a.example.org")}),
+				)},
+				registry: "registry.example.org",
+			},
+			want: want{
+				proxy: "http://a.example.org",
+			},
+		},
+		"InvalidProxyScheme": {
+			reason: "We should return an error if the proxy URL's scheme is not http or https.",
+			args: args{
+				client: &test.MockClient{MockList: configs(
+					rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", ProxyURL: pointer.String("socks5://proxy.example.org")}),
+				)},
+				registry: "registry.example.org",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtProxyScheme, "socks5"), errFmtRegistryConfig, "registry.example.org", "cool"),
+			},
+		},
+		"GetSecretError": {
+			reason: "We should return any error encountered getting a referenced Secret.",
+			args: args{
+				client: &test.MockClient{
+					MockList: configs(rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", TLS: withCA})),
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+				registry: "registry.example.org",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errBoom, errFmtGetSecret, "ca"), errFmtRegistryConfig, "registry.example.org", "cool"),
+			},
+		},
+		"CABundle": {
+			reason: "We should trust the certificates in the RegistryConfig's CA bundle.",
+			args: args{
+				client: &test.MockClient{
+					MockList: configs(rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", TLS: withCA})),
+					MockGet:  secret(map[string][]byte{SecretKeyCABundle: crt}),
+				},
+				registry: "registry.example.org",
+			},
+			want: want{
+				rootCAs: true,
+			},
+		},
+		"InvalidCABundle": {
+			reason: "We should return an error if the RegistryConfig's CA bundle contains no certificates.",
+			args: args{
+				client: &test.MockClient{
+					MockList: configs(rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", TLS: withCA})),
+					MockGet:  secret(map[string][]byte{SecretKeyCABundle: []byte("nope")}),
+				},
+				registry: "registry.example.org",
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errNoCACerts), errFmtRegistryConfig, "registry.example.org", "cool"),
+			},
+		},
+		"ClientCertificate": {
+			reason: "We should present the RegistryConfig's client certificate.",
+			args: args{
+				client: &test.MockClient{
+					MockList: configs(rc("cool", v1alpha1.RegistryConfigSpec{Registry: "registry.example.org", TLS: withCert})),
+					MockGet:  secret(map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				},
+				registry: "registry.example.org",
+			},
+			want: want{
+				clientCerts: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			base := &http.Transport{}
+			s := NewRegistryConfigTransports(tc.args.client, "crossplane-system", base)
+			rt, err := s.Transport(context.Background(), tc.args.registry)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nTransport(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := want{base: rt == base}
+			tr := rt.(*http.Transport)
+			if tr.Proxy != nil {
+				u, _ := tr.Proxy(&http.Request{})
+				got.proxy = u.String()
+			}
+			if tr.TLSClientConfig != nil {
+				got.rootCAs = tr.TLSClientConfig.RootCAs != nil
+				got.clientCerts = len(tr.TLSClientConfig.Certificates)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nTransport(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// Transports are reused until the RegistryConfig changes.
+			again, _ := s.Transport(context.Background(), tc.args.registry)
+			if again != rt {
+				t.Errorf("\n%s\nTransport(...): returned a new transport for an unchanged RegistryConfig", tc.reason)
+			}
+		})
+	}
+}